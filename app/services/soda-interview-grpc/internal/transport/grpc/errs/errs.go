@@ -0,0 +1,172 @@
+package errs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/product"
+	"soda-interview/business/core/referral-blog"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	sodafinance "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/auth"
+	"soda-interview/foundation/validate"
+)
+
+// Domain is reported in the google.rpc.ErrorInfo detail of every mapped error.
+const Domain = "soda-interview"
+
+type mapping struct {
+	target error
+	code   codes.Code
+	reason string
+}
+
+// mappings lists the domain errors that are safe to expose to callers.
+// Store-level sentinels are listed next to their core counterparts because
+// some services (order, finance) propagate store errors directly.
+var mappings = []mapping{
+	{product.ErrNotFound, codes.NotFound, "PRODUCT_NOT_FOUND"},
+	{productstore.ErrNotFound, codes.NotFound, "PRODUCT_NOT_FOUND"},
+	{product.ErrArchived, codes.FailedPrecondition, "PRODUCT_ARCHIVED"},
+	{product.ErrBelowReserved, codes.FailedPrecondition, "STOCK_BELOW_RESERVED"},
+	{referralblog.ErrNotFound, codes.NotFound, "BLOG_NOT_FOUND"},
+	{blogstore.ErrNotFound, codes.NotFound, "BLOG_NOT_FOUND"},
+	{referralblog.ErrNotAuthor, codes.PermissionDenied, "NOT_BLOG_AUTHOR"},
+	{referralblog.ErrInvalidTransition, codes.FailedPrecondition, "INVALID_BLOG_STATUS_TRANSITION"},
+	{finance.ErrNotFound, codes.NotFound, "WALLET_NOT_FOUND"},
+	{sodafinance.ErrNotFound, codes.NotFound, "WALLET_NOT_FOUND"},
+	{finance.ErrInsufficientPoints, codes.FailedPrecondition, "INSUFFICIENT_POINTS"},
+	{sodafinance.ErrInsufficientPoints, codes.FailedPrecondition, "INSUFFICIENT_POINTS"},
+	{order.ErrInsufficientBalance, codes.FailedPrecondition, "INSUFFICIENT_BALANCE"},
+	{sodafinance.ErrInsufficientBalance, codes.FailedPrecondition, "INSUFFICIENT_BALANCE"},
+	{order.ErrPaymentDeclined, codes.FailedPrecondition, "PAYMENT_DECLINED"},
+	{finance.ErrDailyCapExceeded, codes.FailedPrecondition, "DAILY_CONVERSION_CAP_EXCEEDED"},
+	{finance.ErrNoPolicy, codes.FailedPrecondition, "NO_CONVERSION_POLICY"},
+	{finance.ErrWatchUnavailable, codes.Unavailable, "WATCH_UNAVAILABLE"},
+	{sodafinance.ErrNoPolicy, codes.FailedPrecondition, "NO_CONVERSION_POLICY"},
+	{order.ErrNotFound, codes.NotFound, "ORDER_NOT_FOUND"},
+	{orderstore.ErrNotFound, codes.NotFound, "ORDER_NOT_FOUND"},
+	{order.ErrRuleNotFound, codes.NotFound, "REWARD_RULE_NOT_FOUND"},
+	{orderstore.ErrRuleNotFound, codes.NotFound, "REWARD_RULE_NOT_FOUND"},
+	{order.ErrInvalidTransition, codes.FailedPrecondition, "INVALID_STATUS_TRANSITION"},
+	{order.ErrReferralProductMismatch, codes.InvalidArgument, "REFERRAL_PRODUCT_MISMATCH"},
+	{order.ErrSelfReferral, codes.FailedPrecondition, "SELF_REFERRAL"},
+	{order.ErrBlogNotPublished, codes.FailedPrecondition, "BLOG_NOT_PUBLISHED"},
+	{order.ErrProductArchived, codes.FailedPrecondition, "PRODUCT_ARCHIVED"},
+	{order.ErrOutOfStock, codes.FailedPrecondition, "OUT_OF_STOCK"},
+	{productstore.ErrOutOfStock, codes.FailedPrecondition, "OUT_OF_STOCK"},
+	{orderstore.ErrStatusChanged, codes.Aborted, "ORDER_STATUS_CHANGED"},
+	{idempotencystore.ErrKeyReused, codes.InvalidArgument, "IDEMPOTENCY_KEY_REUSED"},
+	{idempotencystore.ErrKeyInFlight, codes.Aborted, "IDEMPOTENCY_KEY_IN_FLIGHT"},
+	{auth.ErrUnauthenticated, codes.Unauthenticated, "UNAUTHENTICATED"},
+	{auth.ErrPermissionDenied, codes.PermissionDenied, "PERMISSION_DENIED"},
+	{pgx.ErrNoRows, codes.NotFound, "NOT_FOUND"},
+}
+
+// UnaryServerInterceptor translates errors returned by handlers into gRPC
+// status errors. Errors it does not recognise are passed through untouched so
+// the server's outer interceptor can log and hide them.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err)
+		}
+		return nil
+	}
+}
+
+// ToStatus converts a domain error into a gRPC status error with
+// google.rpc error details attached. Errors that already carry a status and
+// errors with no known mapping are returned unchanged.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if fe, ok := validate.AsFieldErrors(err); ok {
+		br := &errdetails.BadRequest{}
+		for _, f := range fe {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		return withDetails(codes.InvalidArgument, fe.Error(), errorInfo("INVALID_ARGUMENT"), br)
+	}
+
+	for _, m := range mappings {
+		if !errors.Is(err, m.target) {
+			continue
+		}
+		details := []protoadapt.MessageV1{errorInfo(m.reason)}
+		if m.code == codes.FailedPrecondition {
+			details = append(details, &errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        m.reason,
+					Description: err.Error(),
+				}},
+			})
+		}
+		return withDetails(m.code, err.Error(), details...)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return withDetails(codes.AlreadyExists, "resource already exists", errorInfo("ALREADY_EXISTS"))
+		case "23503": // foreign_key_violation
+			return withDetails(codes.FailedPrecondition, "referenced resource does not exist", errorInfo("REFERENCE_NOT_FOUND"))
+		case "23514": // check_violation
+			return withDetails(codes.FailedPrecondition, "request violates a data constraint", errorInfo("CONSTRAINT_VIOLATION"))
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+	}
+
+	return err
+}
+
+func errorInfo(reason string) protoadapt.MessageV1 {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: Domain}
+}
+
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if withD, err := st.WithDetails(details...); err == nil {
+		st = withD
+	}
+	return st.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"soda-interview/business/core/order"
+	"soda-interview/foundation/auth"
 	orderv1 "soda-interview/foundation/proto/order/v1"
 )
 
@@ -13,23 +14,210 @@ type Handler struct {
 }
 
 func (h *Handler) PlaceOrder(ctx context.Context, req *orderv1.PlaceOrderRequest) (*orderv1.OrderResponse, error) {
+	buyerID, err := auth.UserID(ctx, req.BuyerId)
+	if err != nil {
+		return nil, err
+	}
+
 	o, err := h.Service.PlaceOrder(ctx, order.PlaceOrderReq{
-		BuyerID:   req.BuyerId,
-		ProductID: req.ProductId,
-		BlogID:    req.BlogId,
+		BuyerID:        buyerID,
+		ProductID:      req.ProductId,
+		BlogID:         req.BlogId,
+		Payment:        toPayment(req.Payment),
+		IdempotencyKey: req.IdempotencyKey,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	return toOrderResponse(o), nil
+}
+
+func (h *Handler) PlaceCartOrder(ctx context.Context, req *orderv1.PlaceCartOrderRequest) (*orderv1.OrderResponse, error) {
+	buyerID, err := auth.UserID(ctx, req.BuyerId)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]order.CartLine, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = order.CartLine{
+			ProductID: l.ProductId,
+			Quantity:  l.Quantity,
+			BlogID:    l.BlogId,
+		}
+	}
+
+	o, err := h.Service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+		BuyerID:        buyerID,
+		Lines:          lines,
+		Payment:        toPayment(req.Payment),
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(o), nil
+}
+
+// CancelOrder is open to the buyer of the order and to admins.
+func (h *Handler) CancelOrder(ctx context.Context, req *orderv1.CancelOrderRequest) (*orderv1.OrderResponse, error) {
+	if _, ok := auth.FromContext(ctx); ok && req.OrderId != "" {
+		current, err := h.Service.GetOrder(ctx, req.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := auth.UserID(ctx, current.BuyerID); err != nil {
+			return nil, err
+		}
+	}
+
+	o, err := h.Service.CancelOrder(ctx, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(o), nil
+}
+
+// RefundOrder and UpdateOrderStatus are store operations and need the admin role.
+func (h *Handler) RefundOrder(ctx context.Context, req *orderv1.RefundOrderRequest) (*orderv1.OrderResponse, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	o, err := h.Service.RefundOrder(ctx, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(o), nil
+}
+
+func (h *Handler) UpdateOrderStatus(ctx context.Context, req *orderv1.UpdateOrderStatusRequest) (*orderv1.OrderResponse, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	o, err := h.Service.UpdateStatus(ctx, req.OrderId, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(o), nil
+}
+
+func (h *Handler) CreateRewardRule(ctx context.Context, req *orderv1.CreateRewardRuleRequest) (*orderv1.RewardRule, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := h.Service.CreateRewardRule(ctx, order.NewRewardRule{
+		Name:              req.Name,
+		Recipient:         req.Recipient,
+		Kind:              req.Kind,
+		Value:             req.Value,
+		Priority:          req.Priority,
+		ProductID:         req.ProductId,
+		FirstPurchaseOnly: req.FirstPurchaseOnly,
+		MinAuthorSales:    req.MinAuthorSales,
+		MaxAuthorSales:    req.MaxAuthorSales,
+		UserCapPoints:     req.UserCapPoints,
+		StartsAt:          req.StartsAt,
+		EndsAt:            req.EndsAt,
+		CreatedBy:         auth.Actor(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toRewardRuleResponse(r), nil
+}
+
+func (h *Handler) ListRewardRules(ctx context.Context, _ *orderv1.ListRewardRulesRequest) (*orderv1.ListRewardRulesResponse, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rules, err := h.Service.ListRewardRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &orderv1.ListRewardRulesResponse{
+		Rules: make([]*orderv1.RewardRule, len(rules)),
+	}
+	for i, r := range rules {
+		resp.Rules[i] = toRewardRuleResponse(r)
+	}
+	return resp, nil
+}
+
+func (h *Handler) EndRewardRule(ctx context.Context, req *orderv1.EndRewardRuleRequest) (*orderv1.RewardRule, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := h.Service.EndRewardRule(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toRewardRuleResponse(r), nil
+}
+
+func toPayment(p *orderv1.Payment) order.Payment {
+	return order.Payment{
+		BalanceAmount:  p.GetBalanceAmount(),
+		ExternalAmount: p.GetExternalAmount(),
+	}
+}
+
+func toOrderResponse(o order.Order) *orderv1.OrderResponse {
+	items := make([]*orderv1.OrderItem, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = &orderv1.OrderItem{
+			LineNo:      item.LineNo,
+			ProductId:   item.ProductID,
+			BlogId:      item.BlogID,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+			Attribution: item.Attribution,
+		}
+	}
+
 	return &orderv1.OrderResponse{
 		Order: &orderv1.Order{
-			Id:        o.ID,
-			BuyerId:   o.BuyerID,
-			ProductId: o.ProductID,
-			Amount:    o.Amount,
-			Status:    o.Status,
-			CreatedAt: o.CreatedAt,
+			Id:             o.ID,
+			BuyerId:        o.BuyerID,
+			ProductId:      o.ProductID,
+			Amount:         o.Amount,
+			Status:         o.Status,
+			CreatedAt:      o.CreatedAt,
+			Items:          items,
+			BalanceAmount:  o.Payment.BalanceAmount,
+			ExternalAmount: o.Payment.ExternalAmount,
+			PaymentId:      o.PaymentID,
 		},
-	}, nil
-}
\ No newline at end of file
+	}
+}
+
+func toRewardRuleResponse(r order.RewardRule) *orderv1.RewardRule {
+	return &orderv1.RewardRule{
+		Id:                r.ID,
+		Name:              r.Name,
+		Recipient:         r.Recipient,
+		Kind:              r.Kind,
+		Value:             r.Value,
+		Priority:          r.Priority,
+		ProductId:         r.ProductID,
+		FirstPurchaseOnly: r.FirstPurchaseOnly,
+		MinAuthorSales:    r.MinAuthorSales,
+		MaxAuthorSales:    r.MaxAuthorSales,
+		UserCapPoints:     r.UserCapPoints,
+		StartsAt:          r.StartsAt,
+		EndsAt:            r.EndsAt,
+		CreatedBy:         r.CreatedBy,
+		CreatedAt:         r.CreatedAt,
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"soda-interview/business/core/product"
+	"soda-interview/foundation/auth"
 	productv1 "soda-interview/foundation/proto/product/v1"
+	"soda-interview/foundation/validate"
 )
 
 type Handler struct {
@@ -18,31 +20,140 @@ func (h *Handler) GetProduct(ctx context.Context, req *productv1.ProductRequest)
 		return nil, err
 	}
 
-	return &productv1.Product{
-		Id:                p.ID,
-		Name:              p.Name,
-		Description:       p.Description,
-		Price:             p.Price,
-		BuyerRewardPoints: p.BuyerRewardPoints,
-	}, nil
+	return toProductResponse(p), nil
 }
 
-func (h *Handler) ListProducts(ctx context.Context, _ *productv1.Empty) (*productv1.ProductList, error) {
-	products, err := h.Service.ListProducts(ctx)
+func (h *Handler) ListProducts(ctx context.Context, req *productv1.ListProductsRequest) (*productv1.ProductList, error) {
+	page, err := h.Service.ListProducts(ctx, product.ListProductsReq{
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		NameContains: req.NameContains,
+		OrderBy:      req.OrderBy,
+		PageToken:    req.PageToken,
+		PageSize:     int(req.PageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*productv1.Product, len(page.Products))
+	for i, p := range page.Products {
+		list[i] = toProductResponse(p)
+	}
+
+	return &productv1.ProductList{Products: list, NextPageToken: page.NextPageToken}, nil
+}
+
+func (h *Handler) CreateProduct(ctx context.Context, req *productv1.CreateProductRequest) (*productv1.Product, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	p, err := h.Service.Create(ctx, product.NewProduct{
+		Name:               req.Name,
+		Description:        req.Description,
+		Price:              req.Price,
+		BuyerRewardPoints:  req.BuyerRewardPoints,
+		AuthorRewardPoints: req.AuthorRewardPoints,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	list := make([]*productv1.Product, len(products))
-	for i, p := range products {
-		list[i] = &productv1.Product{
-			Id:                p.ID,
-			Name:              p.Name,
-			Description:       p.Description,
-			Price:             p.Price,
-			BuyerRewardPoints: p.BuyerRewardPoints,
+	return toProductResponse(p), nil
+}
+
+func (h *Handler) UpdateProduct(ctx context.Context, req *productv1.UpdateProductRequest) (*productv1.Product, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var fe validate.FieldErrors
+	var up product.UpdateProduct
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch path {
+		case "name":
+			up.Name = &req.Name
+		case "description":
+			up.Description = &req.Description
+		case "price":
+			up.Price = &req.Price
+		case "buyer_reward_points":
+			up.BuyerRewardPoints = &req.BuyerRewardPoints
+		case "author_reward_points":
+			up.AuthorRewardPoints = &req.AuthorRewardPoints
+		default:
+			fe.Add("update_mask", "unknown field "+path)
 		}
 	}
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		fe.Add("update_mask", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := h.Service.Update(ctx, req.Id, up)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProductResponse(p), nil
+}
+
+func (h *Handler) ArchiveProduct(ctx context.Context, req *productv1.ArchiveProductRequest) (*productv1.Product, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	p, err := h.Service.Archive(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
 
-	return &productv1.ProductList{Products: list}, nil
-}
\ No newline at end of file
+	return toProductResponse(p), nil
+}
+
+func (h *Handler) GetStock(ctx context.Context, req *productv1.GetStockRequest) (*productv1.Stock, error) {
+	st, err := h.Service.GetStock(ctx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStockResponse(st), nil
+}
+
+func (h *Handler) AdjustStock(ctx context.Context, req *productv1.AdjustStockRequest) (*productv1.Stock, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := h.Service.AdjustStock(ctx, req.ProductId, req.Delta, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStockResponse(st), nil
+}
+
+func toProductResponse(p product.Product) *productv1.Product {
+	return &productv1.Product{
+		Id:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		BuyerRewardPoints: p.BuyerRewardPoints,
+		ArchivedAt:        p.ArchivedAt,
+		CreatedAt:         p.CreatedAt,
+	}
+}
+
+func toStockResponse(st product.Stock) *productv1.Stock {
+	return &productv1.Stock{
+		ProductId: st.ProductID,
+		Tracked:   st.Tracked,
+		OnHand:    st.OnHand,
+		Reserved:  st.Reserved,
+		Available: st.Available(),
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 
 	"soda-interview/business/core/referral-blog"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	"soda-interview/foundation/auth"
 	referralblogv1 "soda-interview/foundation/proto/referral-blog/v1"
+	"soda-interview/foundation/validate"
 )
 
 type Handler struct {
@@ -13,10 +16,16 @@ type Handler struct {
 }
 
 func (h *Handler) CreateBlog(ctx context.Context, req *referralblogv1.CreateBlogRequest) (*referralblogv1.Blog, error) {
+	authorID, err := auth.UserID(ctx, req.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
 	nb := referralblog.NewBlog{
-		AuthorID:  req.AuthorId,
+		AuthorID:  authorID,
 		Content:   req.Content,
 		ProductID: req.ProductId,
+		Draft:     req.Draft,
 	}
 
 	b, err := h.Service.CreateBlog(ctx, nb)
@@ -24,12 +33,7 @@ func (h *Handler) CreateBlog(ctx context.Context, req *referralblogv1.CreateBlog
 		return nil, err
 	}
 
-	return &referralblogv1.Blog{
-		Id:              b.ID,
-		AuthorId:        b.AuthorID,
-		Content:         b.Content,
-		LinkedProductId: b.LinkedProductID,
-	}, nil
+	return toBlogResponse(b), nil
 }
 
 func (h *Handler) GetBlog(ctx context.Context, req *referralblogv1.BlogRequest) (*referralblogv1.Blog, error) {
@@ -38,29 +42,220 @@ func (h *Handler) GetBlog(ctx context.Context, req *referralblogv1.BlogRequest)
 		return nil, err
 	}
 
-	return &referralblogv1.Blog{
-		Id:              b.ID,
-		AuthorId:        b.AuthorID,
-		Content:         b.Content,
-		LinkedProductId: b.LinkedProductID,
-	}, nil
+	// Blogs that are not published are hidden from everyone but their
+	// author and admins.
+	if b.Status != blogstore.StatusPublished {
+		if _, err := auth.UserID(ctx, b.AuthorID); err != nil {
+			return nil, referralblog.ErrNotFound
+		}
+	}
+
+	return toBlogResponse(b), nil
 }
 
-func (h *Handler) ListBlogs(ctx context.Context, _ *referralblogv1.Empty) (*referralblogv1.BlogList, error) {
-	blogs, err := h.Service.ListBlogs(ctx)
+func (h *Handler) ListBlogs(ctx context.Context, req *referralblogv1.ListBlogsRequest) (*referralblogv1.BlogList, error) {
+	page, err := h.Service.ListBlogs(ctx, referralblog.ListBlogsReq{
+		AuthorID:  req.AuthorId,
+		ProductID: req.ProductId,
+		OrderBy:   req.OrderBy,
+		PageToken: req.PageToken,
+		PageSize:  int(req.PageSize),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	list := make([]*referralblogv1.Blog, len(blogs))
-	for i, b := range blogs {
-		list[i] = &referralblogv1.Blog{
-			Id:              b.ID,
-			AuthorId:        b.AuthorID,
-			Content:         b.Content,
-			LinkedProductId: b.LinkedProductID,
+	list := make([]*referralblogv1.Blog, len(page.Blogs))
+	for i, b := range page.Blogs {
+		list[i] = toBlogResponse(b)
+	}
+
+	return &referralblogv1.BlogList{Blogs: list, NextPageToken: page.NextPageToken}, nil
+}
+
+func (h *Handler) UpdateBlog(ctx context.Context, req *referralblogv1.UpdateBlogRequest) (*referralblogv1.Blog, error) {
+	authorID, err := auth.UserID(ctx, req.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	var fe validate.FieldErrors
+	var ub referralblog.UpdateBlog
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch path {
+		case "content":
+			ub.Content = &req.Content
+		case "status":
+			ub.Status = &req.Status
+		default:
+			fe.Add("update_mask", "unknown field "+path)
 		}
 	}
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		fe.Add("update_mask", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return nil, err
+	}
+
+	b, err := h.Service.UpdateBlog(ctx, req.Id, authorID, ub)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBlogResponse(b), nil
+}
+
+func (h *Handler) DeleteBlog(ctx context.Context, req *referralblogv1.DeleteBlogRequest) (*referralblogv1.Empty, error) {
+	authorID, err := auth.UserID(ctx, req.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.Service.DeleteBlog(ctx, req.Id, authorID); err != nil {
+		return nil, err
+	}
+
+	return &referralblogv1.Empty{}, nil
+}
+
+func (h *Handler) ModerateBlog(ctx context.Context, req *referralblogv1.ModerateBlogRequest) (*referralblogv1.Blog, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	b, err := h.Service.ModerateBlog(ctx, req.Id, auth.Actor(ctx), referralblog.Moderation{
+		Status: req.Status,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return &referralblogv1.BlogList{Blogs: list}, nil
-}
\ No newline at end of file
+	return toBlogResponse(b), nil
+}
+
+func (h *Handler) ListBlogRevisions(ctx context.Context, req *referralblogv1.ListBlogRevisionsRequest) (*referralblogv1.ListBlogRevisionsResponse, error) {
+	b, err := h.Service.GetBlog(ctx, req.BlogId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := auth.UserID(ctx, b.AuthorID); err != nil {
+		return nil, err
+	}
+
+	revs, err := h.Service.ListRevisions(ctx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &referralblogv1.ListBlogRevisionsResponse{
+		Revisions: make([]*referralblogv1.BlogRevision, len(revs)),
+	}
+	for i, r := range revs {
+		resp.Revisions[i] = &referralblogv1.BlogRevision{
+			Id:        r.ID,
+			BlogId:    r.BlogID,
+			Action:    r.Action,
+			Content:   r.Content,
+			Status:    r.Status,
+			ActorId:   r.ActorID,
+			Note:      r.Note,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+	return resp, nil
+}
+
+func (h *Handler) ReferralAnalytics(ctx context.Context, req *referralblogv1.ReferralAnalyticsRequest) (*referralblogv1.ReferralAnalyticsResponse, error) {
+	// Admins may look at any author, or all of them; everyone else sees
+	// their own referrals.
+	authorID := req.AuthorId
+	if err := auth.RequireAdmin(ctx); err != nil {
+		if authorID, err = auth.UserID(ctx, req.AuthorId); err != nil {
+			return nil, err
+		}
+	}
+
+	a, err := h.Service.ReferralAnalytics(ctx, referralblog.AnalyticsReq{
+		AuthorID:    authorID,
+		BlogID:      req.BlogId,
+		From:        req.From,
+		To:          req.To,
+		Granularity: req.Granularity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &referralblogv1.ReferralAnalyticsResponse{
+		From:        a.From,
+		To:          a.To,
+		Granularity: a.Granularity,
+		Totals:      toStatsResponse(a.Totals),
+		Blogs:       make([]*referralblogv1.BlogReferralStats, len(a.Blogs)),
+		Authors:     make([]*referralblogv1.AuthorReferralStats, len(a.Authors)),
+		Series:      make([]*referralblogv1.ReferralBucket, len(a.Series)),
+	}
+	for i, b := range a.Blogs {
+		resp.Blogs[i] = &referralblogv1.BlogReferralStats{
+			BlogId:    b.BlogID,
+			AuthorId:  b.AuthorID,
+			ProductId: b.ProductID,
+			Stats:     toStatsResponse(b.ReferralStats),
+		}
+	}
+	for i, au := range a.Authors {
+		resp.Authors[i] = &referralblogv1.AuthorReferralStats{
+			AuthorId: au.AuthorID,
+			Blogs:    au.Blogs,
+			Stats:    toStatsResponse(au.ReferralStats),
+		}
+	}
+	for i, b := range a.Series {
+		resp.Series[i] = &referralblogv1.ReferralBucket{
+			Start: b.Start,
+			Stats: toStatsResponse(b.ReferralStats),
+		}
+	}
+	return resp, nil
+}
+
+func (h *Handler) RecordReferralVisit(ctx context.Context, req *referralblogv1.RecordReferralVisitRequest) (*referralblogv1.ReferralVisit, error) {
+	visitorID, err := auth.UserID(ctx, req.VisitorId)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := h.Service.RecordVisit(ctx, visitorID, req.BlogId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &referralblogv1.ReferralVisit{
+		Id:        v.ID,
+		VisitorId: v.VisitorID,
+		BlogId:    v.BlogID,
+		VisitedAt: v.VisitedAt,
+	}, nil
+}
+
+func toStatsResponse(st referralblog.ReferralStats) *referralblogv1.ReferralStats {
+	return &referralblogv1.ReferralStats{
+		Orders:  st.Orders,
+		Revenue: st.Revenue,
+		Points:  st.Points,
+	}
+}
+
+func toBlogResponse(b referralblog.Blog) *referralblogv1.Blog {
+	return &referralblogv1.Blog{
+		Id:              b.ID,
+		AuthorId:        b.AuthorID,
+		Content:         b.Content,
+		LinkedProductId: b.LinkedProductID,
+		Status:          b.Status,
+		CreatedAt:       b.CreatedAt,
+		UpdatedAt:       b.UpdatedAt,
+	}
+}
@@ -2,8 +2,12 @@ package sodafinance
 
 import (
 	"context"
+	"time"
+
+	"google.golang.org/grpc"
 
 	"soda-interview/business/core/finance"
+	"soda-interview/foundation/auth"
 	financev1 "soda-interview/foundation/proto/soda-finance/v1"
 )
 
@@ -13,7 +17,12 @@ type Handler struct {
 }
 
 func (h *Handler) GetWallet(ctx context.Context, req *financev1.UserRequest) (*financev1.Wallet, error) {
-	w, err := h.Service.GetWallet(ctx, req.UserId)
+	userID, err := auth.UserID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := h.Service.GetWallet(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +35,12 @@ func (h *Handler) GetWallet(ctx context.Context, req *financev1.UserRequest) (*f
 }
 
 func (h *Handler) ConvertPoints(ctx context.Context, req *financev1.ConvertRequest) (*financev1.Wallet, error) {
-	w, err := h.Service.ConvertPoints(ctx, req.UserId, req.PointsToConvert)
+	userID, err := auth.UserID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := h.Service.ConvertPointsIdempotent(ctx, userID, req.PointsToConvert, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
@@ -36,4 +50,149 @@ func (h *Handler) ConvertPoints(ctx context.Context, req *financev1.ConvertReque
 		SodaPoints:  w.SodaPoints,
 		SodaBalance: w.SodaBalance,
 	}, nil
-}
\ No newline at end of file
+}
+func (h *Handler) WatchWallet(req *financev1.WatchWalletRequest, stream grpc.ServerStreamingServer[financev1.WalletEvent]) error {
+	ctx := stream.Context()
+	userID, err := auth.UserID(ctx, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	return h.Service.WatchWallet(ctx, userID, func(u finance.WalletUpdate) error {
+		if u.Heartbeat {
+			return stream.Send(&financev1.WalletEvent{Event: &financev1.WalletEvent_Heartbeat{
+				Heartbeat: &financev1.Heartbeat{SentAt: time.Now().Unix()},
+			}})
+		}
+		return stream.Send(&financev1.WalletEvent{Event: &financev1.WalletEvent_Wallet{
+			Wallet: &financev1.Wallet{
+				UserId:      u.Wallet.UserID,
+				SodaPoints:  u.Wallet.SodaPoints,
+				SodaBalance: u.Wallet.SodaBalance,
+			},
+		}})
+	})
+}
+
+func (h *Handler) ListTransactions(ctx context.Context, req *financev1.ListTransactionsRequest) (*financev1.ListTransactionsResponse, error) {
+	userID, err := auth.UserID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := h.Service.ListTransactions(ctx, finance.ListTransactionsReq{
+		UserID:      userID,
+		Types:       req.Types,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		PageToken:   req.PageToken,
+		PageSize:    int(req.PageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &financev1.ListTransactionsResponse{
+		Transactions:  make([]*financev1.Transaction, 0, len(page.Transactions)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, t := range page.Transactions {
+		resp.Transactions = append(resp.Transactions, &financev1.Transaction{
+			Id:             t.ID,
+			UserId:         t.UserID,
+			Type:           t.Type,
+			Amount:         t.Amount,
+			RelatedOrderId: t.RelatedOrderID,
+			CreatedAt:      t.CreatedAt,
+			PolicyVersion:  t.PolicyVersion,
+			RewardRuleIds:  t.RewardRuleIDs,
+		})
+	}
+	return resp, nil
+}
+
+func (h *Handler) GetExpiringPoints(ctx context.Context, req *financev1.GetExpiringPointsRequest) (*financev1.GetExpiringPointsResponse, error) {
+	userID, err := auth.UserID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ep, err := h.Service.GetExpiringPoints(ctx, userID, req.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &financev1.GetExpiringPointsResponse{
+		TotalPoints: ep.Total,
+		Lots:        make([]*financev1.PointLot, len(ep.Lots)),
+	}
+	for i, l := range ep.Lots {
+		resp.Lots[i] = &financev1.PointLot{
+			TransactionId: l.TransactionID,
+			Points:        l.Remaining,
+			ExpiresAt:     l.ExpiresAt,
+		}
+	}
+	return resp, nil
+}
+
+func (h *Handler) GetConversionPolicy(ctx context.Context, _ *financev1.GetConversionPolicyRequest) (*financev1.ConversionPolicy, error) {
+	p, err := h.Service.ActivePolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toPolicyResponse(p), nil
+}
+
+func (h *Handler) ScheduleConversionPolicy(ctx context.Context, req *financev1.ScheduleConversionPolicyRequest) (*financev1.ConversionPolicy, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	p, err := h.Service.SchedulePolicy(ctx, finance.NewPolicy{
+		PointsPerYen:   req.PointsPerYen,
+		MinBalance:     req.MinBalance,
+		MinPoints:      req.MinPoints,
+		MaxPoints:      req.MaxPoints,
+		DailyCapPoints: req.DailyCapPoints,
+		EffectiveFrom:  req.EffectiveFrom,
+		CreatedBy:      auth.Actor(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPolicyResponse(p), nil
+}
+
+func (h *Handler) ListConversionPolicies(ctx context.Context, _ *financev1.ListConversionPoliciesRequest) (*financev1.ListConversionPoliciesResponse, error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	policies, err := h.Service.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &financev1.ListConversionPoliciesResponse{
+		Policies: make([]*financev1.ConversionPolicy, len(policies)),
+	}
+	for i, p := range policies {
+		resp.Policies[i] = toPolicyResponse(p)
+	}
+	return resp, nil
+}
+
+func toPolicyResponse(p finance.Policy) *financev1.ConversionPolicy {
+	return &financev1.ConversionPolicy{
+		Version:        p.Version,
+		PointsPerYen:   p.PointsPerYen,
+		MinBalance:     p.MinBalance,
+		MinPoints:      p.MinPoints,
+		MaxPoints:      p.MaxPoints,
+		DailyCapPoints: p.DailyCapPoints,
+		EffectiveFrom:  p.EffectiveFrom,
+		CreatedBy:      p.CreatedBy,
+		CreatedAt:      p.CreatedAt,
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"soda-interview/business/core/order"
+	"soda-interview/foundation/logger"
+	"soda-interview/foundation/payment"
+)
+
+// maxBodyBytes bounds the size of a webhook request body.
+const maxBodyBytes = 1 << 20
+
+// PaymentHandler receives the payment gateway's webhooks and settles the
+// orders they are about. Anything but a 2xx answer makes the gateway deliver
+// the event again, so only errors worth retrying get a 5xx.
+type PaymentHandler struct {
+	Log     *logger.Logger
+	Gateway payment.Gateway
+	Service *order.Service
+}
+
+func (h *PaymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "unreadable body", http.StatusBadRequest)
+		return
+	}
+
+	ev, err := h.Gateway.VerifyWebhook(r.Header, body)
+	if err != nil {
+		h.Log.Warn("Rejected payment webhook", "error", err)
+		http.Error(w, "invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	log := h.Log.With("event_id", ev.ID, "event", ev.Type, "order_id", ev.Reference, "payment_id", ev.PaymentID)
+
+	var o order.Order
+	switch ev.Type {
+	case payment.EventAuthorized:
+		o, err = h.Service.ConfirmPayment(r.Context(), ev.Reference, ev.PaymentID)
+	case payment.EventFailed:
+		o, err = h.Service.FailPayment(r.Context(), ev.Reference, ev.PaymentID)
+	default:
+		// Events the service does not act on are acknowledged so the
+		// gateway stops sending them.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch {
+	case err == nil:
+		log.Info("Applied payment event", "status", o.Status)
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, order.ErrNotFound):
+		// The order may not be committed yet; let the gateway retry.
+		log.Warn("Payment event for unknown order")
+		http.Error(w, "order not found", http.StatusNotFound)
+	case errors.Is(err, order.ErrPaymentMismatch):
+		log.Error("Payment event does not match order", "error", err)
+		http.Error(w, "payment does not match order", http.StatusConflict)
+	default:
+		log.Error("Failed to apply payment event", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -1,26 +1,37 @@
 package main
 
 import (
+	"net/http"
+	"os"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
 
+	"soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/errs"
 	grpctransportorder "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/order"
 	grpctransportproduct "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/product"
 	grpctransportreferral_blog "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/referral-blog"
 	grpctransportsoda_finance "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/soda-finance"
+	"soda-interview/app/services/soda-interview-grpc/internal/transport/webhook"
 
 	"soda-interview/business/core/finance"
 	"soda-interview/business/core/order"
+	"soda-interview/business/core/outbox"
 	"soda-interview/business/core/product"
 	"soda-interview/business/core/referral-blog"
 
+	idempotencystore "soda-interview/business/data/stores/idempotency"
 	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	productstore "soda-interview/business/data/stores/product"
 	blogstore "soda-interview/business/data/stores/referral-blog"
 	financestore "soda-interview/business/data/stores/soda-finance"
 
 	"soda-interview/foundation/bootstrap"
+	"soda-interview/foundation/config"
+	"soda-interview/foundation/events"
 	"soda-interview/foundation/logger"
+	"soda-interview/foundation/payment"
 	orderv1 "soda-interview/foundation/proto/order/v1"
 	productv1 "soda-interview/foundation/proto/product/v1"
 	referralblogv1 "soda-interview/foundation/proto/referral-blog/v1"
@@ -28,19 +39,50 @@ import (
 )
 
 func main() {
-	bootstrap.Run(func(log *logger.Logger, db *pgxpool.Pool, grpcServer *grpc.Server) {
+	// Domain errors are translated to gRPC status codes before they reach
+	// the built-in interceptors in bootstrap.
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(errs.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(errs.StreamServerInterceptor()),
+	}
+
+	bootstrap.Run(func(log *logger.Logger, cfg *config.Config, db *pgxpool.Pool, grpcServer *grpc.Server) []bootstrap.Worker {
 		// Stores
 		productSt := productstore.NewStore(log, db)
 		blogSt := blogstore.NewStore(log, db)
 		orderSt := orderstore.NewStore(log, db)
 		financeSt := financestore.NewStore(log, db)
+		financeSt.SetPointsTTL(cfg.Finance.PointsTTL)
+		idempotencySt := idempotencystore.NewStore(log, db)
+		outboxSt := outboxstore.NewStore(log, db)
 
 		// Core Services
-		// Note: Product and Blog services accept interfaces. Order and Finance accept concrete stores for TX handling.
+		// Note: Product accepts an interface. The others accept concrete stores for TX handling.
 		productService := product.NewService(log, productSt)
-		blogService := referralblog.NewService(log, blogSt)
-		financeService := finance.NewService(log, db, financeSt)
-		orderService := order.NewService(log, db, orderSt, productSt, blogSt, financeSt)
+		blogService := referralblog.NewService(log, db, blogSt, outboxSt)
+		financeService := finance.NewService(log, db, financeSt, idempotencySt, outboxSt)
+		orderService := order.NewService(log, db, orderSt, productSt, blogSt, financeSt, idempotencySt, outboxSt)
+		orderService.SetAttributionWindow(cfg.Referral.AttributionWindow)
+
+		// Wallet watches follow committed wallet changes from every replica.
+		walletWatcher := financestore.NewWalletWatcher(log, db)
+		financeService.SetWalletWatcher(walletWatcher, cfg.Finance.WatchHeartbeat)
+
+		// Payment gateway
+		var gateway payment.Gateway
+		switch cfg.Payment.Provider {
+		case "fake":
+			fake := cfg.Payment.Fake
+			gateway = payment.NewFake(log, cfg.Payment.WebhookSecret, payment.FakeConfig{
+				WebhookURL:   fake.WebhookURL,
+				WebhookDelay: fake.WebhookDelay,
+				DeclineAbove: fake.DeclineAbove,
+			})
+		}
+		if gateway != nil {
+			orderService.SetGateway(gateway)
+			log.Info("Payment gateway enabled", "provider", cfg.Payment.Provider)
+		}
 
 		// Transport Handlers
 		productHandler := &grpctransportproduct.Handler{Service: productService}
@@ -55,5 +97,38 @@ func main() {
 		orderv1.RegisterOrderServiceServer(grpcServer, orderHandler)
 
 		log.Info("All services registered")
-	})
+
+		// Background workers
+		workers := []bootstrap.Worker{walletWatcher}
+		if sweep := cfg.Finance.ExpirySweep; sweep.Enabled {
+			workers = append(workers, finance.NewExpirySweeper(log, financeService, sweep.Interval, sweep.BatchSize))
+		}
+		if relay := cfg.Outbox.Relay; relay.Enabled {
+			publisher, err := events.NewFile(cfg.Outbox.Publisher.Path)
+			if err != nil {
+				log.Error("Failed to open event publisher", "path", cfg.Outbox.Publisher.Path, "error", err)
+				os.Exit(1)
+			}
+			workers = append(workers, outbox.NewRelay(log, db, outboxSt, publisher, outbox.RelayConfig{
+				Interval:    relay.Interval,
+				BatchSize:   relay.BatchSize,
+				MaxAttempts: relay.MaxAttempts,
+				Backoff:     relay.Backoff,
+			}))
+		}
+		if gateway != nil {
+			retry := cfg.Payment.Retry
+			workers = append(workers, order.NewPaymentRetrier(log, orderService, order.PaymentRetryConfig{
+				Interval:    retry.Interval,
+				BatchSize:   retry.BatchSize,
+				MaxAttempts: retry.MaxAttempts,
+				Backoff:     retry.Backoff,
+			}))
+
+			mux := http.NewServeMux()
+			mux.Handle(cfg.Payment.WebhookPath, &webhook.PaymentHandler{Log: log, Gateway: gateway, Service: orderService})
+			workers = append(workers, bootstrap.NewHTTPWorker(log, "payment webhook", cfg.GetPaymentWebhookAddress(), mux))
+		}
+		return workers
+	}, opts...)
 }
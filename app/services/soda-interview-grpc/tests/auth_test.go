@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	grpctransportproduct "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/product"
+	grpctransportsoda_finance "soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/soda-finance"
+	"soda-interview/foundation/auth"
+	productv1 "soda-interview/foundation/proto/product/v1"
+	financev1 "soda-interview/foundation/proto/soda-finance/v1"
+)
+
+func Test_Auth(t *testing.T) {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating rsa key: %v", err)
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "use": "sig",
+			"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "oct", "kid": "hmac-1", "alg": "HS256", "k": b64(secret)},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("writing jwks: %v", err)
+	}
+
+	keys, err := auth.LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet failed: %v", err)
+	}
+	verifier := auth.NewVerifier(keys, "soda-interview", "soda-interview", time.Minute)
+
+	// sign builds a compact JWT; alg "none" leaves the signature empty.
+	sign := func(t *testing.T, alg, kid string, claims map[string]any) string {
+		t.Helper()
+		h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+		c, _ := json.Marshal(claims)
+		signed := b64(h) + "." + b64(c)
+
+		var sig []byte
+		switch alg {
+		case "HS256":
+			mac := hmac.New(sha256.New, secret)
+			mac.Write([]byte(signed))
+			sig = mac.Sum(nil)
+		case "RS256":
+			digest := sha256.Sum256([]byte(signed))
+			sig, err = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+			if err != nil {
+				t.Fatalf("signing: %v", err)
+			}
+		}
+		return signed + "." + b64(sig)
+	}
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"sub":   "user-1",
+			"iss":   "soda-interview",
+			"aud":   []string{"soda-interview"},
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"admin"},
+		}
+	}
+
+	t.Run("Verify_Success", func(t *testing.T) {
+		for _, tc := range []struct{ alg, kid string }{{"HS256", "hmac-1"}, {"RS256", "rsa-1"}} {
+			p, err := verifier.Verify(sign(t, tc.alg, tc.kid, validClaims()))
+			if err != nil {
+				t.Fatalf("%s: Verify failed: %v", tc.alg, err)
+			}
+			if p.Subject != "user-1" || !p.IsAdmin() {
+				t.Errorf("%s: unexpected principal %+v", tc.alg, p)
+			}
+		}
+	})
+
+	t.Run("Verify_Rejects", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			token func() string
+		}{
+			{"Expired", func() string {
+				c := validClaims()
+				c["exp"] = time.Now().Add(-time.Hour).Unix()
+				return sign(t, "HS256", "hmac-1", c)
+			}},
+			{"MissingExp", func() string {
+				c := validClaims()
+				delete(c, "exp")
+				return sign(t, "HS256", "hmac-1", c)
+			}},
+			{"WrongAudience", func() string {
+				c := validClaims()
+				c["aud"] = "someone-else"
+				return sign(t, "RS256", "rsa-1", c)
+			}},
+			{"WrongIssuer", func() string {
+				c := validClaims()
+				c["iss"] = "evil"
+				return sign(t, "RS256", "rsa-1", c)
+			}},
+			{"AlgNone", func() string { return sign(t, "none", "", validClaims()) }},
+			{"UnknownKid", func() string { return sign(t, "RS256", "rsa-2", validClaims()) }},
+			{"AlgMismatch", func() string { return sign(t, "HS256", "rsa-1", validClaims()) }},
+			{"Tampered", func() string {
+				tok := sign(t, "HS256", "hmac-1", validClaims())
+				return tok[:len(tok)-2] + "AA"
+			}},
+			{"Malformed", func() string { return "not-a-jwt" }},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := verifier.Verify(tc.token()); !errors.Is(err, auth.ErrUnauthenticated) {
+					t.Errorf("expected ErrUnauthenticated, got %v", err)
+				}
+			})
+		}
+	})
+
+	user := auth.NewContext(context.Background(), auth.Principal{Subject: "user-1"})
+	admin := auth.NewContext(context.Background(), auth.Principal{Subject: "ops", Roles: []string{auth.RoleAdmin}})
+
+	t.Run("UserID", func(t *testing.T) {
+		if id, err := auth.UserID(user, ""); err != nil || id != "user-1" {
+			t.Errorf("expected caller's ID, got %q, %v", id, err)
+		}
+		if _, err := auth.UserID(user, "user-2"); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+		if id, err := auth.UserID(admin, "user-2"); err != nil || id != "user-2" {
+			t.Errorf("expected admin to act for user-2, got %q, %v", id, err)
+		}
+	})
+
+	t.Run("Handlers_DenyOtherUsers", func(t *testing.T) {
+		finance := &grpctransportsoda_finance.Handler{}
+		if _, err := finance.GetWallet(user, &financev1.UserRequest{UserId: "user-2"}); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("expected ErrPermissionDenied reading another wallet, got %v", err)
+		}
+		if _, err := finance.ConvertPoints(user, &financev1.ConvertRequest{UserId: "user-2", PointsToConvert: 2000}); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("expected ErrPermissionDenied converting for another user, got %v", err)
+		}
+
+		products := &grpctransportproduct.Handler{}
+		if _, err := products.CreateProduct(user, &productv1.CreateProductRequest{Name: "x", Price: 1}); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("expected ErrPermissionDenied creating a product, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_BalancePayment(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T, price int64) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:          uuid.NewString(),
+			Name:        "Balance Product",
+			Description: "Desc",
+			Price:       price,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	setupBuyer := func(t *testing.T, yen int64) string {
+		userID := uuid.NewString()
+		if _, err := fStore.GetOrCreateWallet(ctx, userID); err != nil {
+			t.Fatalf("create wallet failed: %v", err)
+		}
+		if _, err := fStore.Adjust(ctx, userID, 0, yen); err != nil {
+			t.Fatalf("add balance failed: %v", err)
+		}
+		return userID
+	}
+
+	balance := func(t *testing.T, userID string) int64 {
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		return w.SodaBalance
+	}
+
+	t.Run("Success_SplitPaymentDebitsBalance", func(t *testing.T) {
+		buyerID := setupBuyer(t, 500)
+
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+			Payment:   order.Payment{BalanceAmount: 300, ExternalAmount: 700},
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if o.Payment.BalanceAmount != 300 || o.Payment.ExternalAmount != 700 {
+			t.Errorf("unexpected payment on order: %+v", o.Payment)
+		}
+		if got := balance(t, buyerID); got != 200 {
+			t.Errorf("expected 200 yen left, got %d", got)
+		}
+
+		txs, err := fStore.ListTransactionsByOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("ListTransactionsByOrder failed: %v", err)
+		}
+		var spent int64
+		for _, tx := range txs {
+			if tx.Type == financestore.TxSpent {
+				spent += tx.Amount
+			}
+		}
+		if spent != 300 {
+			t.Errorf("expected a SPENT transaction of 300, got %d", spent)
+		}
+	})
+
+	t.Run("Success_NoPaymentIsExternal", func(t *testing.T) {
+		buyerID := setupBuyer(t, 500)
+
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if o.Payment.ExternalAmount != 1000 || o.Payment.BalanceAmount != 0 {
+			t.Errorf("expected a fully external payment, got %+v", o.Payment)
+		}
+		if got := balance(t, buyerID); got != 500 {
+			t.Errorf("expected balance untouched, got %d", got)
+		}
+	})
+
+	t.Run("Fail_SplitDoesNotCoverAmount", func(t *testing.T) {
+		buyerID := setupBuyer(t, 500)
+
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+			Payment:   order.Payment{BalanceAmount: 300, ExternalAmount: 300},
+		})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Fatalf("expected field errors, got %v", err)
+		}
+	})
+
+	t.Run("Fail_InsufficientBalanceRollsBack", func(t *testing.T) {
+		buyerID := setupBuyer(t, 100)
+		productID := createProduct(t, 1000)
+
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: productID,
+			Payment:   order.Payment{BalanceAmount: 1000},
+		})
+		if !errors.Is(err, order.ErrInsufficientBalance) {
+			t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+		}
+		if got := balance(t, buyerID); got != 100 {
+			t.Errorf("expected balance untouched, got %d", got)
+		}
+		n, err := oStore.CountOrdersByBuyerAndProduct(ctx, buyerID, productID)
+		if err != nil {
+			t.Fatalf("CountOrdersByBuyerAndProduct failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected no order to be placed, got %d", n)
+		}
+	})
+
+	t.Run("Success_ConcurrentSpendsNeverGoNegative", func(t *testing.T) {
+		buyerID := setupBuyer(t, 1000)
+		productIDs := []string{createProduct(t, 600), createProduct(t, 600), createProduct(t, 600)}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(productIDs))
+		for i, productID := range productIDs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+					BuyerID:   buyerID,
+					ProductID: productID,
+					Payment:   order.Payment{BalanceAmount: 600},
+				})
+			}()
+		}
+		wg.Wait()
+
+		var placed int
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				placed++
+			case !errors.Is(err, order.ErrInsufficientBalance):
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if placed != 1 {
+			t.Errorf("expected exactly one order paid from balance, got %d", placed)
+		}
+		if got := balance(t, buyerID); got != 400 {
+			t.Errorf("expected 400 yen left, got %d", got)
+		}
+	})
+
+	t.Run("Success_CancelRefundsBalance", func(t *testing.T) {
+		buyerID := setupBuyer(t, 500)
+
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+			Payment:   order.Payment{BalanceAmount: 500, ExternalAmount: 500},
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if _, err := orderService.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+		if got := balance(t, buyerID); got != 500 {
+			t.Errorf("expected 500 yen refunded, got balance %d", got)
+		}
+
+		r, err := financeService.VerifyLedger(ctx)
+		if err != nil {
+			t.Fatalf("VerifyLedger failed: %v", err)
+		}
+		if !r.OK() {
+			t.Errorf("ledger out of balance: %+v", r)
+		}
+	})
+}
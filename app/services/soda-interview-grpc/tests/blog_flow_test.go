@@ -9,7 +9,9 @@ import (
 	"soda-interview/business/core/order"
 	"soda-interview/business/core/referral-blog"
 	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
 	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	productstore "soda-interview/business/data/stores/product"
 	blogstore "soda-interview/business/data/stores/referral-blog"
 	financestore "soda-interview/business/data/stores/soda-finance"
@@ -26,10 +28,12 @@ func Test_BlogToPurchaseFlow(t *testing.T) {
 	pStore := productstore.NewStore(c.Log, c.DB)
 	bStore := blogstore.NewStore(c.Log, c.DB)
 	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
 
 	// Setup Services
-	blogService := referralblog.NewService(c.Log, bStore)
-	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore)
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
 
 	ctx := context.Background()
 
@@ -84,6 +88,14 @@ func Test_BlogToPurchaseFlow(t *testing.T) {
 		if blog.LinkedProductID != product.ID {
 			t.Errorf("Expected linked product ID %s, got %s", product.ID, blog.LinkedProductID)
 		}
+		if blog.Status != blogstore.StatusPendingReview {
+			t.Errorf("Expected new blog to await review, got %s", blog.Status)
+		}
+
+		// A moderator publishes it so it can earn referral rewards.
+		if _, err := blogService.ModerateBlog(ctx, blog.ID, "moderator", referralblog.Moderation{Status: blogstore.StatusPublished}); err != nil {
+			t.Fatalf("ModerateBlog failed: %v", err)
+		}
 
 		// 3. Buyer places Order via Blog
 		// Expectation: Order is placed successfully, referencing the blog.
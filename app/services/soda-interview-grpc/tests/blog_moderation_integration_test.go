@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/referral-blog"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_BlogModeration(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Moderated Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	createBlog := func(t *testing.T, authorID, productID string, draft bool) referralblog.Blog {
+		b, err := blogService.CreateBlog(ctx, referralblog.NewBlog{
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Draft:     draft,
+		})
+		if err != nil {
+			t.Fatalf("CreateBlog failed: %v", err)
+		}
+		return b
+	}
+
+	moderate := func(t *testing.T, blogID, status string) referralblog.Blog {
+		b, err := blogService.ModerateBlog(ctx, blogID, "moderator", referralblog.Moderation{Status: status, Reason: "policy"})
+		if err != nil {
+			t.Fatalf("ModerateBlog(%s) failed: %v", status, err)
+		}
+		return b
+	}
+
+	placeOrder := func(productID, blogID string) error {
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: productID, BlogID: blogID})
+		return err
+	}
+
+	t.Run("Success_DraftSubmittedAndPublished", func(t *testing.T) {
+		authorID, productID := uuid.NewString(), createProduct(t)
+		b := createBlog(t, authorID, productID, true)
+		if b.Status != blogstore.StatusDraft {
+			t.Fatalf("expected DRAFT, got %s", b.Status)
+		}
+
+		content, submit := "Even better than I said", blogstore.StatusPendingReview
+		b, err := blogService.UpdateBlog(ctx, b.ID, authorID, referralblog.UpdateBlog{Content: &content, Status: &submit})
+		if err != nil {
+			t.Fatalf("UpdateBlog failed: %v", err)
+		}
+		if b.Status != blogstore.StatusPendingReview || b.Content != content {
+			t.Fatalf("expected the edit submitted for review, got %+v", b)
+		}
+
+		if err := placeOrder(productID, b.ID); !errors.Is(err, order.ErrBlogNotPublished) {
+			t.Fatalf("expected ErrBlogNotPublished before review, got %v", err)
+		}
+
+		moderate(t, b.ID, blogstore.StatusPublished)
+		if err := placeOrder(productID, b.ID); err != nil {
+			t.Fatalf("PlaceOrder via published blog failed: %v", err)
+		}
+
+		revs, err := blogService.ListRevisions(ctx, b.ID)
+		if err != nil {
+			t.Fatalf("ListRevisions failed: %v", err)
+		}
+		wantActions := []string{blogstore.ActionCreated, blogstore.ActionEdited, blogstore.ActionModerated}
+		if len(revs) != len(wantActions) {
+			t.Fatalf("expected %d revisions, got %+v", len(wantActions), revs)
+		}
+		for i, want := range wantActions {
+			if revs[i].Action != want {
+				t.Errorf("revision %d: expected %s, got %s", i, want, revs[i].Action)
+			}
+		}
+		if revs[0].Content != "Check this out!" || revs[1].Content != content || revs[2].ActorID != "moderator" {
+			t.Errorf("unexpected revision history: %+v", revs)
+		}
+	})
+
+	t.Run("Success_EditingPublishedBlogNeedsReview", func(t *testing.T) {
+		authorID, productID := uuid.NewString(), createProduct(t)
+		b := createBlog(t, authorID, productID, false)
+		moderate(t, b.ID, blogstore.StatusPublished)
+
+		content := "Updated thoughts"
+		b, err := blogService.UpdateBlog(ctx, b.ID, authorID, referralblog.UpdateBlog{Content: &content})
+		if err != nil {
+			t.Fatalf("UpdateBlog failed: %v", err)
+		}
+		if b.Status != blogstore.StatusPendingReview {
+			t.Errorf("expected PENDING_REVIEW after editing, got %s", b.Status)
+		}
+		if err := placeOrder(productID, b.ID); !errors.Is(err, order.ErrBlogNotPublished) {
+			t.Errorf("expected ErrBlogNotPublished, got %v", err)
+		}
+	})
+
+	t.Run("Success_TakenDownAndReinstated", func(t *testing.T) {
+		productID := createProduct(t)
+		b := createBlog(t, uuid.NewString(), productID, false)
+		moderate(t, b.ID, blogstore.StatusPublished)
+
+		moderate(t, b.ID, blogstore.StatusTakenDown)
+		if err := placeOrder(productID, b.ID); !errors.Is(err, order.ErrBlogNotPublished) {
+			t.Errorf("expected ErrBlogNotPublished for a taken down blog, got %v", err)
+		}
+		page, err := blogService.ListBlogs(ctx, referralblog.ListBlogsReq{})
+		if err != nil {
+			t.Fatalf("ListBlogs failed: %v", err)
+		}
+		for _, lb := range page.Blogs {
+			if lb.ID == b.ID {
+				t.Error("expected a taken down blog to be unlisted")
+			}
+		}
+
+		moderate(t, b.ID, blogstore.StatusPublished)
+		if err := placeOrder(productID, b.ID); err != nil {
+			t.Errorf("PlaceOrder after reinstating failed: %v", err)
+		}
+	})
+
+	t.Run("Fail_NotAuthor", func(t *testing.T) {
+		b := createBlog(t, uuid.NewString(), createProduct(t), true)
+
+		content := "Hijacked"
+		_, err := blogService.UpdateBlog(ctx, b.ID, uuid.NewString(), referralblog.UpdateBlog{Content: &content})
+		if !errors.Is(err, referralblog.ErrNotAuthor) {
+			t.Errorf("expected ErrNotAuthor for update, got %v", err)
+		}
+		if err := blogService.DeleteBlog(ctx, b.ID, uuid.NewString()); !errors.Is(err, referralblog.ErrNotAuthor) {
+			t.Errorf("expected ErrNotAuthor for delete, got %v", err)
+		}
+	})
+
+	t.Run("Fail_InvalidTransitions", func(t *testing.T) {
+		authorID := uuid.NewString()
+		b := createBlog(t, authorID, createProduct(t), true)
+
+		// Moderators only decide on blogs submitted for review.
+		_, err := blogService.ModerateBlog(ctx, b.ID, "moderator", referralblog.Moderation{Status: blogstore.StatusPublished})
+		if !errors.Is(err, referralblog.ErrInvalidTransition) {
+			t.Errorf("expected ErrInvalidTransition publishing a draft, got %v", err)
+		}
+
+		// Authors cannot publish their own blogs.
+		publish := blogstore.StatusPublished
+		_, err = blogService.UpdateBlog(ctx, b.ID, authorID, referralblog.UpdateBlog{Status: &publish})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Errorf("expected a field error for an author publishing, got %v", err)
+		}
+
+		// Rejections must say why.
+		submit := blogstore.StatusPendingReview
+		if _, err := blogService.UpdateBlog(ctx, b.ID, authorID, referralblog.UpdateBlog{Status: &submit}); err != nil {
+			t.Fatalf("UpdateBlog failed: %v", err)
+		}
+		_, err = blogService.ModerateBlog(ctx, b.ID, "moderator", referralblog.Moderation{Status: blogstore.StatusRejected})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Errorf("expected a field error for a rejection without reason, got %v", err)
+		}
+	})
+
+	t.Run("Success_DeletedBlogIsGone", func(t *testing.T) {
+		authorID, productID := uuid.NewString(), createProduct(t)
+		b := createBlog(t, authorID, productID, false)
+		moderate(t, b.ID, blogstore.StatusPublished)
+
+		if err := blogService.DeleteBlog(ctx, b.ID, authorID); err != nil {
+			t.Fatalf("DeleteBlog failed: %v", err)
+		}
+		if _, err := blogService.GetBlog(ctx, b.ID); !errors.Is(err, referralblog.ErrNotFound) {
+			t.Errorf("expected ErrNotFound after delete, got %v", err)
+		}
+		if err := placeOrder(productID, b.ID); !errors.Is(err, blogstore.ErrNotFound) {
+			t.Errorf("expected blog not found when ordering, got %v", err)
+		}
+		if err := blogService.DeleteBlog(ctx, b.ID, authorID); !errors.Is(err, referralblog.ErrNotFound) {
+			t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+		}
+	})
+}
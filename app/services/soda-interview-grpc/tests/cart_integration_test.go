@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/product"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_PlaceCartOrder(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	service := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	productService := product.NewService(c.Log, pStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T, price int64, buyerReward, authorReward int32) db.Product {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Cart Product",
+			Description:        "Desc",
+			Price:              price,
+			BuyerRewardPoints:  buyerReward,
+			AuthorRewardPoints: authorReward,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p
+	}
+
+	createBlog := func(t *testing.T, authorID, productID string) db.Blog {
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return b
+	}
+
+	getPoints := func(t *testing.T, userID string) int64 {
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		return w.SodaPoints
+	}
+
+	t.Run("Success_TotalsAndRewardsPerLine", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+		p1 := createProduct(t, 1000, 100, 50)
+		p2 := createProduct(t, 250, 20, 10)
+		blog := createBlog(t, authorID, p1.ID)
+
+		o, err := service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: buyerID,
+			Lines: []order.CartLine{
+				{ProductID: p1.ID, Quantity: 2, BlogID: blog.ID},
+				{ProductID: p2.ID, Quantity: 3},
+			},
+		})
+		if err != nil {
+			t.Fatalf("PlaceCartOrder failed: %v", err)
+		}
+
+		if o.Amount != 2*1000+3*250 {
+			t.Errorf("expected amount %d, got %d", 2*1000+3*250, o.Amount)
+		}
+		if o.ProductID != "" {
+			t.Errorf("expected no order-level product for a cart, got %s", o.ProductID)
+		}
+		if len(o.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(o.Items))
+		}
+		if it := o.Items[1]; it.LineNo != 2 || it.ProductID != p2.ID || it.UnitPrice != 250 || it.Amount != 750 {
+			t.Errorf("unexpected second line: %+v", it)
+		}
+
+		if got := getPoints(t, buyerID); got != 120 {
+			t.Errorf("expected buyer points 120, got %d", got)
+		}
+		if got := getPoints(t, authorID); got != 50 {
+			t.Errorf("expected author points 50, got %d", got)
+		}
+	})
+
+	t.Run("Success_FirstPurchaseIsPerProduct", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		bought := createProduct(t, 100, 100, 0)
+		fresh := createProduct(t, 100, 30, 0)
+
+		if _, err := service.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: bought.ID}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		if _, err := service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: buyerID,
+			Lines: []order.CartLine{
+				{ProductID: bought.ID, Quantity: 1},
+				{ProductID: fresh.ID, Quantity: 1},
+			},
+		}); err != nil {
+			t.Fatalf("PlaceCartOrder failed: %v", err)
+		}
+
+		if got := getPoints(t, buyerID); got != 130 {
+			t.Errorf("expected buyer points 130, got %d", got)
+		}
+	})
+
+	t.Run("Fail_OneBadLineRollsBackCart", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		ok := createProduct(t, 100, 10, 0)
+		scarce := createProduct(t, 100, 10, 0)
+		if _, err := productService.AdjustStock(ctx, scarce.ID, 1, "initial stock"); err != nil {
+			t.Fatalf("AdjustStock failed: %v", err)
+		}
+
+		_, err := service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: buyerID,
+			Lines: []order.CartLine{
+				{ProductID: ok.ID, Quantity: 1},
+				{ProductID: scarce.ID, Quantity: 2},
+			},
+		})
+		if !errors.Is(err, order.ErrOutOfStock) {
+			t.Fatalf("expected ErrOutOfStock, got %v", err)
+		}
+
+		if _, err := fStore.GetWallet(ctx, buyerID); !errors.Is(err, financestore.ErrNotFound) {
+			t.Errorf("expected no wallet after rollback, got %v", err)
+		}
+		if st, _ := productService.GetStock(ctx, scarce.ID); st.Reserved != 0 {
+			t.Errorf("expected nothing reserved after rollback, got %d", st.Reserved)
+		}
+	})
+
+	t.Run("Success_CancelReleasesEveryLine", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		p1 := createProduct(t, 100, 10, 0)
+		p2 := createProduct(t, 100, 10, 0)
+		for _, p := range []db.Product{p1, p2} {
+			if _, err := productService.AdjustStock(ctx, p.ID, 5, "initial stock"); err != nil {
+				t.Fatalf("AdjustStock failed: %v", err)
+			}
+		}
+
+		o, err := service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: buyerID,
+			Lines: []order.CartLine{
+				{ProductID: p1.ID, Quantity: 2},
+				{ProductID: p2.ID, Quantity: 3},
+			},
+		})
+		if err != nil {
+			t.Fatalf("PlaceCartOrder failed: %v", err)
+		}
+
+		if _, err := service.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		for _, p := range []db.Product{p1, p2} {
+			st, err := productService.GetStock(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("GetStock failed: %v", err)
+			}
+			if st.Reserved != 0 || st.OnHand != 5 {
+				t.Errorf("expected stock restored for %s, got %+v", p.ID, st)
+			}
+		}
+		if got := getPoints(t, buyerID); got != 0 {
+			t.Errorf("expected buyer points clawed back, got %d", got)
+		}
+	})
+
+	t.Run("Fail_Validation", func(t *testing.T) {
+		p := createProduct(t, 100, 0, 0)
+
+		_, err := service.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: uuid.NewString(),
+			Lines: []order.CartLine{
+				{ProductID: p.ID, Quantity: 1},
+				{ProductID: p.ID, Quantity: 0},
+			},
+		})
+		fe, ok := validate.AsFieldErrors(err)
+		if !ok {
+			t.Fatalf("expected FieldErrors, got %v", err)
+		}
+		if len(fe) != 2 || fe[0].Field != "lines[1].product_id" || fe[1].Field != "lines[1].quantity" {
+			t.Errorf("unexpected field errors: %v", fe)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_ConversionPolicy(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Service
+	service := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	setupWallet := func(t *testing.T, points int64) string {
+		userID := uuid.NewString()
+		if _, err := fStore.GetOrCreateWallet(ctx, userID); err != nil {
+			t.Fatalf("setupWallet: create failed: %v", err)
+		}
+		if _, err := fStore.Adjust(ctx, userID, points, 0); err != nil {
+			t.Fatalf("setupWallet: add points failed: %v", err)
+		}
+		return userID
+	}
+
+	schedule := func(t *testing.T, np finance.NewPolicy) finance.Policy {
+		np.CreatedBy = "test"
+		p, err := service.SchedulePolicy(ctx, np)
+		if err != nil {
+			t.Fatalf("SchedulePolicy failed: %v", err)
+		}
+		return p
+	}
+
+	lastConversion := func(t *testing.T, userID string) finance.Transaction {
+		page, err := service.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID: userID,
+			Types:  []string{financestore.TxConverted},
+		})
+		if err != nil {
+			t.Fatalf("ListTransactions failed: %v", err)
+		}
+		if len(page.Transactions) == 0 {
+			t.Fatal("expected a CONVERTED transaction")
+		}
+		return page.Transactions[0]
+	}
+
+	t.Run("Success_SeededPolicyMatchesLegacyRules", func(t *testing.T) {
+		p, err := service.ActivePolicy(ctx)
+		if err != nil {
+			t.Fatalf("ActivePolicy failed: %v", err)
+		}
+		if p.Version != 1 || p.PointsPerYen != 2 || p.MinBalance != 1001 {
+			t.Errorf("unexpected seeded policy: %+v", p)
+		}
+
+		userID := setupWallet(t, 2000)
+		if _, err := service.ConvertPoints(ctx, userID, 1000); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if tx := lastConversion(t, userID); tx.PolicyVersion != 1 {
+			t.Errorf("expected policy version 1, got %d", tx.PolicyVersion)
+		}
+	})
+
+	t.Run("Success_FuturePolicyNotYetApplied", func(t *testing.T) {
+		future := schedule(t, finance.NewPolicy{
+			PointsPerYen:  10,
+			MinPoints:     10,
+			EffectiveFrom: time.Now().Add(time.Hour).Unix(),
+		})
+
+		active, err := service.ActivePolicy(ctx)
+		if err != nil {
+			t.Fatalf("ActivePolicy failed: %v", err)
+		}
+		if active.Version == future.Version {
+			t.Fatalf("policy effective in an hour is already active")
+		}
+
+		policies, err := service.ListPolicies(ctx)
+		if err != nil {
+			t.Fatalf("ListPolicies failed: %v", err)
+		}
+		if len(policies) < 2 || policies[0].Version != future.Version {
+			t.Errorf("expected scheduled policy listed first, got %+v", policies)
+		}
+	})
+
+	t.Run("Success_NewRateRecordedOnTransaction", func(t *testing.T) {
+		p := schedule(t, finance.NewPolicy{PointsPerYen: 4, MinPoints: 4})
+		userID := setupWallet(t, 1000)
+
+		w, err := service.ConvertPoints(ctx, userID, 400)
+		if err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if w.SodaPoints != 600 || w.SodaBalance != 100 {
+			t.Errorf("expected 600 points and 100 yen, got %+v", w)
+		}
+		if tx := lastConversion(t, userID); tx.PolicyVersion != p.Version {
+			t.Errorf("expected policy version %d, got %d", p.Version, tx.PolicyVersion)
+		}
+	})
+
+	t.Run("Fail_AmountNotMultipleOfRate", func(t *testing.T) {
+		schedule(t, finance.NewPolicy{PointsPerYen: 4, MinPoints: 4})
+		userID := setupWallet(t, 1000)
+
+		_, err := service.ConvertPoints(ctx, userID, 402)
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Fatalf("expected field errors, got %v", err)
+		}
+	})
+
+	t.Run("Success_ConvertAllHonoursMaxPoints", func(t *testing.T) {
+		schedule(t, finance.NewPolicy{PointsPerYen: 2, MinPoints: 2, MaxPoints: 300})
+		userID := setupWallet(t, 1000)
+
+		if _, err := service.ConvertPoints(ctx, userID, 400); err == nil {
+			t.Fatal("expected error above max_points, got nil")
+		}
+
+		w, err := service.ConvertPoints(ctx, userID, 0)
+		if err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if w.SodaPoints != 700 || w.SodaBalance != 150 {
+			t.Errorf("expected 700 points and 150 yen, got %+v", w)
+		}
+	})
+
+	t.Run("Fail_DailyCapExceeded", func(t *testing.T) {
+		schedule(t, finance.NewPolicy{PointsPerYen: 2, MinPoints: 2, DailyCapPoints: 500})
+		userID := setupWallet(t, 1000)
+
+		if _, err := service.ConvertPoints(ctx, userID, 400); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		_, err := service.ConvertPoints(ctx, userID, 200)
+		if !errors.Is(err, finance.ErrDailyCapExceeded) {
+			t.Fatalf("expected ErrDailyCapExceeded, got %v", err)
+		}
+
+		// Converting everything takes only what is left of the cap.
+		w, err := service.ConvertPoints(ctx, userID, 0)
+		if err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if w.SodaPoints != 500 || w.SodaBalance != 250 {
+			t.Errorf("expected 500 points and 250 yen, got %+v", w)
+		}
+	})
+
+	t.Run("Fail_EffectiveFromInPast", func(t *testing.T) {
+		_, err := service.SchedulePolicy(ctx, finance.NewPolicy{
+			PointsPerYen:  2,
+			MinPoints:     2,
+			EffectiveFrom: time.Now().Add(-time.Hour).Unix(),
+			CreatedBy:     "test",
+		})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Fatalf("expected field errors, got %v", err)
+		}
+	})
+}
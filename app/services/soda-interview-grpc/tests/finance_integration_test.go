@@ -8,6 +8,8 @@ import (
 
 	"soda-interview/business/core/finance"
 	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	financestore "soda-interview/business/data/stores/soda-finance"
 	tt "soda-interview/zarf/testing"
 )
@@ -19,9 +21,11 @@ func Test_ConvertPoints(t *testing.T) {
 
 	// Setup Stores
 	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
 
 	// Setup Service
-	service := finance.NewService(c.Log, c.DB, fStore)
+	service := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
 	ctx := context.Background()
 
 	// Helpers
@@ -32,10 +36,7 @@ func Test_ConvertPoints(t *testing.T) {
 			t.Fatalf("setupWallet: create failed: %v", err)
 		}
 		if points > 0 {
-			_, err = fStore.AddPoints(ctx, db.AddPointsParams{
-				Amount: points,
-				UserID: userID,
-			})
+			_, err = fStore.Adjust(ctx, userID, points, 0)
 			if err != nil {
 				t.Fatalf("setupWallet: add points failed: %v", err)
 			}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/app/services/soda-interview-grpc/internal/transport/grpc/errs"
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/product"
+	sodafinance "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+)
+
+func Test_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"ProductNotFound", fmt.Errorf("getting product: %w", product.ErrNotFound), codes.NotFound, "PRODUCT_NOT_FOUND"},
+		{"WalletNotFound_Store", fmt.Errorf("getting wallet: %w", sodafinance.ErrNotFound), codes.NotFound, "WALLET_NOT_FOUND"},
+		{"InsufficientPoints", fmt.Errorf("%w: have 10", finance.ErrInsufficientPoints), codes.FailedPrecondition, "INSUFFICIENT_POINTS"},
+		{"Validation", validate.FieldErrors{{Field: "user_id", Message: "is required"}}, codes.InvalidArgument, "INVALID_ARGUMENT"},
+		{"ForeignKey", fmt.Errorf("creating blog: %w", &pgconn.PgError{Code: "23503", Message: "insert or update on table \"blogs\""}), codes.FailedPrecondition, "REFERENCE_NOT_FOUND"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st, ok := status.FromError(errs.ToStatus(tc.err))
+			if !ok {
+				t.Fatalf("expected a status error, got %v", tc.err)
+			}
+			if st.Code() != tc.code {
+				t.Errorf("expected code %s, got %s", tc.code, st.Code())
+			}
+
+			var reason string
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tc.reason {
+				t.Errorf("expected reason %s, got %q", tc.reason, reason)
+			}
+		})
+	}
+
+	t.Run("Validation_FieldViolations", func(t *testing.T) {
+		st, _ := status.FromError(errs.ToStatus(validate.FieldErrors{
+			{Field: "buyer_id", Message: "is required"},
+			{Field: "product_id", Message: "is required"},
+		}))
+
+		var violations int
+		for _, d := range st.Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				violations = len(br.FieldViolations)
+			}
+		}
+		if violations != 2 {
+			t.Errorf("expected 2 field violations, got %d", violations)
+		}
+	})
+
+	t.Run("SQLNotLeaked", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint \"orders_pkey\""}
+		st, _ := status.FromError(errs.ToStatus(pgErr))
+		if st.Code() != codes.AlreadyExists {
+			t.Errorf("expected AlreadyExists, got %s", st.Code())
+		}
+		if st.Message() == pgErr.Error() {
+			t.Errorf("expected SQL text to be hidden, got %q", st.Message())
+		}
+	})
+
+	t.Run("Unknown_PassedThrough", func(t *testing.T) {
+		err := errors.New("connection reset")
+		if got := errs.ToStatus(err); got != err {
+			t.Errorf("expected unknown error to be returned unchanged, got %v", got)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		st, _ := status.FromError(errs.ToStatus(fmt.Errorf("querying: %w", context.Canceled)))
+		if st.Code() != codes.Canceled {
+			t.Errorf("expected Canceled, got %s", st.Code())
+		}
+	})
+}
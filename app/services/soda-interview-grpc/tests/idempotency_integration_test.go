@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_Idempotency(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createReferral := func(t *testing.T, authorID string) (productID, blogID string) {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Idempotent Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: p.ID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return p.ID, b.ID
+	}
+
+	getWallet := func(t *testing.T, userID string) db.Wallet {
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("PlaceOrder_Replay_ReturnsSameOrder", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+		productID, blogID := createReferral(t, authorID)
+
+		req := order.PlaceOrderReq{
+			BuyerID:        buyerID,
+			ProductID:      productID,
+			BlogID:         blogID,
+			IdempotencyKey: uuid.NewString(),
+		}
+
+		first, err := orderService.PlaceOrder(ctx, req)
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		second, err := orderService.PlaceOrder(ctx, req)
+		if err != nil {
+			t.Fatalf("PlaceOrder replay failed: %v", err)
+		}
+
+		if first.ID != second.ID {
+			t.Errorf("expected replay to return order %s, got %s", first.ID, second.ID)
+		}
+		if w := getWallet(t, buyerID); w.SodaPoints != 100 {
+			t.Errorf("expected buyer points 100, got %d", w.SodaPoints)
+		}
+		if w := getWallet(t, authorID); w.SodaPoints != 50 {
+			t.Errorf("expected author points 50, got %d", w.SodaPoints)
+		}
+	})
+
+	t.Run("PlaceOrder_KeyReused_DifferentRequest", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		productID, blogID := createReferral(t, uuid.NewString())
+		otherProductID, _ := createReferral(t, uuid.NewString())
+		key := uuid.NewString()
+
+		if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:        buyerID,
+			ProductID:      productID,
+			BlogID:         blogID,
+			IdempotencyKey: key,
+		}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:        buyerID,
+			ProductID:      otherProductID,
+			IdempotencyKey: key,
+		})
+		if !errors.Is(err, idempotencystore.ErrKeyReused) {
+			t.Fatalf("expected ErrKeyReused, got %v", err)
+		}
+	})
+
+	t.Run("ConvertPoints_Replay_ConvertsOnce", func(t *testing.T) {
+		userID := uuid.NewString()
+		if _, err := fStore.GetOrCreateWallet(ctx, userID); err != nil {
+			t.Fatalf("create wallet failed: %v", err)
+		}
+		if _, err := fStore.Adjust(ctx, userID, 3000, 0); err != nil {
+			t.Fatalf("add points failed: %v", err)
+		}
+
+		key := uuid.NewString()
+		first, err := financeService.ConvertPointsIdempotent(ctx, userID, 1200, key)
+		if err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		second, err := financeService.ConvertPointsIdempotent(ctx, userID, 1200, key)
+		if err != nil {
+			t.Fatalf("ConvertPoints replay failed: %v", err)
+		}
+
+		if first != second {
+			t.Errorf("expected replay to return %+v, got %+v", first, second)
+		}
+		w := getWallet(t, userID)
+		if w.SodaPoints != 1800 || w.SodaBalance != 600 {
+			t.Errorf("expected 1800 points and 600 yen, got %d points and %d yen", w.SodaPoints, w.SodaBalance)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/product"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_Inventory(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	productService := product.NewService(c.Log, pStore)
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	stockedProduct := func(t *testing.T, units int32) db.Product {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Stocked Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  10,
+			AuthorRewardPoints: 5,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		if _, err := productService.AdjustStock(ctx, p.ID, units, "initial stock"); err != nil {
+			t.Fatalf("AdjustStock failed: %v", err)
+		}
+		return p
+	}
+
+	getStock := func(t *testing.T, productID string) product.Stock {
+		st, err := productService.GetStock(ctx, productID)
+		if err != nil {
+			t.Fatalf("GetStock failed: %v", err)
+		}
+		return st
+	}
+
+	t.Run("Success_ReserveShipCancel", func(t *testing.T) {
+		p := stockedProduct(t, 3)
+
+		shipped, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		cancelled, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		if st := getStock(t, p.ID); st.OnHand != 3 || st.Reserved != 2 {
+			t.Fatalf("expected 3 on hand and 2 reserved, got %+v", st)
+		}
+
+		if _, err := orderService.UpdateStatus(ctx, shipped.ID, order.StatusShipped); err != nil {
+			t.Fatalf("UpdateStatus SHIPPED failed: %v", err)
+		}
+		if _, err := orderService.CancelOrder(ctx, cancelled.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		st := getStock(t, p.ID)
+		if st.OnHand != 2 || st.Reserved != 0 || st.Available() != 2 {
+			t.Fatalf("expected 2 on hand and none reserved, got %+v", st)
+		}
+
+		moves, err := pStore.ListInventoryMovements(ctx, p.ID)
+		if err != nil {
+			t.Fatalf("ListInventoryMovements failed: %v", err)
+		}
+		var kinds []string
+		for _, m := range moves {
+			kinds = append(kinds, m.Kind)
+		}
+		want := []string{"ADJUST", "RESERVE", "RESERVE", "SHIP", "RELEASE"}
+		if len(kinds) != len(want) {
+			t.Fatalf("expected movements %v, got %v", want, kinds)
+		}
+		for i := range want {
+			if kinds[i] != want[i] {
+				t.Fatalf("expected movements %v, got %v", want, kinds)
+			}
+		}
+	})
+
+	t.Run("Fail_OutOfStock", func(t *testing.T) {
+		p := stockedProduct(t, 1)
+
+		if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID})
+		if !errors.Is(err, order.ErrOutOfStock) {
+			t.Fatalf("expected ErrOutOfStock, got %v", err)
+		}
+	})
+
+	t.Run("Concurrent_LastUnit", func(t *testing.T) {
+		p := stockedProduct(t, 1)
+
+		const buyers = 10
+		var wg sync.WaitGroup
+		errs := make([]error, buyers)
+		for i := 0; i < buyers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+					BuyerID:   uuid.NewString(),
+					ProductID: p.ID,
+				})
+			}(i)
+		}
+		wg.Wait()
+
+		var placed, outOfStock int
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				placed++
+			case errors.Is(err, order.ErrOutOfStock):
+				outOfStock++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if placed != 1 || outOfStock != buyers-1 {
+			t.Fatalf("expected 1 order and %d out of stock, got %d and %d", buyers-1, placed, outOfStock)
+		}
+
+		if st := getStock(t, p.ID); st.Reserved != 1 || st.Available() != 0 {
+			t.Fatalf("expected the last unit reserved, got %+v", st)
+		}
+	})
+
+	t.Run("Fail_AdjustBelowReserved", func(t *testing.T) {
+		p := stockedProduct(t, 2)
+
+		if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		_, err := productService.AdjustStock(ctx, p.ID, -2, "damaged")
+		if !errors.Is(err, product.ErrBelowReserved) {
+			t.Fatalf("expected ErrBelowReserved, got %v", err)
+		}
+	})
+
+	t.Run("Fail_NegativeFirstAdjustLeavesUntracked", func(t *testing.T) {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:    uuid.NewString(),
+			Name:  "Untracked",
+			Price: 100,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+
+		_, err = productService.AdjustStock(ctx, p.ID, -5, "miscount")
+		if !errors.Is(err, product.ErrBelowReserved) {
+			t.Fatalf("expected ErrBelowReserved, got %v", err)
+		}
+		if st := getStock(t, p.ID); st.Tracked {
+			t.Errorf("expected product to stay untracked, got %+v", st)
+		}
+		if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID}); err != nil {
+			t.Errorf("PlaceOrder failed: %v", err)
+		}
+	})
+
+	t.Run("Success_UntrackedProductUnlimited", func(t *testing.T) {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:    uuid.NewString(),
+			Name:  "Untracked",
+			Price: 100,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: p.ID}); err != nil {
+				t.Fatalf("PlaceOrder failed: %v", err)
+			}
+		}
+		if st := getStock(t, p.ID); st.Tracked {
+			t.Errorf("expected product to stay untracked, got %+v", st)
+		}
+	})
+}
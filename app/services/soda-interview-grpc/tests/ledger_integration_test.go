@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_Ledger(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	verify := func(t *testing.T) finance.LedgerReport {
+		r, err := financeService.VerifyLedger(ctx)
+		if err != nil {
+			t.Fatalf("VerifyLedger failed: %v", err)
+		}
+		return r
+	}
+
+	t.Run("Success_FullFlowBalances", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Ledger Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  1500,
+			AuthorRewardPoints: 300,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: p.ID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: p.ID, BlogID: b.ID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if _, err := financeService.ConvertPoints(ctx, buyerID, 0); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if _, err := orderService.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		if r := verify(t); !r.OK() {
+			t.Fatalf("expected a clean ledger, got %+v", r)
+		}
+
+		txs, err := fStore.ListTransactionsByOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("ListTransactionsByOrder failed: %v", err)
+		}
+		for _, tx := range txs {
+			entries, err := fStore.ListLedgerEntries(ctx, tx.ID)
+			if err != nil {
+				t.Fatalf("ListLedgerEntries failed: %v", err)
+			}
+			if len(entries) == 0 {
+				t.Errorf("expected entries for %s transaction %s", tx.Type, tx.ID)
+			}
+		}
+	})
+
+	t.Run("Fail_UnbalancedPosting", func(t *testing.T) {
+		userID := uuid.NewString()
+		_, err := fStore.Post(ctx, financestore.Posting{
+			UserID: userID,
+			Type:   financestore.TxAdjustment,
+			Amount: 100,
+			Entries: []financestore.Entry{
+				{Account: financestore.AdjustmentsPoints, Direction: financestore.Debit, Amount: 100},
+				{Account: financestore.UserPoints(userID), Direction: financestore.Credit, Amount: 90},
+			},
+		})
+		if !errors.Is(err, financestore.ErrUnbalanced) {
+			t.Fatalf("expected ErrUnbalanced, got %v", err)
+		}
+		if _, err := fStore.GetWallet(ctx, userID); !errors.Is(err, financestore.ErrNotFound) {
+			t.Errorf("expected no wallet after a rejected posting, got %v", err)
+		}
+	})
+
+	t.Run("Success_RebuildFixesDrift", func(t *testing.T) {
+		userID := uuid.NewString()
+		if _, err := fStore.Adjust(ctx, userID, 700, 30); err != nil {
+			t.Fatalf("Adjust failed: %v", err)
+		}
+
+		// Corrupt the projection behind the ledger's back.
+		if _, err := c.DB.Exec(ctx, "UPDATE wallets SET soda_points = 1 WHERE user_id = $1", userID); err != nil {
+			t.Fatalf("corrupting wallet failed: %v", err)
+		}
+
+		r := verify(t)
+		if len(r.Drift) != 1 || r.Drift[0].UserID != userID || r.Drift[0].LedgerPoints != 700 {
+			t.Fatalf("expected drift on %s, got %+v", userID, r.Drift)
+		}
+
+		if _, err := financeService.RebuildWallets(ctx); err != nil {
+			t.Fatalf("RebuildWallets failed: %v", err)
+		}
+		if r := verify(t); !r.OK() {
+			t.Fatalf("expected a clean ledger after rebuild, got %+v", r)
+		}
+
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		if w.SodaPoints != 700 || w.SodaBalance != 30 {
+			t.Errorf("expected 700 points and 30 yen, got %d and %d", w.SodaPoints, w.SodaBalance)
+		}
+	})
+
+	t.Run("Success_RepairDriftRecordsAdjustment", func(t *testing.T) {
+		userID := uuid.NewString()
+		if _, err := fStore.Adjust(ctx, userID, 500, 0); err != nil {
+			t.Fatalf("Adjust failed: %v", err)
+		}
+
+		// A movement that bypassed the ledger.
+		if _, err := c.DB.Exec(ctx, "UPDATE wallets SET soda_points = 450, soda_balance = 20 WHERE user_id = $1", userID); err != nil {
+			t.Fatalf("corrupting wallet failed: %v", err)
+		}
+
+		repaired, err := financeService.RepairDrift(ctx)
+		if err != nil {
+			t.Fatalf("RepairDrift failed: %v", err)
+		}
+		if len(repaired) != 1 || repaired[0].UserID != userID {
+			t.Fatalf("expected %s to be repaired, got %+v", userID, repaired)
+		}
+		if r := verify(t); !r.OK() {
+			t.Fatalf("expected a clean ledger after repair, got %+v", r)
+		}
+
+		// The wallet keeps the totals users already saw.
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		if w.SodaPoints != 450 || w.SodaBalance != 20 {
+			t.Errorf("expected 450 points and 20 yen, got %d and %d", w.SodaPoints, w.SodaBalance)
+		}
+
+		page, err := financeService.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID: userID,
+			Types:  []string{financestore.TxAdjustment},
+		})
+		if err != nil {
+			t.Fatalf("ListTransactions failed: %v", err)
+		}
+		if len(page.Transactions) != 2 {
+			t.Errorf("expected 2 ADJUSTMENT transactions, got %d", len(page.Transactions))
+		}
+	})
+}
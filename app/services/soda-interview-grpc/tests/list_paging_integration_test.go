@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/product"
+	"soda-interview/business/core/referral-blog"
+	"soda-interview/business/data/stores/db"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_ListPaging(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	productService := product.NewService(c.Log, pStore)
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	ctx := context.Background()
+
+	// Five products, created a minute apart, oldest first: id i costs
+	// prices[i]. Two share a price so ties fall back to id.
+	base := time.Now().Add(-time.Hour)
+	names := []string{"Cola", "Lemon Soda", "Cream Soda", "Tonic", "100% Soda"}
+	prices := []int64{300, 150, 150, 500, 200}
+	var products []string
+	for i, name := range names {
+		p, err := productService.Create(ctx, product.NewProduct{Name: name, Price: prices[i]})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if _, err := c.DB.Exec(ctx, "UPDATE products SET created_at = $2 WHERE id = $1",
+			p.ID, base.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("backdating product failed: %v", err)
+		}
+		products = append(products, p.ID)
+	}
+
+	// listProducts follows next_page_token to the end and returns the ids in
+	// the order they were listed.
+	listProducts := func(t *testing.T, req product.ListProductsReq) []string {
+		var ids []string
+		for {
+			page, err := productService.ListProducts(ctx, req)
+			if err != nil {
+				t.Fatalf("ListProducts failed: %v", err)
+			}
+			if limit := cmp.Or(req.PageSize, product.DefaultPageSize); len(page.Products) > limit {
+				t.Fatalf("expected at most %d products per page, got %d", limit, len(page.Products))
+			}
+			for _, p := range page.Products {
+				ids = append(ids, p.ID)
+			}
+			if page.NextPageToken == "" {
+				return ids
+			}
+			req.PageToken = page.NextPageToken
+		}
+	}
+
+	t.Run("Success_ProductsNewestFirst", func(t *testing.T) {
+		got := listProducts(t, product.ListProductsReq{PageSize: 2})
+		want := slices.Clone(products)
+		slices.Reverse(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("expected %v newest first, got %v", want, got)
+		}
+
+		got = listProducts(t, product.ListProductsReq{OrderBy: product.OrderByCreatedAt, PageSize: 2})
+		if !slices.Equal(got, products) {
+			t.Errorf("expected %v oldest first, got %v", products, got)
+		}
+	})
+
+	t.Run("Success_ProductsByPrice", func(t *testing.T) {
+		cheap := []string{products[1], products[2]}
+		slices.Sort(cheap)
+		want := append(cheap, products[4], products[0], products[3])
+
+		got := listProducts(t, product.ListProductsReq{OrderBy: product.OrderByPrice, PageSize: 2})
+		if !slices.Equal(got, want) {
+			t.Errorf("expected %v by price, got %v", want, got)
+		}
+
+		got = listProducts(t, product.ListProductsReq{OrderBy: product.OrderByPriceDesc, PageSize: 1})
+		slices.Reverse(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("expected %v by price descending, got %v", want, got)
+		}
+	})
+
+	t.Run("Success_ProductFilters", func(t *testing.T) {
+		got := listProducts(t, product.ListProductsReq{MinPrice: 150, MaxPrice: 300, OrderBy: product.OrderByCreatedAt})
+		if want := []string{products[0], products[1], products[2], products[4]}; !slices.Equal(got, want) {
+			t.Errorf("expected %v priced 150 to 300, got %v", want, got)
+		}
+
+		got = listProducts(t, product.ListProductsReq{NameContains: "soda", OrderBy: product.OrderByCreatedAt})
+		if want := []string{products[1], products[2], products[4]}; !slices.Equal(got, want) {
+			t.Errorf("expected %v named like soda, got %v", want, got)
+		}
+
+		// Wildcards in the filter match literally.
+		got = listProducts(t, product.ListProductsReq{NameContains: "0%"})
+		if want := []string{products[4]}; !slices.Equal(got, want) {
+			t.Errorf("expected only %v for 0%%, got %v", want, got)
+		}
+
+		if _, err := productService.Archive(ctx, products[3]); err != nil {
+			t.Fatalf("Archive failed: %v", err)
+		}
+		got = listProducts(t, product.ListProductsReq{MinPrice: 400})
+		if len(got) != 0 {
+			t.Errorf("expected archived products to be omitted, got %v", got)
+		}
+	})
+
+	t.Run("Fail_InvalidProductRequests", func(t *testing.T) {
+		page, err := productService.ListProducts(ctx, product.ListProductsReq{PageSize: 1})
+		if err != nil {
+			t.Fatalf("ListProducts failed: %v", err)
+		}
+
+		tests := map[string]product.ListProductsReq{
+			"page size over max":       {PageSize: product.MaxPageSize + 1},
+			"unknown order":            {OrderBy: "name"},
+			"max price below min":      {MinPrice: 300, MaxPrice: 200},
+			"garbage token":            {PageToken: "!!!"},
+			"token from another order": {OrderBy: product.OrderByPrice, PageToken: page.NextPageToken},
+		}
+		for name, req := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := productService.ListProducts(ctx, req)
+				if _, ok := validate.AsFieldErrors(err); !ok {
+					t.Errorf("expected FieldErrors, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Success_Blogs", func(t *testing.T) {
+		author, other := uuid.NewString(), uuid.NewString()
+		createBlog := func(t *testing.T, authorID, productID, status string) string {
+			b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+				ID:        uuid.NewString(),
+				AuthorID:  authorID,
+				Content:   "Check this out!",
+				ProductID: productID,
+				Status:    status,
+			})
+			if err != nil {
+				t.Fatalf("createBlog failed: %v", err)
+			}
+			return b.ID
+		}
+		first := createBlog(t, author, products[0], blogstore.StatusPublished)
+		second := createBlog(t, author, products[1], blogstore.StatusPublished)
+		third := createBlog(t, author, products[0], blogstore.StatusPublished)
+		createBlog(t, author, products[0], blogstore.StatusDraft)
+		createBlog(t, other, products[0], blogstore.StatusPublished)
+		for i, id := range []string{first, second, third} {
+			if _, err := c.DB.Exec(ctx, "UPDATE blogs SET created_at = $2 WHERE id = $1",
+				id, base.Add(time.Duration(i)*time.Minute)); err != nil {
+				t.Fatalf("backdating blog failed: %v", err)
+			}
+		}
+
+		listBlogs := func(t *testing.T, req referralblog.ListBlogsReq) []string {
+			var ids []string
+			for {
+				page, err := blogService.ListBlogs(ctx, req)
+				if err != nil {
+					t.Fatalf("ListBlogs failed: %v", err)
+				}
+				for _, b := range page.Blogs {
+					ids = append(ids, b.ID)
+				}
+				if page.NextPageToken == "" {
+					return ids
+				}
+				req.PageToken = page.NextPageToken
+			}
+		}
+
+		if got, want := listBlogs(t, referralblog.ListBlogsReq{AuthorID: author, PageSize: 1}), []string{third, second, first}; !slices.Equal(got, want) {
+			t.Errorf("expected the author's published blogs %v newest first, got %v", want, got)
+		}
+		got := listBlogs(t, referralblog.ListBlogsReq{AuthorID: author, ProductID: products[0], OrderBy: referralblog.OrderByCreatedAt, PageSize: 1})
+		if want := []string{first, third}; !slices.Equal(got, want) {
+			t.Errorf("expected %v for the author and product oldest first, got %v", want, got)
+		}
+		if got := listBlogs(t, referralblog.ListBlogsReq{ProductID: products[0]}); len(got) != 3 {
+			t.Errorf("expected 3 published blogs for the product, got %v", got)
+		}
+
+		_, err := blogService.ListBlogs(ctx, referralblog.ListBlogsReq{OrderBy: "author_id"})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Errorf("expected FieldErrors for an unknown order, got %v", err)
+		}
+	})
+}
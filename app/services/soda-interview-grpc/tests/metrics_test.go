@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+
+	"soda-interview/foundation/metrics"
+)
+
+func Test_MetricsExposition(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	requests := r.NewCounter("test_requests_total", "Requests handled.", "method", "code")
+	requests.Inc("/svc/Get", "OK")
+	requests.Inc("/svc/Get", "OK")
+	requests.Inc("/svc/Get", `Not"Found`)
+
+	latency := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1}, "method")
+	latency.Observe(0.05, "/svc/Get")
+	latency.Observe(0.5, "/svc/Get")
+	latency.Observe(5, "/svc/Get")
+
+	r.NewGaugeFunc("test_idle_conns", "Idle connections.", func() float64 { return 3 })
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(rec.Body)
+	if err != nil {
+		t.Fatalf("parsing exposition: %v", err)
+	}
+
+	labelsOf := func(m *dto.Metric) map[string]string {
+		out := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			out[l.GetName()] = l.GetValue()
+		}
+		return out
+	}
+
+	reqs := families["test_requests_total"]
+	if reqs.GetType() != dto.MetricType_COUNTER || reqs.GetHelp() != "Requests handled." {
+		t.Fatalf("unexpected requests family: %v", reqs)
+	}
+	counts := make(map[string]float64)
+	for _, m := range reqs.GetMetric() {
+		l := labelsOf(m)
+		if l["method"] != "/svc/Get" {
+			t.Errorf("unexpected method label %q", l["method"])
+		}
+		counts[l["code"]] = m.GetCounter().GetValue()
+	}
+	if counts["OK"] != 2 || counts[`Not"Found`] != 1 || len(counts) != 2 {
+		t.Errorf("unexpected request counts %v", counts)
+	}
+
+	lat := families["test_latency_seconds"]
+	if lat.GetType() != dto.MetricType_HISTOGRAM || len(lat.GetMetric()) != 1 {
+		t.Fatalf("unexpected latency family: %v", lat)
+	}
+	h := lat.GetMetric()[0].GetHistogram()
+	if h.GetSampleCount() != 3 || h.GetSampleSum() != 5.55 {
+		t.Errorf("expected 3 observations summing to 5.55, got %d and %v", h.GetSampleCount(), h.GetSampleSum())
+	}
+	buckets := make(map[float64]uint64)
+	for _, b := range h.GetBucket() {
+		buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+	}
+	if buckets[0.1] != 1 || buckets[1] != 2 {
+		t.Errorf("unexpected buckets %v", buckets)
+	}
+
+	idle := families["test_idle_conns"]
+	if idle.GetType() != dto.MetricType_GAUGE || len(idle.GetMetric()) != 1 || idle.GetMetric()[0].GetGauge().GetValue() != 3 {
+		t.Errorf("unexpected idle conns family: %v", idle)
+	}
+}
+
+func Test_MetricsDuplicateRegistrationPanics(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewCounter("test_dup_total", "Dup.")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected registering a name twice to panic")
+		}
+	}()
+	r.NewCounter("test_dup_total", "Dup.")
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,7 +10,9 @@ import (
 
 	"soda-interview/business/core/order"
 	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
 	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	productstore "soda-interview/business/data/stores/product"
 	blogstore "soda-interview/business/data/stores/referral-blog"
 	financestore "soda-interview/business/data/stores/soda-finance"
@@ -26,9 +29,11 @@ func Test_PlaceOrder(t *testing.T) {
 	pStore := productstore.NewStore(c.Log, c.DB)
 	bStore := blogstore.NewStore(c.Log, c.DB)
 	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
 
 	// Setup Service
-	service := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore)
+	service := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
 	ctx := context.Background()
 
 	// Helpers
@@ -55,6 +60,7 @@ func Test_PlaceOrder(t *testing.T) {
 			AuthorID:  authorID,
 			Content:   "Check this out!",
 			ProductID: productID,
+			Status:    blogstore.StatusPublished,
 		})
 		if err != nil {
 			t.Fatalf("createBlog failed: %v", err)
@@ -195,4 +201,93 @@ func Test_PlaceOrder(t *testing.T) {
 			t.Errorf("expected Buyer Points 300 (100+200), got %d. NOTE: If this is 100, the 'First Purchase' logic is flawed.", buyerWallet.SodaPoints)
 		}
 	})
+
+	t.Run("Fail_BlogPromotesDifferentProduct", func(t *testing.T) {
+		c.Truncate(t)
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+
+		prod1 := createProduct(t, 1000, 100, 50)
+		prod2 := createProduct(t, 2000, 200, 50)
+		blog := createBlog(t, authorID, prod1.ID)
+
+		_, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: prod2.ID,
+			BlogID:    blog.ID,
+		})
+		if !errors.Is(err, order.ErrReferralProductMismatch) {
+			t.Fatalf("expected ErrReferralProductMismatch, got %v", err)
+		}
+
+		if _, err := fStore.GetWallet(ctx, authorID); err == nil {
+			t.Error("expected no author wallet to be created for a rejected order")
+		}
+	})
+
+	t.Run("Fail_SelfReferral", func(t *testing.T) {
+		c.Truncate(t)
+		authorID := uuid.NewString()
+
+		prod := createProduct(t, 1000, 100, 50)
+		blog := createBlog(t, authorID, prod.ID)
+
+		_, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   authorID,
+			ProductID: prod.ID,
+			BlogID:    blog.ID,
+		})
+		if !errors.Is(err, order.ErrSelfReferral) {
+			t.Fatalf("expected ErrSelfReferral, got %v", err)
+		}
+	})
+
+	t.Run("Fail_ProductArchived", func(t *testing.T) {
+		c.Truncate(t)
+		prod := createProduct(t, 1000, 100, 50)
+
+		if _, err := pStore.ArchiveProduct(ctx, prod.ID); err != nil {
+			t.Fatalf("ArchiveProduct failed: %v", err)
+		}
+
+		_, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   uuid.NewString(),
+			ProductID: prod.ID,
+		})
+		if !errors.Is(err, order.ErrProductArchived) {
+			t.Fatalf("expected ErrProductArchived, got %v", err)
+		}
+	})
+
+	t.Run("Success_NoBlog_NoAuthorReward", func(t *testing.T) {
+		c.Truncate(t)
+		buyerID := uuid.NewString()
+
+		prod := createProduct(t, 1000, 100, 50)
+
+		ord, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: prod.ID,
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder without blog failed: %v", err)
+		}
+		if ord.BlogID != "" {
+			t.Errorf("expected empty BlogID, got %s", ord.BlogID)
+		}
+
+		// Buyer still earns the first purchase reward.
+		buyerWallet := getWallet(t, buyerID)
+		if buyerWallet.SodaPoints != 100 {
+			t.Errorf("expected Buyer Points 100, got %d", buyerWallet.SodaPoints)
+		}
+
+		txs, err := fStore.ListTransactionsByOrder(ctx, ord.ID)
+		if err != nil {
+			t.Fatalf("ListTransactionsByOrder failed: %v", err)
+		}
+		if len(txs) != 1 {
+			t.Errorf("expected only the buyer reward transaction, got %d", len(txs))
+		}
+	})
 }
@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_OrderLifecycle(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	placeOrder := func(t *testing.T, buyerID, authorID string, buyerReward, authorReward int64) order.Order {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Lifecycle Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  int32(buyerReward),
+			AuthorRewardPoints: int32(authorReward),
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: p.ID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: p.ID,
+			BlogID:    b.ID,
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		return o
+	}
+
+	getWallet := func(t *testing.T, userID string) db.Wallet {
+		w, err := fStore.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("Cancel_ReversesRewards", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+		o := placeOrder(t, buyerID, authorID, 100, 50)
+
+		cancelled, err := orderService.CancelOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+		if cancelled.Status != order.StatusCancelled {
+			t.Errorf("expected status CANCELLED, got %s", cancelled.Status)
+		}
+
+		if w := getWallet(t, buyerID); w.SodaPoints != 0 {
+			t.Errorf("expected buyer points 0 after cancel, got %d", w.SodaPoints)
+		}
+		if w := getWallet(t, authorID); w.SodaPoints != 0 {
+			t.Errorf("expected author points 0 after cancel, got %d", w.SodaPoints)
+		}
+
+		txs, err := fStore.ListTransactionsByOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("ListTransactionsByOrder failed: %v", err)
+		}
+		var earned, clawedBack int64
+		for _, tx := range txs {
+			switch tx.Type {
+			case financestore.TxEarned:
+				earned += tx.Amount
+			case financestore.TxClawback:
+				clawedBack += tx.Amount
+			}
+		}
+		if earned != clawedBack {
+			t.Errorf("expected clawback %d to match earned %d", clawedBack, earned)
+		}
+	})
+
+	t.Run("Cancel_Twice_Fails", func(t *testing.T) {
+		o := placeOrder(t, uuid.NewString(), uuid.NewString(), 100, 50)
+
+		if _, err := orderService.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		_, err := orderService.CancelOrder(ctx, o.ID)
+		if !errors.Is(err, order.ErrInvalidTransition) {
+			t.Fatalf("expected ErrInvalidTransition, got %v", err)
+		}
+	})
+
+	t.Run("Refund_RequiresShipment", func(t *testing.T) {
+		o := placeOrder(t, uuid.NewString(), uuid.NewString(), 100, 50)
+
+		_, err := orderService.RefundOrder(ctx, o.ID)
+		if !errors.Is(err, order.ErrInvalidTransition) {
+			t.Fatalf("expected ErrInvalidTransition refunding a CONFIRMED order, got %v", err)
+		}
+	})
+
+	t.Run("Refund_AfterConversion_ClawsBackBalance", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		authorID := uuid.NewString()
+		o := placeOrder(t, buyerID, authorID, 1200, 0)
+
+		if _, err := orderService.UpdateStatus(ctx, o.ID, order.StatusShipped); err != nil {
+			t.Fatalf("UpdateStatus SHIPPED failed: %v", err)
+		}
+		if _, err := orderService.UpdateStatus(ctx, o.ID, order.StatusDelivered); err != nil {
+			t.Fatalf("UpdateStatus DELIVERED failed: %v", err)
+		}
+
+		// Buyer converts all 1200 points into 600 yen before the refund.
+		if _, err := financeService.ConvertPoints(ctx, buyerID, 0); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+
+		refunded, err := orderService.RefundOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("RefundOrder failed: %v", err)
+		}
+		if refunded.Status != order.StatusRefunded {
+			t.Errorf("expected status REFUNDED, got %s", refunded.Status)
+		}
+
+		w := getWallet(t, buyerID)
+		if w.SodaPoints != 0 {
+			t.Errorf("expected buyer points 0, got %d", w.SodaPoints)
+		}
+		if w.SodaBalance != 0 {
+			t.Errorf("expected buyer balance 0 after clawback, got %d", w.SodaBalance)
+		}
+	})
+
+	t.Run("Refund_AfterSpending_LeavesDebt", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		o := placeOrder(t, buyerID, uuid.NewString(), 1200, 0)
+
+		if _, err := orderService.UpdateStatus(ctx, o.ID, order.StatusShipped); err != nil {
+			t.Fatalf("UpdateStatus SHIPPED failed: %v", err)
+		}
+		if _, err := financeService.ConvertPoints(ctx, buyerID, 0); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+
+		// Simulate the buyer having spent part of the converted balance.
+		if _, err := fStore.Adjust(ctx, buyerID, 0, -500); err != nil {
+			t.Fatalf("Adjust failed: %v", err)
+		}
+
+		if _, err := orderService.RefundOrder(ctx, o.ID); err != nil {
+			t.Fatalf("RefundOrder failed: %v", err)
+		}
+
+		// 100 yen recovers 200 points; the remaining 1000 points stay as debt.
+		w := getWallet(t, buyerID)
+		if w.SodaBalance != 0 {
+			t.Errorf("expected buyer balance 0, got %d", w.SodaBalance)
+		}
+		if w.SodaPoints != -1000 {
+			t.Errorf("expected buyer points -1000, got %d", w.SodaPoints)
+		}
+	})
+
+	t.Run("Rebuy_AfterCancel_GetsBuyerReward", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		o := placeOrder(t, buyerID, uuid.NewString(), 100, 0)
+
+		if _, err := orderService.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		if _, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: o.ProductID,
+			BlogID:    o.BlogID,
+		}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		if w := getWallet(t, buyerID); w.SodaPoints != 100 {
+			t.Errorf("expected buyer points 100 after re-buying, got %d", w.SodaPoints)
+		}
+	})
+
+	t.Run("UpdateStatus_RejectsCancel", func(t *testing.T) {
+		o := placeOrder(t, uuid.NewString(), uuid.NewString(), 100, 50)
+
+		_, err := orderService.UpdateStatus(ctx, o.ID, order.StatusCancelled)
+		if !errors.Is(err, order.ErrInvalidTransition) {
+			t.Fatalf("expected ErrInvalidTransition, got %v", err)
+		}
+	})
+
+	t.Run("Cancel_NotFound", func(t *testing.T) {
+		_, err := orderService.CancelOrder(ctx, uuid.NewString())
+		if !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
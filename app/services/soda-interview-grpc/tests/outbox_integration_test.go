@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/outbox"
+	"soda-interview/business/core/referral-blog"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/events"
+	eventsv1 "soda-interview/foundation/proto/events/v1"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_Outbox(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Outbox Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	newRelay := func(p events.Publisher) *outbox.Relay {
+		return outbox.NewRelay(c.Log, c.DB, obStore, p, outbox.RelayConfig{
+			Interval:    time.Second,
+			BatchSize:   2,
+			MaxAttempts: 2,
+		})
+	}
+
+	listEvents := func(t *testing.T, aggregateID string) []db.OutboxEvent {
+		evs, err := obStore.List(ctx, aggregateID)
+		if err != nil {
+			t.Fatalf("listing outbox events failed: %v", err)
+		}
+		return evs
+	}
+
+	t.Run("Success_OrderWritesEvents", func(t *testing.T) {
+		c.Truncate(t)
+		buyerID, authorID := uuid.NewString(), uuid.NewString()
+		productID := createProduct(t)
+
+		blog, err := blogService.CreateBlog(ctx, referralblog.NewBlog{AuthorID: authorID, Content: "Great!", ProductID: productID})
+		if err != nil {
+			t.Fatalf("CreateBlog failed: %v", err)
+		}
+		if _, err := blogService.ModerateBlog(ctx, blog.ID, "moderator", referralblog.Moderation{Status: blogstore.StatusPublished}); err != nil {
+			t.Fatalf("ModerateBlog failed: %v", err)
+		}
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: productID, BlogID: blog.ID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		placed := listEvents(t, o.ID)
+		if len(placed) != 1 || placed[0].EventType != "events.v1.OrderPlaced" {
+			t.Fatalf("expected one OrderPlaced event, got %+v", placed)
+		}
+		var ev eventsv1.OrderPlaced
+		if err := protojson.Unmarshal(placed[0].Payload, &ev); err != nil {
+			t.Fatalf("decoding OrderPlaced failed: %v", err)
+		}
+		if ev.BuyerId != buyerID || ev.Amount != 1000 || len(ev.Lines) != 1 || ev.Lines[0].BlogId != blog.ID {
+			t.Errorf("unexpected OrderPlaced payload: %v", &ev)
+		}
+
+		for userID, want := range map[string]int64{buyerID: 100, authorID: 50} {
+			evs := listEvents(t, userID)
+			if len(evs) != 1 || evs[0].EventType != "events.v1.PointsEarned" {
+				t.Fatalf("expected one PointsEarned for %s, got %+v", userID, evs)
+			}
+			var earned eventsv1.PointsEarned
+			if err := protojson.Unmarshal(evs[0].Payload, &earned); err != nil {
+				t.Fatalf("decoding PointsEarned failed: %v", err)
+			}
+			if earned.Points != want || earned.OrderId != o.ID {
+				t.Errorf("unexpected PointsEarned payload: %v", &earned)
+			}
+		}
+	})
+
+	t.Run("Success_RolledBackOrderWritesNothing", func(t *testing.T) {
+		c.Truncate(t)
+
+		_, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   uuid.NewString(),
+			ProductID: createProduct(t),
+			Payment:   order.Payment{BalanceAmount: 1000},
+		})
+		if !errors.Is(err, order.ErrInsufficientBalance) {
+			t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+		}
+		if evs := listEvents(t, ""); len(evs) != 0 {
+			t.Errorf("expected no events, got %d", len(evs))
+		}
+	})
+
+	t.Run("Success_RelayPublishesOnce", func(t *testing.T) {
+		c.Truncate(t)
+		userID := uuid.NewString()
+		if _, err := fStore.Earn(ctx, userID, 2000, uuid.NewString()); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+		if _, err := financeService.ConvertPoints(ctx, userID, 1000); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if _, err := blogService.CreateBlog(ctx, referralblog.NewBlog{AuthorID: userID, Content: "Hi", ProductID: createProduct(t)}); err != nil {
+			t.Fatalf("CreateBlog failed: %v", err)
+		}
+
+		pub := events.NewMemory()
+		relay := newRelay(pub)
+		n, err := relay.PublishDue(ctx, time.Now())
+		if err != nil {
+			t.Fatalf("PublishDue failed: %v", err)
+		}
+		msgs := pub.Messages()
+		if n != 2 || len(msgs) != 2 {
+			t.Fatalf("expected 2 events published, got %d (%d messages)", n, len(msgs))
+		}
+		if msgs[0].Type != "events.v1.PointsConverted" || msgs[1].Type != "events.v1.BlogCreated" {
+			t.Errorf("unexpected event order: %s, %s", msgs[0].Type, msgs[1].Type)
+		}
+		var conv eventsv1.PointsConverted
+		if err := protojson.Unmarshal(msgs[0].Payload, &conv); err != nil {
+			t.Fatalf("decoding PointsConverted failed: %v", err)
+		}
+		if conv.UserId != userID || conv.Points != 1000 || conv.Yen != 500 || conv.PolicyVersion != 1 {
+			t.Errorf("unexpected PointsConverted payload: %v", &conv)
+		}
+
+		if n, err := relay.PublishDue(ctx, time.Now()); err != nil || n != 0 {
+			t.Errorf("expected nothing left to publish, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("Success_FailingEventIsRetriedThenDead", func(t *testing.T) {
+		c.Truncate(t)
+		if _, err := blogService.CreateBlog(ctx, referralblog.NewBlog{AuthorID: uuid.NewString(), Content: "Hi", ProductID: createProduct(t)}); err != nil {
+			t.Fatalf("CreateBlog failed: %v", err)
+		}
+
+		pub := events.NewMemory()
+		pub.SetError(errors.New("broker unavailable"))
+		relay := newRelay(pub)
+
+		if _, err := relay.PublishDue(ctx, time.Now()); err != nil {
+			t.Fatalf("PublishDue failed: %v", err)
+		}
+		evs := listEvents(t, "")
+		if len(evs) != 1 || evs[0].Status != outboxstore.StatusPending || evs[0].Attempts != 1 {
+			t.Fatalf("expected a pending event with one attempt, got %+v", evs)
+		}
+
+		if _, err := relay.PublishDue(ctx, time.Now()); err != nil {
+			t.Fatalf("PublishDue failed: %v", err)
+		}
+		evs = listEvents(t, "")
+		if evs[0].Status != outboxstore.StatusDead || evs[0].LastError.String != "broker unavailable" {
+			t.Fatalf("expected the event dead-lettered, got %+v", evs[0])
+		}
+
+		// Dead events stay put once the publisher recovers, until requeued.
+		pub.SetError(nil)
+		if n, _ := relay.PublishDue(ctx, time.Now()); n != 0 {
+			t.Errorf("expected dead event not to be published, got %d", n)
+		}
+		if ok, err := obStore.Requeue(ctx, evs[0].ID); err != nil || !ok {
+			t.Fatalf("Requeue failed: %v, %v", ok, err)
+		}
+		if n, err := relay.PublishDue(ctx, time.Now()); err != nil || n != 1 {
+			t.Errorf("expected requeued event published, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("Success_FilePublisherAppendsLines", func(t *testing.T) {
+		c.Truncate(t)
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		pub, err := events.NewFile(path)
+		if err != nil {
+			t.Fatalf("NewFile failed: %v", err)
+		}
+		defer pub.Close()
+
+		for range 3 {
+			if _, err := blogService.CreateBlog(ctx, referralblog.NewBlog{AuthorID: uuid.NewString(), Content: "Hi", ProductID: createProduct(t)}); err != nil {
+				t.Fatalf("CreateBlog failed: %v", err)
+			}
+		}
+		if n, err := newRelay(pub).PublishDue(ctx, time.Now()); err != nil || n != 3 {
+			t.Fatalf("expected 3 events published, got %d, %v", n, err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("opening event file failed: %v", err)
+		}
+		defer f.Close()
+
+		var lines int
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var m events.Message
+			if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+				t.Fatalf("decoding line failed: %v", err)
+			}
+			if m.Type != "events.v1.BlogCreated" || m.ID == "" {
+				t.Errorf("unexpected message: %+v", m)
+			}
+			lines++
+		}
+		if lines != 3 {
+			t.Errorf("expected 3 lines, got %d", lines)
+		}
+	})
+}
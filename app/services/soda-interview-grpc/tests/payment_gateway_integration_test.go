@@ -0,0 +1,387 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/app/services/soda-interview-grpc/internal/transport/webhook"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/payment"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_PaymentGateway(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Service with a fake gateway that sends no webhooks of its own
+	gateway := payment.NewFake(c.Log, "test-secret", payment.FakeConfig{DeclineAbove: 5000})
+	service := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	service.SetGateway(gateway)
+	receiver := &webhook.PaymentHandler{Log: c.Log, Gateway: gateway, Service: service}
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T, price int64) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Gateway Product",
+			Description:        "Desc",
+			Price:              price,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	createBlog := func(t *testing.T, authorID, productID string) string {
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return b.ID
+	}
+
+	points := func(t *testing.T, userID string) int64 {
+		w, err := fStore.GetWallet(ctx, userID)
+		if errors.Is(err, financestore.ErrNotFound) {
+			return 0
+		}
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		return w.SodaPoints
+	}
+
+	send := func(t *testing.T, ev payment.Event) int {
+		header, body, err := gateway.Webhook(ev)
+		if err != nil {
+			t.Fatalf("building webhook failed: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewReader(body))
+		req.Header = header
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	paymentStatus := func(t *testing.T, paymentID string) string {
+		s, err := gateway.Status(paymentID)
+		if err != nil {
+			t.Fatalf("gateway status failed: %v", err)
+		}
+		return s
+	}
+
+	t.Run("Success_RewardsWaitForWebhook", func(t *testing.T) {
+		buyerID, authorID := uuid.NewString(), uuid.NewString()
+		productID := createProduct(t, 1000)
+
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: productID,
+			BlogID:    createBlog(t, authorID, productID),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if o.Status != order.StatusPendingPayment || o.PaymentID == "" {
+			t.Fatalf("expected a PENDING_PAYMENT order with a payment, got %s %q", o.Status, o.PaymentID)
+		}
+		if got := points(t, buyerID) + points(t, authorID); got != 0 {
+			t.Errorf("expected no rewards before payment, got %d points", got)
+		}
+
+		ev := payment.Event{Type: payment.EventAuthorized, PaymentID: o.PaymentID, Reference: o.ID, Amount: 1000}
+		if code := send(t, ev); code != http.StatusNoContent {
+			t.Fatalf("expected 204 from receiver, got %d", code)
+		}
+
+		got, err := service.GetOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("GetOrder failed: %v", err)
+		}
+		if got.Status != order.StatusConfirmed {
+			t.Errorf("expected CONFIRMED after webhook, got %s", got.Status)
+		}
+		if points(t, buyerID) != 100 || points(t, authorID) != 50 {
+			t.Errorf("expected 100/50 points, got %d/%d", points(t, buyerID), points(t, authorID))
+		}
+		if s := paymentStatus(t, o.PaymentID); s != payment.FakeCaptured {
+			t.Errorf("expected payment captured, got %s", s)
+		}
+
+		// Redelivering the event changes nothing.
+		if code := send(t, ev); code != http.StatusNoContent {
+			t.Fatalf("expected 204 for redelivery, got %d", code)
+		}
+		if points(t, buyerID) != 100 {
+			t.Errorf("expected rewards paid once, got %d buyer points", points(t, buyerID))
+		}
+	})
+
+	t.Run("Fail_BadSignatureRejected", func(t *testing.T) {
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   uuid.NewString(),
+			ProductID: createProduct(t, 1000),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		header, body, err := gateway.Webhook(payment.Event{Type: payment.EventAuthorized, PaymentID: o.PaymentID, Reference: o.ID})
+		if err != nil {
+			t.Fatalf("building webhook failed: %v", err)
+		}
+		body = bytes.Replace(body, []byte(o.ID), []byte(uuid.NewString()), 1)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewReader(body))
+		req.Header = header
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for a tampered body, got %d", rec.Code)
+		}
+
+		got, err := service.GetOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("GetOrder failed: %v", err)
+		}
+		if got.Status != order.StatusPendingPayment {
+			t.Errorf("expected order still pending, got %s", got.Status)
+		}
+	})
+
+	t.Run("Success_FailedPaymentCancels", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		if _, err := fStore.GetOrCreateWallet(ctx, buyerID); err != nil {
+			t.Fatalf("create wallet failed: %v", err)
+		}
+		if _, err := fStore.Adjust(ctx, buyerID, 0, 400); err != nil {
+			t.Fatalf("add balance failed: %v", err)
+		}
+
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+			Payment:   order.Payment{BalanceAmount: 400, ExternalAmount: 600},
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		if code := send(t, payment.Event{Type: payment.EventFailed, PaymentID: o.PaymentID, Reference: o.ID}); code != http.StatusNoContent {
+			t.Fatalf("expected 204 from receiver, got %d", code)
+		}
+
+		got, err := service.GetOrder(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("GetOrder failed: %v", err)
+		}
+		if got.Status != order.StatusCancelled {
+			t.Errorf("expected CANCELLED after failed payment, got %s", got.Status)
+		}
+		w, err := fStore.GetWallet(ctx, buyerID)
+		if err != nil {
+			t.Fatalf("getWallet failed: %v", err)
+		}
+		if w.SodaBalance != 400 || w.SodaPoints != 0 {
+			t.Errorf("expected balance refunded and no points, got %+v", w)
+		}
+		if s := paymentStatus(t, o.PaymentID); s != payment.FakeVoided {
+			t.Errorf("expected payment voided, got %s", s)
+		}
+	})
+
+	t.Run("Fail_DeclinedPlacesNothing", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		productID := createProduct(t, 6000)
+
+		_, err := service.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: productID})
+		if !errors.Is(err, order.ErrPaymentDeclined) {
+			t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+		}
+		n, err := oStore.CountOrdersByBuyerAndProduct(ctx, buyerID, productID)
+		if err != nil {
+			t.Fatalf("CountOrdersByBuyerAndProduct failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected no order to be placed, got %d", n)
+		}
+	})
+
+	t.Run("Fail_MismatchedPayment", func(t *testing.T) {
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   uuid.NewString(),
+			ProductID: createProduct(t, 1000),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		_, err = service.ConfirmPayment(ctx, o.ID, "pay_other")
+		if !errors.Is(err, order.ErrPaymentMismatch) {
+			t.Errorf("expected ErrPaymentMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Success_RefundAfterCapture", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: createProduct(t, 1000)})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if _, err := service.ConfirmPayment(ctx, o.ID, o.PaymentID); err != nil {
+			t.Fatalf("ConfirmPayment failed: %v", err)
+		}
+		if _, err := service.UpdateStatus(ctx, o.ID, order.StatusShipped); err != nil {
+			t.Fatalf("UpdateStatus failed: %v", err)
+		}
+		if _, err := service.RefundOrder(ctx, o.ID); err != nil {
+			t.Fatalf("RefundOrder failed: %v", err)
+		}
+
+		if s := paymentStatus(t, o.PaymentID); s != payment.FakeRefunded {
+			t.Errorf("expected payment refunded, got %s", s)
+		}
+		if got := points(t, buyerID); got != 0 {
+			t.Errorf("expected rewards clawed back, got %d points", got)
+		}
+	})
+
+	t.Run("Success_GatewayCallsRetriedAfterCommit", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: createProduct(t, 1000)})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		// The gateway being down does not undo the committed changes.
+		gateway.SetError(errors.New("gateway unavailable"))
+		defer gateway.SetError(nil)
+		if _, err := service.ConfirmPayment(ctx, o.ID, o.PaymentID); err != nil {
+			t.Fatalf("ConfirmPayment failed: %v", err)
+		}
+		if _, err := service.CancelOrder(ctx, o.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+		if s := paymentStatus(t, o.PaymentID); s != payment.FakeAuthorized {
+			t.Errorf("expected payment still authorized, got %s", s)
+		}
+
+		actions, err := oStore.ListPaymentActions(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("ListPaymentActions failed: %v", err)
+		}
+		if len(actions) != 2 || actions[0].Action != orderstore.ActionCapture || actions[1].Action != orderstore.ActionRefund {
+			t.Fatalf("expected a capture then a refund queued, got %+v", actions)
+		}
+		for _, a := range actions {
+			if a.Status != orderstore.ActionPending {
+				t.Errorf("expected action %s pending, got %s", a.Action, a.Status)
+			}
+		}
+
+		// Once the gateway is back the capture runs before its refund.
+		gateway.SetError(nil)
+		retrier := order.NewPaymentRetrier(c.Log, service, order.PaymentRetryConfig{BatchSize: 10, MaxAttempts: 3})
+		n, err := retrier.RetryDue(ctx, time.Now())
+		if err != nil {
+			t.Fatalf("RetryDue failed: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("expected 2 gateway calls made, got %d", n)
+		}
+		if s := paymentStatus(t, o.PaymentID); s != payment.FakeRefunded {
+			t.Errorf("expected payment refunded, got %s", s)
+		}
+		if n, err := retrier.RetryDue(ctx, time.Now()); err != nil || n != 0 {
+			t.Errorf("expected nothing left to retry, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("Fail_GatewayCallDeadLettered", func(t *testing.T) {
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: createProduct(t, 1000)})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+
+		gateway.SetError(errors.New("gateway unavailable"))
+		defer gateway.SetError(nil)
+		if _, err := service.ConfirmPayment(ctx, o.ID, o.PaymentID); err != nil {
+			t.Fatalf("ConfirmPayment failed: %v", err)
+		}
+
+		// The attempt made after commit counts, so one retry uses up two.
+		retrier := order.NewPaymentRetrier(c.Log, service, order.PaymentRetryConfig{BatchSize: 10, MaxAttempts: 2})
+		if _, err := retrier.RetryDue(ctx, time.Now()); err != nil {
+			t.Fatalf("RetryDue failed: %v", err)
+		}
+		actions, err := oStore.ListPaymentActions(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("ListPaymentActions failed: %v", err)
+		}
+		if len(actions) != 1 || actions[0].Status != orderstore.ActionDead || actions[0].Attempts != 2 {
+			t.Fatalf("expected the capture dead after 2 attempts, got %+v", actions)
+		}
+		if !actions[0].LastError.Valid {
+			t.Errorf("expected the last error to be kept")
+		}
+	})
+
+	t.Run("Success_BalanceOnlySkipsGateway", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		if _, err := fStore.GetOrCreateWallet(ctx, buyerID); err != nil {
+			t.Fatalf("create wallet failed: %v", err)
+		}
+		if _, err := fStore.Adjust(ctx, buyerID, 0, 1000); err != nil {
+			t.Fatalf("add balance failed: %v", err)
+		}
+
+		o, err := service.PlaceOrder(ctx, order.PlaceOrderReq{
+			BuyerID:   buyerID,
+			ProductID: createProduct(t, 1000),
+			Payment:   order.Payment{BalanceAmount: 1000},
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if o.Status != order.StatusConfirmed || o.PaymentID != "" {
+			t.Errorf("expected a CONFIRMED order without a payment, got %s %q", o.Status, o.PaymentID)
+		}
+		if got := points(t, buyerID); got != 100 {
+			t.Errorf("expected 100 points at once, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/database/postgres"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_PointsExpiry(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Service
+	service := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	earn := func(t *testing.T, userID string, points int64, ttl time.Duration) {
+		fStore.SetPointsTTL(ttl)
+		defer fStore.SetPointsTTL(financestore.DefaultPointsTTL)
+		if _, err := fStore.Earn(ctx, userID, points, uuid.NewString()); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+	}
+
+	expiring := func(t *testing.T, userID string) finance.ExpiringPoints {
+		ep, err := service.GetExpiringPoints(ctx, userID, time.Now().Add(2*financestore.DefaultPointsTTL).Unix())
+		if err != nil {
+			t.Fatalf("GetExpiringPoints failed: %v", err)
+		}
+		return ep
+	}
+
+	t.Run("Success_ConvertSpendsSoonestExpiryFirst", func(t *testing.T) {
+		userID := uuid.NewString()
+		earn(t, userID, 1000, 48*time.Hour)
+		earn(t, userID, 1000, 24*time.Hour)
+
+		if _, err := service.ConvertPoints(ctx, userID, 1200); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+
+		ep := expiring(t, userID)
+		if ep.Total != 800 || len(ep.Lots) != 1 || ep.Lots[0].Remaining != 800 {
+			t.Fatalf("expected only the later lot left with 800 points, got %+v", ep)
+		}
+		if until := time.Until(time.Unix(ep.Lots[0].ExpiresAt, 0)); until < 47*time.Hour {
+			t.Errorf("expected the 48h lot to remain, expires in %s", until)
+		}
+	})
+
+	t.Run("Success_ClawbackDrawsTheOrdersLot", func(t *testing.T) {
+		userID, orderA, orderB := uuid.NewString(), uuid.NewString(), uuid.NewString()
+		fStore.SetPointsTTL(24 * time.Hour)
+		if _, err := fStore.Earn(ctx, userID, 100, orderA); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+		fStore.SetPointsTTL(48 * time.Hour)
+		if _, err := fStore.Earn(ctx, userID, 200, orderB); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+		fStore.SetPointsTTL(financestore.DefaultPointsTTL)
+
+		// Reversing order B leaves order A's sooner-expiring lot alone.
+		if _, err := fStore.Clawback(ctx, userID, 200, orderB); err != nil {
+			t.Fatalf("Clawback failed: %v", err)
+		}
+		ep := expiring(t, userID)
+		if ep.Total != 100 || len(ep.Lots) != 1 || ep.Lots[0].Remaining != 100 {
+			t.Fatalf("expected only order A's lot left with 100 points, got %+v", ep)
+		}
+		if until := time.Until(time.Unix(ep.Lots[0].ExpiresAt, 0)); until > 25*time.Hour {
+			t.Errorf("expected the 24h lot to remain, expires in %s", until)
+		}
+	})
+
+	t.Run("Success_WindowExcludesLaterLots", func(t *testing.T) {
+		userID := uuid.NewString()
+		earn(t, userID, 100, 24*time.Hour)
+		earn(t, userID, 200, 60*24*time.Hour)
+
+		ep, err := service.GetExpiringPoints(ctx, userID, 0)
+		if err != nil {
+			t.Fatalf("GetExpiringPoints failed: %v", err)
+		}
+		if ep.Total != 100 {
+			t.Errorf("expected 100 points expiring within 30 days, got %d", ep.Total)
+		}
+	})
+
+	t.Run("Success_SweepExpiresDueLots", func(t *testing.T) {
+		userID := uuid.NewString()
+		earn(t, userID, 300, time.Millisecond)
+		earn(t, userID, 500, 24*time.Hour)
+		time.Sleep(10 * time.Millisecond)
+
+		if _, err := service.ExpirePoints(ctx, time.Now(), 1); err != nil {
+			t.Fatalf("ExpirePoints failed: %v", err)
+		}
+
+		w, err := service.GetWallet(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		if w.SodaPoints != 500 {
+			t.Errorf("expected 500 points after expiry, got %d", w.SodaPoints)
+		}
+
+		page, err := service.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID: userID,
+			Types:  []string{financestore.TxExpired},
+		})
+		if err != nil {
+			t.Fatalf("ListTransactions failed: %v", err)
+		}
+		if len(page.Transactions) != 1 || page.Transactions[0].Amount != 300 {
+			t.Errorf("expected one EXPIRED transaction of 300, got %+v", page.Transactions)
+		}
+
+		r, err := service.VerifyLedger(ctx)
+		if err != nil {
+			t.Fatalf("VerifyLedger failed: %v", err)
+		}
+		if !r.OK() {
+			t.Errorf("ledger out of balance after expiry: %+v", r)
+		}
+
+		// A second sweep finds nothing left to expire.
+		n, err := service.ExpirePoints(ctx, time.Now(), 10)
+		if err != nil {
+			t.Fatalf("ExpirePoints failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected nothing expired on the second sweep, got %d", n)
+		}
+	})
+
+	t.Run("Success_AdvisoryLockElectsOneLeader", func(t *testing.T) {
+		const key = 42
+		ran, err := postgres.WithAdvisoryLock(ctx, c.DB, key, func(ctx context.Context) error {
+			inner, err := postgres.WithAdvisoryLock(ctx, c.DB, key, func(context.Context) error {
+				t.Error("second holder ran while the lock was held")
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if inner {
+				t.Error("expected the second attempt not to get the lock")
+			}
+			return nil
+		})
+		if err != nil || !ran {
+			t.Fatalf("expected to take the lock, got %v, %v", ran, err)
+		}
+
+		ran, err = postgres.WithAdvisoryLock(ctx, c.DB, key, func(context.Context) error { return nil })
+		if err != nil || !ran {
+			t.Errorf("expected the lock to be free again, got %v, %v", ran, err)
+		}
+	})
+}
@@ -2,12 +2,14 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/google/uuid"
 
 	"soda-interview/business/core/product"
 	productstore "soda-interview/business/data/stores/product"
+	"soda-interview/foundation/validate"
 	tt "soda-interview/zarf/testing"
 )
 
@@ -76,10 +78,9 @@ func Test_ProductService(t *testing.T) {
 		if err == nil {
 			t.Fatal("Expected error for non-existent product, got nil")
 		}
-		if err.Error() == "" {
-			t.Error("Expected error message, got empty")
+		if !errors.Is(err, product.ErrNotFound) {
+			t.Errorf("Expected product.ErrNotFound, got %v", err)
 		}
-		// In a real app we might check for specific ErrNotFound wrapping
 	})
 
 	t.Run("List_Success", func(t *testing.T) {
@@ -93,13 +94,100 @@ func Test_ProductService(t *testing.T) {
 			}
 		}
 
-		list, err := service.ListProducts(ctx)
+		page, err := service.ListProducts(ctx, product.ListProductsReq{})
 		if err != nil {
 			t.Fatalf("ListProducts failed: %v", err)
 		}
+		list := page.Products
 
 		if len(list) != 3 {
 			t.Errorf("Expected 3 products, got %d", len(list))
 		}
 	})
+
+	t.Run("Update_Success_OnlyMaskedFields", func(t *testing.T) {
+		created, err := service.Create(ctx, product.NewProduct{Name: "Before", Description: "Keep me", Price: 100})
+		if err != nil {
+			t.Fatalf("Setup create failed: %v", err)
+		}
+
+		name := "After"
+		price := int64(250)
+		updated, err := service.Update(ctx, created.ID, product.UpdateProduct{Name: &name, Price: &price})
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+
+		if updated.Name != "After" || updated.Price != 250 {
+			t.Errorf("Expected name After and price 250, got %s and %d", updated.Name, updated.Price)
+		}
+		if updated.Description != "Keep me" {
+			t.Errorf("Expected description to be unchanged, got %s", updated.Description)
+		}
+	})
+
+	t.Run("Update_Fail_Validation", func(t *testing.T) {
+		price := int64(-1)
+		_, err := service.Update(ctx, uuid.NewString(), product.UpdateProduct{Price: &price})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Fatalf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Archive_HidesFromList", func(t *testing.T) {
+		c.Truncate(t)
+
+		keep, err := service.Create(ctx, product.NewProduct{Name: "Keep", Price: 100})
+		if err != nil {
+			t.Fatalf("Setup create failed: %v", err)
+		}
+		gone, err := service.Create(ctx, product.NewProduct{Name: "Gone", Price: 100})
+		if err != nil {
+			t.Fatalf("Setup create failed: %v", err)
+		}
+
+		archived, err := service.Archive(ctx, gone.ID)
+		if err != nil {
+			t.Fatalf("Archive failed: %v", err)
+		}
+		if archived.ArchivedAt == 0 {
+			t.Error("Expected ArchivedAt to be set")
+		}
+
+		// Archiving again is a no-op.
+		again, err := service.Archive(ctx, gone.ID)
+		if err != nil {
+			t.Fatalf("Second archive failed: %v", err)
+		}
+		if again.ArchivedAt != archived.ArchivedAt {
+			t.Errorf("Expected ArchivedAt %d to be kept, got %d", archived.ArchivedAt, again.ArchivedAt)
+		}
+
+		page, err := service.ListProducts(ctx, product.ListProductsReq{})
+		if err != nil {
+			t.Fatalf("ListProducts failed: %v", err)
+		}
+		list := page.Products
+		if len(list) != 1 || list[0].ID != keep.ID {
+			t.Errorf("Expected only %s to be listed, got %+v", keep.ID, list)
+		}
+
+		// Archived products are still readable by ID.
+		if _, err := service.GetProduct(ctx, gone.ID); err != nil {
+			t.Errorf("GetProduct on archived product failed: %v", err)
+		}
+
+		name := "Renamed"
+		_, err = service.Update(ctx, gone.ID, product.UpdateProduct{Name: &name})
+		if !errors.Is(err, product.ErrArchived) {
+			t.Errorf("Expected ErrArchived updating an archived product, got %v", err)
+		}
+	})
+
+	t.Run("Archive_Fail_NotFound", func(t *testing.T) {
+		_, err := service.Archive(ctx, uuid.NewString())
+		if !errors.Is(err, product.ErrNotFound) {
+			t.Errorf("Expected product.ErrNotFound, got %v", err)
+		}
+	})
 }
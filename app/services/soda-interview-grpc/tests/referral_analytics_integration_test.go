@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/referral-blog"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_ReferralAnalytics(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T, price int64, authorReward int32) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Analytics Product",
+			Description:        "Desc",
+			Price:              price,
+			BuyerRewardPoints:  10,
+			AuthorRewardPoints: authorReward,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	createBlog := func(t *testing.T, authorID, productID string) string {
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return b.ID
+	}
+
+	// placeAt places an order and backdates it to at.
+	placeAt := func(t *testing.T, productID, blogID string, at time.Time) string {
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: uuid.NewString(), ProductID: productID, BlogID: blogID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if _, err := c.DB.Exec(ctx, "UPDATE orders SET created_at = $2 WHERE id = $1", o.ID, at); err != nil {
+			t.Fatalf("backdating order failed: %v", err)
+		}
+		return o.ID
+	}
+
+	// Monday 2 March 2026 and Wednesday 4 March 2026, UTC.
+	monday := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	rangeFrom := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rangeTo := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	authorA, authorB := uuid.NewString(), uuid.NewString()
+	cheap, pricey := createProduct(t, 1000, 50), createProduct(t, 3000, 80)
+	blogA1, blogA2, blogB := createBlog(t, authorA, cheap), createBlog(t, authorA, pricey), createBlog(t, authorB, cheap)
+
+	placeAt(t, cheap, blogA1, monday)
+	placeAt(t, cheap, blogA1, wednesday)
+	placeAt(t, pricey, blogA2, wednesday)
+	placeAt(t, cheap, blogB, monday)
+	placeAt(t, cheap, "", monday) // No referral.
+	cancelled := placeAt(t, cheap, blogA1, monday)
+	if _, err := orderService.CancelOrder(ctx, cancelled); err != nil {
+		t.Fatalf("cancelling order failed: %v", err)
+	}
+	placeAt(t, cheap, blogA1, rangeTo) // Outside the range.
+
+	t.Run("Success_PerBlogAndDaily", func(t *testing.T) {
+		a, err := blogService.ReferralAnalytics(ctx, referralblog.AnalyticsReq{
+			AuthorID: authorA,
+			From:     rangeFrom.Unix(),
+			To:       rangeTo.Unix(),
+		})
+		if err != nil {
+			t.Fatalf("ReferralAnalytics failed: %v", err)
+		}
+
+		want := referralblog.ReferralStats{Orders: 3, Revenue: 5000, Points: 180}
+		if a.Totals != want {
+			t.Errorf("expected totals %+v, got %+v", want, a.Totals)
+		}
+		if len(a.Blogs) != 2 || a.Blogs[0].BlogID != blogA2 || a.Blogs[1].BlogID != blogA1 {
+			t.Fatalf("expected blogs by revenue, got %+v", a.Blogs)
+		}
+		if got := a.Blogs[1].ReferralStats; got != (referralblog.ReferralStats{Orders: 2, Revenue: 2000, Points: 100}) {
+			t.Errorf("unexpected stats for the cheap blog: %+v", got)
+		}
+		if len(a.Authors) != 1 || a.Authors[0].AuthorID != authorA || a.Authors[0].Blogs != 2 {
+			t.Errorf("expected only author A, got %+v", a.Authors)
+		}
+
+		if len(a.Series) != 7 || a.Series[0].Start != rangeFrom.Unix() {
+			t.Fatalf("expected 7 daily buckets from the range start, got %+v", a.Series)
+		}
+		if a.Series[1].Orders != 1 || a.Series[3].Orders != 2 || a.Series[2].Orders != 0 {
+			t.Errorf("unexpected daily orders: %+v", a.Series)
+		}
+	})
+
+	t.Run("Success_AllAuthorsWeekly", func(t *testing.T) {
+		a, err := blogService.ReferralAnalytics(ctx, referralblog.AnalyticsReq{
+			From:        rangeFrom.Unix(),
+			To:          rangeTo.Unix(),
+			Granularity: referralblog.GranularityWeek,
+		})
+		if err != nil {
+			t.Fatalf("ReferralAnalytics failed: %v", err)
+		}
+
+		if a.Totals.Orders != 4 || a.Totals.Revenue != 6000 {
+			t.Errorf("expected 4 orders worth 6000, got %+v", a.Totals)
+		}
+		if len(a.Authors) != 2 || a.Authors[0].AuthorID != authorA {
+			t.Errorf("expected both authors, A first, got %+v", a.Authors)
+		}
+		// The range starts on a Sunday, so it spans two weeks.
+		if len(a.Series) != 2 || a.Series[1].Start != monday.Truncate(24*time.Hour).Unix() || a.Series[1].Orders != 4 {
+			t.Errorf("unexpected weekly series: %+v", a.Series)
+		}
+	})
+
+	t.Run("Success_OneBlog", func(t *testing.T) {
+		a, err := blogService.ReferralAnalytics(ctx, referralblog.AnalyticsReq{
+			BlogID: blogB,
+			From:   rangeFrom.Unix(),
+			To:     rangeTo.Unix(),
+		})
+		if err != nil {
+			t.Fatalf("ReferralAnalytics failed: %v", err)
+		}
+		if len(a.Blogs) != 1 || a.Blogs[0].BlogID != blogB || a.Totals.Points != 50 {
+			t.Errorf("expected only blog B, got %+v", a)
+		}
+	})
+
+	t.Run("Fail_InvalidRange", func(t *testing.T) {
+		tests := []referralblog.AnalyticsReq{
+			{From: rangeTo.Unix(), To: rangeFrom.Unix()},
+			{From: rangeFrom.AddDate(-2, 0, 0).Unix(), To: rangeTo.Unix()},
+			{Granularity: "MONTH"},
+		}
+		for _, req := range tests {
+			_, err := blogService.ReferralAnalytics(ctx, req)
+			if _, ok := validate.AsFieldErrors(err); !ok {
+				t.Errorf("expected a field error for %+v, got %v", req, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/order"
+	"soda-interview/business/core/referral-blog"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_ReferralAttribution(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	const window = 7 * 24 * time.Hour
+	blogService := referralblog.NewService(c.Log, c.DB, bStore, obStore)
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	orderService.SetAttributionWindow(window)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Attributed Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	createBlog := func(t *testing.T, authorID, productID, status string) string {
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Status:    status,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return b.ID
+	}
+
+	visit := func(t *testing.T, visitorID, blogID string) referralblog.Visit {
+		v, err := blogService.RecordVisit(ctx, visitorID, blogID)
+		if err != nil {
+			t.Fatalf("RecordVisit failed: %v", err)
+		}
+		return v
+	}
+
+	placeOrder := func(t *testing.T, buyerID, productID, blogID string) order.Order {
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: productID, BlogID: blogID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		return o
+	}
+
+	authorPoints := func(t *testing.T, authorID string) int64 {
+		w, err := fStore.GetWallet(ctx, authorID)
+		if errors.Is(err, financestore.ErrNotFound) {
+			return 0
+		}
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		return w.SodaPoints
+	}
+
+	t.Run("Success_LastTouch", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		firstAuthor, lastAuthor := uuid.NewString(), uuid.NewString()
+		first := createBlog(t, firstAuthor, productID, blogstore.StatusPublished)
+		last := createBlog(t, lastAuthor, productID, blogstore.StatusPublished)
+
+		visit(t, buyerID, first)
+		visit(t, buyerID, last)
+
+		o := placeOrder(t, buyerID, productID, "")
+		if o.BlogID != last || o.Attribution != order.AttributionLastTouch {
+			t.Fatalf("expected the order credited to the last blog visited, got blog %q via %q", o.BlogID, o.Attribution)
+		}
+		if item := o.Items[0]; item.BlogID != last || item.Attribution != order.AttributionLastTouch {
+			t.Errorf("expected the line credited to the last blog visited, got %+v", item)
+		}
+		if got := authorPoints(t, lastAuthor); got != 50 {
+			t.Errorf("expected 50 points for the last author, got %d", got)
+		}
+		if got := authorPoints(t, firstAuthor); got != 0 {
+			t.Errorf("expected nothing for the first author, got %d", got)
+		}
+	})
+
+	t.Run("Success_RequestedBlogWins", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		visited := createBlog(t, uuid.NewString(), productID, blogstore.StatusPublished)
+		requested := createBlog(t, uuid.NewString(), productID, blogstore.StatusPublished)
+		visit(t, buyerID, visited)
+
+		o := placeOrder(t, buyerID, productID, requested)
+		if o.BlogID != requested || o.Attribution != order.AttributionRequest {
+			t.Errorf("expected the requested blog, got blog %q via %q", o.BlogID, o.Attribution)
+		}
+	})
+
+	t.Run("Success_NoQualifyingVisit", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+
+		// A visit outside the window.
+		stale := visit(t, buyerID, createBlog(t, uuid.NewString(), productID, blogstore.StatusPublished))
+		if _, err := c.DB.Exec(ctx, "UPDATE referral_visits SET visited_at = $2 WHERE id = $1",
+			stale.ID, time.Now().Add(-window-time.Hour)); err != nil {
+			t.Fatalf("backdating visit failed: %v", err)
+		}
+		// A visit to a blog about another product.
+		visit(t, buyerID, createBlog(t, uuid.NewString(), createProduct(t), blogstore.StatusPublished))
+		// A visit to the buyer's own blog.
+		visit(t, buyerID, createBlog(t, buyerID, productID, blogstore.StatusPublished))
+		// A visit to a blog that was taken down since.
+		takenDown := createBlog(t, uuid.NewString(), productID, blogstore.StatusPublished)
+		visit(t, buyerID, takenDown)
+		if _, err := bStore.UpdateBlog(ctx, takenDown, "Check this out!", blogstore.StatusTakenDown); err != nil {
+			t.Fatalf("taking blog down failed: %v", err)
+		}
+
+		o := placeOrder(t, buyerID, productID, "")
+		if o.BlogID != "" || o.Attribution != "" || o.Items[0].Attribution != "" {
+			t.Errorf("expected no referral, got blog %q via %q", o.BlogID, o.Attribution)
+		}
+	})
+
+	t.Run("Success_WindowOff", func(t *testing.T) {
+		plain := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		visit(t, buyerID, createBlog(t, uuid.NewString(), productID, blogstore.StatusPublished))
+
+		o, err := plain.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: productID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if o.BlogID != "" {
+			t.Errorf("expected no last-touch attribution without a window, got %q", o.BlogID)
+		}
+	})
+
+	t.Run("Success_CartLines", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		visitedProduct, otherProduct := createProduct(t), createProduct(t)
+		blogID := createBlog(t, uuid.NewString(), visitedProduct, blogstore.StatusPublished)
+		visit(t, buyerID, blogID)
+
+		o, err := orderService.PlaceCartOrder(ctx, order.PlaceCartOrderReq{
+			BuyerID: buyerID,
+			Lines: []order.CartLine{
+				{ProductID: visitedProduct, Quantity: 1},
+				{ProductID: otherProduct, Quantity: 1},
+			},
+		})
+		if err != nil {
+			t.Fatalf("PlaceCartOrder failed: %v", err)
+		}
+		if o.Attribution != "" {
+			t.Errorf("expected no order-level attribution for a cart, got %q", o.Attribution)
+		}
+		if item := o.Items[0]; item.BlogID != blogID || item.Attribution != order.AttributionLastTouch {
+			t.Errorf("expected the visited product's line credited to the blog, got %+v", item)
+		}
+		if item := o.Items[1]; item.BlogID != "" || item.Attribution != "" {
+			t.Errorf("expected no referral for the other line, got %+v", item)
+		}
+	})
+
+	t.Run("Fail_VisitUnpublishedBlog", func(t *testing.T) {
+		draft := createBlog(t, uuid.NewString(), createProduct(t), blogstore.StatusDraft)
+		if _, err := blogService.RecordVisit(ctx, uuid.NewString(), draft); !errors.Is(err, referralblog.ErrNotFound) {
+			t.Errorf("expected ErrNotFound visiting a draft, got %v", err)
+		}
+		if _, err := blogService.RecordVisit(ctx, uuid.NewString(), uuid.NewString()); !errors.Is(err, referralblog.ErrNotFound) {
+			t.Errorf("expected ErrNotFound visiting a missing blog, got %v", err)
+		}
+	})
+}
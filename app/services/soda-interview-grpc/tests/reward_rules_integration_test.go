@@ -0,0 +1,342 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	"soda-interview/business/core/order"
+	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	productstore "soda-interview/business/data/stores/product"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_RewardRules(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	oStore := orderstore.NewStore(c.Log, c.DB)
+	pStore := productstore.NewStore(c.Log, c.DB)
+	bStore := blogstore.NewStore(c.Log, c.DB)
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Services
+	orderService := order.NewService(c.Log, c.DB, oStore, pStore, bStore, fStore, iStore, obStore)
+	financeService := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	createProduct := func(t *testing.T) string {
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                 uuid.NewString(),
+			Name:               "Rewarded Product",
+			Description:        "Desc",
+			Price:              1000,
+			BuyerRewardPoints:  100,
+			AuthorRewardPoints: 50,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		return p.ID
+	}
+
+	createBlog := func(t *testing.T, authorID, productID string) string {
+		b, err := bStore.CreateBlog(ctx, db.CreateBlogParams{
+			ID:        uuid.NewString(),
+			AuthorID:  authorID,
+			Content:   "Check this out!",
+			ProductID: productID,
+			Status:    blogstore.StatusPublished,
+		})
+		if err != nil {
+			t.Fatalf("createBlog failed: %v", err)
+		}
+		return b.ID
+	}
+
+	createRule := func(t *testing.T, nr order.NewRewardRule) order.RewardRule {
+		nr.Name = "Campaign"
+		nr.CreatedBy = "admin"
+		r, err := orderService.CreateRewardRule(ctx, nr)
+		if err != nil {
+			t.Fatalf("CreateRewardRule failed: %v", err)
+		}
+		return r
+	}
+
+	placeOrder := func(t *testing.T, buyerID, productID, blogID string) order.Order {
+		o, err := orderService.PlaceOrder(ctx, order.PlaceOrderReq{BuyerID: buyerID, ProductID: productID, BlogID: blogID})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		return o
+	}
+
+	points := func(t *testing.T, userID string) int64 {
+		w, err := fStore.GetWallet(ctx, userID)
+		if errors.Is(err, financestore.ErrNotFound) {
+			return 0
+		}
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		return w.SodaPoints
+	}
+
+	earned := func(t *testing.T, userID string) []finance.Transaction {
+		page, err := financeService.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID: userID,
+			Types:  []string{financestore.TxEarned},
+		})
+		if err != nil {
+			t.Fatalf("ListTransactions failed: %v", err)
+		}
+		return page.Transactions
+	}
+
+	t.Run("Success_CampaignBonus", func(t *testing.T) {
+		buyerID, authorID, productID := uuid.NewString(), uuid.NewString(), createProduct(t)
+		rule := createRule(t, order.NewRewardRule{
+			Recipient:         order.RecipientBuyer,
+			Kind:              order.RuleBonus,
+			Value:             30,
+			ProductID:         productID,
+			FirstPurchaseOnly: true,
+		})
+
+		placeOrder(t, buyerID, productID, createBlog(t, authorID, productID))
+
+		if got := points(t, buyerID); got != 130 {
+			t.Errorf("expected 130 points for the buyer, got %d", got)
+		}
+		if txs := earned(t, buyerID); len(txs) != 1 || !slices.Equal(txs[0].RewardRuleIDs, []int64{rule.ID}) {
+			t.Errorf("expected one EARNED transaction naming rule %d, got %+v", rule.ID, txs)
+		}
+		if txs := earned(t, authorID); len(txs) != 1 || len(txs[0].RewardRuleIDs) != 0 {
+			t.Errorf("expected the author's EARNED transaction to name no rule, got %+v", txs)
+		}
+	})
+
+	t.Run("Success_PriorityOrder", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		// The multiplier runs last, so it doubles the percentage too.
+		createRule(t, order.NewRewardRule{
+			Recipient: order.RecipientBuyer,
+			Kind:      order.RuleMultiplier,
+			Value:     200,
+			Priority:  2,
+			ProductID: productID,
+		})
+		createRule(t, order.NewRewardRule{
+			Recipient: order.RecipientBuyer,
+			Kind:      order.RulePercentOfPrice,
+			Value:     1000,
+			Priority:  1,
+			ProductID: productID,
+		})
+
+		o := placeOrder(t, buyerID, productID, "")
+		if got := points(t, buyerID); got != 400 {
+			t.Errorf("expected (100 + 10%% of 1000) * 2 = 400 points, got %d", got)
+		}
+		if txs := earned(t, buyerID); len(txs) != 1 || len(txs[0].RewardRuleIDs) != 2 || txs[0].RelatedOrderID != o.ID {
+			t.Errorf("expected one EARNED transaction naming both rules, got %+v", txs)
+		}
+	})
+
+	t.Run("Success_AuthorTiers", func(t *testing.T) {
+		authorID, productID := uuid.NewString(), createProduct(t)
+		blogID := createBlog(t, authorID, productID)
+		createRule(t, order.NewRewardRule{
+			Recipient:      order.RecipientAuthor,
+			Kind:           order.RuleBonus,
+			Value:          20,
+			ProductID:      productID,
+			MaxAuthorSales: 1000,
+		})
+		createRule(t, order.NewRewardRule{
+			Recipient:      order.RecipientAuthor,
+			Kind:           order.RuleBonus,
+			Value:          80,
+			ProductID:      productID,
+			MinAuthorSales: 1000,
+		})
+
+		// No sales yet: the first tier.
+		placeOrder(t, uuid.NewString(), productID, blogID)
+		if got := points(t, authorID); got != 70 {
+			t.Fatalf("expected 50 + 20 points for the first tier, got %d", got)
+		}
+		// 1000 yen referred: the second tier.
+		placeOrder(t, uuid.NewString(), productID, blogID)
+		if got := points(t, authorID); got != 70+130 {
+			t.Errorf("expected 50 + 80 more points for the second tier, got %d", got-70)
+		}
+		// Rules for authors never pay without a referral.
+		buyerID := uuid.NewString()
+		placeOrder(t, buyerID, productID, "")
+		if got := points(t, buyerID); got != 100 {
+			t.Errorf("expected only the product's 100 points for the buyer, got %d", got)
+		}
+	})
+
+	t.Run("Success_UserCap", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		createRule(t, order.NewRewardRule{
+			Recipient:     order.RecipientBuyer,
+			Kind:          order.RuleBonus,
+			Value:         30,
+			ProductID:     productID,
+			UserCapPoints: 50,
+		})
+
+		first := placeOrder(t, buyerID, productID, "")
+		placeOrder(t, buyerID, productID, "")
+		placeOrder(t, buyerID, productID, "")
+		// 100 + 30 on the first purchase, then 20 until the cap is reached.
+		if got := points(t, buyerID); got != 150 {
+			t.Fatalf("expected 150 points with the cap reached, got %d", got)
+		}
+
+		// Points from cancelled orders no longer count towards the cap.
+		if _, err := orderService.CancelOrder(ctx, first.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+		placeOrder(t, buyerID, productID, "")
+		if got := points(t, buyerID); got != 20+30 {
+			t.Errorf("expected 50 points after cancelling and ordering again, got %d", got)
+		}
+	})
+
+	t.Run("Success_EndRule", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		rule := createRule(t, order.NewRewardRule{
+			Recipient: order.RecipientBuyer,
+			Kind:      order.RuleBonus,
+			Value:     30,
+			ProductID: productID,
+		})
+
+		ended, err := orderService.EndRewardRule(ctx, rule.ID)
+		if err != nil {
+			t.Fatalf("EndRewardRule failed: %v", err)
+		}
+		if ended.EndsAt == 0 {
+			t.Fatalf("expected an end time, got %+v", ended)
+		}
+		again, err := orderService.EndRewardRule(ctx, rule.ID)
+		if err != nil || again.EndsAt != ended.EndsAt {
+			t.Errorf("expected ending again to keep the end time, got %+v, %v", again, err)
+		}
+
+		placeOrder(t, buyerID, productID, "")
+		if got := points(t, buyerID); got != 100 {
+			t.Errorf("expected only the product's 100 points after the rule ended, got %d", got)
+		}
+
+		if _, err := orderService.EndRewardRule(ctx, rule.ID+1000); !errors.Is(err, order.ErrRuleNotFound) {
+			t.Errorf("expected ErrRuleNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Success_ScheduledRule", func(t *testing.T) {
+		buyerID, productID := uuid.NewString(), createProduct(t)
+		createRule(t, order.NewRewardRule{
+			Recipient: order.RecipientBuyer,
+			Kind:      order.RuleBonus,
+			Value:     30,
+			ProductID: productID,
+			StartsAt:  time.Now().Add(time.Hour).Unix(),
+		})
+
+		placeOrder(t, buyerID, productID, "")
+		if got := points(t, buyerID); got != 100 {
+			t.Errorf("expected the rule not to apply before it starts, got %d points", got)
+		}
+
+		rules, err := orderService.ListRewardRules(ctx)
+		if err != nil {
+			t.Fatalf("ListRewardRules failed: %v", err)
+		}
+		for i := 1; i < len(rules); i++ {
+			if rules[i-1].ID < rules[i].ID {
+				t.Fatalf("expected newest first, got %d before %d", rules[i-1].ID, rules[i].ID)
+			}
+		}
+	})
+
+	t.Run("Success_LargeAmountClamped", func(t *testing.T) {
+		buyerID := uuid.NewString()
+		// 10000 basis points of this price overflows an int64 when multiplied
+		// out directly.
+		p, err := pStore.CreateProduct(ctx, db.CreateProductParams{
+			ID:                uuid.NewString(),
+			Name:              "Expensive Product",
+			Price:             1e16,
+			BuyerRewardPoints: 100,
+		})
+		if err != nil {
+			t.Fatalf("createProduct failed: %v", err)
+		}
+		createRule(t, order.NewRewardRule{
+			Recipient: order.RecipientBuyer,
+			Kind:      order.RulePercentOfPrice,
+			Value:     10000,
+			ProductID: p.ID,
+		})
+
+		placeOrder(t, buyerID, p.ID, "")
+		if got := points(t, buyerID); got != math.MaxInt32 {
+			t.Errorf("expected the points clamped to %d, got %d", math.MaxInt32, got)
+		}
+	})
+
+	t.Run("Fail_Validation", func(t *testing.T) {
+		productID := createProduct(t)
+		tests := map[string]order.NewRewardRule{
+			"multiplier not above 100": {Recipient: order.RecipientBuyer, Kind: order.RuleMultiplier, Value: 100},
+			"multiplier above 100x":    {Recipient: order.RecipientBuyer, Kind: order.RuleMultiplier, Value: 10001},
+			"percent above 100%":       {Recipient: order.RecipientBuyer, Kind: order.RulePercentOfPrice, Value: 10001},
+			"bonus above int32":        {Recipient: order.RecipientBuyer, Kind: order.RuleBonus, Value: math.MaxInt32 + 1},
+			"unknown kind":             {Recipient: order.RecipientBuyer, Kind: "JACKPOT", Value: 10},
+			"tier for buyer":           {Recipient: order.RecipientBuyer, Kind: order.RuleBonus, Value: 10, MinAuthorSales: 100},
+			"empty tier":               {Recipient: order.RecipientAuthor, Kind: order.RuleBonus, Value: 10, MinAuthorSales: 100, MaxAuthorSales: 100},
+			"starts in the past":       {Recipient: order.RecipientBuyer, Kind: order.RuleBonus, Value: 10, StartsAt: time.Now().Add(-time.Hour).Unix()},
+			"ends before it starts":    {Recipient: order.RecipientBuyer, Kind: order.RuleBonus, Value: 10, StartsAt: time.Now().Add(time.Hour).Unix(), EndsAt: time.Now().Unix()},
+		}
+		for name, nr := range tests {
+			t.Run(name, func(t *testing.T) {
+				nr.Name, nr.CreatedBy, nr.ProductID = "Campaign", "admin", productID
+				_, err := orderService.CreateRewardRule(ctx, nr)
+				if _, ok := validate.AsFieldErrors(err); !ok {
+					t.Errorf("expected field errors, got %v", err)
+				}
+			})
+		}
+
+		_, err := orderService.CreateRewardRule(ctx, order.NewRewardRule{
+			Name: "Campaign", Recipient: order.RecipientBuyer, Kind: order.RuleBonus, Value: 10,
+			ProductID: uuid.NewString(), CreatedBy: "admin",
+		})
+		if !errors.Is(err, productstore.ErrNotFound) {
+			t.Errorf("expected ErrNotFound for a missing product, got %v", err)
+		}
+	})
+}
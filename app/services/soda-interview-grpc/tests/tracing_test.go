@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"soda-interview/foundation/logger"
+	"soda-interview/foundation/tracing"
+)
+
+func Test_Tracing(t *testing.T) {
+	ctx := context.Background()
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("Traceparent_RoundTrip", func(t *testing.T) {
+		remote := tracing.Extract(ctx, metadata.Pairs("traceparent", traceparent))
+		sc := trace.SpanContextFromContext(remote)
+		if !sc.IsSampled() || !sc.IsRemote() || sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("unexpected span context %+v", sc)
+		}
+
+		md := metadata.MD{}
+		tracing.Inject(remote, md)
+		if got := md.Get("traceparent"); len(got) != 1 || got[0] != traceparent {
+			t.Errorf("expected %s, got %v", traceparent, got)
+		}
+
+		for _, bad := range []string{
+			"",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		} {
+			if sc := trace.SpanContextFromContext(tracing.Extract(ctx, metadata.Pairs("traceparent", bad))); sc.IsValid() {
+				t.Errorf("expected %q to be rejected", bad)
+			}
+		}
+	})
+
+	t.Run("Spans_ContinueRemoteTraceAndExport", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, err := tracing.NewWriterExporter(&buf)
+		if err != nil {
+			t.Fatalf("NewWriterExporter failed: %v", err)
+		}
+		tp := tracing.New(tracing.Config{SampleRate: 0, Exporter: exporter})
+		tracer := tp.Tracer("test")
+
+		remote := trace.SpanContextFromContext(tracing.Extract(ctx, metadata.Pairs("traceparent", traceparent)))
+		ctx := trace.ContextWithRemoteSpanContext(ctx, remote)
+
+		// The caller sampled the trace, so it is recorded despite SampleRate 0.
+		ctx, server := tracer.Start(ctx, "svc/Method", trace.WithSpanKind(trace.SpanKindServer))
+		_, child := tracer.Start(ctx, "child", trace.WithAttributes(attribute.Int64("n", 3)))
+		tracing.SetError(child, errors.New("boom"))
+		child.End()
+		server.End()
+
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+
+		type spanJSON struct {
+			Name        string
+			SpanContext struct{ TraceID, SpanID string }
+			Parent      struct{ SpanID string }
+			Status      struct{ Code, Description string }
+		}
+		var spans []spanJSON
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var s spanJSON
+			if err := dec.Decode(&s); err != nil {
+				t.Fatalf("decoding span: %v", err)
+			}
+			spans = append(spans, s)
+		}
+		if len(spans) != 2 {
+			t.Fatalf("expected 2 spans, got %d", len(spans))
+		}
+
+		c, s := spans[0], spans[1]
+		if c.SpanContext.TraceID != remote.TraceID().String() || s.SpanContext.TraceID != remote.TraceID().String() {
+			t.Errorf("expected spans in the remote trace, got %v and %v", c.SpanContext.TraceID, s.SpanContext.TraceID)
+		}
+		if s.Parent.SpanID != remote.SpanID().String() || c.Parent.SpanID != s.SpanContext.SpanID {
+			t.Errorf("unexpected parentage: server %+v, child %+v", s, c)
+		}
+		if c.Status.Code != "Error" || c.Status.Description != "boom" {
+			t.Errorf("unexpected child span %+v", c)
+		}
+	})
+
+	t.Run("Spans_UnsampledNotExported", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := tracing.New(tracing.Config{SampleRate: 0, Exporter: exporter})
+
+		_, span := tp.Tracer("test").Start(ctx, "root")
+		if span.IsRecording() || !span.SpanContext().IsValid() {
+			t.Errorf("expected a valid, non-recording span")
+		}
+		span.End()
+
+		_ = tp.ForceFlush(context.Background())
+		if got := exporter.GetSpans(); len(got) != 0 {
+			t.Errorf("expected nothing exported, got %v", got)
+		}
+		_ = tp.Shutdown(context.Background())
+	})
+
+	t.Run("OTLPExporter_Payload", func(t *testing.T) {
+		var (
+			req         coltracepb.ExportTraceServiceRequest
+			path, ctype string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, ctype = r.URL.Path, r.Header.Get("Content-Type")
+			data, _ := io.ReadAll(r.Body)
+			if err := proto.Unmarshal(data, &req); err != nil {
+				t.Errorf("decoding export request: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/x-protobuf")
+		}))
+		defer srv.Close()
+
+		exporter, err := tracing.NewOTLPExporter(ctx, srv.URL)
+		if err != nil {
+			t.Fatalf("NewOTLPExporter failed: %v", err)
+		}
+		tp := tracing.New(tracing.Config{SampleRate: 1, Exporter: exporter, ServiceName: "soda-interview", ServiceVersion: "test"})
+
+		parentCtx, parent := tp.Tracer("test").Start(ctx, "svc/Method", trace.WithSpanKind(trace.SpanKindServer))
+		_, span := tp.Tracer("test").Start(parentCtx, "db GetOrder",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql"), attribute.Int64("db.rows_affected", 2)),
+		)
+		tracing.SetError(span, errors.New("boom"))
+		span.End()
+		parent.End()
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+
+		if path != "/v1/traces" || ctype != "application/x-protobuf" {
+			t.Errorf("expected protobuf POST to /v1/traces, got %q with %q", path, ctype)
+		}
+		if len(req.ResourceSpans) != 1 {
+			t.Fatalf("expected one resourceSpans entry, got %d", len(req.ResourceSpans))
+		}
+		rs := req.ResourceSpans[0]
+
+		resAttrs := make(map[string]string)
+		for _, kv := range rs.GetResource().GetAttributes() {
+			resAttrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+		}
+		if resAttrs["service.name"] != "soda-interview" || resAttrs["service.version"] != "test" {
+			t.Errorf("unexpected resource attributes %v", resAttrs)
+		}
+
+		if len(rs.ScopeSpans) != 1 || rs.ScopeSpans[0].GetScope().GetName() != "test" {
+			t.Fatalf("unexpected scopeSpans %v", rs.ScopeSpans)
+		}
+		byName := make(map[string]*tracepb.Span)
+		for _, s := range rs.ScopeSpans[0].Spans {
+			byName[s.GetName()] = s
+		}
+		server, client := byName["svc/Method"], byName["db GetOrder"]
+		if server == nil || client == nil {
+			t.Fatalf("expected both spans, got %v", byName)
+		}
+
+		// Trace and span IDs travel as raw 16- and 8-byte strings.
+		sc := span.SpanContext()
+		if len(client.TraceId) != 16 || hex.EncodeToString(client.TraceId) != sc.TraceID().String() {
+			t.Errorf("unexpected traceId %x", client.TraceId)
+		}
+		if len(client.SpanId) != 8 || hex.EncodeToString(client.SpanId) != sc.SpanID().String() {
+			t.Errorf("unexpected spanId %x", client.SpanId)
+		}
+		if !bytes.Equal(client.ParentSpanId, server.SpanId) || len(server.ParentSpanId) != 0 {
+			t.Errorf("unexpected parentage: server %x <- %x, client %x <- %x",
+				server.SpanId, server.ParentSpanId, client.SpanId, client.ParentSpanId)
+		}
+		if server.Kind != tracepb.Span_SPAN_KIND_SERVER || client.Kind != tracepb.Span_SPAN_KIND_CLIENT {
+			t.Errorf("unexpected kinds %v and %v", server.Kind, client.Kind)
+		}
+		if client.StartTimeUnixNano == 0 || client.EndTimeUnixNano < client.StartTimeUnixNano {
+			t.Errorf("unexpected times %d..%d", client.StartTimeUnixNano, client.EndTimeUnixNano)
+		}
+		if client.GetStatus().GetCode() != tracepb.Status_STATUS_CODE_ERROR || client.GetStatus().GetMessage() != "boom" {
+			t.Errorf("unexpected status %v", client.GetStatus())
+		}
+
+		attrs := make(map[string]*commonpb.AnyValue)
+		for _, kv := range client.Attributes {
+			attrs[kv.GetKey()] = kv.GetValue()
+		}
+		if attrs["db.system"].GetStringValue() != "postgresql" || attrs["db.rows_affected"].GetIntValue() != 2 {
+			t.Errorf("unexpected attributes %v", attrs)
+		}
+	})
+
+	t.Run("Logger_InjectsTraceIDs", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(&buf, "info")
+
+		tp := sdktrace.NewTracerProvider()
+		defer tp.Shutdown(context.Background())
+		spanCtx, span := tp.Tracer("test").Start(ctx, "op")
+		sc := span.SpanContext()
+
+		log.InfoContext(spanCtx, "with context")
+		log.WithContext(spanCtx).Info("bound context")
+		log.Info("no context")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 log lines, got %d", len(lines))
+		}
+		for i, line := range lines {
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("decoding log line: %v", err)
+			}
+			_, tagged := rec["trace_id"]
+			if want := i < 2; tagged != want {
+				t.Errorf("line %d: expected trace_id present=%v, got %v", i, want, rec)
+				continue
+			}
+			if tagged && (rec["trace_id"] != sc.TraceID().String() || rec["span_id"] != sc.SpanID().String()) {
+				t.Errorf("line %d: unexpected ids %v", i, rec)
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_ListTransactions(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Service
+	service := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	ctx := context.Background()
+
+	// Helpers
+	record := func(t *testing.T, userID, txType string, amount int64) {
+		_, err := fStore.Post(ctx, financestore.Posting{
+			UserID:         userID,
+			Type:           txType,
+			Amount:         amount,
+			RelatedOrderID: uuid.NewString(),
+			Entries: []financestore.Entry{
+				{Account: financestore.AdjustmentsPoints, Direction: financestore.Debit, Amount: amount},
+				{Account: financestore.UserPoints(userID), Direction: financestore.Credit, Amount: amount},
+			},
+		})
+		if err != nil {
+			t.Fatalf("post failed: %v", err)
+		}
+	}
+
+	t.Run("Success_PagesNewestFirst", func(t *testing.T) {
+		userID := uuid.NewString()
+		for i := int64(1); i <= 5; i++ {
+			record(t, userID, financestore.TxEarned, i)
+		}
+		record(t, uuid.NewString(), financestore.TxEarned, 99) // another user
+
+		var amounts []int64
+		token := ""
+		pages := 0
+		for {
+			page, err := service.ListTransactions(ctx, finance.ListTransactionsReq{
+				UserID:    userID,
+				PageToken: token,
+				PageSize:  2,
+			})
+			if err != nil {
+				t.Fatalf("ListTransactions failed: %v", err)
+			}
+			pages++
+			for _, tx := range page.Transactions {
+				amounts = append(amounts, tx.Amount)
+				if tx.RelatedOrderID == "" {
+					t.Errorf("expected related order id on %s", tx.ID)
+				}
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			token = page.NextPageToken
+		}
+
+		if pages != 3 {
+			t.Errorf("expected 3 pages, got %d", pages)
+		}
+		want := []int64{5, 4, 3, 2, 1}
+		if len(amounts) != len(want) {
+			t.Fatalf("expected %v, got %v", want, amounts)
+		}
+		for i := range want {
+			if amounts[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, amounts)
+			}
+		}
+	})
+
+	t.Run("Success_FilterByType", func(t *testing.T) {
+		userID := uuid.NewString()
+		record(t, userID, financestore.TxEarned, 100)
+		record(t, userID, financestore.TxConverted, 50)
+		record(t, userID, financestore.TxEarned, 200)
+
+		page, err := service.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID: userID,
+			Types:  []string{financestore.TxConverted},
+		})
+		if err != nil {
+			t.Fatalf("ListTransactions failed: %v", err)
+		}
+		if len(page.Transactions) != 1 || page.Transactions[0].Type != financestore.TxConverted {
+			t.Fatalf("expected one CONVERTED entry, got %+v", page.Transactions)
+		}
+		if page.NextPageToken != "" {
+			t.Errorf("expected no next page, got %q", page.NextPageToken)
+		}
+	})
+
+	t.Run("Fail_InvalidPageToken", func(t *testing.T) {
+		_, err := service.ListTransactions(ctx, finance.ListTransactionsReq{
+			UserID:    uuid.NewString(),
+			PageToken: "not-a-token",
+		})
+		if _, ok := validate.AsFieldErrors(err); !ok {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+}
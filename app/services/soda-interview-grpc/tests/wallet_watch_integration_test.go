@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/business/core/finance"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	tt "soda-interview/zarf/testing"
+)
+
+func Test_WatchWallet(t *testing.T) {
+	c := tt.NewDBContainer(t)
+	defer c.Teardown(t)
+	c.Truncate(t)
+
+	// Setup Stores
+	fStore := financestore.NewStore(c.Log, c.DB)
+	iStore := idempotencystore.NewStore(c.Log, c.DB)
+	obStore := outboxstore.NewStore(c.Log, c.DB)
+
+	// Setup Service with a running watcher
+	service := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+	watcher := financestore.NewWalletWatcher(c.Log, c.DB)
+	service.SetWalletWatcher(watcher, 200*time.Millisecond)
+
+	ctx := context.Background()
+	watcherCtx, stopWatcher := context.WithCancel(ctx)
+	watcherDone := make(chan struct{})
+	go func() {
+		watcher.Run(watcherCtx)
+		close(watcherDone)
+	}()
+	defer func() {
+		stopWatcher()
+		<-watcherDone
+	}()
+
+	// Helpers
+	type watch struct {
+		updates chan finance.WalletUpdate
+		done    chan error
+		cancel  context.CancelFunc
+	}
+
+	startWatch := func(t *testing.T, userID string) *watch {
+		ctx, cancel := context.WithCancel(ctx)
+		w := &watch{updates: make(chan finance.WalletUpdate, 16), done: make(chan error, 1), cancel: cancel}
+		go func() {
+			w.done <- service.WatchWallet(ctx, userID, func(u finance.WalletUpdate) error {
+				w.updates <- u
+				return nil
+			})
+		}()
+		t.Cleanup(cancel)
+		return w
+	}
+
+	// nextWallet skips heartbeats and returns the next wallet sent.
+	nextWallet := func(t *testing.T, w *watch) finance.Wallet {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case u := <-w.updates:
+				if !u.Heartbeat {
+					return u.Wallet
+				}
+			case <-timeout:
+				t.Fatal("timed out waiting for a wallet update")
+			}
+		}
+	}
+
+	t.Run("Success_InitialWalletAndUpdates", func(t *testing.T) {
+		userID := uuid.NewString()
+		w := startWatch(t, userID)
+
+		if got := nextWallet(t, w); got != (finance.Wallet{UserID: userID}) {
+			t.Errorf("expected an empty wallet first, got %+v", got)
+		}
+
+		if _, err := fStore.Earn(ctx, userID, 2000, uuid.NewString()); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+		if got := nextWallet(t, w); got.SodaPoints != 2000 {
+			t.Errorf("expected 2000 points after earning, got %+v", got)
+		}
+
+		if _, err := service.ConvertPoints(ctx, userID, 1000); err != nil {
+			t.Fatalf("ConvertPoints failed: %v", err)
+		}
+		if got := nextWallet(t, w); got.SodaPoints != 1000 || got.SodaBalance != 500 {
+			t.Errorf("expected 1000 points and 500 yen after converting, got %+v", got)
+		}
+	})
+
+	t.Run("Success_OtherUsersAndRollbacksAreSilent", func(t *testing.T) {
+		userID := uuid.NewString()
+		w := startWatch(t, userID)
+		nextWallet(t, w)
+
+		if _, err := fStore.Earn(ctx, uuid.NewString(), 500, uuid.NewString()); err != nil {
+			t.Fatalf("earn failed: %v", err)
+		}
+
+		tx, err := c.DB.Begin(ctx)
+		if err != nil {
+			t.Fatalf("begin failed: %v", err)
+		}
+		if _, err := fStore.WithTx(tx).Earn(ctx, userID, 500, uuid.NewString()); err != nil {
+			t.Fatalf("earn in tx failed: %v", err)
+		}
+		if err := tx.Rollback(ctx); err != nil {
+			t.Fatalf("rollback failed: %v", err)
+		}
+
+		select {
+		case u := <-w.updates:
+			if !u.Heartbeat {
+				t.Errorf("expected no wallet update, got %+v", u.Wallet)
+			}
+		case <-time.After(time.Second):
+			t.Error("expected a heartbeat on an idle watch")
+		}
+	})
+
+	t.Run("Success_EndsWhenClientLeaves", func(t *testing.T) {
+		w := startWatch(t, uuid.NewString())
+		nextWallet(t, w)
+
+		w.cancel()
+		select {
+		case err := <-w.done:
+			if err != nil {
+				t.Errorf("expected a clean end, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("watch did not end after cancel")
+		}
+	})
+
+	t.Run("Fail_NoWatcher", func(t *testing.T) {
+		plain := finance.NewService(c.Log, c.DB, fStore, iStore, obStore)
+		err := plain.WatchWallet(ctx, uuid.NewString(), func(finance.WalletUpdate) error { return nil })
+		if !errors.Is(err, finance.ErrWatchUnavailable) {
+			t.Errorf("expected ErrWatchUnavailable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Command reconcile checks that every wallet matches the ledger and that every
+// ledger transaction balances, and reports any discrepancies.
+//
+// Usage:
+//
+//	go run ./app/tooling/reconcile [-env local] [-dsn DSN] [-format json|csv] [-out FILE] [-repair]
+//
+// With -repair, wallets that drifted from the ledger get an ADJUSTMENT
+// transaction for the difference, all inside one database transaction. The
+// command exits with status 2 when discrepancies remain unrepaired so a
+// nightly job can alert on it.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"soda-interview/business/core/finance"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/config"
+	"soda-interview/foundation/database/postgres"
+	"soda-interview/foundation/logger"
+)
+
+// errDiscrepancies signals that the report is not clean.
+var errDiscrepancies = errors.New("discrepancies found")
+
+func main() {
+	if err := run(); err != nil {
+		if errors.Is(err, errDiscrepancies) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type report struct {
+	CheckedAt  time.Time                       `json:"checked_at"`
+	Repaired   bool                            `json:"repaired"`
+	Drift      []finance.WalletDrift           `json:"drift"`
+	Unbalanced []finance.UnbalancedTransaction `json:"unbalanced"`
+}
+
+func run() error {
+	env := flag.String("env", "local", "config environment to load")
+	dsn := flag.String("dsn", "", "database DSN; overrides the config, e.g. to point at a snapshot")
+	format := flag.String("format", "json", "report format: json or csv")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	repair := flag.Bool("repair", false, "write ADJUSTMENT transactions for drifted wallets")
+	timeout := flag.Duration("timeout", 10*time.Minute, "overall time limit")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	// The report goes to stdout, so logs go to stderr.
+	log := logger.New(os.Stderr, "INFO")
+
+	if *dsn == "" {
+		_, b, _, _ := runtime.Caller(0)
+		projectRoot := filepath.Join(filepath.Dir(b), "../../../")
+		configPath := filepath.Join(projectRoot, "foundation/config")
+
+		cfg, err := config.LoadWithPath(configPath, *env)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		*dsn = cfg.GetDatabaseDSN()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbPool, err := postgres.New(ctx, *dsn)
+	if err != nil {
+		return fmt.Errorf("connecting to db: %w", err)
+	}
+	defer dbPool.Close()
+
+	fStore := financestore.NewStore(log, dbPool)
+	iStore := idempotencystore.NewStore(log, dbPool)
+	obStore := outboxstore.NewStore(log, dbPool)
+	service := finance.NewService(log, dbPool, fStore, iStore, obStore)
+
+	log.InfoContext(ctx, "Starting reconciliation...", "repair", *repair)
+
+	r := report{CheckedAt: time.Now().UTC()}
+	lr, err := service.VerifyLedger(ctx)
+	if err != nil {
+		return fmt.Errorf("verifying ledger: %w", err)
+	}
+	r.Drift, r.Unbalanced = lr.Drift, lr.Unbalanced
+
+	if *repair && len(r.Drift) > 0 {
+		repaired, err := service.RepairDrift(ctx)
+		if err != nil {
+			return fmt.Errorf("repairing drift: %w", err)
+		}
+		r.Drift, r.Repaired = repaired, true
+		log.InfoContext(ctx, "Wrote adjustments", "wallets", len(repaired))
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("creating report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		err = writeJSON(w, r)
+	case "csv":
+		err = writeCSV(w, r)
+	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	log.InfoContext(ctx, "Reconciliation completed", "drift", len(r.Drift), "unbalanced", len(r.Unbalanced))
+
+	if len(r.Unbalanced) > 0 || (len(r.Drift) > 0 && !r.Repaired) {
+		return errDiscrepancies
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, r report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// writeCSV emits one row per discrepancy: wallet drift yields a row per
+// currency that differs, and an unbalanced transaction a row per currency
+// whose entries do not net to zero.
+func writeCSV(w io.Writer, r report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"kind", "subject", "currency", "cached", "ledger", "difference", "repaired"}); err != nil {
+		return err
+	}
+
+	itoa := func(n int64) string { return strconv.FormatInt(n, 10) }
+	repaired := strconv.FormatBool(r.Repaired)
+
+	for _, d := range r.Drift {
+		if d.SodaPoints != d.LedgerPoints {
+			if err := cw.Write([]string{"WALLET_DRIFT", d.UserID, financestore.CurrencyPoints,
+				itoa(d.SodaPoints), itoa(d.LedgerPoints), itoa(d.SodaPoints - d.LedgerPoints), repaired}); err != nil {
+				return err
+			}
+		}
+		if d.SodaBalance != d.LedgerBalance {
+			if err := cw.Write([]string{"WALLET_DRIFT", d.UserID, financestore.CurrencyYen,
+				itoa(d.SodaBalance), itoa(d.LedgerBalance), itoa(d.SodaBalance - d.LedgerBalance), repaired}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, u := range r.Unbalanced {
+		if err := cw.Write([]string{"UNBALANCED_TRANSACTION", u.TransactionID, u.Currency,
+			"0", itoa(u.Net), itoa(u.Net), "false"}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -10,6 +10,7 @@ import (
 
 	"soda-interview/business/core/referral-blog"
 	"soda-interview/business/data/stores/db"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	blogstore "soda-interview/business/data/stores/referral-blog"
 	"soda-interview/foundation/config"
 	"soda-interview/foundation/database/postgres"
@@ -45,7 +46,7 @@ func run() error {
 	defer dbPool.Close()
 
 	bStore := blogstore.NewStore(log, dbPool)
-	_ = referralblog.NewService(log, bStore)
+	_ = referralblog.NewService(log, dbPool, bStore, outboxstore.NewStore(log, dbPool))
 
 	// Hardcoded data matching the SQL generation plan for consistency
 	blogs := []struct {
@@ -79,6 +80,7 @@ func run() error {
 			AuthorID:  b.AuthorID,
 			Content:   b.Content,
 			ProductID: b.ProductID,
+			Status:    blogstore.StatusPublished,
 		})
 		if err != nil {
 			return fmt.Errorf("creating blog %s: %w", b.ID, err)
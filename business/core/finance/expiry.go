@@ -0,0 +1,141 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"soda-interview/foundation/database/postgres"
+	"soda-interview/foundation/logger"
+	"soda-interview/foundation/validate"
+)
+
+// DefaultExpiryWindow is how far ahead GetExpiringPoints looks when the
+// caller gives no bound.
+const DefaultExpiryWindow = 30 * 24 * time.Hour
+
+// expiryLockKey is the Postgres advisory lock that elects the replica running
+// the expiry sweep.
+const expiryLockKey int64 = 0x736f64610001 // "soda" 1
+
+// PointLot is points earned in one transaction that have not been spent.
+type PointLot struct {
+	TransactionID string
+	Remaining     int64
+	ExpiresAt     int64 // Unix timestamp
+}
+
+type ExpiringPoints struct {
+	Total int64
+	Lots  []PointLot // Soonest first.
+}
+
+// GetExpiringPoints returns the user's points that expire before the given
+// Unix timestamp. Zero means DefaultExpiryWindow from now.
+func (s *Service) GetExpiringPoints(ctx context.Context, userID string, before int64) (ExpiringPoints, error) {
+	var fe validate.FieldErrors
+	if userID == "" {
+		fe.Add("user_id", "is required")
+	}
+	if before < 0 {
+		fe.Add("before", "must not be negative")
+	}
+	if err := fe.Err(); err != nil {
+		return ExpiringPoints{}, err
+	}
+
+	until := time.Now().Add(DefaultExpiryWindow)
+	if before > 0 {
+		until = time.Unix(before, 0)
+	}
+
+	rows, err := s.store.ListExpiringPointLots(ctx, userID, until)
+	if err != nil {
+		return ExpiringPoints{}, err
+	}
+
+	ep := ExpiringPoints{Lots: make([]PointLot, len(rows))}
+	for i, l := range rows {
+		ep.Lots[i] = PointLot{
+			TransactionID: l.TransactionID,
+			Remaining:     l.Remaining,
+			ExpiresAt:     l.ExpiresAt.Time.Unix(),
+		}
+		ep.Total += l.Remaining
+	}
+	return ep, nil
+}
+
+// ExpirePoints expires every lot due by now, batchSize lots at a time, and
+// returns the points written off. Each lot expires in its own transaction so
+// a failure leaves the lots already done in place.
+func (s *Service) ExpirePoints(ctx context.Context, now time.Time, batchSize int) (int64, error) {
+	var total int64
+	for {
+		ids, err := s.store.ListDuePointLots(ctx, now, int32(batchSize))
+		if err != nil {
+			return total, err
+		}
+		for _, id := range ids {
+			n, err := s.store.ExpireLot(ctx, id)
+			if err != nil {
+				return total, fmt.Errorf("expiring lot %d: %w", id, err)
+			}
+			total += n
+			pointsExpired.Add(float64(n))
+		}
+		if len(ids) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// ExpirySweeper periodically expires due points. Every replica may run one;
+// a Postgres advisory lock lets only one of them sweep at a time.
+type ExpirySweeper struct {
+	log       *logger.Logger
+	service   *Service
+	interval  time.Duration
+	batchSize int
+}
+
+func NewExpirySweeper(log *logger.Logger, service *Service, interval time.Duration, batchSize int) *ExpirySweeper {
+	return &ExpirySweeper{
+		log:       log,
+		service:   service,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run sweeps every interval until ctx is done.
+func (w *ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ExpirySweeper) sweep(ctx context.Context) {
+	var expired int64
+	leader, err := postgres.WithAdvisoryLock(ctx, w.service.pool, expiryLockKey, func(ctx context.Context) error {
+		var err error
+		expired, err = w.service.ExpirePoints(ctx, time.Now(), w.batchSize)
+		return err
+	})
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil:
+		w.log.Error("Points expiry sweep failed", "error", err, "expired_points", expired)
+	case leader && expired > 0:
+		w.log.Info("Expired points", "points", expired)
+	}
+}
@@ -2,16 +2,22 @@ package finance
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	sodafinance "soda-interview/business/data/stores/soda-finance"
 	"soda-interview/foundation/logger"
+	eventsv1 "soda-interview/foundation/proto/events/v1"
+	"soda-interview/foundation/tracing"
+	"soda-interview/foundation/validate"
 
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -19,6 +25,9 @@ var (
 	ErrInsufficientPoints = errors.New("insufficient points")
 )
 
+// opConvertPoints names the operation recorded against idempotency keys.
+const opConvertPoints = "finance.ConvertPoints"
+
 type Wallet struct {
 	UserID      string
 	SodaPoints  int64
@@ -26,23 +35,37 @@ type Wallet struct {
 }
 
 type Service struct {
-	log *logger.Logger
-	pool *pgxpool.Pool
-	store *sodafinance.Store
+	log              *logger.Logger
+	pool             *pgxpool.Pool
+	store            *sodafinance.Store
+	idempotencyStore *idempotencystore.Store
+	outboxStore      *outboxstore.Store
+	watcher          *sodafinance.WalletWatcher
+	heartbeat        time.Duration
 }
 
-func NewService(log *logger.Logger, pool *pgxpool.Pool, store *sodafinance.Store) *Service {
+func NewService(log *logger.Logger, pool *pgxpool.Pool, store *sodafinance.Store, idempotencyStore *idempotencystore.Store, outboxStore *outboxstore.Store) *Service {
 	return &Service{
-		log:  log,
-		pool: pool,
-		store: store,
+		log:              log,
+		pool:             pool,
+		store:            store,
+		idempotencyStore: idempotencyStore,
+		outboxStore:      outboxStore,
 	}
 }
 
 func (s *Service) GetWallet(ctx context.Context, userID string) (Wallet, error) {
+	if userID == "" {
+		var fe validate.FieldErrors
+		fe.Add("user_id", "is required")
+		return Wallet{}, fe
+	}
+
 	w, err := s.store.GetWallet(ctx, userID)
 	if err != nil {
-		// handle specific store errors if needed
+		if errors.Is(err, sodafinance.ErrNotFound) {
+			return Wallet{}, ErrNotFound
+		}
 		return Wallet{}, fmt.Errorf("querying wallet: %w", err)
 	}
 	return toWallet(w), nil
@@ -56,7 +79,39 @@ func (s *Service) EnsureWalletExists(ctx context.Context, userID string) error {
 	return nil
 }
 
+// ConvertPoints exchanges points for yen under the conversion policy in force.
+// Zero pointsToConvert converts as many points as the policy allows.
 func (s *Service) ConvertPoints(ctx context.Context, userID string, pointsToConvert int64) (Wallet, error) {
+	return s.ConvertPointsIdempotent(ctx, userID, pointsToConvert, "")
+}
+
+// ConvertPointsIdempotent behaves like ConvertPoints. When idempotencyKey is
+// set, a retry with the same key returns the wallet from the original
+// conversion instead of converting again.
+func (s *Service) ConvertPointsIdempotent(ctx context.Context, userID string, pointsToConvert int64, idempotencyKey string) (_ Wallet, err error) {
+	ctx, span := tracing.Start(ctx, opConvertPoints,
+		attribute.String("finance.user_id", userID),
+		attribute.Int64("finance.points_requested", pointsToConvert),
+	)
+	defer func() {
+		tracing.SetError(span, err)
+		span.End()
+	}()
+
+	var fe validate.FieldErrors
+	if userID == "" {
+		fe.Add("user_id", "is required")
+	}
+	if pointsToConvert < 0 {
+		fe.Add("points_to_convert", "must not be negative")
+	}
+	if len(idempotencyKey) > idempotencystore.MaxKeyLength {
+		fe.Add("idempotency_key", fmt.Sprintf("must be at most %d characters", idempotencystore.MaxKeyLength))
+	}
+	if err := fe.Err(); err != nil {
+		return Wallet{}, err
+	}
+
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return Wallet{}, fmt.Errorf("beginning transaction: %w", err)
@@ -65,62 +120,109 @@ func (s *Service) ConvertPoints(ctx context.Context, userID string, pointsToConv
 
 	// Use the transactional store
 	txStore := s.store.WithTx(tx)
+	txIdempotency := s.idempotencyStore.WithTx(tx)
 
-	w, err := txStore.GetWallet(ctx, userID)
+	if idempotencyKey != "" {
+		resp, replay, err := txIdempotency.Begin(ctx, userID, idempotencyKey, opConvertPoints,
+			idempotencystore.Hash(fmt.Sprint(pointsToConvert)), idempotencystore.DefaultTTL)
+		if err != nil {
+			return Wallet{}, fmt.Errorf("checking idempotency key: %w", err)
+		}
+		if replay {
+			var w Wallet
+			if err := json.Unmarshal(resp, &w); err != nil {
+				return Wallet{}, fmt.Errorf("decoding stored wallet: %w", err)
+			}
+			span.SetAttributes(attribute.Bool("idempotency.replay", true))
+			return w, nil
+		}
+	}
+
+	// Lock the wallet first so concurrent conversions see each other's
+	// totals when checking the daily cap.
+	w, err := txStore.GetWalletForUpdate(ctx, userID)
 	if err != nil {
+		if errors.Is(err, sodafinance.ErrNotFound) {
+			return Wallet{}, ErrNotFound
+		}
 		return Wallet{}, fmt.Errorf("getting wallet: %w", err)
 	}
 
-	if w.SodaPoints <= 1000 {
-		return Wallet{}, fmt.Errorf("%w: must have > 1000 points, have %d", ErrInsufficientPoints, w.SodaPoints)
+	dbPolicy, err := txStore.GetActiveConversionPolicy(ctx)
+	if err != nil {
+		if errors.Is(err, sodafinance.ErrNoPolicy) {
+			return Wallet{}, ErrNoPolicy
+		}
+		return Wallet{}, err
 	}
+	policy := toPolicy(dbPolicy)
 
-	amount := pointsToConvert
-	if amount <= 0 {
-		amount = w.SodaPoints
-	}
-	
-	if amount > w.SodaPoints {
-		return Wallet{}, fmt.Errorf("%w: requesting %d, have %d", ErrInsufficientPoints, amount, w.SodaPoints)
-	}
-	
-	yen := amount / 2
-	pointsDeducted := yen * 2
-	
-	if pointsDeducted == 0 {
-		if err := tx.Commit(ctx); err != nil {
-			return Wallet{}, fmt.Errorf("commit: %w", err)
+	var convertedToday int64
+	if policy.DailyCapPoints > 0 {
+		convertedToday, err = txStore.SumConvertedPointsSince(ctx, userID, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			return Wallet{}, err
 		}
-		return toWallet(w), nil
 	}
 
-	updatedW, err := txStore.ConvertPointsToBalance(ctx, db.ConvertPointsToBalanceParams{
-		PointsDeducted: pointsDeducted,
-		BalanceAdded:   yen,
-		UserID:         userID,
-	})
+	pointsDeducted, yen, err := policy.quote(w.SodaPoints, pointsToConvert, convertedToday)
 	if err != nil {
+		return Wallet{}, err
+	}
+
+	updatedW, err := txStore.Convert(ctx, userID, pointsDeducted, yen, policy.Version)
+	if err != nil {
+		if errors.Is(err, sodafinance.ErrInsufficientPoints) {
+			return Wallet{}, fmt.Errorf("%w: balance changed during conversion", ErrInsufficientPoints)
+		}
 		return Wallet{}, fmt.Errorf("converting points: %w", err)
 	}
 
-	_, err = txStore.CreateTransaction(ctx, db.CreateTransactionParams{
-		ID:             uuid.NewString(),
-		UserID:         userID,
-		Type:           "CONVERTED",
-		Amount:         pointsDeducted,
-		RelatedOrderID: pgtype.Text{Valid: false},
+	err = s.outboxStore.WithTx(tx).Append(ctx, userID, &eventsv1.PointsConverted{
+		UserId:        userID,
+		Points:        pointsDeducted,
+		Yen:           yen,
+		PolicyVersion: policy.Version,
 	})
 	if err != nil {
-		return Wallet{}, fmt.Errorf("creating transaction log: %w", err)
+		return Wallet{}, err
+	}
+
+	if err := complete(ctx, txIdempotency, userID, idempotencyKey, toWallet(updatedW)); err != nil {
+		return Wallet{}, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return Wallet{}, fmt.Errorf("committing transaction: %w", err)
 	}
-	
+
+	span.SetAttributes(
+		attribute.Int64("finance.points_converted", pointsDeducted),
+		attribute.Int64("finance.yen", yen),
+		attribute.Int64("finance.policy_version", policy.Version),
+	)
+	pointsConverted.Add(float64(pointsDeducted))
+	yenConverted.Add(float64(yen))
+
 	return toWallet(updatedW), nil
 }
 
+// complete stores w as the response for idempotencyKey. It is a no-op when the
+// request carried no key.
+func complete(ctx context.Context, store *idempotencystore.Store, userID, idempotencyKey string, w Wallet) error {
+	if idempotencyKey == "" {
+		return nil
+	}
+	resp, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("encoding wallet: %w", err)
+	}
+	if err := store.Complete(ctx, userID, idempotencyKey, resp); err != nil {
+		return fmt.Errorf("storing idempotent response: %w", err)
+	}
+	return nil
+}
+
 func toWallet(w db.Wallet) Wallet {
 	return Wallet{
 		UserID:      w.UserID,
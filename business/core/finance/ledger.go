@@ -0,0 +1,118 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+
+	"soda-interview/business/data/stores/db"
+)
+
+// WalletDrift is a wallet whose cached totals disagree with its ledger
+// accounts.
+type WalletDrift struct {
+	UserID        string
+	SodaPoints    int64
+	SodaBalance   int64
+	LedgerPoints  int64
+	LedgerBalance int64
+}
+
+// UnbalancedTransaction is a transaction whose entries do not net to zero in
+// Currency.
+type UnbalancedTransaction struct {
+	TransactionID string
+	Currency      string
+	Net           int64
+}
+
+type LedgerReport struct {
+	Drift      []WalletDrift
+	Unbalanced []UnbalancedTransaction
+}
+
+// OK reports whether the ledger balances and every wallet matches it.
+func (r LedgerReport) OK() bool {
+	return len(r.Drift) == 0 && len(r.Unbalanced) == 0
+}
+
+// VerifyLedger checks that every transaction balances and that the cached
+// wallets equal the sum of their ledger accounts.
+func (s *Service) VerifyLedger(ctx context.Context) (LedgerReport, error) {
+	drift, err := s.store.ListWalletDrift(ctx)
+	if err != nil {
+		return LedgerReport{}, fmt.Errorf("checking wallets: %w", err)
+	}
+	unbalanced, err := s.store.ListUnbalancedTransactions(ctx)
+	if err != nil {
+		return LedgerReport{}, fmt.Errorf("checking transactions: %w", err)
+	}
+
+	var r LedgerReport
+	for _, d := range drift {
+		r.Drift = append(r.Drift, toWalletDrift(d))
+	}
+	for _, u := range unbalanced {
+		r.Unbalanced = append(r.Unbalanced, UnbalancedTransaction{
+			TransactionID: u.TransactionID,
+			Currency:      u.Currency,
+			Net:           u.Net,
+		})
+	}
+	return r, nil
+}
+
+// RepairDrift records an ADJUSTMENT for every wallet that disagrees with the
+// ledger, so the ledger explains the cached totals. Postings are blocked while
+// it runs and the adjustments commit together. It returns the drift repaired.
+func (s *Service) RepairDrift(ctx context.Context) ([]WalletDrift, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := s.store.WithTx(tx)
+	if err := txStore.LockLedger(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := txStore.ListWalletDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checking wallets: %w", err)
+	}
+
+	drift := make([]WalletDrift, 0, len(rows))
+	for _, d := range rows {
+		pointsDelta := d.SodaPoints - d.LedgerPoints
+		balanceDelta := d.SodaBalance - d.LedgerBalance
+		if err := txStore.RecordAdjustment(ctx, d.UserID, pointsDelta, balanceDelta); err != nil {
+			return nil, fmt.Errorf("adjusting %s: %w", d.UserID, err)
+		}
+		drift = append(drift, toWalletDrift(d))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return drift, nil
+}
+
+// RebuildWallets overwrites every cached wallet with its ledger totals and
+// returns how many wallets were rewritten.
+func (s *Service) RebuildWallets(ctx context.Context) (int64, error) {
+	n, err := s.store.RebuildWallets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("rebuilding wallets: %w", err)
+	}
+	return n, nil
+}
+
+func toWalletDrift(d db.ListWalletDriftRow) WalletDrift {
+	return WalletDrift{
+		UserID:        d.UserID,
+		SodaPoints:    d.SodaPoints,
+		SodaBalance:   d.SodaBalance,
+		LedgerPoints:  d.LedgerPoints,
+		LedgerBalance: d.LedgerBalance,
+	}
+}
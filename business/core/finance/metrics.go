@@ -0,0 +1,9 @@
+package finance
+
+import "soda-interview/foundation/metrics"
+
+var (
+	pointsConverted = metrics.NewCounter("soda_points_converted_total", "Soda Points converted to Soda Balance.")
+	yenConverted    = metrics.NewCounter("soda_balance_converted_yen_total", "Soda Balance (yen) credited by conversions.")
+	pointsExpired   = metrics.NewCounter("soda_points_expired_total", "Soda Points written off by the expiry sweep.")
+)
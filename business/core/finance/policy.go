@@ -0,0 +1,204 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"soda-interview/business/data/stores/db"
+	sodafinance "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrNoPolicy is returned when no conversion policy is in force.
+var ErrNoPolicy = errors.New("no conversion policy in force")
+
+// ErrDailyCapExceeded is returned when a conversion would take the user past
+// the policy's daily cap.
+var ErrDailyCapExceeded = errors.New("daily conversion cap exceeded")
+
+// Policy is a version of the points-to-yen conversion rules. Policies are
+// never edited; a change is a new version with a later EffectiveFrom.
+type Policy struct {
+	Version int64
+	// PointsPerYen is the number of points exchanged for one yen.
+	PointsPerYen int64
+	// MinBalance is the smallest soda_points balance allowed to convert.
+	MinBalance int64
+	// MinPoints and MaxPoints bound a single conversion. MaxPoints is zero
+	// when there is no upper bound.
+	MinPoints int64
+	MaxPoints int64
+	// DailyCapPoints bounds the points a user may convert per UTC day. Zero
+	// means no cap.
+	DailyCapPoints int64
+	EffectiveFrom  int64 // Unix timestamp
+	CreatedBy      string
+	CreatedAt      int64 // Unix timestamp
+}
+
+// NewPolicy is a policy to schedule. A zero EffectiveFrom means now.
+type NewPolicy struct {
+	PointsPerYen   int64
+	MinBalance     int64
+	MinPoints      int64
+	MaxPoints      int64
+	DailyCapPoints int64
+	EffectiveFrom  int64
+	CreatedBy      string
+}
+
+func (p NewPolicy) Validate() error {
+	var fe validate.FieldErrors
+	if p.PointsPerYen <= 0 {
+		fe.Add("points_per_yen", "must be positive")
+	}
+	if p.MinBalance < 0 {
+		fe.Add("min_balance", "must not be negative")
+	}
+	if p.MinPoints <= 0 {
+		fe.Add("min_points", "must be positive")
+	}
+	if p.MaxPoints < 0 {
+		fe.Add("max_points", "must not be negative")
+	} else if p.MaxPoints > 0 && p.MaxPoints < p.MinPoints {
+		fe.Add("max_points", "must not be less than min_points")
+	}
+	if p.DailyCapPoints < 0 {
+		fe.Add("daily_cap_points", "must not be negative")
+	}
+	if p.EffectiveFrom < 0 {
+		fe.Add("effective_from", "must not be negative")
+	}
+	if p.CreatedBy == "" {
+		fe.Add("created_by", "is required")
+	}
+	return fe.Err()
+}
+
+// SchedulePolicy records a new policy version. It takes effect at
+// EffectiveFrom, which may not be in the past, so conversions already made
+// keep the version they were priced under.
+func (s *Service) SchedulePolicy(ctx context.Context, np NewPolicy) (Policy, error) {
+	if err := np.Validate(); err != nil {
+		return Policy{}, err
+	}
+
+	now := time.Now()
+	effectiveFrom := now
+	if np.EffectiveFrom > 0 {
+		effectiveFrom = time.Unix(np.EffectiveFrom, 0)
+		if effectiveFrom.Before(now.Truncate(time.Second)) {
+			var fe validate.FieldErrors
+			fe.Add("effective_from", "must not be in the past")
+			return Policy{}, fe
+		}
+	}
+
+	p, err := s.store.CreateConversionPolicy(ctx, db.CreateConversionPolicyParams{
+		PointsPerYen:   np.PointsPerYen,
+		MinBalance:     np.MinBalance,
+		MinPoints:      np.MinPoints,
+		MaxPoints:      pgtype.Int8{Int64: np.MaxPoints, Valid: np.MaxPoints > 0},
+		DailyCapPoints: pgtype.Int8{Int64: np.DailyCapPoints, Valid: np.DailyCapPoints > 0},
+		EffectiveFrom:  pgtype.Timestamptz{Time: effectiveFrom, Valid: true},
+		CreatedBy:      np.CreatedBy,
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+	return toPolicy(p), nil
+}
+
+// ActivePolicy returns the policy in force now.
+func (s *Service) ActivePolicy(ctx context.Context) (Policy, error) {
+	p, err := s.store.GetActiveConversionPolicy(ctx)
+	if err != nil {
+		if errors.Is(err, sodafinance.ErrNoPolicy) {
+			return Policy{}, ErrNoPolicy
+		}
+		return Policy{}, err
+	}
+	return toPolicy(p), nil
+}
+
+// ListPolicies returns every policy version, scheduled ones included, latest
+// effective first.
+func (s *Service) ListPolicies(ctx context.Context) ([]Policy, error) {
+	rows, err := s.store.ListConversionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]Policy, len(rows))
+	for i, p := range rows {
+		policies[i] = toPolicy(p)
+	}
+	return policies, nil
+}
+
+// quote works out how many points a conversion takes and the yen it pays
+// under p. requested is zero to convert as much as the policy allows;
+// convertedToday is what the user has already converted this UTC day.
+// A requested amount is converted exactly or rejected, never rounded down.
+func (p Policy) quote(balance, requested, convertedToday int64) (points, yen int64, err error) {
+	if balance < p.MinBalance {
+		return 0, 0, fmt.Errorf("%w: need at least %d points to convert, have %d", ErrInsufficientPoints, p.MinBalance, balance)
+	}
+
+	if requested > 0 {
+		var fe validate.FieldErrors
+		switch {
+		case requested < p.MinPoints:
+			fe.Add("points_to_convert", fmt.Sprintf("must be at least %d", p.MinPoints))
+		case p.MaxPoints > 0 && requested > p.MaxPoints:
+			fe.Add("points_to_convert", fmt.Sprintf("must be at most %d", p.MaxPoints))
+		case requested%p.PointsPerYen != 0:
+			fe.Add("points_to_convert", fmt.Sprintf("must be a multiple of %d", p.PointsPerYen))
+		}
+		if err := fe.Err(); err != nil {
+			return 0, 0, err
+		}
+		if requested > balance {
+			return 0, 0, fmt.Errorf("%w: requesting %d, have %d", ErrInsufficientPoints, requested, balance)
+		}
+		if p.DailyCapPoints > 0 && convertedToday+requested > p.DailyCapPoints {
+			return 0, 0, fmt.Errorf("%w: %d of %d points left today", ErrDailyCapExceeded, max(p.DailyCapPoints-convertedToday, 0), p.DailyCapPoints)
+		}
+		return requested, requested / p.PointsPerYen, nil
+	}
+
+	points = balance
+	if p.MaxPoints > 0 {
+		points = min(points, p.MaxPoints)
+	}
+	if p.DailyCapPoints > 0 {
+		left := p.DailyCapPoints - convertedToday
+		if left <= 0 {
+			return 0, 0, fmt.Errorf("%w: %d points already converted today", ErrDailyCapExceeded, convertedToday)
+		}
+		points = min(points, left)
+	}
+	// Whole yen only; the remainder stays in the wallet.
+	points -= points % p.PointsPerYen
+	if points < p.MinPoints {
+		return 0, 0, fmt.Errorf("%w: can convert %d points, need at least %d", ErrInsufficientPoints, points, p.MinPoints)
+	}
+	return points, points / p.PointsPerYen, nil
+}
+
+func toPolicy(p db.ConversionPolicy) Policy {
+	return Policy{
+		Version:        p.Version,
+		PointsPerYen:   p.PointsPerYen,
+		MinBalance:     p.MinBalance,
+		MinPoints:      p.MinPoints,
+		MaxPoints:      p.MaxPoints.Int64,
+		DailyCapPoints: p.DailyCapPoints.Int64,
+		EffectiveFrom:  p.EffectiveFrom.Time.Unix(),
+		CreatedBy:      p.CreatedBy,
+		CreatedAt:      p.CreatedAt.Time.Unix(),
+	}
+}
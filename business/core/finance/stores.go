@@ -9,10 +9,8 @@ import (
 type Storer interface {
 	GetWallet(ctx context.Context, userID string) (db.Wallet, error)
 	CreateWallet(ctx context.Context, userID string) (db.Wallet, error)
-	AddPoints(ctx context.Context, params db.AddPointsParams) (db.Wallet, error)
-	AddBalance(ctx context.Context, params db.AddBalanceParams) (db.Wallet, error)
-	ConvertPointsToBalance(ctx context.Context, params db.ConvertPointsToBalanceParams) (db.Wallet, error)
-	CreateTransaction(ctx context.Context, params db.CreateTransactionParams) (db.Transaction, error)
+	Convert(ctx context.Context, userID string, points, yen, policyVersion int64) (db.Wallet, error)
+	Adjust(ctx context.Context, userID string, pointsDelta, balanceDelta int64) (db.Wallet, error)
 	
 	// WithTx returns a version of the store that runs in the transaction.
 	// For interface compliance, we might return 'Storer' or use a specific mechanism.
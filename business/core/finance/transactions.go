@@ -0,0 +1,142 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"soda-interview/business/data/stores/db"
+	"soda-interview/foundation/paging"
+	"soda-interview/foundation/validate"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Page size bounds for ListTransactions.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// transactionsOrder is the only sort ListTransactions has; page tokens carry
+// it like those of other lists.
+const transactionsOrder = "created_at desc"
+
+// Transaction is a single entry in a wallet's history. Amount is always
+// positive; Type says which way the wallet moved.
+type Transaction struct {
+	ID             string
+	UserID         string
+	Type           string
+	Amount         int64
+	RelatedOrderID string  // Empty when the entry is not tied to an order.
+	PolicyVersion  int64   // Conversion policy applied to CONVERTED entries, zero otherwise.
+	RewardRuleIDs  []int64 // Reward rules that added to EARNED entries.
+	CreatedAt      int64
+}
+
+type ListTransactionsReq struct {
+	UserID string
+	// Types restricts the result to these transaction types. Empty means all.
+	Types []string
+	// CreatedFrom and CreatedTo bound created_at as Unix timestamps, from
+	// inclusive and to exclusive. Zero leaves that side open.
+	CreatedFrom int64
+	CreatedTo   int64
+	PageToken   string
+	PageSize    int
+}
+
+func (r ListTransactionsReq) Validate() error {
+	var fe validate.FieldErrors
+	if r.UserID == "" {
+		fe.Add("user_id", "is required")
+	}
+	if r.CreatedFrom < 0 {
+		fe.Add("created_from", "must not be negative")
+	}
+	if r.CreatedTo < 0 {
+		fe.Add("created_to", "must not be negative")
+	}
+	if r.CreatedFrom > 0 && r.CreatedTo > 0 && r.CreatedTo <= r.CreatedFrom {
+		fe.Add("created_to", "must be after created_from")
+	}
+	if r.PageSize < 0 || r.PageSize > MaxPageSize {
+		fe.Add("page_size", fmt.Sprintf("must be between 0 and %d", MaxPageSize))
+	}
+	if r.PageToken != "" {
+		if c, err := paging.Parse(r.PageToken); err != nil || c.OrderBy != transactionsOrder {
+			fe.Add("page_token", "is invalid")
+		}
+	}
+	return fe.Err()
+}
+
+type TransactionPage struct {
+	Transactions []Transaction
+	// NextPageToken fetches the following page. Empty on the last page.
+	NextPageToken string
+}
+
+// ListTransactions returns a user's wallet history newest first. Pages are
+// keyed on (created_at, id), so entries written while a client is paging do
+// not shift or repeat later pages.
+func (s *Service) ListTransactions(ctx context.Context, req ListTransactionsReq) (TransactionPage, error) {
+	if err := req.Validate(); err != nil {
+		return TransactionPage{}, err
+	}
+
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	params := db.ListTransactionsByUserParams{
+		UserID:   req.UserID,
+		Types:    req.Types,
+		RowLimit: int32(pageSize + 1),
+	}
+	if req.CreatedFrom > 0 {
+		params.CreatedFrom = pgtype.Timestamptz{Time: time.Unix(req.CreatedFrom, 0), Valid: true}
+	}
+	if req.CreatedTo > 0 {
+		params.CreatedTo = pgtype.Timestamptz{Time: time.Unix(req.CreatedTo, 0), Valid: true}
+	}
+	if req.PageToken != "" {
+		// Already checked by Validate.
+		c, _ := paging.Parse(req.PageToken)
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: time.UnixMicro(c.Key), Valid: true}
+		params.CursorID = c.ID
+	}
+
+	rows, err := s.store.ListTransactionsByUser(ctx, params)
+	if err != nil {
+		return TransactionPage{}, fmt.Errorf("listing transactions: %w", err)
+	}
+
+	var page TransactionPage
+	if len(rows) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		page.NextPageToken = paging.Cursor{OrderBy: transactionsOrder, Key: last.CreatedAt.Time.UnixMicro(), ID: last.ID}.Token()
+	}
+
+	page.Transactions = make([]Transaction, len(rows))
+	for i, t := range rows {
+		page.Transactions[i] = toTransaction(t)
+	}
+	return page, nil
+}
+
+func toTransaction(t db.Transaction) Transaction {
+	return Transaction{
+		ID:             t.ID,
+		UserID:         t.UserID,
+		Type:           t.Type,
+		Amount:         t.Amount,
+		RelatedOrderID: t.RelatedOrderID.String,
+		PolicyVersion:  t.PolicyVersion.Int64,
+		RewardRuleIDs:  t.RewardRuleIds,
+		CreatedAt:      t.CreatedAt.Time.Unix(),
+	}
+}
@@ -0,0 +1,104 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sodafinance "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/validate"
+)
+
+// ErrWatchUnavailable is returned by WatchWallet when no wallet watcher is
+// running.
+var ErrWatchUnavailable = errors.New("wallet updates are unavailable")
+
+// DefaultWatchHeartbeat applies when SetWalletWatcher is given no heartbeat
+// interval.
+const DefaultWatchHeartbeat = 15 * time.Second
+
+// WalletUpdate is one message of a wallet watch: either the wallet as it now
+// stands, or a heartbeat showing the stream is alive.
+type WalletUpdate struct {
+	Wallet    Wallet
+	Heartbeat bool
+}
+
+// SetWalletWatcher enables WatchWallet. A heartbeat is sent after every
+// interval without a wallet change.
+func (s *Service) SetWalletWatcher(w *sodafinance.WalletWatcher, heartbeat time.Duration) {
+	if heartbeat <= 0 {
+		heartbeat = DefaultWatchHeartbeat
+	}
+	s.watcher = w
+	s.heartbeat = heartbeat
+}
+
+// WatchWallet sends the user's wallet, then sends it again every time a
+// change to it commits, until ctx is done or the watcher stops. A user with
+// no wallet yet is sent an empty one.
+func (s *Service) WatchWallet(ctx context.Context, userID string, send func(WalletUpdate) error) error {
+	if userID == "" {
+		var fe validate.FieldErrors
+		fe.Add("user_id", "is required")
+		return fe
+	}
+	if s.watcher == nil {
+		return ErrWatchUnavailable
+	}
+
+	// Subscribe before the first read so no change falls in between.
+	changes, unsubscribe := s.watcher.Subscribe(userID)
+	defer unsubscribe()
+
+	last, err := s.currentWallet(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := send(WalletUpdate{Wallet: last}); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			w, err := s.currentWallet(ctx, userID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			// Updates that leave the totals alone (or were already sent)
+			// are not worth a message.
+			if w == last {
+				continue
+			}
+			last = w
+			if err := send(WalletUpdate{Wallet: w}); err != nil {
+				return err
+			}
+			ticker.Reset(s.heartbeat)
+		case <-ticker.C:
+			if err := send(WalletUpdate{Heartbeat: true}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Service) currentWallet(ctx context.Context, userID string) (Wallet, error) {
+	w, err := s.GetWallet(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return Wallet{UserID: userID}, nil
+	}
+	return w, err
+}
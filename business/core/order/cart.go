@@ -0,0 +1,98 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	idempotencystore "soda-interview/business/data/stores/idempotency"
+	"soda-interview/foundation/validate"
+)
+
+// MaxCartLines bounds the number of lines in a single cart order.
+const MaxCartLines = 50
+
+// CartLine is one product in a cart. Each line earns the product's buyer
+// reward if the buyer has not bought it before, and the author reward when a
+// referral blog is given. Rewards are per line, not per unit.
+type CartLine struct {
+	ProductID string
+	Quantity  int32
+	BlogID    string // Optional. Referral blog for this line.
+}
+
+type PlaceCartOrderReq struct {
+	BuyerID string
+	Lines   []CartLine
+	Payment Payment
+	// IdempotencyKey is optional. Retrying with the same key returns the
+	// original order instead of placing a new one.
+	IdempotencyKey string
+}
+
+// Validate checks that the cart is complete. A product may appear on only one
+// line; order more units by raising the quantity.
+func (r PlaceCartOrderReq) Validate() error {
+	var fe validate.FieldErrors
+	if r.BuyerID == "" {
+		fe.Add("buyer_id", "is required")
+	}
+	switch {
+	case len(r.Lines) == 0:
+		fe.Add("lines", "is required")
+	case len(r.Lines) > MaxCartLines:
+		fe.Add("lines", fmt.Sprintf("must have at most %d lines", MaxCartLines))
+	}
+
+	seen := make(map[string]int, len(r.Lines))
+	for i, line := range r.Lines {
+		field := fmt.Sprintf("lines[%d]", i)
+		if line.ProductID == "" {
+			fe.Add(field+".product_id", "is required")
+		} else if j, ok := seen[line.ProductID]; ok {
+			fe.Add(field+".product_id", fmt.Sprintf("duplicates lines[%d]", j))
+		} else {
+			seen[line.ProductID] = i
+		}
+		if line.Quantity <= 0 {
+			fe.Add(field+".quantity", "must be positive")
+		}
+	}
+
+	r.Payment.validate(&fe)
+	if len(r.IdempotencyKey) > idempotencystore.MaxKeyLength {
+		fe.Add("idempotency_key", fmt.Sprintf("must be at most %d characters", idempotencystore.MaxKeyLength))
+	}
+	return fe.Err()
+}
+
+// hash fingerprints the cart for idempotency checks.
+func (r PlaceCartOrderReq) hash() string {
+	parts := make([]string, 0, 3*len(r.Lines))
+	for _, line := range r.Lines {
+		parts = append(parts, line.ProductID, strconv.Itoa(int(line.Quantity)), line.BlogID)
+	}
+	parts = append(parts, r.Payment.hashParts()...)
+	return idempotencystore.Hash(parts...)
+}
+
+// opPlaceCartOrder names the operation recorded against idempotency keys.
+const opPlaceCartOrder = "order.PlaceCartOrder"
+
+// PlaceCartOrder places one order for several products. Every line is priced,
+// checked for stock and rewarded in the same transaction, so the cart either
+// goes through whole or not at all.
+func (s *Service) PlaceCartOrder(ctx context.Context, req PlaceCartOrderReq) (Order, error) {
+	if err := req.Validate(); err != nil {
+		return Order{}, err
+	}
+
+	return s.place(ctx, placement{
+		buyerID:        req.BuyerID,
+		lines:          req.Lines,
+		payment:        req.Payment,
+		idempotencyKey: req.IdempotencyKey,
+		operation:      opPlaceCartOrder,
+		requestHash:    req.hash(),
+	})
+}
@@ -0,0 +1,22 @@
+package order
+
+import "soda-interview/foundation/metrics"
+
+var (
+	ordersPlaced = metrics.NewCounter("soda_orders_placed_total",
+		"Orders placed, by kind (single or cart).", "kind")
+	pointsEarned = metrics.NewCounter("soda_points_earned_total",
+		"Reward points credited for orders, by recipient (buyer or author).", "recipient")
+	ordersReversed = metrics.NewCounter("soda_orders_reversed_total",
+		"Orders cancelled or refunded, by resulting status.", "status")
+	paymentsSettled = metrics.NewCounter("soda_order_payments_settled_total",
+		"Pending payments the gateway confirmed or failed, by resulting order status.", "status")
+	referralsAttributed = metrics.NewCounter("soda_referrals_attributed_total",
+		"Order lines credited to a referral blog, by attribution source (REQUEST or LAST_TOUCH).", "source")
+	paymentActionFailures = metrics.NewCounter("soda_payment_action_failures_total",
+		"Failed gateway calls for order payments, by action (CAPTURE, VOID or REFUND).", "action")
+	paymentActionsDead = metrics.NewCounter("soda_payment_actions_dead_lettered_total",
+		"Gateway calls for order payments given up on after too many failed attempts, by action.", "action")
+	rewardRulesApplied = metrics.NewCounter("soda_reward_rules_applied_total",
+		"Reward rules that added points to an order line.")
+)
@@ -2,44 +2,131 @@ package order
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"soda-interview/business/data/stores/db"
+	idempotencystore "soda-interview/business/data/stores/idempotency"
 	orderstore "soda-interview/business/data/stores/order"
+	outboxstore "soda-interview/business/data/stores/outbox"
 	productstore "soda-interview/business/data/stores/product"
 	blogstore "soda-interview/business/data/stores/referral-blog"
 	financestore "soda-interview/business/data/stores/soda-finance"
 	"soda-interview/foundation/logger"
+	"soda-interview/foundation/payment"
+	eventsv1 "soda-interview/foundation/proto/events/v1"
+	"soda-interview/foundation/tracing"
+	"soda-interview/foundation/validate"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	// ErrReferralProductMismatch is returned when the referral blog promotes a
+	// different product than the one being ordered.
+	ErrReferralProductMismatch = errors.New("referral blog does not promote the ordered product")
+	// ErrSelfReferral is returned when the buyer is the author of the referral blog.
+	ErrSelfReferral = errors.New("buyer cannot be referred by their own blog")
+	// ErrBlogNotPublished is returned when the referral blog has not passed
+	// moderation, or was taken down.
+	ErrBlogNotPublished = errors.New("referral blog is not published")
+	// ErrProductArchived is returned when ordering a product that is no longer on sale.
+	ErrProductArchived = errors.New("product is archived")
+	// ErrOutOfStock is returned when a stock-tracked product has no units left.
+	ErrOutOfStock = errors.New("product is out of stock")
+)
+
+// Attribution sources persisted in order_items.attribution: how a referred
+// line got its blog.
+const (
+	// AttributionRequest means the client named the blog.
+	AttributionRequest = "REQUEST"
+	// AttributionLastTouch means the buyer named no blog and the line went to
+	// the last blog they visited for the product within the attribution
+	// window.
+	AttributionLastTouch = "LAST_TOUCH"
 )
 
 type Order struct {
 	ID        string
 	BuyerID   string
-	ProductID string
+	ProductID string // Empty for cart orders spanning several products.
 	BlogID    string
-	Amount    int64
-	Status    string
-	CreatedAt int64
+	// Attribution is how BlogID was found. Like ProductID and BlogID, it is
+	// only set for single-line orders; see Items for the rest.
+	Attribution string
+	Amount      int64
+	Payment     Payment
+	PaymentID   string // The gateway's reference for the external amount, if any.
+	Status      string
+	CreatedAt   int64
+	Items       []Item
+}
+
+// Item is one line of an order.
+type Item struct {
+	LineNo      int32
+	ProductID   string
+	BlogID      string
+	Attribution string // REQUEST or LAST_TOUCH. Empty without a referral.
+	Quantity    int32
+	UnitPrice   int64
+	Amount      int64
 }
 
 type PlaceOrderReq struct {
 	BuyerID   string
 	ProductID string
-	BlogID    string
+	// BlogID is optional. Without it, the order is credited to the last blog
+	// the buyer visited for the product within the attribution window, if any.
+	BlogID  string
+	Payment Payment
+	// IdempotencyKey is optional. Retrying with the same key returns the
+	// original order instead of placing a new one.
+	IdempotencyKey string
 }
 
+// Validate checks that the order request is complete.
+func (r PlaceOrderReq) Validate() error {
+	var fe validate.FieldErrors
+	if r.BuyerID == "" {
+		fe.Add("buyer_id", "is required")
+	}
+	if r.ProductID == "" {
+		fe.Add("product_id", "is required")
+	}
+	r.Payment.validate(&fe)
+	if len(r.IdempotencyKey) > idempotencystore.MaxKeyLength {
+		fe.Add("idempotency_key", fmt.Sprintf("must be at most %d characters", idempotencystore.MaxKeyLength))
+	}
+	return fe.Err()
+}
+
+// opPlaceOrder names the operation recorded against idempotency keys.
+const opPlaceOrder = "order.PlaceOrder"
+
 type Service struct {
-	log          *logger.Logger
-	pool         *pgxpool.Pool
-	orderStore   *orderstore.Store
-	productStore *productstore.Store
-	blogStore    *blogstore.Store
-	financeStore *financestore.Store
+	log              *logger.Logger
+	pool             *pgxpool.Pool
+	orderStore       *orderstore.Store
+	productStore     *productstore.Store
+	blogStore        *blogstore.Store
+	financeStore     *financestore.Store
+	idempotencyStore *idempotencystore.Store
+	outboxStore      *outboxstore.Store
+	gateway          payment.Gateway
+	// attributionWindow is how far back visits can earn the referral of a
+	// line without a blog. Zero turns last-touch attribution off.
+	attributionWindow time.Duration
 }
 
 func NewService(
@@ -49,18 +136,100 @@ func NewService(
 	productStore *productstore.Store,
 	blogStore *blogstore.Store,
 	financeStore *financestore.Store,
+	idempotencyStore *idempotencystore.Store,
+	outboxStore *outboxstore.Store,
 ) *Service {
 	return &Service{
-		log:          log,
-		pool:         pool,
-		orderStore:   orderStore,
-		productStore: productStore,
-		blogStore:    blogStore,
-		financeStore: financeStore,
+		log:              log,
+		pool:             pool,
+		orderStore:       orderStore,
+		productStore:     productStore,
+		blogStore:        blogStore,
+		financeStore:     financeStore,
+		idempotencyStore: idempotencyStore,
+		outboxStore:      outboxStore,
 	}
 }
 
+// SetGateway makes orders with an external amount wait in PENDING_PAYMENT
+// until g confirms the payment. Without a gateway they are confirmed when
+// placed and the external amount is assumed to be collected elsewhere.
+func (s *Service) SetGateway(g payment.Gateway) {
+	s.gateway = g
+}
+
+// SetAttributionWindow credits lines ordered without a blog to the last blog
+// the buyer visited for the product within d. Zero turns this off.
+func (s *Service) SetAttributionWindow(d time.Duration) {
+	s.attributionWindow = d
+}
+
 func (s *Service) PlaceOrder(ctx context.Context, req PlaceOrderReq) (Order, error) {
+	if err := req.Validate(); err != nil {
+		return Order{}, err
+	}
+
+	return s.place(ctx, placement{
+		buyerID:        req.BuyerID,
+		lines:          []CartLine{{ProductID: req.ProductID, Quantity: 1, BlogID: req.BlogID}},
+		payment:        req.Payment,
+		idempotencyKey: req.IdempotencyKey,
+		operation:      opPlaceOrder,
+		requestHash:    idempotencystore.Hash(append([]string{req.ProductID, req.BlogID}, req.Payment.hashParts()...)...),
+	})
+}
+
+// placement is a validated order request. PlaceOrder places a single line and
+// PlaceCartOrder any number of them.
+type placement struct {
+	buyerID        string
+	lines          []CartLine
+	payment        Payment
+	idempotencyKey string
+	operation      string
+	requestHash    string
+}
+
+// pricedLine is a cart line with the product and referral blog it resolved to.
+type pricedLine struct {
+	CartLine
+	product       db.Product
+	blog          db.Blog
+	attribution   string
+	firstPurchase bool
+
+	// The points the line pays once the order is confirmed, reward rules
+	// included, and what each rule added.
+	buyerPoints, authorPoints int64
+	grants                    []ruleGrant
+}
+
+// rewards returns the points the product itself pays the buyer and the
+// referral blog author for the line, before reward rules.
+func (l pricedLine) rewards() (buyer, author int32) {
+	if l.firstPurchase {
+		buyer = max(l.product.BuyerRewardPoints, 0)
+	}
+	if l.BlogID != "" {
+		author = max(l.product.AuthorRewardPoints, 0)
+	}
+	return buyer, author
+}
+
+// place writes the order and its lines, reserves stock, takes the balance part
+// of the payment and pays out the rewards of every line in one transaction.
+// When the external amount goes through the gateway, the order is left in
+// PENDING_PAYMENT instead and its rewards wait for the gateway to confirm.
+func (s *Service) place(ctx context.Context, p placement) (_ Order, err error) {
+	ctx, span := tracing.Start(ctx, p.operation,
+		attribute.String("order.buyer_id", p.buyerID),
+		attribute.Int64("order.lines", int64(len(p.lines))),
+	)
+	defer func() {
+		tracing.SetError(span, err)
+		span.End()
+	}()
+
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return Order{}, fmt.Errorf("beginning transaction: %w", err)
@@ -72,119 +241,646 @@ func (s *Service) PlaceOrder(ctx context.Context, req PlaceOrderReq) (Order, err
 	qTxProduct := s.productStore.WithTx(tx)
 	qTxBlog := s.blogStore.WithTx(tx)
 	qTxFinance := s.financeStore.WithTx(tx)
+	qTxIdempotency := s.idempotencyStore.WithTx(tx)
+	qTxOutbox := s.outboxStore.WithTx(tx)
 
-	product, err := qTxProduct.GetProduct(ctx, req.ProductID)
-	if err != nil {
-		return Order{}, fmt.Errorf("getting product: %w", err)
+	if p.idempotencyKey != "" {
+		resp, replay, err := qTxIdempotency.Begin(ctx, p.buyerID, p.idempotencyKey, p.operation,
+			p.requestHash, idempotencystore.DefaultTTL)
+		if err != nil {
+			return Order{}, fmt.Errorf("checking idempotency key: %w", err)
+		}
+		if replay {
+			var o Order
+			if err := json.Unmarshal(resp, &o); err != nil {
+				return Order{}, fmt.Errorf("decoding stored order: %w", err)
+			}
+			span.SetAttributes(attribute.Bool("idempotency.replay", true))
+			return o, nil
+		}
 	}
 
-	blog, err := qTxBlog.GetBlog(ctx, req.BlogID)
-	if err != nil {
-		return Order{}, fmt.Errorf("getting blog: %w", err)
+	lines := make([]pricedLine, len(p.lines))
+	var total int64
+	for i, line := range p.lines {
+		product, err := qTxProduct.GetProductForShare(ctx, line.ProductID)
+		if err != nil {
+			return Order{}, fmt.Errorf("getting product: %w", err)
+		}
+		if product.ArchivedAt.Valid {
+			return Order{}, fmt.Errorf("%w: %s", ErrProductArchived, product.ID)
+		}
+
+		var blog db.Blog
+		var attribution string
+		switch {
+		case line.BlogID != "":
+			// Locked so the blog cannot be taken down while the order
+			// attributes its rewards.
+			blog, err = qTxBlog.GetBlogForShare(ctx, line.BlogID)
+			if err != nil {
+				return Order{}, fmt.Errorf("getting blog: %w", err)
+			}
+
+			if err := checkReferral(blog, p.buyerID, line.ProductID); err != nil {
+				return Order{}, err
+			}
+			attribution = AttributionRequest
+		case s.attributionWindow > 0:
+			var ok bool
+			blog, ok, err = s.lastTouch(ctx, qTxBlog, p.buyerID, line.ProductID)
+			if err != nil {
+				return Order{}, err
+			}
+			if ok {
+				line.BlogID = blog.ID
+				attribution = AttributionLastTouch
+			}
+		}
+
+		count, err := qTxOrder.CountOrdersByBuyerAndProduct(ctx, p.buyerID, line.ProductID)
+		if err != nil {
+			return Order{}, fmt.Errorf("counting orders: %w", err)
+		}
+
+		lines[i] = pricedLine{CartLine: line, product: product, blog: blog, attribution: attribution, firstPurchase: count == 0}
+		total += product.Price * int64(line.Quantity)
 	}
 
-	count, err := qTxOrder.CountOrdersByBuyerAndProduct(ctx, req.BuyerID, req.ProductID)
+	if err := s.applyRewardRules(ctx, qTxOrder, p.buyerID, lines); err != nil {
+		return Order{}, fmt.Errorf("applying reward rules: %w", err)
+	}
+
+	payment, err := p.payment.resolve(total)
 	if err != nil {
-		return Order{}, fmt.Errorf("counting orders: %w", err)
+		return Order{}, err
 	}
 
-	isFirstPurchase := count == 0
+	status := StatusConfirmed
+	if s.gateway != nil && payment.ExternalAmount > 0 {
+		status = StatusPendingPayment
+	}
+
+	// A single-line order also records its product and blog on the order row,
+	// as orders did before carts.
+	var productID, blogID, attribution pgtype.Text
+	if len(lines) == 1 {
+		productID = pgtype.Text{String: lines[0].ProductID, Valid: true}
+		blogID = pgtype.Text{String: lines[0].BlogID, Valid: lines[0].BlogID != ""}
+		attribution = pgtype.Text{String: lines[0].attribution, Valid: lines[0].attribution != ""}
+	}
 
 	orderID := uuid.NewString()
 	now := time.Now()
 	dbOrder, err := qTxOrder.CreateOrder(ctx, db.CreateOrderParams{
-		ID:        orderID,
-		BuyerID:   req.BuyerID,
-		ProductID: req.ProductID,
-		BlogID:    req.BlogID,
-		Amount:    product.Price,
-		Status:    "CONFIRMED",
-		CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		ID:            orderID,
+		BuyerID:       p.buyerID,
+		ProductID:     productID,
+		BlogID:        blogID,
+		Amount:        total,
+		Status:        status,
+		CreatedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+		BalanceAmount: payment.BalanceAmount,
+		Attribution:   attribution,
 	})
 	if err != nil {
 		return Order{}, fmt.Errorf("creating order: %w", err)
 	}
 
-	if _, err := qTxFinance.GetOrCreateWallet(ctx, req.BuyerID); err != nil {
+	items := make([]db.OrderItem, len(lines))
+	for i, line := range lines {
+		items[i], err = qTxOrder.CreateOrderItem(ctx, db.CreateOrderItemParams{
+			OrderID:            orderID,
+			LineNo:             int32(i + 1),
+			ProductID:          line.ProductID,
+			BlogID:             pgtype.Text{String: line.BlogID, Valid: line.BlogID != ""},
+			Quantity:           line.Quantity,
+			UnitPrice:          line.product.Price,
+			Amount:             line.product.Price * int64(line.Quantity),
+			BuyerRewardPoints:  int32(line.buyerPoints),
+			AuthorRewardPoints: int32(line.authorPoints),
+			Attribution:        pgtype.Text{String: line.attribution, Valid: line.attribution != ""},
+		})
+		if err != nil {
+			return Order{}, fmt.Errorf("creating order item: %w", err)
+		}
+		for _, g := range line.grants {
+			if err := qTxOrder.CreateRewardGrant(ctx, db.CreateRewardGrantParams{
+				OrderID: orderID,
+				LineNo:  items[i].LineNo,
+				RuleID:  g.ruleID,
+				UserID:  g.userID,
+				Points:  g.points,
+			}); err != nil {
+				return Order{}, err
+			}
+		}
+	}
+
+	// Reserve in product order so concurrent carts lock stock rows in the
+	// same order and cannot deadlock.
+	byProduct := slices.Clone(lines)
+	slices.SortFunc(byProduct, func(a, b pricedLine) int { return strings.Compare(a.ProductID, b.ProductID) })
+	for _, line := range byProduct {
+		if err := reserveStock(ctx, qTxProduct, line.ProductID, orderID, line.Quantity); err != nil {
+			return Order{}, err
+		}
+	}
+
+	if _, err := qTxFinance.GetOrCreateWallet(ctx, p.buyerID); err != nil {
 		return Order{}, fmt.Errorf("ensuring buyer wallet: %w", err)
 	}
 
-	if isFirstPurchase {
-		if err := s.distributeBuyerRewards(ctx, qTxFinance, req.BuyerID, product.BuyerRewardPoints, orderID); err != nil {
-			return Order{}, fmt.Errorf("distributing buyer rewards: %w", err)
+	if err := payFromBalance(ctx, qTxFinance, p.buyerID, payment.BalanceAmount, orderID); err != nil {
+		return Order{}, err
+	}
+
+	if err := qTxOutbox.Append(ctx, orderID, orderPlacedEvent(dbOrder, items)); err != nil {
+		return Order{}, err
+	}
+
+	var earned rewards
+	if status == StatusConfirmed {
+		earned, err = s.payRewards(ctx, qTxOrder, qTxFinance, qTxBlog, qTxOutbox, p.buyerID, orderID, items)
+		if err != nil {
+			return Order{}, err
 		}
 	}
 
-	authorID := blog.AuthorID
-	if _, err := qTxFinance.GetOrCreateWallet(ctx, authorID); err != nil {
-		return Order{}, fmt.Errorf("ensuring author wallet: %w", err)
+	// The external amount is authorized once everything else has been
+	// written. If the order is not committed after all, the authorization is
+	// released again.
+	if status == StatusPendingPayment {
+		dbOrder, err = s.authorize(ctx, qTxOrder, dbOrder)
+		if err != nil {
+			return Order{}, err
+		}
+		defer func() {
+			if err != nil {
+				s.voidAbandoned(ctx, dbOrder.PaymentID.String)
+			}
+		}()
 	}
 
-	if err := s.distributeAuthorRewards(ctx, qTxFinance, authorID, product.AuthorRewardPoints, orderID); err != nil {
-		return Order{}, fmt.Errorf("distributing author rewards: %w", err)
+	o := toOrder(dbOrder, items)
+
+	if p.idempotencyKey != "" {
+		resp, err := json.Marshal(o)
+		if err != nil {
+			return Order{}, fmt.Errorf("encoding order: %w", err)
+		}
+		if err := qTxIdempotency.Complete(ctx, p.buyerID, p.idempotencyKey, resp); err != nil {
+			return Order{}, fmt.Errorf("storing idempotent response: %w", err)
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return Order{}, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	return Order{
-		ID:        dbOrder.ID,
-		BuyerID:   dbOrder.BuyerID,
-		ProductID: dbOrder.ProductID,
-		BlogID:    dbOrder.BlogID,
-		Amount:    dbOrder.Amount,
-		Status:    dbOrder.Status,
-		CreatedAt: dbOrder.CreatedAt.Time.Unix(),
-	}, nil
+	span.SetAttributes(attribute.String("order.id", orderID), attribute.Int64("order.amount", total))
+
+	kind := "single"
+	if p.operation == opPlaceCartOrder {
+		kind = "cart"
+	}
+	ordersPlaced.Inc(kind)
+	earned.record()
+	for _, line := range lines {
+		if line.attribution != "" {
+			referralsAttributed.Inc(line.attribution)
+		}
+		rewardRulesApplied.Add(float64(len(line.grants)))
+	}
+
+	return o, nil
 }
 
-func (s *Service) distributeBuyerRewards(ctx context.Context, txFinance *financestore.Store, buyerID string, points int32, orderID string) error {
-	amount := int64(points)
-	if amount <= 0 {
+// lastTouch finds the blog the buyer visited last for the product within the
+// attribution window and locks it like a requested blog. It reports false
+// when there is none. A blog that stops qualifying in the meantime simply
+// earns nothing; the order goes through without a referral.
+func (s *Service) lastTouch(ctx context.Context, txBlog *blogstore.Store, buyerID, productID string) (db.Blog, bool, error) {
+	visit, err := txBlog.LatestVisit(ctx, buyerID, productID, time.Now().Add(-s.attributionWindow))
+	if err != nil {
+		if errors.Is(err, blogstore.ErrNoVisit) {
+			return db.Blog{}, false, nil
+		}
+		return db.Blog{}, false, err
+	}
+
+	blog, err := txBlog.GetBlogForShare(ctx, visit.BlogID)
+	if err != nil {
+		return db.Blog{}, false, fmt.Errorf("getting blog: %w", err)
+	}
+	if checkReferral(blog, buyerID, productID) != nil {
+		return db.Blog{}, false, nil
+	}
+	return blog, true, nil
+}
+
+// checkReferral enforces the attribution rules for a referral blog: it must
+// be published, promote the ordered product and not belong to the buyer.
+func checkReferral(blog db.Blog, buyerID, productID string) error {
+	if blog.DeletedAt.Valid {
+		return fmt.Errorf("%w: %s", blogstore.ErrNotFound, blog.ID)
+	}
+	if blog.Status != blogstore.StatusPublished {
+		return fmt.Errorf("%w: blog %s is %s", ErrBlogNotPublished, blog.ID, blog.Status)
+	}
+	if blog.ProductID != productID {
+		return fmt.Errorf("%w: blog %s links product %s", ErrReferralProductMismatch, blog.ID, blog.ProductID)
+	}
+	if blog.AuthorID == buyerID {
+		return ErrSelfReferral
+	}
+	return nil
+}
+
+// GetOrder returns an order with its lines.
+func (s *Service) GetOrder(ctx context.Context, orderID string) (Order, error) {
+	o, err := s.orderStore.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, orderstore.ErrNotFound) {
+			return Order{}, ErrNotFound
+		}
+		return Order{}, fmt.Errorf("getting order: %w", err)
+	}
+
+	items, err := s.orderStore.ListOrderItems(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("listing order items: %w", err)
+	}
+
+	return toOrder(o, items), nil
+}
+
+// CancelOrder cancels an order that has not shipped yet, returns any Soda
+// Balance it was paid with and claws back the rewards it granted.
+func (s *Service) CancelOrder(ctx context.Context, orderID string) (Order, error) {
+	return s.transition(ctx, orderID, StatusCancelled)
+}
+
+// RefundOrder refunds a shipped or delivered order, returns any Soda Balance
+// it was paid with and claws back the rewards it granted.
+func (s *Service) RefundOrder(ctx context.Context, orderID string) (Order, error) {
+	return s.transition(ctx, orderID, StatusRefunded)
+}
+
+// UpdateStatus moves an order forward through fulfilment (CONFIRMED, SHIPPED,
+// DELIVERED). Cancellations and refunds must go through CancelOrder and
+// RefundOrder so that rewards are reversed.
+func (s *Service) UpdateStatus(ctx context.Context, orderID, status string) (Order, error) {
+	if reversesRewards(status) {
+		return Order{}, fmt.Errorf("%w: use the dedicated RPC to move an order to %s", ErrInvalidTransition, status)
+	}
+	return s.transition(ctx, orderID, status)
+}
+
+func (s *Service) transition(ctx context.Context, orderID, to string) (Order, error) {
+	if orderID == "" {
+		var fe validate.FieldErrors
+		fe.Add("order_id", "is required")
+		return Order{}, fe
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Order{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qTxOrder := s.orderStore.WithTx(tx)
+	qTxProduct := s.productStore.WithTx(tx)
+	qTxBlog := s.blogStore.WithTx(tx)
+	qTxFinance := s.financeStore.WithTx(tx)
+	qTxOutbox := s.outboxStore.WithTx(tx)
+
+	current, err := qTxOrder.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, orderstore.ErrNotFound) {
+			return Order{}, ErrNotFound
+		}
+		return Order{}, fmt.Errorf("getting order: %w", err)
+	}
+
+	if err := checkTransition(current.Status, to); err != nil {
+		return Order{}, err
+	}
+
+	updated, err := qTxOrder.UpdateOrderStatus(ctx, orderID, current.Status, to)
+	if err != nil {
+		return Order{}, fmt.Errorf("updating order status: %w", err)
+	}
+
+	items, err := qTxOrder.ListOrderItems(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("listing order items: %w", err)
+	}
+
+	for _, item := range items {
+		if err := settleStock(ctx, qTxProduct, item.ProductID, orderID, to); err != nil {
+			return Order{}, err
+		}
+	}
+
+	// Orders waiting for payment have not paid out their rewards yet.
+	var earned rewards
+	if current.Status == StatusPendingPayment && to == StatusConfirmed {
+		earned, err = s.payRewards(ctx, qTxOrder, qTxFinance, qTxBlog, qTxOutbox, current.BuyerID, orderID, items)
+		if err != nil {
+			return Order{}, err
+		}
+	}
+
+	if reversesRewards(to) {
+		// Refund first so the clawback can recover converted points from
+		// the returned balance.
+		if err := refundBalance(ctx, qTxFinance, orderID); err != nil {
+			return Order{}, err
+		}
+		if err := s.reverseRewards(ctx, qTxFinance, orderID); err != nil {
+			return Order{}, fmt.Errorf("reversing rewards: %w", err)
+		}
+	}
+
+	// The gateway call commits with the change and is made afterwards, so
+	// the order is not locked across it and a failed commit moves no money.
+	actionID, err := s.queuePayment(ctx, qTxOrder, current, to)
+	if err != nil {
+		return Order{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Order{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	earned.record()
+	if reversesRewards(to) {
+		ordersReversed.Inc(to)
+	}
+	s.settlePayment(ctx, actionID)
+
+	return toOrder(updated, items), nil
+}
+
+// reserveStock holds quantity units for the order when the product is
+// stock-tracked. Untracked products are not limited.
+func reserveStock(ctx context.Context, txProduct *productstore.Store, productID, orderID string, quantity int32) error {
+	if _, err := txProduct.GetStock(ctx, productID); err != nil {
+		if errors.Is(err, productstore.ErrNotTracked) {
+			return nil
+		}
+		return fmt.Errorf("querying stock: %w", err)
+	}
+
+	if _, err := txProduct.ReserveStock(ctx, productID, orderID, quantity); err != nil {
+		if errors.Is(err, productstore.ErrOutOfStock) {
+			return fmt.Errorf("%w: %s", ErrOutOfStock, productID)
+		}
+		return fmt.Errorf("reserving stock: %w", err)
+	}
+	return nil
+}
+
+// settleStock resolves the units an order holds when it moves to status:
+// shipping takes them off hand and cancelling makes them available again.
+// Refunds leave stock alone; returned goods are restocked with AdjustStock.
+func settleStock(ctx context.Context, txProduct *productstore.Store, productID, orderID, status string) error {
+	if status != StatusShipped && status != StatusCancelled {
 		return nil
 	}
 
-	if _, err := txFinance.AddPoints(ctx, db.AddPointsParams{
-		Amount: amount,
-		UserID: buyerID,
-	}); err != nil {
-		return fmt.Errorf("adding points: %w", err)
+	held, err := txProduct.ReservedForOrder(ctx, productID, orderID)
+	if err != nil {
+		return fmt.Errorf("querying reservation: %w", err)
+	}
+	if held <= 0 {
+		return nil
 	}
 
-	if _, err := txFinance.CreateTransaction(ctx, db.CreateTransactionParams{
-		ID:             uuid.NewString(),
-		UserID:         buyerID,
-		Type:           "EARNED",
-		Amount:         amount,
-		RelatedOrderID: pgtype.Text{String: orderID, Valid: true},
-	}); err != nil {
-		return fmt.Errorf("logging transaction: %w", err)
+	if status == StatusShipped {
+		if _, err := txProduct.ShipStock(ctx, productID, orderID, held); err != nil {
+			return fmt.Errorf("shipping stock: %w", err)
+		}
+		return nil
+	}
+	if _, err := txProduct.ReleaseStock(ctx, productID, orderID, held); err != nil {
+		return fmt.Errorf("releasing stock: %w", err)
 	}
 	return nil
 }
 
-func (s *Service) distributeAuthorRewards(ctx context.Context, txFinance *financestore.Store, authorID string, points int32, orderID string) error {
+// rewards counts the points an order paid out.
+type rewards struct {
+	buyer, author int64
+}
+
+func (r rewards) record() {
+	pointsEarned.Add(float64(r.buyer), "buyer")
+	pointsEarned.Add(float64(r.author), "author")
+}
+
+// payRewards credits the rewards recorded on the order's lines to the buyer
+// and to the authors of the referral blogs, with a PointsEarned event for each.
+// The EARNED transactions name the reward rules that added to them.
+func (s *Service) payRewards(ctx context.Context, txOrder *orderstore.Store, txFinance *financestore.Store, txBlog *blogstore.Store, txOutbox *outboxstore.Store, buyerID, orderID string, items []db.OrderItem) (rewards, error) {
+	grants, err := txOrder.ListRewardGrants(ctx, orderID)
+	if err != nil {
+		return rewards{}, err
+	}
+	buyerRules, authorRules := ruleIDs(grants, RecipientBuyer), ruleIDs(grants, RecipientAuthor)
+
+	var r rewards
+	for _, item := range items {
+		if err := s.distributeBuyerRewards(ctx, txFinance, buyerID, item.BuyerRewardPoints, orderID, buyerRules[item.LineNo]); err != nil {
+			return rewards{}, fmt.Errorf("distributing buyer rewards: %w", err)
+		}
+		if err := appendPointsEarned(ctx, txOutbox, buyerID, item.BuyerRewardPoints, orderID, "buyer"); err != nil {
+			return rewards{}, err
+		}
+		r.buyer += int64(item.BuyerRewardPoints)
+
+		if !item.BlogID.Valid {
+			continue
+		}
+		blog, err := txBlog.GetBlog(ctx, item.BlogID.String)
+		if err != nil {
+			return rewards{}, fmt.Errorf("getting blog: %w", err)
+		}
+		if _, err := txFinance.GetOrCreateWallet(ctx, blog.AuthorID); err != nil {
+			return rewards{}, fmt.Errorf("ensuring author wallet: %w", err)
+		}
+		if err := s.distributeAuthorRewards(ctx, txFinance, blog.AuthorID, item.AuthorRewardPoints, orderID, authorRules[item.LineNo]); err != nil {
+			return rewards{}, fmt.Errorf("distributing author rewards: %w", err)
+		}
+		if err := appendPointsEarned(ctx, txOutbox, blog.AuthorID, item.AuthorRewardPoints, orderID, "author"); err != nil {
+			return rewards{}, err
+		}
+		r.author += int64(item.AuthorRewardPoints)
+	}
+	return r, nil
+}
+
+func appendPointsEarned(ctx context.Context, txOutbox *outboxstore.Store, userID string, points int32, orderID, recipient string) error {
+	if points <= 0 {
+		return nil
+	}
+	return txOutbox.Append(ctx, userID, &eventsv1.PointsEarned{
+		UserId:    userID,
+		Points:    int64(points),
+		OrderId:   orderID,
+		Recipient: recipient,
+	})
+}
+
+func orderPlacedEvent(o db.Order, items []db.OrderItem) *eventsv1.OrderPlaced {
+	ev := &eventsv1.OrderPlaced{
+		OrderId:       o.ID,
+		BuyerId:       o.BuyerID,
+		Amount:        o.Amount,
+		BalanceAmount: o.BalanceAmount,
+		Status:        o.Status,
+		Lines:         make([]*eventsv1.OrderLine, len(items)),
+		CreatedAt:     o.CreatedAt.Time.Unix(),
+	}
+	for i, item := range items {
+		ev.Lines[i] = &eventsv1.OrderLine{
+			ProductId:   item.ProductID,
+			BlogId:      item.BlogID.String,
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+			Attribution: item.Attribution.String,
+		}
+	}
+	return ev
+}
+
+func (s *Service) distributeBuyerRewards(ctx context.Context, txFinance *financestore.Store, buyerID string, points int32, orderID string, ruleIDs []int64) error {
 	amount := int64(points)
 	if amount <= 0 {
 		return nil
 	}
 
-	if _, err := txFinance.AddPoints(ctx, db.AddPointsParams{
-		Amount: amount,
-		UserID: authorID,
-	}); err != nil {
-		return fmt.Errorf("adding points: %w", err)
+	if _, err := txFinance.EarnWithRules(ctx, buyerID, amount, orderID, ruleIDs); err != nil {
+		return fmt.Errorf("crediting points: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) distributeAuthorRewards(ctx context.Context, txFinance *financestore.Store, authorID string, points int32, orderID string, ruleIDs []int64) error {
+	amount := int64(points)
+	if amount <= 0 {
+		return nil
+	}
+
+	if _, err := txFinance.EarnWithRules(ctx, authorID, amount, orderID, ruleIDs); err != nil {
+		return fmt.Errorf("crediting points: %w", err)
+	}
+	return nil
+}
+
+// reverseRewards claws back every EARNED transaction recorded for the order.
+func (s *Service) reverseRewards(ctx context.Context, txFinance *financestore.Store, orderID string) error {
+	txs, err := txFinance.ListTransactionsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("listing order transactions: %w", err)
+	}
+
+	var users []string
+	earned := make(map[string]int64)
+	for _, t := range txs {
+		if t.Type != financestore.TxEarned {
+			continue
+		}
+		if _, ok := earned[t.UserID]; !ok {
+			users = append(users, t.UserID)
+		}
+		earned[t.UserID] += t.Amount
+	}
+
+	for _, userID := range users {
+		if err := s.clawback(ctx, txFinance, userID, earned[userID], orderID); err != nil {
+			return fmt.Errorf("clawing back from %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// clawback removes points from a user's wallet. Points are taken from
+// soda_points first. If the user already converted them, the shortfall is
+// recovered from soda_balance at the rate of the conversion policy in force,
+// and anything still missing is left as negative soda_points to be netted
+// against future rewards.
+func (s *Service) clawback(ctx context.Context, txFinance *financestore.Store, userID string, points int64, orderID string) error {
+	if points <= 0 {
+		return nil
+	}
+
+	w, err := txFinance.GetWalletForUpdate(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("locking wallet: %w", err)
 	}
 
-	if _, err := txFinance.CreateTransaction(ctx, db.CreateTransactionParams{
-		ID:             uuid.NewString(),
-		UserID:         authorID,
-		Type:           "EARNED",
-		Amount:         amount,
-		RelatedOrderID: pgtype.Text{String: orderID, Valid: true},
-	}); err != nil {
-		return fmt.Errorf("logging transaction: %w", err)
+	fromPoints := min(points, max(w.SodaPoints, 0))
+	shortfall := points - fromPoints
+
+	var yen, rate int64
+	if shortfall > 0 && w.SodaBalance > 0 {
+		policy, err := txFinance.GetActiveConversionPolicy(ctx)
+		switch {
+		case err == nil:
+			rate = policy.PointsPerYen
+			yen = min(shortfall/rate, w.SodaBalance)
+			shortfall -= yen * rate
+		case !errors.Is(err, financestore.ErrNoPolicy):
+			return fmt.Errorf("getting conversion policy: %w", err)
+		}
+	}
+
+	pointsDebit := fromPoints + shortfall
+	if pointsDebit > 0 {
+		if _, err := txFinance.Clawback(ctx, userID, pointsDebit, orderID); err != nil {
+			return fmt.Errorf("deducting points: %w", err)
+		}
+	}
+
+	if yen > 0 {
+		if _, err := txFinance.ClawbackBalance(ctx, userID, yen, yen*rate, orderID); err != nil {
+			return fmt.Errorf("deducting balance: %w", err)
+		}
 	}
+
 	return nil
 }
+
+func toOrder(o db.Order, items []db.OrderItem) Order {
+	order := Order{
+		ID:          o.ID,
+		BuyerID:     o.BuyerID,
+		ProductID:   o.ProductID.String,
+		BlogID:      o.BlogID.String,
+		Attribution: o.Attribution.String,
+		Amount:      o.Amount,
+		Payment: Payment{
+			BalanceAmount:  o.BalanceAmount,
+			ExternalAmount: o.Amount - o.BalanceAmount,
+		},
+		PaymentID: o.PaymentID.String,
+		Status:    o.Status,
+		CreatedAt: o.CreatedAt.Time.Unix(),
+		Items:     make([]Item, len(items)),
+	}
+	for i, item := range items {
+		order.Items[i] = Item{
+			LineNo:      item.LineNo,
+			ProductID:   item.ProductID,
+			BlogID:      item.BlogID.String,
+			Attribution: item.Attribution.String,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+		}
+	}
+	return order
+}
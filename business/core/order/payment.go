@@ -0,0 +1,313 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"soda-interview/business/data/stores/db"
+	orderstore "soda-interview/business/data/stores/order"
+	financestore "soda-interview/business/data/stores/soda-finance"
+	"soda-interview/foundation/payment"
+	"soda-interview/foundation/validate"
+)
+
+var (
+	// ErrInsufficientBalance is returned when the buyer's Soda Balance cannot
+	// cover the balance part of a payment.
+	ErrInsufficientBalance = errors.New("insufficient soda balance")
+	// ErrPaymentDeclined is returned when the gateway refuses to authorize
+	// the external amount.
+	ErrPaymentDeclined = errors.New("payment declined")
+	// ErrPaymentMismatch is returned when a gateway event names a payment
+	// other than the one recorded on the order.
+	ErrPaymentMismatch = errors.New("payment does not belong to the order")
+)
+
+// Payment splits an order's amount between the buyer's Soda Balance and an
+// external method. The zero value pays everything externally.
+type Payment struct {
+	BalanceAmount  int64
+	ExternalAmount int64
+}
+
+func (p Payment) isZero() bool {
+	return p == Payment{}
+}
+
+// validate adds field errors for amounts that can never be valid. Whether
+// the split covers the order is only known once it is priced; see resolve.
+func (p Payment) validate(fe *validate.FieldErrors) {
+	if p.BalanceAmount < 0 {
+		fe.Add("payment.balance_amount", "must not be negative")
+	}
+	if p.ExternalAmount < 0 {
+		fe.Add("payment.external_amount", "must not be negative")
+	}
+}
+
+// resolve returns the split that pays total.
+func (p Payment) resolve(total int64) (Payment, error) {
+	if p.isZero() {
+		return Payment{ExternalAmount: total}, nil
+	}
+	if p.BalanceAmount+p.ExternalAmount != total {
+		var fe validate.FieldErrors
+		fe.Add("payment", fmt.Sprintf("balance_amount and external_amount must add up to the order amount %d", total))
+		return Payment{}, fe
+	}
+	return p, nil
+}
+
+// hashParts fingerprints the split for idempotency checks. The zero value
+// adds nothing, so keys taken before payments existed still match.
+func (p Payment) hashParts() []string {
+	if p.isZero() {
+		return nil
+	}
+	return []string{strconv.FormatInt(p.BalanceAmount, 10), strconv.FormatInt(p.ExternalAmount, 10)}
+}
+
+// payFromBalance debits the balance part of an order from the buyer's wallet.
+func payFromBalance(ctx context.Context, txFinance *financestore.Store, buyerID string, yen int64, orderID string) error {
+	if yen <= 0 {
+		return nil
+	}
+	if _, err := txFinance.Spend(ctx, buyerID, yen, orderID); err != nil {
+		if errors.Is(err, financestore.ErrInsufficientBalance) {
+			return fmt.Errorf("%w: paying %d", ErrInsufficientBalance, yen)
+		}
+		return fmt.Errorf("paying from balance: %w", err)
+	}
+	return nil
+}
+
+// refundBalance returns whatever the order took from Soda Balance, less
+// anything already refunded.
+func refundBalance(ctx context.Context, txFinance *financestore.Store, orderID string) error {
+	txs, err := txFinance.ListTransactionsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("listing order transactions: %w", err)
+	}
+
+	var users []string
+	owed := make(map[string]int64)
+	for _, t := range txs {
+		switch t.Type {
+		case financestore.TxSpent:
+			if _, ok := owed[t.UserID]; !ok {
+				users = append(users, t.UserID)
+			}
+			owed[t.UserID] += t.Amount
+		case financestore.TxSpentRefund:
+			owed[t.UserID] -= t.Amount
+		}
+	}
+
+	for _, userID := range users {
+		if owed[userID] <= 0 {
+			continue
+		}
+		if _, err := txFinance.RefundSpend(ctx, userID, owed[userID], orderID); err != nil {
+			return fmt.Errorf("refunding balance to %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// ConfirmPayment confirms an order once the gateway reports its payment
+// authorized: the payment is captured and the order's rewards are paid out.
+// Gateways deliver events at least once, so an order that already left
+// PENDING_PAYMENT is returned unchanged.
+func (s *Service) ConfirmPayment(ctx context.Context, orderID, paymentID string) (Order, error) {
+	return s.settle(ctx, orderID, paymentID, StatusConfirmed)
+}
+
+// FailPayment cancels an order whose payment did not go through, releasing
+// its stock and returning any Soda Balance it was paid with.
+func (s *Service) FailPayment(ctx context.Context, orderID, paymentID string) (Order, error) {
+	return s.settle(ctx, orderID, paymentID, StatusCancelled)
+}
+
+func (s *Service) settle(ctx context.Context, orderID, paymentID, to string) (Order, error) {
+	var fe validate.FieldErrors
+	if orderID == "" {
+		fe.Add("order_id", "is required")
+	}
+	if paymentID == "" {
+		fe.Add("payment_id", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return Order{}, err
+	}
+
+	o, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if o.PaymentID != paymentID {
+		return Order{}, fmt.Errorf("%w: order %s has payment %q", ErrPaymentMismatch, orderID, o.PaymentID)
+	}
+	if o.Status != StatusPendingPayment {
+		return o, nil
+	}
+
+	o, err = s.transition(ctx, orderID, to)
+	if errors.Is(err, ErrInvalidTransition) {
+		// Another delivery of the event settled the order first.
+		return s.GetOrder(ctx, orderID)
+	}
+	if err != nil {
+		return Order{}, err
+	}
+	paymentsSettled.Inc(to)
+	return o, nil
+}
+
+// authorize asks the gateway to hold the external amount of a new order and
+// records the payment on the order.
+func (s *Service) authorize(ctx context.Context, txOrder *orderstore.Store, o db.Order) (db.Order, error) {
+	a, err := s.gateway.Authorize(ctx, payment.AuthorizeReq{
+		Reference:  o.ID,
+		CustomerID: o.BuyerID,
+		Amount:     o.Amount - o.BalanceAmount,
+	})
+	if err != nil {
+		if errors.Is(err, payment.ErrDeclined) {
+			return db.Order{}, fmt.Errorf("%w: %v", ErrPaymentDeclined, err)
+		}
+		return db.Order{}, fmt.Errorf("authorizing payment: %w", err)
+	}
+
+	updated, err := txOrder.SetOrderPaymentID(ctx, o.ID, a.PaymentID)
+	if err != nil {
+		s.voidAbandoned(ctx, a.PaymentID)
+		return db.Order{}, err
+	}
+	return updated, nil
+}
+
+// voidAbandoned releases the authorization of an order that was never
+// committed. The authorization lapses on its own if this fails.
+func (s *Service) voidAbandoned(ctx context.Context, paymentID string) {
+	if err := s.gateway.Void(context.WithoutCancel(ctx), paymentID); err != nil {
+		s.log.Error("Failed to void abandoned payment", "payment_id", paymentID, "error", err)
+	}
+}
+
+// queuePayment records what an order moving to status means for its
+// payment: confirming captures the authorization, cancelling before capture
+// voids it and cancelling or refunding after capture refunds it. The gateway
+// is only called once the change commits; see settlePayment. It returns the
+// action's id, or zero when the payment is left alone.
+func (s *Service) queuePayment(ctx context.Context, txOrder *orderstore.Store, current db.Order, to string) (int64, error) {
+	if !current.PaymentID.Valid {
+		return 0, nil
+	}
+	if s.gateway == nil {
+		return 0, fmt.Errorf("order %s has payment %s but no payment gateway is configured", current.ID, current.PaymentID.String)
+	}
+
+	params := db.CreatePaymentActionParams{
+		OrderID:   current.ID,
+		PaymentID: current.PaymentID.String,
+		Amount:    current.Amount - current.BalanceAmount,
+	}
+	pending := current.Status == StatusPendingPayment
+
+	switch {
+	case pending && to == StatusConfirmed:
+		params.Action = orderstore.ActionCapture
+	case pending && reversesRewards(to):
+		params.Action = orderstore.ActionVoid
+	case reversesRewards(to):
+		params.Action = orderstore.ActionRefund
+	default:
+		return 0, nil
+	}
+
+	a, err := txOrder.CreatePaymentAction(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return a.ID, nil
+}
+
+// settlePayment makes the gateway call an order change queued, right after
+// the change commits. A call that fails is left to the PaymentRetrier.
+func (s *Service) settlePayment(ctx context.Context, actionID int64) {
+	if actionID == 0 {
+		return
+	}
+	// The change is committed, so its call is made even if the caller has
+	// gone away.
+	if err := s.runPaymentAction(context.WithoutCancel(ctx), actionID); err != nil {
+		s.log.Warn("Payment action failed, will retry", "action_id", actionID, "error", err)
+	}
+}
+
+// runPaymentAction claims the action and makes its gateway call. An action
+// that is done, waiting behind an earlier one for the same payment, or held
+// by a retrier is left alone.
+func (s *Service) runPaymentAction(ctx context.Context, id int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txOrder := s.orderStore.WithTx(tx)
+
+	a, ok, err := txOrder.ClaimPaymentAction(ctx, id)
+	if err != nil || !ok {
+		return err
+	}
+
+	callErr := s.callGateway(ctx, a)
+	if callErr != nil {
+		// Still due: the retrier backs off from its next attempt.
+		err = txOrder.MarkPaymentActionFailed(ctx, a.ID, callErr, time.Now(), false)
+	} else {
+		err = txOrder.MarkPaymentActionDone(ctx, a.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	if callErr != nil {
+		paymentActionFailures.Inc(a.Action)
+	}
+	return callErr
+}
+
+// callGateway makes the gateway call a payment action records.
+func (s *Service) callGateway(ctx context.Context, a db.PaymentAction) error {
+	if s.gateway == nil {
+		return fmt.Errorf("payment action %d needs a payment gateway and none is configured", a.ID)
+	}
+
+	switch a.Action {
+	case orderstore.ActionCapture:
+		if err := s.gateway.Capture(ctx, a.PaymentID, a.Amount); err != nil {
+			return fmt.Errorf("capturing payment: %w", err)
+		}
+	case orderstore.ActionVoid:
+		if err := s.gateway.Void(ctx, a.PaymentID); err != nil {
+			return fmt.Errorf("voiding payment: %w", err)
+		}
+	case orderstore.ActionRefund:
+		// Keyed by the action, so a retry after a lost reply refunds once.
+		key := "payment-action-" + strconv.FormatInt(a.ID, 10)
+		if err := s.gateway.Refund(ctx, a.PaymentID, a.Amount, key); err != nil {
+			return fmt.Errorf("refunding payment: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown payment action %q", a.Action)
+	}
+	return nil
+}
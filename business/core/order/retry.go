@@ -0,0 +1,153 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"soda-interview/foundation/logger"
+)
+
+// maxPaymentBackoff caps the delay between attempts at a gateway call.
+const maxPaymentBackoff = time.Hour
+
+type PaymentRetryConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempts is how many times a gateway call is tried before its
+	// action is moved to the DEAD status.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// further attempt, up to an hour.
+	Backoff time.Duration
+}
+
+// PaymentRetrier retries the gateway calls that failed after the order
+// change owing them committed. Every replica may run one: actions are
+// claimed with SKIP LOCKED, so retriers share the work without waiting on
+// each other.
+type PaymentRetrier struct {
+	log     *logger.Logger
+	service *Service
+	cfg     PaymentRetryConfig
+}
+
+func NewPaymentRetrier(log *logger.Logger, service *Service, cfg PaymentRetryConfig) *PaymentRetrier {
+	return &PaymentRetrier{
+		log:     log,
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+// Run retries every interval until ctx is done.
+func (r *PaymentRetrier) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RetryDue(ctx, time.Now()); err != nil && !errors.Is(err, context.Canceled) {
+			r.log.Error("Payment retry failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RetryDue makes the gateway calls due by now, a batch at a time, and
+// returns how many went through. A call that goes through can let the next
+// one for its payment run, so it keeps on until a batch claims nothing, and
+// stops after the first batch with a failure so failing calls wait for their
+// backoff.
+func (r *PaymentRetrier) RetryDue(ctx context.Context, now time.Time) (int, error) {
+	var total int
+	for {
+		res, err := r.retryBatch(ctx, now)
+		total += res.done
+		if err != nil {
+			return total, err
+		}
+		if res.claimed == 0 || res.failed > 0 {
+			return total, nil
+		}
+	}
+}
+
+type retryResult struct {
+	claimed, done, failed int
+}
+
+func (r *PaymentRetrier) retryBatch(ctx context.Context, now time.Time) (retryResult, error) {
+	s := r.service
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return retryResult{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txOrder := s.orderStore.WithTx(tx)
+
+	claimed, err := txOrder.ClaimDuePaymentActions(ctx, now, int32(r.cfg.BatchSize))
+	if err != nil {
+		return retryResult{}, err
+	}
+
+	res := retryResult{claimed: len(claimed)}
+	var failed, dead []string
+	for _, a := range claimed {
+		callErr := s.callGateway(ctx, a)
+		if callErr == nil {
+			if err := txOrder.MarkPaymentActionDone(ctx, a.ID); err != nil {
+				return retryResult{}, err
+			}
+			res.done++
+			continue
+		}
+		if ctx.Err() != nil {
+			// Shutting down is not the action's fault; leave its attempts alone.
+			return retryResult{}, ctx.Err()
+		}
+
+		attempts := int(a.Attempts) + 1
+		isDead := attempts >= r.cfg.MaxAttempts
+		if err := txOrder.MarkPaymentActionFailed(ctx, a.ID, callErr, now.Add(r.backoff(attempts)), isDead); err != nil {
+			return retryResult{}, err
+		}
+		failed = append(failed, a.Action)
+		if isDead {
+			dead = append(dead, a.Action)
+			r.log.Error("Payment action dead-lettered", "action_id", a.ID, "order_id", a.OrderID,
+				"action", a.Action, "attempts", attempts, "error", callErr)
+		} else {
+			r.log.Warn("Payment action failed, will retry", "action_id", a.ID, "order_id", a.OrderID,
+				"action", a.Action, "attempts", attempts, "error", callErr)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return retryResult{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	for _, a := range failed {
+		paymentActionFailures.Inc(a)
+	}
+	for _, a := range dead {
+		paymentActionsDead.Inc(a)
+	}
+	res.failed = len(failed)
+	return res, nil
+}
+
+// backoff returns the delay before retrying a call that failed attempts
+// times.
+func (r *PaymentRetrier) backoff(attempts int) time.Duration {
+	d := r.cfg.Backoff
+	for i := 1; i < attempts && d < maxPaymentBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxPaymentBackoff)
+}
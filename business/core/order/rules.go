@@ -0,0 +1,430 @@
+package order
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"soda-interview/business/data/stores/db"
+	orderstore "soda-interview/business/data/stores/order"
+	"soda-interview/foundation/validate"
+)
+
+// ErrRuleNotFound is returned when a reward rule does not exist.
+var ErrRuleNotFound = errors.New("reward rule not found")
+
+// Reward rule recipients persisted in reward_rules.recipient.
+const (
+	RecipientBuyer  = "BUYER"
+	RecipientAuthor = "AUTHOR"
+)
+
+// Reward rule kinds persisted in reward_rules.kind. Each says what a matching
+// rule adds to the line's points for its recipient.
+const (
+	// RuleBonus adds Value points.
+	RuleBonus = "BONUS"
+	// RulePercentOfPrice adds Value basis points of the line amount, rounded
+	// down. 500 is 5%.
+	RulePercentOfPrice = "PERCENT_OF_PRICE"
+	// RuleMultiplier scales the points so far to Value percent. 200 doubles
+	// them.
+	RuleMultiplier = "MULTIPLIER"
+)
+
+// Upper bounds on Value by kind. A line's points are stored as 32-bit
+// integers, so no bonus can pay more; a percentage stops at the whole line
+// amount and a multiplier at 100 times the points.
+const (
+	maxBonusValue          = math.MaxInt32
+	maxPercentOfPriceValue = 10000
+	maxMultiplierValue     = 10000
+)
+
+// RewardRule adds to the reward points a product pays. When an order is
+// placed, the rules in force are evaluated for each line in priority order,
+// then by ID, on top of the product's own points. Rules are never edited;
+// EndRewardRule stops one early.
+type RewardRule struct {
+	ID        int64
+	Name      string
+	Recipient string
+	Kind      string
+	Value     int64
+	Priority  int32 // Lower runs first.
+
+	// Conditions. Zero values leave them open.
+	ProductID         string
+	FirstPurchaseOnly bool // The buyer has not ordered the product before.
+	// MinAuthorSales and MaxAuthorSales select an author tier: the author's
+	// lifetime referred sales in yen, before the order, must fall in
+	// [MinAuthorSales, MaxAuthorSales).
+	MinAuthorSales int64
+	MaxAuthorSales int64
+	// UserCapPoints bounds what one user can earn from the rule in total.
+	// Points from cancelled or refunded orders do not count.
+	UserCapPoints int64
+
+	StartsAt  int64 // Unix timestamp
+	EndsAt    int64 // Unix timestamp. Zero means open-ended.
+	CreatedBy string
+	CreatedAt int64 // Unix timestamp
+}
+
+// NewRewardRule is a rule to create. A zero StartsAt means now.
+type NewRewardRule struct {
+	Name              string
+	Recipient         string
+	Kind              string
+	Value             int64
+	Priority          int32
+	ProductID         string
+	FirstPurchaseOnly bool
+	MinAuthorSales    int64
+	MaxAuthorSales    int64
+	UserCapPoints     int64
+	StartsAt          int64
+	EndsAt            int64
+	CreatedBy         string
+}
+
+func (nr NewRewardRule) Validate() error {
+	var fe validate.FieldErrors
+	if nr.Name == "" {
+		fe.Add("name", "is required")
+	}
+	if nr.Recipient != RecipientBuyer && nr.Recipient != RecipientAuthor {
+		fe.Add("recipient", "must be BUYER or AUTHOR")
+	}
+	switch nr.Kind {
+	case RuleBonus:
+		if nr.Value <= 0 || nr.Value > maxBonusValue {
+			fe.Add("value", fmt.Sprintf("must be between 1 and %d points", maxBonusValue))
+		}
+	case RulePercentOfPrice:
+		if nr.Value <= 0 || nr.Value > maxPercentOfPriceValue {
+			fe.Add("value", fmt.Sprintf("must be between 1 and %d basis points", maxPercentOfPriceValue))
+		}
+	case RuleMultiplier:
+		if nr.Value <= 100 || nr.Value > maxMultiplierValue {
+			fe.Add("value", fmt.Sprintf("must be more than 100 and at most %d percent", maxMultiplierValue))
+		}
+	default:
+		fe.Add("kind", "must be BONUS, PERCENT_OF_PRICE or MULTIPLIER")
+	}
+	if nr.MinAuthorSales < 0 {
+		fe.Add("min_author_sales", "must not be negative")
+	}
+	if nr.MaxAuthorSales < 0 {
+		fe.Add("max_author_sales", "must not be negative")
+	} else if nr.MaxAuthorSales > 0 && nr.MaxAuthorSales <= nr.MinAuthorSales {
+		fe.Add("max_author_sales", "must be more than min_author_sales")
+	}
+	if nr.Recipient == RecipientBuyer && (nr.MinAuthorSales > 0 || nr.MaxAuthorSales > 0) {
+		fe.Add("recipient", "must be AUTHOR for author tiers")
+	}
+	if nr.UserCapPoints < 0 {
+		fe.Add("user_cap_points", "must not be negative")
+	}
+	if nr.StartsAt < 0 {
+		fe.Add("starts_at", "must not be negative")
+	}
+	if nr.EndsAt < 0 {
+		fe.Add("ends_at", "must not be negative")
+	}
+	if nr.CreatedBy == "" {
+		fe.Add("created_by", "is required")
+	}
+	return fe.Err()
+}
+
+// CreateRewardRule records a rule. It takes effect at StartsAt, which may not
+// be in the past, so orders already placed keep the rewards they got.
+func (s *Service) CreateRewardRule(ctx context.Context, nr NewRewardRule) (RewardRule, error) {
+	if err := nr.Validate(); err != nil {
+		return RewardRule{}, err
+	}
+
+	now := time.Now()
+	startsAt := now
+	var fe validate.FieldErrors
+	if nr.StartsAt > 0 {
+		startsAt = time.Unix(nr.StartsAt, 0)
+		if startsAt.Before(now.Truncate(time.Second)) {
+			fe.Add("starts_at", "must not be in the past")
+		}
+	}
+	var endsAt pgtype.Timestamptz
+	if nr.EndsAt > 0 {
+		endsAt = pgtype.Timestamptz{Time: time.Unix(nr.EndsAt, 0), Valid: true}
+		if !endsAt.Time.After(startsAt) {
+			fe.Add("ends_at", "must be after starts_at")
+		}
+	}
+	if err := fe.Err(); err != nil {
+		return RewardRule{}, err
+	}
+	if nr.ProductID != "" {
+		if _, err := s.productStore.GetProduct(ctx, nr.ProductID); err != nil {
+			return RewardRule{}, err
+		}
+	}
+
+	r, err := s.orderStore.CreateRewardRule(ctx, db.CreateRewardRuleParams{
+		Name:              nr.Name,
+		Recipient:         nr.Recipient,
+		Kind:              nr.Kind,
+		Value:             nr.Value,
+		Priority:          nr.Priority,
+		ProductID:         pgtype.Text{String: nr.ProductID, Valid: nr.ProductID != ""},
+		FirstPurchaseOnly: nr.FirstPurchaseOnly,
+		MinAuthorSales:    nr.MinAuthorSales,
+		MaxAuthorSales:    pgtype.Int8{Int64: nr.MaxAuthorSales, Valid: nr.MaxAuthorSales > 0},
+		UserCapPoints:     pgtype.Int8{Int64: nr.UserCapPoints, Valid: nr.UserCapPoints > 0},
+		StartsAt:          pgtype.Timestamptz{Time: startsAt, Valid: true},
+		EndsAt:            endsAt,
+		CreatedBy:         nr.CreatedBy,
+	})
+	if err != nil {
+		return RewardRule{}, err
+	}
+	return toRewardRule(r), nil
+}
+
+// ListRewardRules returns every rule, ended and scheduled ones included,
+// newest first.
+func (s *Service) ListRewardRules(ctx context.Context) ([]RewardRule, error) {
+	rows, err := s.orderStore.ListRewardRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]RewardRule, len(rows))
+	for i, r := range rows {
+		rules[i] = toRewardRule(r)
+	}
+	return rules, nil
+}
+
+// EndRewardRule stops a rule now. Ending a rule that has already ended
+// returns it as it is.
+func (s *Service) EndRewardRule(ctx context.Context, id int64) (RewardRule, error) {
+	r, err := s.orderStore.EndRewardRule(ctx, id, time.Now())
+	switch {
+	case errors.Is(err, orderstore.ErrRuleEnded):
+		r, err = s.orderStore.GetRewardRule(ctx, id)
+		if err != nil {
+			return RewardRule{}, err
+		}
+	case errors.Is(err, orderstore.ErrRuleNotFound):
+		return RewardRule{}, ErrRuleNotFound
+	case err != nil:
+		return RewardRule{}, err
+	}
+	return toRewardRule(r), nil
+}
+
+// ruleGrant is what one rule added to a line.
+type ruleGrant struct {
+	ruleID int64
+	userID string
+	points int64
+}
+
+// capKey names one user's cap on one rule.
+type capKey struct {
+	ruleID int64
+	userID string
+}
+
+// applyRewardRules works out the points every line pays its buyer and author:
+// the product's own points, then what each rule in force adds. Capped rules
+// are locked per user first, in a fixed order so that concurrent orders
+// cannot deadlock, and the grants already made against them are read.
+func (s *Service) applyRewardRules(ctx context.Context, txOrder *orderstore.Store, buyerID string, lines []pricedLine) error {
+	for i := range lines {
+		buyer, author := lines[i].rewards()
+		lines[i].buyerPoints, lines[i].authorPoints = int64(buyer), int64(author)
+	}
+
+	rows, err := txOrder.ListActiveRewardRules(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	rules := make([]RewardRule, len(rows))
+	for i, r := range rows {
+		rules[i] = toRewardRule(r)
+	}
+
+	sales := make(map[string]int64)
+	for _, line := range lines {
+		if line.BlogID == "" {
+			continue
+		}
+		authorID := line.blog.AuthorID
+		if _, ok := sales[authorID]; ok {
+			continue
+		}
+		sales[authorID], err = txOrder.ReferredSales(ctx, authorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var caps []capKey
+	for _, line := range lines {
+		for _, r := range rules {
+			if r.UserCapPoints > 0 && r.matches(line, sales) {
+				caps = append(caps, capKey{ruleID: r.ID, userID: r.recipientID(buyerID, line)})
+			}
+		}
+	}
+	slices.SortFunc(caps, func(a, b capKey) int {
+		return cmp.Or(cmp.Compare(a.ruleID, b.ruleID), strings.Compare(a.userID, b.userID))
+	})
+	caps = slices.Compact(caps)
+
+	used := make(map[capKey]int64, len(caps))
+	for _, k := range caps {
+		if err := txOrder.LockRewardCap(ctx, k.ruleID, k.userID); err != nil {
+			return err
+		}
+		used[k], err = txOrder.SumRewardGrants(ctx, k.ruleID, k.userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		for _, r := range rules {
+			if !r.matches(*line, sales) {
+				continue
+			}
+
+			points := &line.buyerPoints
+			if r.Recipient == RecipientAuthor {
+				points = &line.authorPoints
+			}
+			// Points are stored as 32-bit integers on the line.
+			extra := min(r.extra(*points, line.product.Price*int64(line.Quantity)), math.MaxInt32-*points)
+
+			k := capKey{ruleID: r.ID, userID: r.recipientID(buyerID, *line)}
+			if r.UserCapPoints > 0 {
+				extra = min(extra, r.UserCapPoints-used[k])
+			}
+			if extra <= 0 {
+				continue
+			}
+
+			*points += extra
+			used[k] += extra
+			line.grants = append(line.grants, ruleGrant{ruleID: r.ID, userID: k.userID, points: extra})
+		}
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to the line. sales holds the
+// lifetime referred sales of the authors of the order's referral blogs.
+func (r RewardRule) matches(line pricedLine, sales map[string]int64) bool {
+	if r.ProductID != "" && r.ProductID != line.ProductID {
+		return false
+	}
+	if r.FirstPurchaseOnly && !line.firstPurchase {
+		return false
+	}
+	if r.Recipient == RecipientAuthor {
+		if line.BlogID == "" {
+			return false
+		}
+		s := sales[line.blog.AuthorID]
+		if s < r.MinAuthorSales || (r.MaxAuthorSales > 0 && s >= r.MaxAuthorSales) {
+			return false
+		}
+	}
+	return true
+}
+
+// extra returns the points the rule adds to a line that pays points so far
+// and costs amount yen. Results too large for an int64 are clamped to it.
+func (r RewardRule) extra(points, amount int64) int64 {
+	switch r.Kind {
+	case RuleBonus:
+		return r.Value
+	case RulePercentOfPrice:
+		return mulDiv(amount, r.Value, 10000)
+	case RuleMultiplier:
+		return mulDiv(points, r.Value-100, 100)
+	}
+	return 0
+}
+
+// mulDiv returns a * b / d rounded down, computing the product in 128 bits so
+// it cannot overflow, and clamps the result to math.MaxInt64. Negative inputs
+// give zero; d must be positive.
+func mulDiv(a, b, d int64) int64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi >= uint64(d) {
+		return math.MaxInt64
+	}
+	q, _ := bits.Div64(hi, lo, uint64(d))
+	return int64(min(q, math.MaxInt64))
+}
+
+func (r RewardRule) recipientID(buyerID string, line pricedLine) string {
+	if r.Recipient == RecipientAuthor {
+		return line.blog.AuthorID
+	}
+	return buyerID
+}
+
+// ruleIDs returns the rules that added to the line's points for the recipient,
+// read back from reward_grants, keyed by line number.
+func ruleIDs(grants []db.ListRewardGrantsByOrderRow, recipient string) map[int32][]int64 {
+	ids := make(map[int32][]int64)
+	for _, g := range grants {
+		if g.Recipient == recipient {
+			ids[g.LineNo] = append(ids[g.LineNo], g.RuleID)
+		}
+	}
+	return ids
+}
+
+func toRewardRule(r db.RewardRule) RewardRule {
+	var endsAt int64
+	if r.EndsAt.Valid {
+		endsAt = r.EndsAt.Time.Unix()
+	}
+	return RewardRule{
+		ID:                r.ID,
+		Name:              r.Name,
+		Recipient:         r.Recipient,
+		Kind:              r.Kind,
+		Value:             r.Value,
+		Priority:          r.Priority,
+		ProductID:         r.ProductID.String,
+		FirstPurchaseOnly: r.FirstPurchaseOnly,
+		MinAuthorSales:    r.MinAuthorSales,
+		MaxAuthorSales:    r.MaxAuthorSales.Int64,
+		UserCapPoints:     r.UserCapPoints.Int64,
+		StartsAt:          r.StartsAt.Time.Unix(),
+		EndsAt:            endsAt,
+		CreatedBy:         r.CreatedBy,
+		CreatedAt:         r.CreatedAt.Time.Unix(),
+	}
+}
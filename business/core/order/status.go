@@ -0,0 +1,43 @@
+package order
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Order statuses persisted in orders.status.
+const (
+	StatusPending = "PENDING"
+	// StatusPendingPayment holds an order until the payment gateway confirms
+	// its external amount.
+	StatusPendingPayment = "PENDING_PAYMENT"
+	StatusConfirmed      = "CONFIRMED"
+	StatusShipped        = "SHIPPED"
+	StatusDelivered      = "DELIVERED"
+	StatusCancelled      = "CANCELLED"
+	StatusRefunded       = "REFUNDED"
+)
+
+// transitions lists the statuses an order may move to from each status.
+// CANCELLED and REFUNDED are terminal.
+var transitions = map[string][]string{
+	StatusPending:        {StatusConfirmed, StatusCancelled},
+	StatusPendingPayment: {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:      {StatusShipped, StatusCancelled},
+	StatusShipped:        {StatusDelivered, StatusRefunded},
+	StatusDelivered:      {StatusRefunded},
+}
+
+// checkTransition reports whether an order in status from may move to status to.
+func checkTransition(from, to string) error {
+	if !slices.Contains(transitions[from], to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// reversesRewards reports whether moving into status voids the sale, in which
+// case the rewards granted for the order must be clawed back.
+func reversesRewards(status string) bool {
+	return status == StatusCancelled || status == StatusRefunded
+}
@@ -0,0 +1,12 @@
+package outbox
+
+import "soda-interview/foundation/metrics"
+
+var (
+	eventsPublished = metrics.NewCounter("soda_outbox_published_total",
+		"Outbox events published, by event type.", "type")
+	publishFailures = metrics.NewCounter("soda_outbox_publish_failures_total",
+		"Failed attempts to publish outbox events, by event type.", "type")
+	eventsDead = metrics.NewCounter("soda_outbox_dead_lettered_total",
+		"Outbox events given up on after too many failed attempts, by event type.", "type")
+)
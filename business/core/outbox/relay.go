@@ -0,0 +1,175 @@
+// Package outbox relays the domain events that services write to the outbox
+// table to a Publisher. Events are published at least once: an event is only
+// marked published after the publisher accepts it, so a crash in between
+// sends it again.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"soda-interview/business/data/stores/db"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	"soda-interview/foundation/events"
+	"soda-interview/foundation/logger"
+)
+
+// maxBackoff caps the delay between attempts to publish an event.
+const maxBackoff = time.Hour
+
+type RelayConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempts is how many times an event is tried before it is moved to
+	// the DEAD status.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// further attempt, up to an hour.
+	Backoff time.Duration
+}
+
+// Relay publishes pending outbox events. Every replica may run one: events
+// are claimed with SKIP LOCKED, so relays share the work without waiting on
+// each other.
+type Relay struct {
+	log       *logger.Logger
+	pool      *pgxpool.Pool
+	store     *outboxstore.Store
+	publisher events.Publisher
+	cfg       RelayConfig
+}
+
+func NewRelay(log *logger.Logger, pool *pgxpool.Pool, store *outboxstore.Store, publisher events.Publisher, cfg RelayConfig) *Relay {
+	return &Relay{
+		log:       log,
+		pool:      pool,
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Run relays every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.PublishDue(ctx, time.Now()); err != nil && !errors.Is(err, context.Canceled) {
+			r.log.Error("Outbox relay failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes the events due by now, a batch at a time, and returns
+// how many were published. It stops after the first batch with a failure so
+// failing events wait for their backoff.
+func (r *Relay) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	var total int
+	for {
+		res, err := r.publishBatch(ctx, now)
+		total += res.published
+		if err != nil {
+			return total, err
+		}
+		if res.claimed < r.cfg.BatchSize || res.failed > 0 {
+			return total, nil
+		}
+	}
+}
+
+type batchResult struct {
+	claimed, published, failed int
+}
+
+func (r *Relay) publishBatch(ctx context.Context, now time.Time) (batchResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return batchResult{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := r.store.WithTx(tx)
+
+	claimed, err := txStore.ClaimDue(ctx, now, int32(r.cfg.BatchSize))
+	if err != nil {
+		return batchResult{}, err
+	}
+
+	res := batchResult{claimed: len(claimed)}
+	var published, failed, dead []string
+	for _, ev := range claimed {
+		pubErr := r.publisher.Publish(ctx, toMessage(ev))
+		if pubErr == nil {
+			if err := txStore.MarkPublished(ctx, ev.ID); err != nil {
+				return batchResult{}, err
+			}
+			published = append(published, ev.EventType)
+			continue
+		}
+		if ctx.Err() != nil {
+			// Shutting down is not the event's fault; leave its attempts alone.
+			return batchResult{}, ctx.Err()
+		}
+
+		attempts := int(ev.Attempts) + 1
+		isDead := attempts >= r.cfg.MaxAttempts
+		if err := txStore.MarkFailed(ctx, ev.ID, pubErr, now.Add(r.backoff(attempts)), isDead); err != nil {
+			return batchResult{}, err
+		}
+		failed = append(failed, ev.EventType)
+		if isDead {
+			dead = append(dead, ev.EventType)
+			r.log.Error("Outbox event dead-lettered", "event_id", ev.EventID, "type", ev.EventType,
+				"attempts", attempts, "error", pubErr)
+		} else {
+			r.log.Warn("Outbox event not published, will retry", "event_id", ev.EventID, "type", ev.EventType,
+				"attempts", attempts, "error", pubErr)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return batchResult{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	for _, t := range published {
+		eventsPublished.Inc(t)
+	}
+	for _, t := range failed {
+		publishFailures.Inc(t)
+	}
+	for _, t := range dead {
+		eventsDead.Inc(t)
+	}
+	res.published, res.failed = len(published), len(failed)
+	return res, nil
+}
+
+// backoff returns the delay before retrying an event that failed attempts
+// times.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.Backoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+func toMessage(ev db.OutboxEvent) events.Message {
+	return events.Message{
+		ID:          ev.EventID,
+		Type:        ev.EventType,
+		AggregateID: ev.AggregateID,
+		Payload:     ev.Payload,
+		OccurredAt:  ev.CreatedAt.Time.Unix(),
+	}
+}
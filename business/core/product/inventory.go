@@ -0,0 +1,85 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"soda-interview/business/data/stores/db"
+	productstore "soda-interview/business/data/stores/product"
+	"soda-interview/foundation/validate"
+)
+
+// ErrBelowReserved is returned when an adjustment would leave fewer units on
+// hand than open orders have reserved.
+var ErrBelowReserved = errors.New("stock on hand cannot fall below reserved")
+
+// Stock is a product's inventory. Products that were never stocked are not
+// tracked and can be ordered without limit.
+type Stock struct {
+	ProductID string
+	Tracked   bool
+	OnHand    int32
+	Reserved  int32 // Held by orders that have not shipped.
+}
+
+// Available is the number of units that can still be ordered.
+func (st Stock) Available() int32 {
+	return st.OnHand - st.Reserved
+}
+
+// GetStock returns the product's inventory.
+func (s *Service) GetStock(ctx context.Context, productID string) (Stock, error) {
+	if _, err := s.GetProduct(ctx, productID); err != nil {
+		return Stock{}, err
+	}
+
+	st, err := s.store.GetStock(ctx, productID)
+	if err != nil {
+		if errors.Is(err, productstore.ErrNotTracked) {
+			return Stock{ProductID: productID}, nil
+		}
+		return Stock{}, fmt.Errorf("querying stock: %w", err)
+	}
+	return toStock(st), nil
+}
+
+// AdjustStock adds delta units on hand (negative to remove) and records the
+// movement with reason. The first adjustment starts tracking the product.
+func (s *Service) AdjustStock(ctx context.Context, productID string, delta int32, reason string) (Stock, error) {
+	var fe validate.FieldErrors
+	if productID == "" {
+		fe.Add("product_id", "is required")
+	}
+	if delta == 0 {
+		fe.Add("delta", "must not be zero")
+	}
+	if reason == "" {
+		fe.Add("reason", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return Stock{}, err
+	}
+
+	if _, err := s.GetProduct(ctx, productID); err != nil {
+		return Stock{}, err
+	}
+
+	st, err := s.store.AdjustStock(ctx, productID, delta, reason)
+	if err != nil {
+		if errors.Is(err, productstore.ErrBelowReserved) {
+			return Stock{}, ErrBelowReserved
+		}
+		return Stock{}, fmt.Errorf("adjusting stock: %w", err)
+	}
+	return toStock(st), nil
+}
+
+func toStock(st db.ProductStock) Stock {
+	return Stock{
+		ProductID: st.ProductID,
+		Tracked:   true,
+		OnHand:    st.OnHand,
+		Reserved:  st.Reserved,
+	}
+}
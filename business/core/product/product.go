@@ -1,17 +1,24 @@
 package product
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"soda-interview/business/data/stores/db"
+	productstore "soda-interview/business/data/stores/product"
 	"soda-interview/foundation/logger"
+	"soda-interview/foundation/paging"
+	"soda-interview/foundation/validate"
 )
 
 var (
 	ErrNotFound = errors.New("product not found")
+	ErrArchived = errors.New("product is archived")
 )
 
 type Product struct {
@@ -21,6 +28,8 @@ type Product struct {
 	Price              int64
 	BuyerRewardPoints  int32
 	AuthorRewardPoints int32
+	ArchivedAt         int64 // Unix timestamp; 0 while the product is on sale.
+	CreatedAt          int64 // Unix timestamp
 }
 
 type NewProduct struct {
@@ -31,6 +40,114 @@ type NewProduct struct {
 	AuthorRewardPoints int32
 }
 
+// Validate checks that the new product can be persisted.
+func (np NewProduct) Validate() error {
+	var fe validate.FieldErrors
+	if np.Name == "" {
+		fe.Add("name", "is required")
+	}
+	if np.Price < 0 {
+		fe.Add("price", "must not be negative")
+	}
+	if np.BuyerRewardPoints < 0 {
+		fe.Add("buyer_reward_points", "must not be negative")
+	}
+	if np.AuthorRewardPoints < 0 {
+		fe.Add("author_reward_points", "must not be negative")
+	}
+	return fe.Err()
+}
+
+// Page sizes for ListProducts.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Sort orders for ListProducts. Each sorts ties by id in the same direction.
+const (
+	OrderByCreatedAt     = productstore.OrderByCreatedAt
+	OrderByCreatedAtDesc = productstore.OrderByCreatedAtDesc
+	OrderByPrice         = productstore.OrderByPrice
+	OrderByPriceDesc     = productstore.OrderByPriceDesc
+)
+
+type ListProductsReq struct {
+	// MinPrice and MaxPrice bound the price in yen, both inclusive. Zero
+	// leaves that side open.
+	MinPrice int64
+	MaxPrice int64
+	// NameContains keeps the products whose name contains it, ignoring case.
+	NameContains string
+	// OrderBy is one of the OrderBy constants. Empty means
+	// OrderByCreatedAtDesc, newest first.
+	OrderBy   string
+	PageToken string
+	PageSize  int
+}
+
+func (r ListProductsReq) Validate() error {
+	var fe validate.FieldErrors
+	if r.MinPrice < 0 {
+		fe.Add("min_price", "must not be negative")
+	}
+	if r.MaxPrice < 0 {
+		fe.Add("max_price", "must not be negative")
+	}
+	if r.MinPrice > 0 && r.MaxPrice > 0 && r.MaxPrice < r.MinPrice {
+		fe.Add("max_price", "must not be less than min_price")
+	}
+	orderBy := cmp.Or(r.OrderBy, OrderByCreatedAtDesc)
+	switch orderBy {
+	case OrderByCreatedAt, OrderByCreatedAtDesc, OrderByPrice, OrderByPriceDesc:
+	default:
+		fe.Add("order_by", "must be created_at, created_at desc, price or price desc")
+	}
+	if r.PageSize < 0 || r.PageSize > MaxPageSize {
+		fe.Add("page_size", fmt.Sprintf("must be between 0 and %d", MaxPageSize))
+	}
+	if r.PageToken != "" {
+		// A token only continues the sort it was issued for.
+		if c, err := paging.Parse(r.PageToken); err != nil || c.OrderBy != orderBy {
+			fe.Add("page_token", "is invalid")
+		}
+	}
+	return fe.Err()
+}
+
+type ProductPage struct {
+	Products []Product
+	// NextPageToken fetches the following page. Empty on the last page.
+	NextPageToken string
+}
+
+// UpdateProduct holds the fields to change. Nil fields are left as they are.
+type UpdateProduct struct {
+	Name               *string
+	Description        *string
+	Price              *int64
+	BuyerRewardPoints  *int32
+	AuthorRewardPoints *int32
+}
+
+// Validate checks the fields being changed.
+func (up UpdateProduct) Validate() error {
+	var fe validate.FieldErrors
+	if up.Name != nil && *up.Name == "" {
+		fe.Add("name", "must not be empty")
+	}
+	if up.Price != nil && *up.Price < 0 {
+		fe.Add("price", "must not be negative")
+	}
+	if up.BuyerRewardPoints != nil && *up.BuyerRewardPoints < 0 {
+		fe.Add("buyer_reward_points", "must not be negative")
+	}
+	if up.AuthorRewardPoints != nil && *up.AuthorRewardPoints < 0 {
+		fe.Add("author_reward_points", "must not be negative")
+	}
+	return fe.Err()
+}
+
 type Service struct {
 	log *logger.Logger
 	store Storer
@@ -44,6 +161,10 @@ func NewService(log *logger.Logger, store Storer) *Service {
 }
 
 func (s *Service) Create(ctx context.Context, np NewProduct) (Product, error) {
+	if err := np.Validate(); err != nil {
+		return Product{}, err
+	}
+
 	id := uuid.NewString()
 	
 	p, err := s.store.CreateProduct(ctx, db.CreateProductParams{
@@ -62,32 +183,152 @@ func (s *Service) Create(ctx context.Context, np NewProduct) (Product, error) {
 }
 
 func (s *Service) GetProduct(ctx context.Context, id string) (Product, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return Product{}, fe
+	}
+
 	p, err := s.store.GetProduct(ctx, id)
 	if err != nil {
-		// Check for not found error? 
-		// The store returns db.Product and error.
-		// If store implementation returns a specific error for Not Found, we can check it.
-		// For now, assuming store returns error if not found.
+		if errors.Is(err, productstore.ErrNotFound) {
+			return Product{}, ErrNotFound
+		}
 		return Product{}, fmt.Errorf("querying product: %w", err)
 	}
-	// Ideally we handle "not found" mapping here if the store returns a specific error.
 	return toProduct(p), nil
 }
 
-func (s *Service) ListProducts(ctx context.Context) ([]Product, error) {
-	products, err := s.store.ListProducts(ctx)
+// Update changes the given fields of an active product.
+func (s *Service) Update(ctx context.Context, id string, up UpdateProduct) (Product, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return Product{}, fe
+	}
+	if err := up.Validate(); err != nil {
+		return Product{}, err
+	}
+
+	p, err := s.store.UpdateProduct(ctx, db.UpdateProductParams{
+		ID:                 id,
+		Name:               pgtype.Text{String: deref(up.Name), Valid: up.Name != nil},
+		Description:        pgtype.Text{String: deref(up.Description), Valid: up.Description != nil},
+		Price:              pgtype.Int8{Int64: deref(up.Price), Valid: up.Price != nil},
+		BuyerRewardPoints:  pgtype.Int4{Int32: deref(up.BuyerRewardPoints), Valid: up.BuyerRewardPoints != nil},
+		AuthorRewardPoints: pgtype.Int4{Int32: deref(up.AuthorRewardPoints), Valid: up.AuthorRewardPoints != nil},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("listing products: %w", err)
+		if errors.Is(err, productstore.ErrNotFound) {
+			return Product{}, s.notFoundOrArchived(ctx, id)
+		}
+		return Product{}, fmt.Errorf("updating product: %w", err)
 	}
-	
-	out := make([]Product, len(products))
+	return toProduct(p), nil
+}
+
+// Archive takes a product off sale. Orders that reference it are kept.
+// Archiving an archived product returns it unchanged.
+func (s *Service) Archive(ctx context.Context, id string) (Product, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return Product{}, fe
+	}
+
+	p, err := s.store.ArchiveProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, productstore.ErrNotFound) {
+			// Either missing or already archived.
+			return s.GetProduct(ctx, id)
+		}
+		return Product{}, fmt.Errorf("archiving product: %w", err)
+	}
+	return toProduct(p), nil
+}
+
+// notFoundOrArchived explains why a conditional write on an active product
+// matched no row.
+func (s *Service) notFoundOrArchived(ctx context.Context, id string) error {
+	p, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p.ArchivedAt != 0 {
+		return ErrArchived
+	}
+	return ErrNotFound
+}
+
+// ListProducts returns a page of the products on sale, newest first unless
+// the request sorts otherwise. Archived products are omitted. Pages are keyed
+// on the sort key and id, so products added while a client is paging do not
+// shift or repeat later pages.
+func (s *Service) ListProducts(ctx context.Context, req ListProductsReq) (ProductPage, error) {
+	if err := req.Validate(); err != nil {
+		return ProductPage{}, err
+	}
+
+	orderBy := cmp.Or(req.OrderBy, OrderByCreatedAtDesc)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	f := productstore.ProductFilter{
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		OrderBy:  orderBy,
+		Limit:    int32(pageSize + 1),
+	}
+	if req.NameContains != "" {
+		f.NamePattern = "%" + likeEscaper.Replace(req.NameContains) + "%"
+	}
+	if req.PageToken != "" {
+		// Already checked by Validate.
+		c, _ := paging.Parse(req.PageToken)
+		f.After = &c
+	}
+
+	products, err := s.store.ListProducts(ctx, f)
+	if err != nil {
+		return ProductPage{}, fmt.Errorf("listing products: %w", err)
+	}
+
+	var page ProductPage
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		last := products[len(products)-1]
+		c := paging.Cursor{OrderBy: orderBy, Key: last.CreatedAt.Time.UnixMicro(), ID: last.ID}
+		if orderBy == OrderByPrice || orderBy == OrderByPriceDesc {
+			c.Key = last.Price
+		}
+		page.NextPageToken = c.Token()
+	}
+
+	page.Products = make([]Product, len(products))
 	for i, p := range products {
-		out[i] = toProduct(p)
+		page.Products[i] = toProduct(p)
 	}
-	return out, nil
+	return page, nil
+}
+
+// likeEscaper makes user input match literally in an ILIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
 }
 
 func toProduct(dbP db.Product) Product {
+	var archivedAt int64
+	if dbP.ArchivedAt.Valid {
+		archivedAt = dbP.ArchivedAt.Time.Unix()
+	}
 	return Product{
 		ID:                 dbP.ID,
 		Name:               dbP.Name,
@@ -95,5 +336,7 @@ func toProduct(dbP db.Product) Product {
 		Price:              dbP.Price,
 		BuyerRewardPoints:  dbP.BuyerRewardPoints,
 		AuthorRewardPoints: dbP.AuthorRewardPoints,
+		ArchivedAt:         archivedAt,
+		CreatedAt:          dbP.CreatedAt.Time.Unix(),
 	}
 }
\ No newline at end of file
@@ -4,11 +4,16 @@ import (
 	"context"
 
 	"soda-interview/business/data/stores/db"
+	productstore "soda-interview/business/data/stores/product"
 )
 
 // Storer defines the behavior required by the product service.
 type Storer interface {
 	CreateProduct(ctx context.Context, params db.CreateProductParams) (db.Product, error)
 	GetProduct(ctx context.Context, id string) (db.Product, error)
-	ListProducts(ctx context.Context) ([]db.Product, error)
+	ListProducts(ctx context.Context, f productstore.ProductFilter) ([]db.Product, error)
+	UpdateProduct(ctx context.Context, params db.UpdateProductParams) (db.Product, error)
+	ArchiveProduct(ctx context.Context, id string) (db.Product, error)
+	GetStock(ctx context.Context, productID string) (db.ProductStock, error)
+	AdjustStock(ctx context.Context, productID string, delta int32, reason string) (db.ProductStock, error)
 }
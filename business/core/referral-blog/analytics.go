@@ -0,0 +1,199 @@
+package referralblog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	"soda-interview/foundation/validate"
+)
+
+// Granularities of the referral analytics series. Buckets are UTC days, and
+// weeks starting on Monday.
+const (
+	GranularityDay  = "DAY"
+	GranularityWeek = "WEEK"
+)
+
+const (
+	// DefaultAnalyticsWindow is how far back ReferralAnalytics looks when
+	// the caller gives no start.
+	DefaultAnalyticsWindow = 30 * 24 * time.Hour
+	// MaxAnalyticsWindow bounds the date range of one ReferralAnalytics
+	// call.
+	MaxAnalyticsWindow = 366 * 24 * time.Hour
+)
+
+// AnalyticsReq selects the referrals to aggregate: orders placed in
+// [From, To), optionally for one author or blog.
+type AnalyticsReq struct {
+	AuthorID    string // Optional. Empty means every author.
+	BlogID      string // Optional.
+	From        int64  // Unix timestamp. Zero means DefaultAnalyticsWindow before To.
+	To          int64  // Unix timestamp. Zero means now.
+	Granularity string // DAY (default) or WEEK.
+}
+
+// ReferralStats sums up referred sales. Only orders that still stand count:
+// confirmed, shipped or delivered, not pending, cancelled or refunded.
+type ReferralStats struct {
+	Orders  int64
+	Revenue int64 // Yen, of the referred lines only.
+	Points  int64 // Author reward points.
+}
+
+type BlogStats struct {
+	BlogID    string
+	AuthorID  string
+	ProductID string
+	ReferralStats
+}
+
+type AuthorStats struct {
+	AuthorID string
+	Blogs    int64 // Blogs with at least one referred order.
+	ReferralStats
+}
+
+// Bucket is one day or week of the series.
+type Bucket struct {
+	Start int64 // Unix timestamp
+	ReferralStats
+}
+
+type Analytics struct {
+	From        int64
+	To          int64
+	Granularity string
+	Totals      ReferralStats
+	Blogs       []BlogStats   // Highest revenue first.
+	Authors     []AuthorStats // Highest revenue first.
+	Series      []Bucket      // Every bucket from From to To, oldest first.
+}
+
+// ReferralAnalytics aggregates referred sales per blog, per author and over
+// time.
+func (s *Service) ReferralAnalytics(ctx context.Context, req AnalyticsReq) (Analytics, error) {
+	to := time.Now()
+	if req.To > 0 {
+		to = time.Unix(req.To, 0)
+	}
+	from := to.Add(-DefaultAnalyticsWindow)
+	if req.From > 0 {
+		from = time.Unix(req.From, 0)
+	}
+	if req.Granularity == "" {
+		req.Granularity = GranularityDay
+	}
+
+	var fe validate.FieldErrors
+	if req.From < 0 {
+		fe.Add("from", "must not be negative")
+	}
+	if req.To < 0 {
+		fe.Add("to", "must not be negative")
+	}
+	if !from.Before(to) {
+		fe.Add("from", "must be before to")
+	} else if to.Sub(from) > MaxAnalyticsWindow {
+		fe.Add("from", fmt.Sprintf("must be at most %d days before to", int(MaxAnalyticsWindow.Hours()/24)))
+	}
+	if req.Granularity != GranularityDay && req.Granularity != GranularityWeek {
+		fe.Add("granularity", "must be DAY or WEEK")
+	}
+	if err := fe.Err(); err != nil {
+		return Analytics{}, err
+	}
+
+	// One snapshot, so the breakdowns add up to the same totals.
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return Analytics{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := s.store.WithTx(tx)
+	f := blogstore.ReferralFilter{From: from, To: to, AuthorID: req.AuthorID, BlogID: req.BlogID}
+
+	blogRows, err := txStore.ReferralStatsByBlog(ctx, f)
+	if err != nil {
+		return Analytics{}, err
+	}
+	authorRows, err := txStore.ReferralStatsByAuthor(ctx, f)
+	if err != nil {
+		return Analytics{}, err
+	}
+	seriesRows, err := txStore.ReferralStatsSeries(ctx, f, bucketUnit(req.Granularity))
+	if err != nil {
+		return Analytics{}, err
+	}
+
+	a := Analytics{
+		From:        from.Unix(),
+		To:          to.Unix(),
+		Granularity: req.Granularity,
+		Blogs:       make([]BlogStats, len(blogRows)),
+		Authors:     make([]AuthorStats, len(authorRows)),
+	}
+	for i, r := range blogRows {
+		a.Blogs[i] = BlogStats{
+			BlogID:        r.BlogID,
+			AuthorID:      r.AuthorID,
+			ProductID:     r.ProductID,
+			ReferralStats: ReferralStats{Orders: r.Orders, Revenue: r.Revenue, Points: r.Points},
+		}
+	}
+	for i, r := range authorRows {
+		a.Authors[i] = AuthorStats{
+			AuthorID:      r.AuthorID,
+			Blogs:         r.Blogs,
+			ReferralStats: ReferralStats{Orders: r.Orders, Revenue: r.Revenue, Points: r.Points},
+		}
+	}
+
+	// The query skips empty buckets; fill them in so clients can chart the
+	// series as is. Every order falls in exactly one bucket, so the buckets
+	// also give the totals without counting cart orders twice.
+	byStart := make(map[int64]ReferralStats, len(seriesRows))
+	for _, r := range seriesRows {
+		byStart[r.BucketStart.Time.Unix()] = ReferralStats{Orders: r.Orders, Revenue: r.Revenue, Points: r.Points}
+	}
+	for start := bucketStart(from, req.Granularity); start.Before(to); start = nextBucket(start, req.Granularity) {
+		st := byStart[start.Unix()]
+		a.Series = append(a.Series, Bucket{Start: start.Unix(), ReferralStats: st})
+		a.Totals.Orders += st.Orders
+		a.Totals.Revenue += st.Revenue
+		a.Totals.Points += st.Points
+	}
+
+	return a, nil
+}
+
+// bucketUnit names the granularity as Postgres date_trunc expects it.
+func bucketUnit(granularity string) string {
+	if granularity == GranularityWeek {
+		return "week"
+	}
+	return "day"
+}
+
+// bucketStart returns the start of the UTC day or week (from Monday) that t
+// falls in.
+func bucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if granularity == GranularityWeek {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+func nextBucket(start time.Time, granularity string) time.Time {
+	if granularity == GranularityWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
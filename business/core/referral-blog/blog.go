@@ -1,17 +1,30 @@
 package referralblog
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"soda-interview/business/data/stores/db"
+	outboxstore "soda-interview/business/data/stores/outbox"
+	blogstore "soda-interview/business/data/stores/referral-blog"
 	"soda-interview/foundation/logger"
+	"soda-interview/foundation/paging"
+	eventsv1 "soda-interview/foundation/proto/events/v1"
+	"soda-interview/foundation/validate"
 )
 
 var (
 	ErrNotFound = errors.New("blog not found")
+	// ErrNotAuthor is returned when someone other than the author changes a
+	// blog.
+	ErrNotAuthor = errors.New("only the author may change this blog")
+	// ErrInvalidTransition is returned when a blog cannot move to the
+	// requested status, or not by the caller's hand.
+	ErrInvalidTransition = errors.New("invalid blog status transition")
 )
 
 type Blog struct {
@@ -19,61 +32,208 @@ type Blog struct {
 	AuthorID        string
 	Content         string
 	LinkedProductID string
+	Status          string
+	CreatedAt       int64 // Unix timestamp
+	UpdatedAt       int64 // Unix timestamp
 }
 
 type NewBlog struct {
 	AuthorID  string
 	Content   string
 	ProductID string
+	// Draft keeps the blog to its author. Otherwise it goes straight to
+	// review.
+	Draft bool
+}
+
+// Validate checks that the new blog can be persisted.
+func (nb NewBlog) Validate() error {
+	var fe validate.FieldErrors
+	if nb.AuthorID == "" {
+		fe.Add("author_id", "is required")
+	}
+	if nb.Content == "" {
+		fe.Add("content", "is required")
+	}
+	if nb.ProductID == "" {
+		fe.Add("product_id", "is required")
+	}
+	return fe.Err()
+}
+
+// Page sizes for ListBlogs.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Sort orders for ListBlogs. Each sorts ties by id in the same direction.
+const (
+	OrderByCreatedAt     = blogstore.OrderByCreatedAt
+	OrderByCreatedAtDesc = blogstore.OrderByCreatedAtDesc
+)
+
+type ListBlogsReq struct {
+	AuthorID  string // Optional. Only this author's blogs.
+	ProductID string // Optional. Only blogs promoting this product.
+	// OrderBy is one of the OrderBy constants. Empty means
+	// OrderByCreatedAtDesc, newest first.
+	OrderBy   string
+	PageToken string
+	PageSize  int
+}
+
+func (r ListBlogsReq) Validate() error {
+	var fe validate.FieldErrors
+	orderBy := cmp.Or(r.OrderBy, OrderByCreatedAtDesc)
+	if orderBy != OrderByCreatedAt && orderBy != OrderByCreatedAtDesc {
+		fe.Add("order_by", "must be created_at or created_at desc")
+	}
+	if r.PageSize < 0 || r.PageSize > MaxPageSize {
+		fe.Add("page_size", fmt.Sprintf("must be between 0 and %d", MaxPageSize))
+	}
+	if r.PageToken != "" {
+		// A token only continues the sort it was issued for.
+		if c, err := paging.Parse(r.PageToken); err != nil || c.OrderBy != orderBy {
+			fe.Add("page_token", "is invalid")
+		}
+	}
+	return fe.Err()
+}
+
+type BlogPage struct {
+	Blogs []Blog
+	// NextPageToken fetches the following page. Empty on the last page.
+	NextPageToken string
 }
 
 type Service struct {
-	log *logger.Logger
-	store Storer
+	log         *logger.Logger
+	pool        *pgxpool.Pool
+	store       *blogstore.Store
+	outboxStore *outboxstore.Store
 }
 
-func NewService(log *logger.Logger, store Storer) *Service {
+func NewService(log *logger.Logger, pool *pgxpool.Pool, store *blogstore.Store, outboxStore *outboxstore.Store) *Service {
 	return &Service{
-		log: log,
-		store: store,
+		log:         log,
+		pool:        pool,
+		store:       store,
+		outboxStore: outboxStore,
 	}
 }
 
 func (s *Service) CreateBlog(ctx context.Context, nb NewBlog) (Blog, error) {
-	id := uuid.NewString()
-	
-	dbBlog, err := s.store.CreateBlog(ctx, db.CreateBlogParams{
-		ID:        id,
+	if err := nb.Validate(); err != nil {
+		return Blog{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Blog{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	status := blogstore.StatusPendingReview
+	if nb.Draft {
+		status = blogstore.StatusDraft
+	}
+
+	txStore := s.store.WithTx(tx)
+	dbBlog, err := txStore.CreateBlog(ctx, db.CreateBlogParams{
+		ID:        uuid.NewString(),
 		AuthorID:  nb.AuthorID,
 		Content:   nb.Content,
 		ProductID: nb.ProductID,
+		Status:    status,
 	})
 	if err != nil {
 		return Blog{}, fmt.Errorf("creating blog: %w", err)
 	}
+	if err := txStore.AddRevision(ctx, dbBlog, blogstore.ActionCreated, nb.AuthorID, ""); err != nil {
+		return Blog{}, err
+	}
+
+	err = s.outboxStore.WithTx(tx).Append(ctx, dbBlog.ID, &eventsv1.BlogCreated{
+		BlogId:    dbBlog.ID,
+		AuthorId:  dbBlog.AuthorID,
+		ProductId: dbBlog.ProductID,
+	})
+	if err != nil {
+		return Blog{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Blog{}, fmt.Errorf("committing transaction: %w", err)
+	}
 
 	return toBlog(dbBlog), nil
 }
 
+// GetBlog returns a blog in any status. Deleted blogs are not found.
 func (s *Service) GetBlog(ctx context.Context, id string) (Blog, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return Blog{}, fe
+	}
+
 	b, err := s.store.GetBlog(ctx, id)
 	if err != nil {
+		if errors.Is(err, blogstore.ErrNotFound) {
+			return Blog{}, ErrNotFound
+		}
 		return Blog{}, fmt.Errorf("querying blog: %w", err)
 	}
+	if b.DeletedAt.Valid {
+		return Blog{}, ErrNotFound
+	}
 	return toBlog(b), nil
 }
 
-func (s *Service) ListBlogs(ctx context.Context) ([]Blog, error) {
-	blogs, err := s.store.ListBlogs(ctx)
+// ListBlogs returns a page of the published blogs, newest first unless the
+// request sorts otherwise. Pages are keyed on (created_at, id), so blogs
+// published while a client is paging do not shift or repeat later pages.
+func (s *Service) ListBlogs(ctx context.Context, req ListBlogsReq) (BlogPage, error) {
+	if err := req.Validate(); err != nil {
+		return BlogPage{}, err
+	}
+
+	orderBy := cmp.Or(req.OrderBy, OrderByCreatedAtDesc)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	f := blogstore.BlogFilter{
+		AuthorID:  req.AuthorID,
+		ProductID: req.ProductID,
+		OrderBy:   orderBy,
+		Limit:     int32(pageSize + 1),
+	}
+	if req.PageToken != "" {
+		// Already checked by Validate.
+		c, _ := paging.Parse(req.PageToken)
+		f.After = &c
+	}
+
+	blogs, err := s.store.ListBlogs(ctx, f)
 	if err != nil {
-		return nil, fmt.Errorf("listing blogs: %w", err)
+		return BlogPage{}, fmt.Errorf("listing blogs: %w", err)
 	}
 
-	out := make([]Blog, len(blogs))
+	var page BlogPage
+	if len(blogs) > pageSize {
+		blogs = blogs[:pageSize]
+		last := blogs[len(blogs)-1]
+		page.NextPageToken = paging.Cursor{OrderBy: orderBy, Key: last.CreatedAt.Time.UnixMicro(), ID: last.ID}.Token()
+	}
+
+	page.Blogs = make([]Blog, len(blogs))
 	for i, b := range blogs {
-		out[i] = toBlog(b)
+		page.Blogs[i] = toBlog(b)
 	}
-	return out, nil
+	return page, nil
 }
 
 func toBlog(dbB db.Blog) Blog {
@@ -82,5 +242,8 @@ func toBlog(dbB db.Blog) Blog {
 		AuthorID:        dbB.AuthorID,
 		Content:         dbB.Content,
 		LinkedProductID: dbB.ProductID,
+		Status:          dbB.Status,
+		CreatedAt:       dbB.CreatedAt.Time.Unix(),
+		UpdatedAt:       dbB.UpdatedAt.Time.Unix(),
 	}
-}
\ No newline at end of file
+}
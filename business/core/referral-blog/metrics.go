@@ -0,0 +1,8 @@
+package referralblog
+
+import "soda-interview/foundation/metrics"
+
+var (
+	blogsModerated = metrics.NewCounter("soda_blogs_moderated_total", "Moderation decisions on referral blogs.", "status")
+	referralVisits = metrics.NewCounter("soda_referral_visits_total", "Blog visits recorded for last-touch attribution.")
+)
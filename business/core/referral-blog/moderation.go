@@ -0,0 +1,263 @@
+package referralblog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"soda-interview/business/data/stores/db"
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	"soda-interview/foundation/validate"
+)
+
+// UpdateBlog holds the changes an author makes. Nil fields are left as they
+// are.
+type UpdateBlog struct {
+	Content *string
+	// Status may only be DRAFT or PENDING_REVIEW; see authorTransitions.
+	Status *string
+}
+
+// Validate checks the fields being changed.
+func (ub UpdateBlog) Validate() error {
+	var fe validate.FieldErrors
+	if ub.Content != nil && *ub.Content == "" {
+		fe.Add("content", "must not be empty")
+	}
+	if ub.Status != nil && *ub.Status != blogstore.StatusDraft && *ub.Status != blogstore.StatusPendingReview {
+		fe.Add("status", "must be DRAFT or PENDING_REVIEW")
+	}
+	return fe.Err()
+}
+
+// Moderation is a moderator's decision on a blog.
+type Moderation struct {
+	Status string // PUBLISHED, REJECTED or TAKEN_DOWN
+	// Reason is shown to the author. Required unless publishing.
+	Reason string
+}
+
+// Validate checks that the decision is complete.
+func (m Moderation) Validate() error {
+	var fe validate.FieldErrors
+	switch m.Status {
+	case blogstore.StatusPublished:
+	case blogstore.StatusRejected, blogstore.StatusTakenDown:
+		if m.Reason == "" {
+			fe.Add("reason", "is required when rejecting or taking down a blog")
+		}
+	case "":
+		fe.Add("status", "is required")
+	default:
+		fe.Add("status", "must be PUBLISHED, REJECTED or TAKEN_DOWN")
+	}
+	return fe.Err()
+}
+
+// Revision is the state a change left a blog in.
+type Revision struct {
+	ID        int64
+	BlogID    string
+	Action    string
+	Content   string
+	Status    string
+	ActorID   string
+	Note      string
+	CreatedAt int64 // Unix timestamp
+}
+
+// UpdateBlog applies an author's changes. Editing the content of a blog
+// outside DRAFT sends it back to PENDING_REVIEW: it stops earning referral
+// rewards until a moderator publishes the new version.
+func (s *Service) UpdateBlog(ctx context.Context, id, authorID string, ub UpdateBlog) (Blog, error) {
+	var fe validate.FieldErrors
+	if id == "" {
+		fe.Add("id", "is required")
+	}
+	if authorID == "" {
+		fe.Add("author_id", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return Blog{}, err
+	}
+	if err := ub.Validate(); err != nil {
+		return Blog{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Blog{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := s.store.WithTx(tx)
+
+	current, err := s.lockOwnBlog(ctx, txStore, id, authorID)
+	if err != nil {
+		return Blog{}, err
+	}
+
+	content, status := current.Content, current.Status
+	if ub.Content != nil && *ub.Content != content {
+		content = *ub.Content
+		if status != blogstore.StatusDraft {
+			status = blogstore.StatusPendingReview
+		}
+	}
+	if ub.Status != nil && *ub.Status != status {
+		if err := checkTransition(authorTransitions, status, *ub.Status); err != nil {
+			return Blog{}, err
+		}
+		status = *ub.Status
+	}
+	if content == current.Content && status == current.Status {
+		return toBlog(current), nil
+	}
+
+	updated, err := txStore.UpdateBlog(ctx, id, content, status)
+	if err != nil {
+		return Blog{}, err
+	}
+	if err := txStore.AddRevision(ctx, updated, blogstore.ActionEdited, authorID, ""); err != nil {
+		return Blog{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Blog{}, fmt.Errorf("committing transaction: %w", err)
+	}
+	return toBlog(updated), nil
+}
+
+// DeleteBlog removes an author's blog from view. Orders it referred keep
+// their referral.
+func (s *Service) DeleteBlog(ctx context.Context, id, authorID string) error {
+	var fe validate.FieldErrors
+	if id == "" {
+		fe.Add("id", "is required")
+	}
+	if authorID == "" {
+		fe.Add("author_id", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := s.store.WithTx(tx)
+
+	if _, err := s.lockOwnBlog(ctx, txStore, id, authorID); err != nil {
+		return err
+	}
+	deleted, err := txStore.DeleteBlog(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := txStore.AddRevision(ctx, deleted, blogstore.ActionDeleted, authorID, ""); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// ModerateBlog records a moderator's decision: publishing a blog under
+// review, rejecting it, or taking a published blog down (and back up).
+func (s *Service) ModerateBlog(ctx context.Context, id, moderatorID string, m Moderation) (Blog, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return Blog{}, fe
+	}
+	if err := m.Validate(); err != nil {
+		return Blog{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Blog{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txStore := s.store.WithTx(tx)
+
+	current, err := txStore.GetBlogForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, blogstore.ErrNotFound) {
+			return Blog{}, ErrNotFound
+		}
+		return Blog{}, err
+	}
+	if err := checkTransition(moderatorTransitions, current.Status, m.Status); err != nil {
+		return Blog{}, err
+	}
+
+	updated, err := txStore.UpdateBlog(ctx, id, current.Content, m.Status)
+	if err != nil {
+		return Blog{}, err
+	}
+	if err := txStore.AddRevision(ctx, updated, blogstore.ActionModerated, moderatorID, m.Reason); err != nil {
+		return Blog{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Blog{}, fmt.Errorf("committing transaction: %w", err)
+	}
+	blogsModerated.Inc(m.Status)
+
+	return toBlog(updated), nil
+}
+
+// ListRevisions returns the history of a blog, oldest first.
+func (s *Service) ListRevisions(ctx context.Context, id string) ([]Revision, error) {
+	if id == "" {
+		var fe validate.FieldErrors
+		fe.Add("id", "is required")
+		return nil, fe
+	}
+
+	revs, err := s.store.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Revision, len(revs))
+	for i, r := range revs {
+		out[i] = toRevision(r)
+	}
+	return out, nil
+}
+
+// lockOwnBlog locks a blog for a change by its author.
+func (s *Service) lockOwnBlog(ctx context.Context, txStore *blogstore.Store, id, authorID string) (db.Blog, error) {
+	b, err := txStore.GetBlogForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, blogstore.ErrNotFound) {
+			return db.Blog{}, ErrNotFound
+		}
+		return db.Blog{}, err
+	}
+	if b.AuthorID != authorID {
+		return db.Blog{}, ErrNotAuthor
+	}
+	return b, nil
+}
+
+func toRevision(r db.BlogRevision) Revision {
+	return Revision{
+		ID:        r.ID,
+		BlogID:    r.BlogID,
+		Action:    r.Action,
+		Content:   r.Content,
+		Status:    r.Status,
+		ActorID:   r.ActorID,
+		Note:      r.Note,
+		CreatedAt: r.CreatedAt.Time.Unix(),
+	}
+}
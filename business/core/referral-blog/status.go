@@ -0,0 +1,34 @@
+package referralblog
+
+import (
+	"fmt"
+	"slices"
+
+	blogstore "soda-interview/business/data/stores/referral-blog"
+)
+
+// authorTransitions lists the statuses an author may move a blog to from
+// each status: submitting a draft for review, or taking a blog back to draft.
+// Only a moderator publishes.
+var authorTransitions = map[string][]string{
+	blogstore.StatusDraft:         {blogstore.StatusPendingReview},
+	blogstore.StatusPendingReview: {blogstore.StatusDraft},
+	blogstore.StatusPublished:     {blogstore.StatusDraft},
+	blogstore.StatusRejected:      {blogstore.StatusDraft, blogstore.StatusPendingReview},
+	blogstore.StatusTakenDown:     {blogstore.StatusDraft, blogstore.StatusPendingReview},
+}
+
+// moderatorTransitions lists the statuses a moderator may move a blog to
+// from each status.
+var moderatorTransitions = map[string][]string{
+	blogstore.StatusPendingReview: {blogstore.StatusPublished, blogstore.StatusRejected},
+	blogstore.StatusPublished:     {blogstore.StatusTakenDown},
+	blogstore.StatusTakenDown:     {blogstore.StatusPublished},
+}
+
+func checkTransition(transitions map[string][]string, from, to string) error {
+	if !slices.Contains(transitions[from], to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package referralblog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	blogstore "soda-interview/business/data/stores/referral-blog"
+	"soda-interview/foundation/validate"
+)
+
+type Visit struct {
+	ID        int64
+	VisitorID string
+	BlogID    string
+	VisitedAt int64 // Unix timestamp
+}
+
+// RecordVisit logs that the visitor viewed a published blog. Orders they
+// place for the blog's product without naming a blog are credited to their
+// latest visit within the attribution window. Blogs that are not published
+// are not found, as they would earn no referral.
+func (s *Service) RecordVisit(ctx context.Context, visitorID, blogID string) (Visit, error) {
+	var fe validate.FieldErrors
+	if visitorID == "" {
+		fe.Add("visitor_id", "is required")
+	}
+	if blogID == "" {
+		fe.Add("blog_id", "is required")
+	}
+	if err := fe.Err(); err != nil {
+		return Visit{}, err
+	}
+
+	b, err := s.store.GetBlog(ctx, blogID)
+	if err != nil {
+		if errors.Is(err, blogstore.ErrNotFound) {
+			return Visit{}, ErrNotFound
+		}
+		return Visit{}, fmt.Errorf("querying blog: %w", err)
+	}
+	if b.DeletedAt.Valid || b.Status != blogstore.StatusPublished {
+		return Visit{}, ErrNotFound
+	}
+
+	v, err := s.store.RecordVisit(ctx, visitorID, blogID)
+	if err != nil {
+		return Visit{}, err
+	}
+	referralVisits.Inc()
+
+	return Visit{
+		ID:        v.ID,
+		VisitorID: v.VisitorID,
+		BlogID:    v.BlogID,
+		VisitedAt: v.VisitedAt.Time.Unix(),
+	}, nil
+}
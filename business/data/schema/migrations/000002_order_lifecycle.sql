@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('PENDING', 'CONFIRMED', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUNDED'));
+
+CREATE INDEX transactions_related_order_id_idx ON transactions (related_order_id);
+
+-- +goose Down
+DROP INDEX transactions_related_order_id_idx;
+ALTER TABLE orders DROP CONSTRAINT orders_status_check;
+ALTER TABLE orders DROP COLUMN updated_at;
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE orders ALTER COLUMN blog_id DROP NOT NULL;
+
+-- +goose Down
+UPDATE orders SET blog_id = '' WHERE blog_id IS NULL;
+ALTER TABLE orders ALTER COLUMN blog_id SET NOT NULL;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX transactions_user_id_created_at_idx ON transactions (user_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX transactions_user_id_created_at_idx;
@@ -0,0 +1,68 @@
+-- +goose Up
+-- Accounts hold a single currency. User accounts are credit-normal: their
+-- balance is credits minus debits. owner_id is NULL for platform accounts.
+CREATE TABLE ledger_accounts (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT,
+    kind TEXT NOT NULL CHECK (kind IN ('USER_POINTS', 'USER_BALANCE', 'REWARD_POOL', 'CONVERSION_SINK', 'ADJUSTMENTS')),
+    currency TEXT NOT NULL CHECK (currency IN ('POINTS', 'YEN')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ledger_accounts_owner_id_idx ON ledger_accounts (owner_id);
+
+-- Every row in transactions is a journal header; its entries debit and credit
+-- equal amounts per currency.
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id),
+    account_id TEXT NOT NULL REFERENCES ledger_accounts(id),
+    direction TEXT NOT NULL CHECK (direction IN ('DEBIT', 'CREDIT')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL CHECK (currency IN ('POINTS', 'YEN')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ledger_entries_account_id_idx ON ledger_entries (account_id);
+CREATE INDEX ledger_entries_transaction_id_idx ON ledger_entries (transaction_id);
+
+-- Open the ledger from the current wallet totals. History recorded before
+-- this migration stays in transactions without entries.
+INSERT INTO ledger_accounts (id, owner_id, kind, currency) VALUES
+    ('platform:reward_pool', NULL, 'REWARD_POOL', 'POINTS'),
+    ('platform:conversion_sink:points', NULL, 'CONVERSION_SINK', 'POINTS'),
+    ('platform:conversion_sink:yen', NULL, 'CONVERSION_SINK', 'YEN'),
+    ('platform:adjustments:points', NULL, 'ADJUSTMENTS', 'POINTS'),
+    ('platform:adjustments:yen', NULL, 'ADJUSTMENTS', 'YEN');
+
+INSERT INTO ledger_accounts (id, owner_id, kind, currency)
+SELECT 'user:' || user_id || ':points', user_id, 'USER_POINTS', 'POINTS' FROM wallets
+UNION ALL
+SELECT 'user:' || user_id || ':balance', user_id, 'USER_BALANCE', 'YEN' FROM wallets;
+
+INSERT INTO transactions (id, user_id, type, amount)
+SELECT 'opening:' || user_id, user_id, 'OPENING_BALANCE', 0
+FROM wallets
+WHERE soda_points <> 0 OR soda_balance <> 0;
+
+INSERT INTO ledger_entries (transaction_id, account_id, direction, amount, currency)
+SELECT 'opening:' || user_id, 'user:' || user_id || ':points',
+       CASE WHEN soda_points > 0 THEN 'CREDIT' ELSE 'DEBIT' END, abs(soda_points), 'POINTS'
+FROM wallets WHERE soda_points <> 0
+UNION ALL
+SELECT 'opening:' || user_id, 'platform:adjustments:points',
+       CASE WHEN soda_points > 0 THEN 'DEBIT' ELSE 'CREDIT' END, abs(soda_points), 'POINTS'
+FROM wallets WHERE soda_points <> 0
+UNION ALL
+SELECT 'opening:' || user_id, 'user:' || user_id || ':balance',
+       CASE WHEN soda_balance > 0 THEN 'CREDIT' ELSE 'DEBIT' END, abs(soda_balance), 'YEN'
+FROM wallets WHERE soda_balance <> 0
+UNION ALL
+SELECT 'opening:' || user_id, 'platform:adjustments:yen',
+       CASE WHEN soda_balance > 0 THEN 'DEBIT' ELSE 'CREDIT' END, abs(soda_balance), 'YEN'
+FROM wallets WHERE soda_balance <> 0;
+
+-- +goose Down
+DROP TABLE ledger_entries;
+DROP TABLE ledger_accounts;
+DELETE FROM transactions WHERE type = 'OPENING_BALANCE';
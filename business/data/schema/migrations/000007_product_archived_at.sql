@@ -0,0 +1,6 @@
+-- +goose Up
+-- Archived products stay in place so orders keep their foreign key.
+ALTER TABLE products ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN archived_at;
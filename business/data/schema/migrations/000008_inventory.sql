@@ -0,0 +1,29 @@
+-- +goose Up
+-- Products without a row here are not stock-tracked and never run out.
+-- available = on_hand - reserved; reserved units belong to unshipped orders.
+CREATE TABLE product_stock (
+    product_id TEXT PRIMARY KEY REFERENCES products(id),
+    on_hand INTEGER NOT NULL DEFAULT 0,
+    reserved INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (reserved >= 0),
+    CHECK (on_hand >= reserved)
+);
+
+CREATE TABLE inventory_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id),
+    order_id TEXT REFERENCES orders(id),
+    kind TEXT NOT NULL CHECK (kind IN ('ADJUST', 'RESERVE', 'RELEASE', 'SHIP')),
+    on_hand_delta INTEGER NOT NULL,
+    reserved_delta INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX inventory_movements_product_id_idx ON inventory_movements (product_id, created_at);
+CREATE INDEX inventory_movements_order_id_idx ON inventory_movements (order_id);
+
+-- +goose Down
+DROP TABLE inventory_movements;
+DROP TABLE product_stock;
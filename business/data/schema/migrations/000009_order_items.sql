@@ -0,0 +1,26 @@
+-- +goose Up
+-- Every order has one row per line. Cart orders span several products, so
+-- orders.product_id is only set for orders placed through PlaceOrder.
+CREATE TABLE order_items (
+    order_id TEXT NOT NULL REFERENCES orders(id),
+    line_no INTEGER NOT NULL,
+    product_id TEXT NOT NULL REFERENCES products(id),
+    blog_id TEXT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    PRIMARY KEY (order_id, line_no)
+);
+
+CREATE INDEX order_items_product_id_idx ON order_items (product_id);
+
+INSERT INTO order_items (order_id, line_no, product_id, blog_id, quantity, unit_price, amount)
+SELECT id, 1, product_id, NULLIF(blog_id, ''), 1, amount, amount FROM orders;
+
+ALTER TABLE orders ALTER COLUMN product_id DROP NOT NULL;
+
+-- +goose Down
+DROP TABLE order_items;
+DELETE FROM inventory_movements WHERE order_id IN (SELECT id FROM orders WHERE product_id IS NULL);
+DELETE FROM orders WHERE product_id IS NULL;
+ALTER TABLE orders ALTER COLUMN product_id SET NOT NULL;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- A conversion policy takes effect at effective_from and stays in force until
+-- a later policy does. Rows are never updated, so the version recorded on a
+-- CONVERTED transaction always explains how it was priced.
+CREATE TABLE conversion_policies (
+    version BIGSERIAL PRIMARY KEY,
+    points_per_yen BIGINT NOT NULL CHECK (points_per_yen > 0),
+    min_balance BIGINT NOT NULL CHECK (min_balance >= 0),
+    min_points BIGINT NOT NULL CHECK (min_points > 0),
+    max_points BIGINT CHECK (max_points >= min_points),
+    daily_cap_points BIGINT CHECK (daily_cap_points > 0),
+    effective_from TIMESTAMPTZ NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX conversion_policies_effective_from_idx ON conversion_policies (effective_from);
+
+-- Version 1 is the rule that was hard-coded before: more than 1000 points
+-- are needed, and 2 points buy 1 yen.
+INSERT INTO conversion_policies (points_per_yen, min_balance, min_points, effective_from, created_by)
+VALUES (2, 1001, 2, '1970-01-01T00:00:00Z', 'migration');
+
+ALTER TABLE transactions ADD COLUMN policy_version BIGINT REFERENCES conversion_policies(version);
+
+UPDATE transactions SET policy_version = 1 WHERE type = 'CONVERTED';
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN policy_version;
+DROP TABLE conversion_policies;
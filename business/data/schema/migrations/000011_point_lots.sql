@@ -0,0 +1,33 @@
+-- +goose Up
+-- Each EARNED transaction opens a lot. Spending points draws lots down
+-- oldest expiry first; the sweeper expires whatever is left at expires_at.
+-- Points held outside lots (balances from before this migration, manual
+-- adjustments) do not expire.
+CREATE TABLE point_lots (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    remaining BIGINT NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+    expires_at TIMESTAMPTZ, -- NULL never expires.
+    expired_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX point_lots_user_id_open_idx ON point_lots (user_id, expires_at, id) WHERE remaining > 0;
+CREATE INDEX point_lots_expires_at_open_idx ON point_lots (expires_at) WHERE remaining > 0;
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT ledger_accounts_kind_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_kind_check
+    CHECK (kind IN ('USER_POINTS', 'USER_BALANCE', 'REWARD_POOL', 'CONVERSION_SINK', 'ADJUSTMENTS', 'EXPIRED_POINTS'));
+
+INSERT INTO ledger_accounts (id, owner_id, kind, currency) VALUES
+    ('platform:expired_points', NULL, 'EXPIRED_POINTS', 'POINTS');
+
+-- +goose Down
+DELETE FROM ledger_entries WHERE account_id = 'platform:expired_points';
+DELETE FROM ledger_accounts WHERE id = 'platform:expired_points';
+ALTER TABLE ledger_accounts DROP CONSTRAINT ledger_accounts_kind_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_kind_check
+    CHECK (kind IN ('USER_POINTS', 'USER_BALANCE', 'REWARD_POOL', 'CONVERSION_SINK', 'ADJUSTMENTS'));
+DROP TABLE point_lots;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- balance_amount is the part of an order paid from the buyer's Soda Balance;
+-- the rest (amount - balance_amount) is paid externally.
+ALTER TABLE orders ADD COLUMN balance_amount BIGINT NOT NULL DEFAULT 0
+    CHECK (balance_amount >= 0 AND balance_amount <= amount);
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT ledger_accounts_kind_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_kind_check
+    CHECK (kind IN ('USER_POINTS', 'USER_BALANCE', 'REWARD_POOL', 'CONVERSION_SINK', 'ADJUSTMENTS', 'EXPIRED_POINTS', 'BALANCE_PAYMENTS'));
+
+INSERT INTO ledger_accounts (id, owner_id, kind, currency) VALUES
+    ('platform:balance_payments:yen', NULL, 'BALANCE_PAYMENTS', 'YEN');
+
+-- +goose Down
+ALTER TABLE ledger_accounts DROP CONSTRAINT ledger_accounts_kind_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_kind_check
+    CHECK (kind IN ('USER_POINTS', 'USER_BALANCE', 'REWARD_POOL', 'CONVERSION_SINK', 'ADJUSTMENTS', 'EXPIRED_POINTS'));
+ALTER TABLE orders DROP COLUMN balance_amount;
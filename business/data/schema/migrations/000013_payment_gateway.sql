@@ -0,0 +1,24 @@
+-- +goose Up
+-- Orders with an external amount wait in PENDING_PAYMENT until the payment
+-- gateway confirms the authorization. payment_id is the gateway's reference.
+ALTER TABLE orders DROP CONSTRAINT orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('PENDING', 'PENDING_PAYMENT', 'CONFIRMED', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUNDED'));
+
+ALTER TABLE orders ADD COLUMN payment_id TEXT;
+CREATE UNIQUE INDEX orders_payment_id_idx ON orders (payment_id) WHERE payment_id IS NOT NULL;
+
+-- The rewards a line pays out once its order is confirmed, fixed when the
+-- order is placed. Lines placed before this migration were paid already.
+ALTER TABLE order_items
+    ADD COLUMN buyer_reward_points INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN author_reward_points INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE order_items DROP COLUMN author_reward_points, DROP COLUMN buyer_reward_points;
+DROP INDEX orders_payment_id_idx;
+ALTER TABLE orders DROP COLUMN payment_id;
+UPDATE orders SET status = 'CANCELLED' WHERE status = 'PENDING_PAYMENT';
+ALTER TABLE orders DROP CONSTRAINT orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('PENDING', 'CONFIRMED', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUNDED'));
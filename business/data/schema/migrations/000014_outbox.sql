@@ -0,0 +1,22 @@
+-- +goose Up
+-- Domain events written in the same transaction as the change they describe
+-- and published afterwards by the outbox relay. Events that keep failing are
+-- left in DEAD for an operator to look at.
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PUBLISHED', 'DEAD')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (next_attempt_at, id) WHERE status = 'PENDING';
+
+-- +goose Down
+DROP TABLE outbox_events;
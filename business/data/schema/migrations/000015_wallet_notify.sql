@@ -0,0 +1,20 @@
+-- +goose Up
+-- Every committed change to a wallet notifies wallet_changed with the user
+-- ID, whichever code path made it. Postgres delivers notifications only when
+-- the transaction commits, so listeners never see a change that rolled back.
+-- +goose StatementBegin
+CREATE FUNCTION notify_wallet_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('wallet_changed', NEW.user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER wallets_notify_changed
+    AFTER INSERT OR UPDATE ON wallets
+    FOR EACH ROW EXECUTE FUNCTION notify_wallet_changed();
+
+-- +goose Down
+DROP TRIGGER wallets_notify_changed ON wallets;
+DROP FUNCTION notify_wallet_changed();
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Blogs only earn referral rewards once a moderator has published them.
+-- Blogs from before moderation were already live, so they start PUBLISHED.
+ALTER TABLE blogs ADD COLUMN status TEXT NOT NULL DEFAULT 'PUBLISHED'
+    CONSTRAINT blogs_status_check
+    CHECK (status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'REJECTED', 'TAKEN_DOWN'));
+ALTER TABLE blogs ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE blogs ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE blogs ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+-- Deleted blogs stay in place so orders keep their referral.
+ALTER TABLE blogs ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- One row per change to a blog, holding the content and status it left
+-- behind.
+CREATE TABLE blog_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    blog_id TEXT NOT NULL REFERENCES blogs(id),
+    action TEXT NOT NULL CHECK (action IN ('CREATED', 'EDITED', 'MODERATED', 'DELETED')),
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '', -- The moderator's reason, if any.
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX blog_revisions_blog_id_idx ON blog_revisions (blog_id, id);
+
+-- +goose Down
+DROP TABLE blog_revisions;
+ALTER TABLE blogs DROP COLUMN deleted_at;
+ALTER TABLE blogs DROP COLUMN updated_at;
+ALTER TABLE blogs DROP COLUMN created_at;
+ALTER TABLE blogs DROP COLUMN status;
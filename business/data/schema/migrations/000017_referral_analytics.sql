@@ -0,0 +1,11 @@
+-- +goose Up
+-- Referral analytics aggregate order lines by blog and author over a range
+-- of order dates.
+CREATE INDEX order_items_blog_id_idx ON order_items (blog_id) WHERE blog_id IS NOT NULL;
+CREATE INDEX blogs_author_id_idx ON blogs (author_id);
+CREATE INDEX orders_created_at_idx ON orders (created_at);
+
+-- +goose Down
+DROP INDEX orders_created_at_idx;
+DROP INDEX blogs_author_id_idx;
+DROP INDEX order_items_blog_id_idx;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Blog views, so that an order placed without a blog can still be credited
+-- to the last blog the buyer read about the product.
+CREATE TABLE referral_visits (
+    id BIGSERIAL PRIMARY KEY,
+    visitor_id TEXT NOT NULL,
+    blog_id TEXT NOT NULL REFERENCES blogs(id),
+    visited_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX referral_visits_visitor_id_idx ON referral_visits (visitor_id, visited_at);
+
+-- How a referred line got its blog: REQUEST when the client named it,
+-- LAST_TOUCH when it came from the buyer's visits. Lines without a blog have
+-- none. Like product_id and blog_id, orders only repeat it for single-line
+-- orders.
+ALTER TABLE order_items ADD COLUMN attribution TEXT
+    CONSTRAINT order_items_attribution_check CHECK (attribution IN ('REQUEST', 'LAST_TOUCH'));
+ALTER TABLE orders ADD COLUMN attribution TEXT
+    CONSTRAINT orders_attribution_check CHECK (attribution IN ('REQUEST', 'LAST_TOUCH'));
+
+UPDATE order_items SET attribution = 'REQUEST' WHERE blog_id IS NOT NULL;
+UPDATE orders SET attribution = 'REQUEST' WHERE blog_id IS NOT NULL AND blog_id <> '';
+
+ALTER TABLE order_items ADD CONSTRAINT order_items_attribution_blog_check
+    CHECK ((attribution IS NULL) = (blog_id IS NULL));
+
+-- +goose Down
+ALTER TABLE orders DROP COLUMN attribution;
+ALTER TABLE order_items DROP COLUMN attribution;
+DROP TABLE referral_visits;
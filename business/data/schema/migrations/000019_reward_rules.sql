@@ -0,0 +1,53 @@
+-- +goose Up
+-- Reward rules add to the reward points set on products. They are evaluated
+-- in priority order, then id, when an order is placed; each one matching a
+-- line adds to the buyer's or the author's points for it:
+--   BONUS             value points;
+--   PERCENT_OF_PRICE  value basis points of the line amount;
+--   MULTIPLIER        the points so far times value percent (200 doubles).
+-- Like conversion policies, rules are never edited, so the ids recorded on
+-- EARNED transactions always explain them. A rule is stopped by ending it.
+CREATE TABLE reward_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    recipient TEXT NOT NULL CHECK (recipient IN ('BUYER', 'AUTHOR')),
+    kind TEXT NOT NULL CHECK (kind IN ('BONUS', 'PERCENT_OF_PRICE', 'MULTIPLIER')),
+    value BIGINT NOT NULL CHECK (value > 0),
+    priority INTEGER NOT NULL DEFAULT 0,
+    -- Conditions. NULL leaves one open.
+    product_id TEXT REFERENCES products(id),
+    first_purchase_only BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Author tiers: the author's lifetime referred sales in yen, before the
+    -- order, must fall in [min_author_sales, max_author_sales).
+    min_author_sales BIGINT NOT NULL DEFAULT 0 CHECK (min_author_sales >= 0),
+    max_author_sales BIGINT CHECK (max_author_sales > min_author_sales),
+    -- The most points one user can earn from the rule over its lifetime.
+    user_cap_points BIGINT CHECK (user_cap_points > 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ CHECK (ends_at >= starts_at),
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX reward_rules_starts_at_idx ON reward_rules (starts_at);
+
+-- The points each rule added to each order line, for caps and for the
+-- EARNED transactions paid out once the order is confirmed.
+CREATE TABLE reward_grants (
+    order_id TEXT NOT NULL,
+    line_no INTEGER NOT NULL,
+    rule_id BIGINT NOT NULL REFERENCES reward_rules(id),
+    user_id TEXT NOT NULL,
+    points BIGINT NOT NULL CHECK (points > 0),
+    PRIMARY KEY (order_id, line_no, rule_id),
+    FOREIGN KEY (order_id, line_no) REFERENCES order_items(order_id, line_no)
+);
+
+CREATE INDEX reward_grants_rule_id_user_id_idx ON reward_grants (rule_id, user_id);
+
+ALTER TABLE transactions ADD COLUMN reward_rule_ids BIGINT[];
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN reward_rule_ids;
+DROP TABLE reward_grants;
+DROP TABLE reward_rules;
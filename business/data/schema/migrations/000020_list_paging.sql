@@ -0,0 +1,17 @@
+-- +goose Up
+-- ListProducts and ListBlogs page by keyset on (sort key, id), one index per
+-- sort. Products that exist before this migration share one created_at, so
+-- id orders them.
+ALTER TABLE products ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX products_created_at_id_idx ON products (created_at, id) WHERE archived_at IS NULL;
+CREATE INDEX products_price_id_idx ON products (price, id) WHERE archived_at IS NULL;
+CREATE INDEX blogs_created_at_id_idx ON blogs (created_at, id) WHERE status = 'PUBLISHED' AND deleted_at IS NULL;
+CREATE INDEX blogs_product_id_idx ON blogs (product_id);
+
+-- +goose Down
+DROP INDEX blogs_product_id_idx;
+DROP INDEX blogs_created_at_id_idx;
+DROP INDEX products_price_id_idx;
+DROP INDEX products_created_at_id_idx;
+ALTER TABLE products DROP COLUMN created_at;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Gateway calls owed by order status changes. The action commits with the
+-- change that owes it and is run afterwards, so no gateway call happens
+-- inside an order's transaction. Actions that keep failing are left in DEAD
+-- for an operator to look at. A payment's actions run in id order.
+CREATE TABLE payment_actions (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL REFERENCES orders(id),
+    payment_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('CAPTURE', 'VOID', 'REFUND')),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DONE', 'DEAD')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    done_at TIMESTAMPTZ
+);
+
+CREATE INDEX payment_actions_pending_idx ON payment_actions (next_attempt_at, id) WHERE status = 'PENDING';
+CREATE INDEX payment_actions_payment_id_idx ON payment_actions (payment_id, id) WHERE status = 'PENDING';
+CREATE INDEX payment_actions_order_id_idx ON payment_actions (order_id);
+
+-- +goose Down
+DROP TABLE payment_actions;
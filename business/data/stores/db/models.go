@@ -9,29 +9,193 @@ import (
 )
 
 type Blog struct {
-	ID        string `json:"id"`
-	AuthorID  string `json:"author_id"`
-	Content   string `json:"content"`
-	ProductID string `json:"product_id"`
-}
-
-type Order struct {
 	ID        string             `json:"id"`
-	BuyerID   string             `json:"buyer_id"`
+	AuthorID  string             `json:"author_id"`
+	Content   string             `json:"content"`
 	ProductID string             `json:"product_id"`
+	Status    string             `json:"status"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type BlogRevision struct {
+	ID        int64              `json:"id"`
 	BlogID    string             `json:"blog_id"`
-	Amount    int64              `json:"amount"`
+	Action    string             `json:"action"`
+	Content   string             `json:"content"`
 	Status    string             `json:"status"`
+	ActorID   string             `json:"actor_id"`
+	Note      string             `json:"note"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ConversionPolicy struct {
+	Version        int64              `json:"version"`
+	PointsPerYen   int64              `json:"points_per_yen"`
+	MinBalance     int64              `json:"min_balance"`
+	MinPoints      int64              `json:"min_points"`
+	MaxPoints      pgtype.Int8        `json:"max_points"`
+	DailyCapPoints pgtype.Int8        `json:"daily_cap_points"`
+	EffectiveFrom  pgtype.Timestamptz `json:"effective_from"`
+	CreatedBy      string             `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	UserID         string             `json:"user_id"`
+	IdempotencyKey string             `json:"idempotency_key"`
+	Operation      string             `json:"operation"`
+	RequestHash    string             `json:"request_hash"`
+	Response       []byte             `json:"response"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+type InventoryMovement struct {
+	ID            int64              `json:"id"`
+	ProductID     string             `json:"product_id"`
+	OrderID       pgtype.Text        `json:"order_id"`
+	Kind          string             `json:"kind"`
+	OnHandDelta   int32              `json:"on_hand_delta"`
+	ReservedDelta int32              `json:"reserved_delta"`
+	Reason        string             `json:"reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID        string             `json:"id"`
+	OwnerID   pgtype.Text        `json:"owner_id"`
+	Kind      string             `json:"kind"`
+	Currency  string             `json:"currency"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type LedgerEntry struct {
+	ID            int64              `json:"id"`
+	TransactionID string             `json:"transaction_id"`
+	AccountID     string             `json:"account_id"`
+	Direction     string             `json:"direction"`
+	Amount        int64              `json:"amount"`
+	Currency      string             `json:"currency"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type Order struct {
+	ID            string             `json:"id"`
+	BuyerID       string             `json:"buyer_id"`
+	ProductID     pgtype.Text        `json:"product_id"`
+	BlogID        pgtype.Text        `json:"blog_id"`
+	Amount        int64              `json:"amount"`
+	Status        string             `json:"status"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	BalanceAmount int64              `json:"balance_amount"`
+	PaymentID     pgtype.Text        `json:"payment_id"`
+	Attribution   pgtype.Text        `json:"attribution"`
+}
+
+type OrderItem struct {
+	OrderID            string      `json:"order_id"`
+	LineNo             int32       `json:"line_no"`
+	ProductID          string      `json:"product_id"`
+	BlogID             pgtype.Text `json:"blog_id"`
+	Quantity           int32       `json:"quantity"`
+	UnitPrice          int64       `json:"unit_price"`
+	Amount             int64       `json:"amount"`
+	BuyerRewardPoints  int32       `json:"buyer_reward_points"`
+	AuthorRewardPoints int32       `json:"author_reward_points"`
+	Attribution        pgtype.Text `json:"attribution"`
+}
+
+type OutboxEvent struct {
+	ID            int64              `json:"id"`
+	EventID       string             `json:"event_id"`
+	EventType     string             `json:"event_type"`
+	AggregateID   string             `json:"aggregate_id"`
+	Payload       []byte             `json:"payload"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	PublishedAt   pgtype.Timestamptz `json:"published_at"`
+}
+
+type PaymentAction struct {
+	ID            int64              `json:"id"`
+	OrderID       string             `json:"order_id"`
+	PaymentID     string             `json:"payment_id"`
+	Action        string             `json:"action"`
+	Amount        int64              `json:"amount"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	DoneAt        pgtype.Timestamptz `json:"done_at"`
+}
+
+type PointLot struct {
+	ID            int64              `json:"id"`
+	UserID        string             `json:"user_id"`
+	TransactionID string             `json:"transaction_id"`
+	Amount        int64              `json:"amount"`
+	Remaining     int64              `json:"remaining"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	ExpiredAt     pgtype.Timestamptz `json:"expired_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type Product struct {
-	ID                 string `json:"id"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	Price              int64  `json:"price"`
-	BuyerRewardPoints  int32  `json:"buyer_reward_points"`
-	AuthorRewardPoints int32  `json:"author_reward_points"`
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Description        string             `json:"description"`
+	Price              int64              `json:"price"`
+	BuyerRewardPoints  int32              `json:"buyer_reward_points"`
+	AuthorRewardPoints int32              `json:"author_reward_points"`
+	ArchivedAt         pgtype.Timestamptz `json:"archived_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProductStock struct {
+	ProductID string             `json:"product_id"`
+	OnHand    int32              `json:"on_hand"`
+	Reserved  int32              `json:"reserved"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ReferralVisit struct {
+	ID        int64              `json:"id"`
+	VisitorID string             `json:"visitor_id"`
+	BlogID    string             `json:"blog_id"`
+	VisitedAt pgtype.Timestamptz `json:"visited_at"`
+}
+
+type RewardGrant struct {
+	OrderID string `json:"order_id"`
+	LineNo  int32  `json:"line_no"`
+	RuleID  int64  `json:"rule_id"`
+	UserID  string `json:"user_id"`
+	Points  int64  `json:"points"`
+}
+
+type RewardRule struct {
+	ID                int64              `json:"id"`
+	Name              string             `json:"name"`
+	Recipient         string             `json:"recipient"`
+	Kind              string             `json:"kind"`
+	Value             int64              `json:"value"`
+	Priority          int32              `json:"priority"`
+	ProductID         pgtype.Text        `json:"product_id"`
+	FirstPurchaseOnly bool               `json:"first_purchase_only"`
+	MinAuthorSales    int64              `json:"min_author_sales"`
+	MaxAuthorSales    pgtype.Int8        `json:"max_author_sales"`
+	UserCapPoints     pgtype.Int8        `json:"user_cap_points"`
+	StartsAt          pgtype.Timestamptz `json:"starts_at"`
+	EndsAt            pgtype.Timestamptz `json:"ends_at"`
+	CreatedBy         string             `json:"created_by"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
 
 type Transaction struct {
@@ -41,6 +205,8 @@ type Transaction struct {
 	Amount         int64              `json:"amount"`
 	RelatedOrderID pgtype.Text        `json:"related_order_id"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	PolicyVersion  pgtype.Int8        `json:"policy_version"`
+	RewardRuleIds  []int64            `json:"reward_rule_ids"`
 }
 
 type Wallet struct {
@@ -6,24 +6,166 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
-	AddBalance(ctx context.Context, arg AddBalanceParams) (Wallet, error)
-	AddPoints(ctx context.Context, arg AddPointsParams) (Wallet, error)
-	ConvertPointsToBalance(ctx context.Context, arg ConvertPointsToBalanceParams) (Wallet, error)
+	// Changes on_hand and logs the movement, creating the stock row for an
+	// untracked product. No row, and nothing written, when on_hand would fall
+	// below reserved. The proposed row never holds a negative on_hand, which
+	// would fail the CHECK before the conflict is seen.
+	AdjustStock(ctx context.Context, arg AdjustStockParams) (AdjustStockRow, error)
+	// Moves the cached wallet projection by the net effect of a ledger posting,
+	// creating the wallet on first use.
+	ApplyWalletDelta(ctx context.Context, arg ApplyWalletDeltaParams) (Wallet, error)
+	ArchiveProduct(ctx context.Context, id string) (Product, error)
+	// Inserts the key, or takes over an expired one. Returns no row while an
+	// unexpired record for the same (user_id, idempotency_key) exists.
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	// Due events, locked until the relay's transaction ends. Rows another relay
+	// holds are skipped, so replicas can relay side by side.
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error)
+	// The action, locked until the transaction ends, if it is pending and
+	// nothing before it for the same payment is. A row another transaction holds
+	// is skipped.
+	ClaimPaymentAction(ctx context.Context, id int64) (PaymentAction, error)
+	// Due actions that nothing earlier for the same payment is waiting on,
+	// locked like ClaimPaymentAction.
+	ClaimPaymentActions(ctx context.Context, arg ClaimPaymentActionsParams) ([]PaymentAction, error)
 	CountOrdersByBuyer(ctx context.Context, buyerID string) (int64, error)
+	// Counts live orders with a line for the product, cart orders included.
 	CountOrdersByBuyerAndProduct(ctx context.Context, arg CountOrdersByBuyerAndProductParams) (int64, error)
 	CreateBlog(ctx context.Context, arg CreateBlogParams) (Blog, error)
+	CreateBlogRevision(ctx context.Context, arg CreateBlogRevisionParams) (BlogRevision, error)
+	CreateConversionPolicy(ctx context.Context, arg CreateConversionPolicyParams) (ConversionPolicy, error)
+	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) error
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
+	CreatePaymentAction(ctx context.Context, arg CreatePaymentActionParams) (PaymentAction, error)
+	CreatePointLot(ctx context.Context, arg CreatePointLotParams) error
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateReferralVisit(ctx context.Context, arg CreateReferralVisitParams) (ReferralVisit, error)
+	CreateRewardGrant(ctx context.Context, arg CreateRewardGrantParams) error
+	CreateRewardRule(ctx context.Context, arg CreateRewardRuleParams) (RewardRule, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
 	CreateWallet(ctx context.Context, userID string) (Wallet, error)
+	// Takes yen from soda_balance only if enough is there. Returns no row
+	// otherwise, so the balance can never go negative.
+	DebitWalletBalance(ctx context.Context, arg DebitWalletBalanceParams) (Wallet, error)
+	DeleteBlog(ctx context.Context, id string) (Blog, error)
+	// Ends a rule that is still running or scheduled. A rule that has not started
+	// yet ends as it starts, so it never applies.
+	EndRewardRule(ctx context.Context, arg EndRewardRuleParams) (RewardRule, error)
+	EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) error
+	ExpirePointLot(ctx context.Context, id int64) error
+	// The policy in force now: the latest one whose effective_from has passed.
+	GetActiveConversionPolicy(ctx context.Context) (ConversionPolicy, error)
+	// Deleted blogs are returned too; callers check deleted_at.
 	GetBlog(ctx context.Context, id string) (Blog, error)
+	// Blocks UpdateBlog, ModerateBlog and DeleteBlog on the row until the
+	// transaction ends.
+	GetBlogForShare(ctx context.Context, id string) (Blog, error)
+	GetBlogForUpdate(ctx context.Context, id string) (Blog, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	// The visitor's most recent visit since the given time to a blog that could
+	// still refer their order of the product.
+	GetLatestReferralVisit(ctx context.Context, arg GetLatestReferralVisitParams) (ReferralVisit, error)
+	GetOrder(ctx context.Context, id string) (Order, error)
+	GetOrderForUpdate(ctx context.Context, id string) (Order, error)
+	GetPointLot(ctx context.Context, id int64) (PointLot, error)
+	GetPointLotForUpdate(ctx context.Context, id int64) (PointLot, error)
 	GetProduct(ctx context.Context, id string) (Product, error)
+	// Blocks ArchiveProduct and UpdateProduct on the row until the transaction ends.
+	GetProductForShare(ctx context.Context, id string) (Product, error)
+	GetRewardRule(ctx context.Context, id int64) (RewardRule, error)
+	GetStock(ctx context.Context, productID string) (ProductStock, error)
 	GetWallet(ctx context.Context, userID string) (Wallet, error)
-	ListBlogs(ctx context.Context) ([]Blog, error)
-	ListProducts(ctx context.Context) ([]Product, error)
+	GetWalletForUpdate(ctx context.Context, userID string) (Wallet, error)
+	// The rules in force at the given time, in the order they are evaluated.
+	ListActiveRewardRules(ctx context.Context, at pgtype.Timestamptz) ([]RewardRule, error)
+	ListBlogRevisions(ctx context.Context, blogID string) ([]BlogRevision, error)
+	// ListBlogs* return published blogs strictly after the (cursor_created_at,
+	// cursor_id) keyset in their sort, like ListProducts*. NULL filters match
+	// every blog.
+	ListBlogsByCreatedAt(ctx context.Context, arg ListBlogsByCreatedAtParams) ([]Blog, error)
+	ListBlogsByCreatedAtDesc(ctx context.Context, arg ListBlogsByCreatedAtDescParams) ([]Blog, error)
+	ListConversionPolicies(ctx context.Context) ([]ConversionPolicy, error)
+	ListDuePointLots(ctx context.Context, arg ListDuePointLotsParams) ([]int64, error)
+	ListExpiringPointLots(ctx context.Context, arg ListExpiringPointLotsParams) ([]PointLot, error)
+	ListInventoryMovements(ctx context.Context, productID string) ([]InventoryMovement, error)
+	ListLedgerEntriesByTransaction(ctx context.Context, transactionID string) ([]LedgerEntry, error)
+	// Open lots the user's EARNED transactions for the order opened, in the
+	// order they are spent.
+	ListOpenOrderPointLotsForUpdate(ctx context.Context, arg ListOpenOrderPointLotsForUpdateParams) ([]PointLot, error)
+	// Open lots in the order they are spent: soonest expiry first, lots that
+	// never expire last.
+	ListOpenPointLotsForUpdate(ctx context.Context, userID string) ([]PointLot, error)
+	ListOrderItems(ctx context.Context, orderID string) ([]OrderItem, error)
+	ListOutboxEvents(ctx context.Context, aggregateID pgtype.Text) ([]OutboxEvent, error)
+	ListPaymentActions(ctx context.Context, orderID string) ([]PaymentAction, error)
+	// ListProducts* return products on sale strictly after the (cursor key,
+	// cursor_id) keyset in their sort, with id breaking ties in the same
+	// direction. Each sort has its own query so the keyset comparison can use
+	// the matching index. NULL filters match every product.
+	ListProductsByCreatedAt(ctx context.Context, arg ListProductsByCreatedAtParams) ([]Product, error)
+	ListProductsByCreatedAtDesc(ctx context.Context, arg ListProductsByCreatedAtDescParams) ([]Product, error)
+	ListProductsByPrice(ctx context.Context, arg ListProductsByPriceParams) ([]Product, error)
+	ListProductsByPriceDesc(ctx context.Context, arg ListProductsByPriceDescParams) ([]Product, error)
+	ListRewardGrantsByOrder(ctx context.Context, orderID string) ([]ListRewardGrantsByOrderRow, error)
+	ListRewardRules(ctx context.Context) ([]RewardRule, error)
+	ListTransactionsByOrder(ctx context.Context, relatedOrderID pgtype.Text) ([]Transaction, error)
+	// Newest first. Rows strictly after the (cursor_created_at, cursor_id) keyset
+	// are returned when a cursor is given; empty types matches every type.
+	ListTransactionsByUser(ctx context.Context, arg ListTransactionsByUserParams) ([]Transaction, error)
+	// Transactions whose entries do not net to zero in some currency.
+	ListUnbalancedTransactions(ctx context.Context) ([]ListUnbalancedTransactionsRow, error)
+	// Wallets whose cached totals differ from the sum of their ledger accounts.
+	ListWalletDrift(ctx context.Context) ([]ListWalletDriftRow, error)
+	// Waits for in-flight postings and blocks new ones until the transaction ends.
+	LockLedger(ctx context.Context) error
+	// Serializes orders drawing on the same user's cap for a rule until the
+	// transaction ends. The key hashes the full rule id with the user id, so
+	// rule ids past 32 bits do not share locks.
+	LockRewardCap(ctx context.Context, arg LockRewardCapParams) error
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkPaymentActionDone(ctx context.Context, id int64) error
+	MarkPaymentActionFailed(ctx context.Context, arg MarkPaymentActionFailedParams) error
+	// Recomputes every wallet from the ledger.
+	RebuildWallets(ctx context.Context) (int64, error)
+	// ReferralStatsByBlog rolled up per author.
+	ReferralStatsByAuthor(ctx context.Context, arg ReferralStatsByAuthorParams) ([]ReferralStatsByAuthorRow, error)
+	// Referred sales per blog: the lines of orders that still stand (confirmed,
+	// shipped or delivered) placed in [created_from, created_to).
+	ReferralStatsByBlog(ctx context.Context, arg ReferralStatsByBlogParams) ([]ReferralStatsByBlogRow, error)
+	// ReferralStatsByBlog totalled per UTC day or week (bucket is 'day' or
+	// 'week'). Buckets without sales are omitted.
+	ReferralStatsSeries(ctx context.Context, arg ReferralStatsSeriesParams) ([]ReferralStatsSeriesRow, error)
+	// Returns units reserved by a cancelled order to the available pool.
+	ReleaseStock(ctx context.Context, arg ReleaseStockParams) (ReleaseStockRow, error)
+	RequeueOutboxEvent(ctx context.Context, id int64) (int64, error)
+	// Holds quantity units for an order. No row when fewer are available.
+	ReserveStock(ctx context.Context, arg ReserveStockParams) (ReserveStockRow, error)
+	// Units of the product still held for the order.
+	ReservedForOrder(ctx context.Context, arg ReservedForOrderParams) (int32, error)
+	SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error
+	SetOrderPaymentID(ctx context.Context, arg SetOrderPaymentIDParams) (Order, error)
+	SetPointLotRemaining(ctx context.Context, arg SetPointLotRemainingParams) error
+	// Removes units reserved by a shipped order from on_hand.
+	ShipStock(ctx context.Context, arg ShipStockParams) (ShipStockRow, error)
+	SumConvertedPointsSince(ctx context.Context, arg SumConvertedPointsSinceParams) (int64, error)
+	// The author's lifetime referred sales in yen: the referred lines of orders
+	// that still stand.
+	SumReferredSalesByAuthor(ctx context.Context, authorID string) (int64, error)
+	// Points the user has earned from the rule on orders that were not cancelled
+	// or refunded.
+	SumRewardGrants(ctx context.Context, arg SumRewardGrantsParams) (int64, error)
+	UpdateBlog(ctx context.Context, arg UpdateBlogParams) (Blog, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	// NULL arguments leave the column unchanged. Archived products are not updated.
+	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
 }
 
 var _ Querier = (*Queries)(nil)
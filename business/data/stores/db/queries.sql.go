@@ -11,59 +11,270 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const addBalance = `-- name: AddBalance :one
-UPDATE wallets SET soda_balance = soda_balance + $1 WHERE user_id = $2 RETURNING user_id, soda_points, soda_balance
+const adjustStock = `-- name: AdjustStock :one
+WITH adjusted AS (
+    INSERT INTO product_stock (product_id, on_hand)
+    SELECT $1, GREATEST($2::INTEGER, 0)
+    WHERE $2 >= 0
+       OR EXISTS (SELECT 1 FROM product_stock s WHERE s.product_id = $1)
+    ON CONFLICT (product_id) DO UPDATE
+    SET on_hand = product_stock.on_hand + $2, updated_at = NOW()
+    WHERE product_stock.on_hand + $2 >= product_stock.reserved
+    RETURNING product_id, on_hand, reserved, updated_at
+), logged AS (
+    INSERT INTO inventory_movements (product_id, kind, on_hand_delta, reserved_delta, reason)
+    SELECT adjusted.product_id, 'ADJUST', $2, 0, $3 FROM adjusted
+)
+SELECT product_id, on_hand, reserved, updated_at FROM adjusted
 `
 
-type AddBalanceParams struct {
-	Amount int64  `json:"amount"`
-	UserID string `json:"user_id"`
+type AdjustStockParams struct {
+	ProductID string `json:"product_id"`
+	Delta     int32  `json:"delta"`
+	Reason    string `json:"reason"`
 }
 
-func (q *Queries) AddBalance(ctx context.Context, arg AddBalanceParams) (Wallet, error) {
-	row := q.db.QueryRow(ctx, addBalance, arg.Amount, arg.UserID)
-	var i Wallet
-	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
+type AdjustStockRow struct {
+	ProductID string             `json:"product_id"`
+	OnHand    int32              `json:"on_hand"`
+	Reserved  int32              `json:"reserved"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Changes on_hand and logs the movement, creating the stock row for an
+// untracked product. No row, and nothing written, when on_hand would fall
+// below reserved. The proposed row never holds a negative on_hand, which
+// would fail the CHECK before the conflict is seen.
+func (q *Queries) AdjustStock(ctx context.Context, arg AdjustStockParams) (AdjustStockRow, error) {
+	row := q.db.QueryRow(ctx, adjustStock, arg.ProductID, arg.Delta, arg.Reason)
+	var i AdjustStockRow
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.Reserved,
+		&i.UpdatedAt,
+	)
 	return i, err
 }
 
-const addPoints = `-- name: AddPoints :one
-UPDATE wallets SET soda_points = soda_points + $1 WHERE user_id = $2 RETURNING user_id, soda_points, soda_balance
+const applyWalletDelta = `-- name: ApplyWalletDelta :one
+INSERT INTO wallets (user_id, soda_points, soda_balance)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET soda_points = wallets.soda_points + EXCLUDED.soda_points,
+    soda_balance = wallets.soda_balance + EXCLUDED.soda_balance
+RETURNING user_id, soda_points, soda_balance
 `
 
-type AddPointsParams struct {
-	Amount int64  `json:"amount"`
-	UserID string `json:"user_id"`
+type ApplyWalletDeltaParams struct {
+	UserID       string `json:"user_id"`
+	PointsDelta  int64  `json:"points_delta"`
+	BalanceDelta int64  `json:"balance_delta"`
 }
 
-func (q *Queries) AddPoints(ctx context.Context, arg AddPointsParams) (Wallet, error) {
-	row := q.db.QueryRow(ctx, addPoints, arg.Amount, arg.UserID)
+// Moves the cached wallet projection by the net effect of a ledger posting,
+// creating the wallet on first use.
+func (q *Queries) ApplyWalletDelta(ctx context.Context, arg ApplyWalletDeltaParams) (Wallet, error) {
+	row := q.db.QueryRow(ctx, applyWalletDelta, arg.UserID, arg.PointsDelta, arg.BalanceDelta)
 	var i Wallet
 	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
 	return i, err
 }
 
-const convertPointsToBalance = `-- name: ConvertPointsToBalance :one
-UPDATE wallets 
-SET soda_points = soda_points - $1,
-    soda_balance = soda_balance + $2
-WHERE user_id = $3 AND soda_points >= $1
-RETURNING user_id, soda_points, soda_balance
+const archiveProduct = `-- name: ArchiveProduct :one
+UPDATE products SET archived_at = NOW() WHERE id = $1 AND archived_at IS NULL RETURNING id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at
 `
 
-type ConvertPointsToBalanceParams struct {
-	PointsDeducted int64  `json:"points_deducted"`
-	BalanceAdded   int64  `json:"balance_added"`
-	UserID         string `json:"user_id"`
+func (q *Queries) ArchiveProduct(ctx context.Context, id string) (Product, error) {
+	row := q.db.QueryRow(ctx, archiveProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.BuyerRewardPoints,
+		&i.AuthorRewardPoints,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-func (q *Queries) ConvertPointsToBalance(ctx context.Context, arg ConvertPointsToBalanceParams) (Wallet, error) {
-	row := q.db.QueryRow(ctx, convertPointsToBalance, arg.PointsDeducted, arg.BalanceAdded, arg.UserID)
-	var i Wallet
-	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (user_id, idempotency_key, operation, request_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET operation = EXCLUDED.operation,
+    request_hash = EXCLUDED.request_hash,
+    response = NULL,
+    created_at = NOW(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= NOW()
+RETURNING user_id, idempotency_key, operation, request_hash, response, created_at, expires_at
+`
+
+type ClaimIdempotencyKeyParams struct {
+	UserID         string             `json:"user_id"`
+	IdempotencyKey string             `json:"idempotency_key"`
+	Operation      string             `json:"operation"`
+	RequestHash    string             `json:"request_hash"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+// Inserts the key, or takes over an expired one. Returns no row while an
+// unexpired record for the same (user_id, idempotency_key) exists.
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey,
+		arg.UserID,
+		arg.IdempotencyKey,
+		arg.Operation,
+		arg.RequestHash,
+		arg.ExpiresAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.IdempotencyKey,
+		&i.Operation,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+SELECT id, event_id, event_type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, created_at, published_at FROM outbox_events
+WHERE status = 'PENDING' AND next_attempt_at <= $1
+ORDER BY id
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ClaimOutboxEventsParams struct {
+	Now       pgtype.Timestamptz `json:"now"`
+	BatchSize int32              `json:"batch_size"`
+}
+
+// Due events, locked until the relay's transaction ends. Rows another relay
+// holds are skipped, so replicas can relay side by side.
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error) {
+	rows, err := q.db.Query(ctx, claimOutboxEvents, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimPaymentAction = `-- name: ClaimPaymentAction :one
+SELECT id, order_id, payment_id, action, amount, status, attempts, next_attempt_at, last_error, created_at, done_at FROM payment_actions a
+WHERE a.id = $1 AND a.status = 'PENDING'
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_actions e
+    WHERE e.payment_id = a.payment_id AND e.id < a.id AND e.status = 'PENDING'
+  )
+FOR UPDATE SKIP LOCKED
+`
+
+// The action, locked until the transaction ends, if it is pending and
+// nothing before it for the same payment is. A row another transaction holds
+// is skipped.
+func (q *Queries) ClaimPaymentAction(ctx context.Context, id int64) (PaymentAction, error) {
+	row := q.db.QueryRow(ctx, claimPaymentAction, id)
+	var i PaymentAction
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.PaymentID,
+		&i.Action,
+		&i.Amount,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DoneAt,
+	)
 	return i, err
 }
 
+const claimPaymentActions = `-- name: ClaimPaymentActions :many
+SELECT id, order_id, payment_id, action, amount, status, attempts, next_attempt_at, last_error, created_at, done_at FROM payment_actions a
+WHERE a.status = 'PENDING' AND a.next_attempt_at <= $1
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_actions e
+    WHERE e.payment_id = a.payment_id AND e.id < a.id AND e.status = 'PENDING'
+  )
+ORDER BY a.id
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ClaimPaymentActionsParams struct {
+	Now       pgtype.Timestamptz `json:"now"`
+	BatchSize int32              `json:"batch_size"`
+}
+
+// Due actions that nothing earlier for the same payment is waiting on,
+// locked like ClaimPaymentAction.
+func (q *Queries) ClaimPaymentActions(ctx context.Context, arg ClaimPaymentActionsParams) ([]PaymentAction, error) {
+	rows, err := q.db.Query(ctx, claimPaymentActions, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PaymentAction
+	for rows.Next() {
+		var i PaymentAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PaymentID,
+			&i.Action,
+			&i.Amount,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DoneAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countOrdersByBuyer = `-- name: CountOrdersByBuyer :one
 SELECT COUNT(*) FROM orders WHERE buyer_id = $1
 `
@@ -76,7 +287,9 @@ func (q *Queries) CountOrdersByBuyer(ctx context.Context, buyerID string) (int64
 }
 
 const countOrdersByBuyerAndProduct = `-- name: CountOrdersByBuyerAndProduct :one
-SELECT COUNT(*) FROM orders WHERE buyer_id = $1 AND product_id = $2
+SELECT COUNT(DISTINCT o.id) FROM orders o
+JOIN order_items i ON i.order_id = o.id
+WHERE o.buyer_id = $1 AND i.product_id = $2 AND o.status NOT IN ('CANCELLED', 'REFUNDED')
 `
 
 type CountOrdersByBuyerAndProductParams struct {
@@ -84,6 +297,7 @@ type CountOrdersByBuyerAndProductParams struct {
 	ProductID string `json:"product_id"`
 }
 
+// Counts live orders with a line for the product, cart orders included.
 func (q *Queries) CountOrdersByBuyerAndProduct(ctx context.Context, arg CountOrdersByBuyerAndProductParams) (int64, error) {
 	row := q.db.QueryRow(ctx, countOrdersByBuyerAndProduct, arg.BuyerID, arg.ProductID)
 	var count int64
@@ -92,7 +306,7 @@ func (q *Queries) CountOrdersByBuyerAndProduct(ctx context.Context, arg CountOrd
 }
 
 const createBlog = `-- name: CreateBlog :one
-INSERT INTO blogs (id, author_id, content, product_id) VALUES ($1, $2, $3, $4) RETURNING id, author_id, content, product_id
+INSERT INTO blogs (id, author_id, content, product_id, status) VALUES ($1, $2, $3, $4, $5) RETURNING id, author_id, content, product_id, status, created_at, updated_at, deleted_at
 `
 
 type CreateBlogParams struct {
@@ -100,6 +314,7 @@ type CreateBlogParams struct {
 	AuthorID  string `json:"author_id"`
 	Content   string `json:"content"`
 	ProductID string `json:"product_id"`
+	Status    string `json:"status"`
 }
 
 func (q *Queries) CreateBlog(ctx context.Context, arg CreateBlogParams) (Blog, error) {
@@ -108,6 +323,7 @@ func (q *Queries) CreateBlog(ctx context.Context, arg CreateBlogParams) (Blog, e
 		arg.AuthorID,
 		arg.Content,
 		arg.ProductID,
+		arg.Status,
 	)
 	var i Blog
 	err := row.Scan(
@@ -115,22 +331,130 @@ func (q *Queries) CreateBlog(ctx context.Context, arg CreateBlogParams) (Blog, e
 		&i.AuthorID,
 		&i.Content,
 		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createBlogRevision = `-- name: CreateBlogRevision :one
+INSERT INTO blog_revisions (blog_id, action, content, status, actor_id, note)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, blog_id, action, content, status, actor_id, note, created_at
+`
+
+type CreateBlogRevisionParams struct {
+	BlogID  string `json:"blog_id"`
+	Action  string `json:"action"`
+	Content string `json:"content"`
+	Status  string `json:"status"`
+	ActorID string `json:"actor_id"`
+	Note    string `json:"note"`
+}
+
+func (q *Queries) CreateBlogRevision(ctx context.Context, arg CreateBlogRevisionParams) (BlogRevision, error) {
+	row := q.db.QueryRow(ctx, createBlogRevision,
+		arg.BlogID,
+		arg.Action,
+		arg.Content,
+		arg.Status,
+		arg.ActorID,
+		arg.Note,
+	)
+	var i BlogRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BlogID,
+		&i.Action,
+		&i.Content,
+		&i.Status,
+		&i.ActorID,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createConversionPolicy = `-- name: CreateConversionPolicy :one
+INSERT INTO conversion_policies (points_per_yen, min_balance, min_points, max_points, daily_cap_points, effective_from, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING version, points_per_yen, min_balance, min_points, max_points, daily_cap_points, effective_from, created_by, created_at
+`
+
+type CreateConversionPolicyParams struct {
+	PointsPerYen   int64              `json:"points_per_yen"`
+	MinBalance     int64              `json:"min_balance"`
+	MinPoints      int64              `json:"min_points"`
+	MaxPoints      pgtype.Int8        `json:"max_points"`
+	DailyCapPoints pgtype.Int8        `json:"daily_cap_points"`
+	EffectiveFrom  pgtype.Timestamptz `json:"effective_from"`
+	CreatedBy      string             `json:"created_by"`
+}
+
+func (q *Queries) CreateConversionPolicy(ctx context.Context, arg CreateConversionPolicyParams) (ConversionPolicy, error) {
+	row := q.db.QueryRow(ctx, createConversionPolicy,
+		arg.PointsPerYen,
+		arg.MinBalance,
+		arg.MinPoints,
+		arg.MaxPoints,
+		arg.DailyCapPoints,
+		arg.EffectiveFrom,
+		arg.CreatedBy,
+	)
+	var i ConversionPolicy
+	err := row.Scan(
+		&i.Version,
+		&i.PointsPerYen,
+		&i.MinBalance,
+		&i.MinPoints,
+		&i.MaxPoints,
+		&i.DailyCapPoints,
+		&i.EffectiveFrom,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const createLedgerEntry = `-- name: CreateLedgerEntry :exec
+INSERT INTO ledger_entries (transaction_id, account_id, direction, amount, currency)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateLedgerEntryParams struct {
+	TransactionID string `json:"transaction_id"`
+	AccountID     string `json:"account_id"`
+	Direction     string `json:"direction"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+}
+
+func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) error {
+	_, err := q.db.Exec(ctx, createLedgerEntry,
+		arg.TransactionID,
+		arg.AccountID,
+		arg.Direction,
+		arg.Amount,
+		arg.Currency,
+	)
+	return err
+}
+
 const createOrder = `-- name: CreateOrder :one
-INSERT INTO orders (id, buyer_id, product_id, blog_id, amount, status, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, buyer_id, product_id, blog_id, amount, status, created_at
+INSERT INTO orders (id, buyer_id, product_id, blog_id, amount, status, created_at, balance_amount, attribution) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, buyer_id, product_id, blog_id, amount, status, created_at, updated_at, balance_amount, payment_id, attribution
 `
 
 type CreateOrderParams struct {
-	ID        string             `json:"id"`
-	BuyerID   string             `json:"buyer_id"`
-	ProductID string             `json:"product_id"`
-	BlogID    string             `json:"blog_id"`
-	Amount    int64              `json:"amount"`
-	Status    string             `json:"status"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID            string             `json:"id"`
+	BuyerID       string             `json:"buyer_id"`
+	ProductID     pgtype.Text        `json:"product_id"`
+	BlogID        pgtype.Text        `json:"blog_id"`
+	Amount        int64              `json:"amount"`
+	Status        string             `json:"status"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	BalanceAmount int64              `json:"balance_amount"`
+	Attribution   pgtype.Text        `json:"attribution"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -142,6 +466,8 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.Amount,
 		arg.Status,
 		arg.CreatedAt,
+		arg.BalanceAmount,
+		arg.Attribution,
 	)
 	var i Order
 	err := row.Scan(
@@ -152,12 +478,144 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.Amount,
 		&i.Status,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BalanceAmount,
+		&i.PaymentID,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const createOrderItem = `-- name: CreateOrderItem :one
+INSERT INTO order_items (order_id, line_no, product_id, blog_id, quantity, unit_price, amount,
+    buyer_reward_points, author_reward_points, attribution)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING order_id, line_no, product_id, blog_id, quantity, unit_price, amount, buyer_reward_points, author_reward_points, attribution
+`
+
+type CreateOrderItemParams struct {
+	OrderID            string      `json:"order_id"`
+	LineNo             int32       `json:"line_no"`
+	ProductID          string      `json:"product_id"`
+	BlogID             pgtype.Text `json:"blog_id"`
+	Quantity           int32       `json:"quantity"`
+	UnitPrice          int64       `json:"unit_price"`
+	Amount             int64       `json:"amount"`
+	BuyerRewardPoints  int32       `json:"buyer_reward_points"`
+	AuthorRewardPoints int32       `json:"author_reward_points"`
+	Attribution        pgtype.Text `json:"attribution"`
+}
+
+func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
+	row := q.db.QueryRow(ctx, createOrderItem,
+		arg.OrderID,
+		arg.LineNo,
+		arg.ProductID,
+		arg.BlogID,
+		arg.Quantity,
+		arg.UnitPrice,
+		arg.Amount,
+		arg.BuyerRewardPoints,
+		arg.AuthorRewardPoints,
+		arg.Attribution,
+	)
+	var i OrderItem
+	err := row.Scan(
+		&i.OrderID,
+		&i.LineNo,
+		&i.ProductID,
+		&i.BlogID,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.Amount,
+		&i.BuyerRewardPoints,
+		&i.AuthorRewardPoints,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :exec
+INSERT INTO outbox_events (event_id, event_type, aggregate_id, payload)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateOutboxEventParams struct {
+	EventID     string `json:"event_id"`
+	EventType   string `json:"event_type"`
+	AggregateID string `json:"aggregate_id"`
+	Payload     []byte `json:"payload"`
+}
+
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error {
+	_, err := q.db.Exec(ctx, createOutboxEvent,
+		arg.EventID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	return err
+}
+
+const createPaymentAction = `-- name: CreatePaymentAction :one
+INSERT INTO payment_actions (order_id, payment_id, action, amount)
+VALUES ($1, $2, $3, $4) RETURNING id, order_id, payment_id, action, amount, status, attempts, next_attempt_at, last_error, created_at, done_at
+`
+
+type CreatePaymentActionParams struct {
+	OrderID   string `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+	Action    string `json:"action"`
+	Amount    int64  `json:"amount"`
+}
+
+func (q *Queries) CreatePaymentAction(ctx context.Context, arg CreatePaymentActionParams) (PaymentAction, error) {
+	row := q.db.QueryRow(ctx, createPaymentAction,
+		arg.OrderID,
+		arg.PaymentID,
+		arg.Action,
+		arg.Amount,
+	)
+	var i PaymentAction
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.PaymentID,
+		&i.Action,
+		&i.Amount,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DoneAt,
 	)
 	return i, err
 }
 
+const createPointLot = `-- name: CreatePointLot :exec
+INSERT INTO point_lots (user_id, transaction_id, amount, remaining, expires_at)
+VALUES ($1, $2, $3, $3, $4)
+`
+
+type CreatePointLotParams struct {
+	UserID        string             `json:"user_id"`
+	TransactionID string             `json:"transaction_id"`
+	Amount        int64              `json:"amount"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreatePointLot(ctx context.Context, arg CreatePointLotParams) error {
+	_, err := q.db.Exec(ctx, createPointLot,
+		arg.UserID,
+		arg.TransactionID,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const createProduct = `-- name: CreateProduct :one
-INSERT INTO products (id, name, description, price, buyer_reward_points, author_reward_points) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, name, description, price, buyer_reward_points, author_reward_points
+INSERT INTO products (id, name, description, price, buyer_reward_points, author_reward_points) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at
 `
 
 type CreateProductParams struct {
@@ -186,12 +644,118 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.Price,
 		&i.BuyerRewardPoints,
 		&i.AuthorRewardPoints,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createReferralVisit = `-- name: CreateReferralVisit :one
+INSERT INTO referral_visits (visitor_id, blog_id) VALUES ($1, $2) RETURNING id, visitor_id, blog_id, visited_at
+`
+
+type CreateReferralVisitParams struct {
+	VisitorID string `json:"visitor_id"`
+	BlogID    string `json:"blog_id"`
+}
+
+func (q *Queries) CreateReferralVisit(ctx context.Context, arg CreateReferralVisitParams) (ReferralVisit, error) {
+	row := q.db.QueryRow(ctx, createReferralVisit, arg.VisitorID, arg.BlogID)
+	var i ReferralVisit
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.BlogID,
+		&i.VisitedAt,
+	)
+	return i, err
+}
+
+const createRewardGrant = `-- name: CreateRewardGrant :exec
+INSERT INTO reward_grants (order_id, line_no, rule_id, user_id, points) VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateRewardGrantParams struct {
+	OrderID string `json:"order_id"`
+	LineNo  int32  `json:"line_no"`
+	RuleID  int64  `json:"rule_id"`
+	UserID  string `json:"user_id"`
+	Points  int64  `json:"points"`
+}
+
+func (q *Queries) CreateRewardGrant(ctx context.Context, arg CreateRewardGrantParams) error {
+	_, err := q.db.Exec(ctx, createRewardGrant,
+		arg.OrderID,
+		arg.LineNo,
+		arg.RuleID,
+		arg.UserID,
+		arg.Points,
+	)
+	return err
+}
+
+const createRewardRule = `-- name: CreateRewardRule :one
+INSERT INTO reward_rules (name, recipient, kind, value, priority, product_id, first_purchase_only,
+    min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, name, recipient, kind, value, priority, product_id, first_purchase_only, min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by, created_at
+`
+
+type CreateRewardRuleParams struct {
+	Name              string             `json:"name"`
+	Recipient         string             `json:"recipient"`
+	Kind              string             `json:"kind"`
+	Value             int64              `json:"value"`
+	Priority          int32              `json:"priority"`
+	ProductID         pgtype.Text        `json:"product_id"`
+	FirstPurchaseOnly bool               `json:"first_purchase_only"`
+	MinAuthorSales    int64              `json:"min_author_sales"`
+	MaxAuthorSales    pgtype.Int8        `json:"max_author_sales"`
+	UserCapPoints     pgtype.Int8        `json:"user_cap_points"`
+	StartsAt          pgtype.Timestamptz `json:"starts_at"`
+	EndsAt            pgtype.Timestamptz `json:"ends_at"`
+	CreatedBy         string             `json:"created_by"`
+}
+
+func (q *Queries) CreateRewardRule(ctx context.Context, arg CreateRewardRuleParams) (RewardRule, error) {
+	row := q.db.QueryRow(ctx, createRewardRule,
+		arg.Name,
+		arg.Recipient,
+		arg.Kind,
+		arg.Value,
+		arg.Priority,
+		arg.ProductID,
+		arg.FirstPurchaseOnly,
+		arg.MinAuthorSales,
+		arg.MaxAuthorSales,
+		arg.UserCapPoints,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.CreatedBy,
+	)
+	var i RewardRule
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Recipient,
+		&i.Kind,
+		&i.Value,
+		&i.Priority,
+		&i.ProductID,
+		&i.FirstPurchaseOnly,
+		&i.MinAuthorSales,
+		&i.MaxAuthorSales,
+		&i.UserCapPoints,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const createTransaction = `-- name: CreateTransaction :one
-INSERT INTO transactions (id, user_id, type, amount, related_order_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, user_id, type, amount, related_order_id, created_at
+INSERT INTO transactions (id, user_id, type, amount, related_order_id, policy_version, reward_rule_ids) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, user_id, type, amount, related_order_id, created_at, policy_version, reward_rule_ids
 `
 
 type CreateTransactionParams struct {
@@ -200,6 +764,8 @@ type CreateTransactionParams struct {
 	Type           string      `json:"type"`
 	Amount         int64       `json:"amount"`
 	RelatedOrderID pgtype.Text `json:"related_order_id"`
+	PolicyVersion  pgtype.Int8 `json:"policy_version"`
+	RewardRuleIds  []int64     `json:"reward_rule_ids"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -209,6 +775,8 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Type,
 		arg.Amount,
 		arg.RelatedOrderID,
+		arg.PolicyVersion,
+		arg.RewardRuleIds,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -218,6 +786,8 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.Amount,
 		&i.RelatedOrderID,
 		&i.CreatedAt,
+		&i.PolicyVersion,
+		&i.RewardRuleIds,
 	)
 	return i, err
 }
@@ -233,69 +803,484 @@ func (q *Queries) CreateWallet(ctx context.Context, userID string) (Wallet, erro
 	return i, err
 }
 
-const getBlog = `-- name: GetBlog :one
-SELECT id, author_id, content, product_id FROM blogs WHERE id = $1
+const debitWalletBalance = `-- name: DebitWalletBalance :one
+UPDATE wallets SET soda_balance = soda_balance - $1
+WHERE user_id = $2 AND soda_balance >= $1
+RETURNING user_id, soda_points, soda_balance
 `
 
-func (q *Queries) GetBlog(ctx context.Context, id string) (Blog, error) {
-	row := q.db.QueryRow(ctx, getBlog, id)
+type DebitWalletBalanceParams struct {
+	Amount int64  `json:"amount"`
+	UserID string `json:"user_id"`
+}
+
+// Takes yen from soda_balance only if enough is there. Returns no row
+// otherwise, so the balance can never go negative.
+func (q *Queries) DebitWalletBalance(ctx context.Context, arg DebitWalletBalanceParams) (Wallet, error) {
+	row := q.db.QueryRow(ctx, debitWalletBalance, arg.Amount, arg.UserID)
+	var i Wallet
+	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
+	return i, err
+}
+
+const deleteBlog = `-- name: DeleteBlog :one
+UPDATE blogs SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, author_id, content, product_id, status, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) DeleteBlog(ctx context.Context, id string) (Blog, error) {
+	row := q.db.QueryRow(ctx, deleteBlog, id)
 	var i Blog
 	err := row.Scan(
 		&i.ID,
 		&i.AuthorID,
 		&i.Content,
 		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const getProduct = `-- name: GetProduct :one
-SELECT id, name, description, price, buyer_reward_points, author_reward_points FROM products WHERE id = $1
+const endRewardRule = `-- name: EndRewardRule :one
+UPDATE reward_rules SET ends_at = GREATEST(starts_at, $1)
+WHERE id = $2 AND (ends_at IS NULL OR ends_at > $1)
+RETURNING id, name, recipient, kind, value, priority, product_id, first_purchase_only, min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by, created_at
 `
 
-func (q *Queries) GetProduct(ctx context.Context, id string) (Product, error) {
-	row := q.db.QueryRow(ctx, getProduct, id)
-	var i Product
+type EndRewardRuleParams struct {
+	EndsAt pgtype.Timestamptz `json:"ends_at"`
+	ID     int64              `json:"id"`
+}
+
+// Ends a rule that is still running or scheduled. A rule that has not started
+// yet ends as it starts, so it never applies.
+func (q *Queries) EndRewardRule(ctx context.Context, arg EndRewardRuleParams) (RewardRule, error) {
+	row := q.db.QueryRow(ctx, endRewardRule, arg.EndsAt, arg.ID)
+	var i RewardRule
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.Description,
-		&i.Price,
-		&i.BuyerRewardPoints,
-		&i.AuthorRewardPoints,
+		&i.Recipient,
+		&i.Kind,
+		&i.Value,
+		&i.Priority,
+		&i.ProductID,
+		&i.FirstPurchaseOnly,
+		&i.MinAuthorSales,
+		&i.MaxAuthorSales,
+		&i.UserCapPoints,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getWallet = `-- name: GetWallet :one
-SELECT user_id, soda_points, soda_balance FROM wallets WHERE user_id = $1
+const ensureLedgerAccount = `-- name: EnsureLedgerAccount :exec
+INSERT INTO ledger_accounts (id, owner_id, kind, currency)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO NOTHING
 `
 
-func (q *Queries) GetWallet(ctx context.Context, userID string) (Wallet, error) {
-	row := q.db.QueryRow(ctx, getWallet, userID)
+type EnsureLedgerAccountParams struct {
+	ID       string      `json:"id"`
+	OwnerID  pgtype.Text `json:"owner_id"`
+	Kind     string      `json:"kind"`
+	Currency string      `json:"currency"`
+}
+
+func (q *Queries) EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) error {
+	_, err := q.db.Exec(ctx, ensureLedgerAccount,
+		arg.ID,
+		arg.OwnerID,
+		arg.Kind,
+		arg.Currency,
+	)
+	return err
+}
+
+const expirePointLot = `-- name: ExpirePointLot :exec
+UPDATE point_lots SET remaining = 0, expired_at = NOW() WHERE id = $1
+`
+
+func (q *Queries) ExpirePointLot(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, expirePointLot, id)
+	return err
+}
+
+const getActiveConversionPolicy = `-- name: GetActiveConversionPolicy :one
+SELECT version, points_per_yen, min_balance, min_points, max_points, daily_cap_points, effective_from, created_by, created_at FROM conversion_policies
+WHERE effective_from <= NOW()
+ORDER BY effective_from DESC, version DESC
+LIMIT 1
+`
+
+// The policy in force now: the latest one whose effective_from has passed.
+func (q *Queries) GetActiveConversionPolicy(ctx context.Context) (ConversionPolicy, error) {
+	row := q.db.QueryRow(ctx, getActiveConversionPolicy)
+	var i ConversionPolicy
+	err := row.Scan(
+		&i.Version,
+		&i.PointsPerYen,
+		&i.MinBalance,
+		&i.MinPoints,
+		&i.MaxPoints,
+		&i.DailyCapPoints,
+		&i.EffectiveFrom,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getBlog = `-- name: GetBlog :one
+SELECT id, author_id, content, product_id, status, created_at, updated_at, deleted_at FROM blogs WHERE id = $1
+`
+
+// Deleted blogs are returned too; callers check deleted_at.
+func (q *Queries) GetBlog(ctx context.Context, id string) (Blog, error) {
+	row := q.db.QueryRow(ctx, getBlog, id)
+	var i Blog
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBlogForShare = `-- name: GetBlogForShare :one
+SELECT id, author_id, content, product_id, status, created_at, updated_at, deleted_at FROM blogs WHERE id = $1 FOR SHARE
+`
+
+// Blocks UpdateBlog, ModerateBlog and DeleteBlog on the row until the
+// transaction ends.
+func (q *Queries) GetBlogForShare(ctx context.Context, id string) (Blog, error) {
+	row := q.db.QueryRow(ctx, getBlogForShare, id)
+	var i Blog
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBlogForUpdate = `-- name: GetBlogForUpdate :one
+SELECT id, author_id, content, product_id, status, created_at, updated_at, deleted_at FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetBlogForUpdate(ctx context.Context, id string) (Blog, error) {
+	row := q.db.QueryRow(ctx, getBlogForUpdate, id)
+	var i Blog
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT user_id, idempotency_key, operation, request_hash, response, created_at, expires_at FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2
+`
+
+type GetIdempotencyKeyParams struct {
+	UserID         string `json:"user_id"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.UserID, arg.IdempotencyKey)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.IdempotencyKey,
+		&i.Operation,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getLatestReferralVisit = `-- name: GetLatestReferralVisit :one
+SELECT v.id, v.visitor_id, v.blog_id, v.visited_at FROM referral_visits v
+JOIN blogs b ON b.id = v.blog_id
+WHERE v.visitor_id = $1
+  AND v.visited_at >= $2
+  AND b.product_id = $3
+  AND b.author_id <> v.visitor_id
+  AND b.status = 'PUBLISHED' AND b.deleted_at IS NULL
+ORDER BY v.visited_at DESC, v.id DESC
+LIMIT 1
+`
+
+type GetLatestReferralVisitParams struct {
+	VisitorID    string             `json:"visitor_id"`
+	VisitedSince pgtype.Timestamptz `json:"visited_since"`
+	ProductID    string             `json:"product_id"`
+}
+
+// The visitor's most recent visit since the given time to a blog that could
+// still refer their order of the product.
+func (q *Queries) GetLatestReferralVisit(ctx context.Context, arg GetLatestReferralVisitParams) (ReferralVisit, error) {
+	row := q.db.QueryRow(ctx, getLatestReferralVisit, arg.VisitorID, arg.VisitedSince, arg.ProductID)
+	var i ReferralVisit
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.BlogID,
+		&i.VisitedAt,
+	)
+	return i, err
+}
+
+const getOrder = `-- name: GetOrder :one
+SELECT id, buyer_id, product_id, blog_id, amount, status, created_at, updated_at, balance_amount, payment_id, attribution FROM orders WHERE id = $1
+`
+
+func (q *Queries) GetOrder(ctx context.Context, id string) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerID,
+		&i.ProductID,
+		&i.BlogID,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BalanceAmount,
+		&i.PaymentID,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, buyer_id, product_id, blog_id, amount, status, created_at, updated_at, balance_amount, payment_id, attribution FROM orders WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id string) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerID,
+		&i.ProductID,
+		&i.BlogID,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BalanceAmount,
+		&i.PaymentID,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const getPointLot = `-- name: GetPointLot :one
+SELECT id, user_id, transaction_id, amount, remaining, expires_at, expired_at, created_at FROM point_lots WHERE id = $1
+`
+
+func (q *Queries) GetPointLot(ctx context.Context, id int64) (PointLot, error) {
+	row := q.db.QueryRow(ctx, getPointLot, id)
+	var i PointLot
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPointLotForUpdate = `-- name: GetPointLotForUpdate :one
+SELECT id, user_id, transaction_id, amount, remaining, expires_at, expired_at, created_at FROM point_lots WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetPointLotForUpdate(ctx context.Context, id int64) (PointLot, error) {
+	row := q.db.QueryRow(ctx, getPointLotForUpdate, id)
+	var i PointLot
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProduct = `-- name: GetProduct :one
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products WHERE id = $1
+`
+
+func (q *Queries) GetProduct(ctx context.Context, id string) (Product, error) {
+	row := q.db.QueryRow(ctx, getProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.BuyerRewardPoints,
+		&i.AuthorRewardPoints,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProductForShare = `-- name: GetProductForShare :one
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products WHERE id = $1 FOR SHARE
+`
+
+// Blocks ArchiveProduct and UpdateProduct on the row until the transaction ends.
+func (q *Queries) GetProductForShare(ctx context.Context, id string) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductForShare, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.BuyerRewardPoints,
+		&i.AuthorRewardPoints,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRewardRule = `-- name: GetRewardRule :one
+SELECT id, name, recipient, kind, value, priority, product_id, first_purchase_only, min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by, created_at FROM reward_rules WHERE id = $1
+`
+
+func (q *Queries) GetRewardRule(ctx context.Context, id int64) (RewardRule, error) {
+	row := q.db.QueryRow(ctx, getRewardRule, id)
+	var i RewardRule
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Recipient,
+		&i.Kind,
+		&i.Value,
+		&i.Priority,
+		&i.ProductID,
+		&i.FirstPurchaseOnly,
+		&i.MinAuthorSales,
+		&i.MaxAuthorSales,
+		&i.UserCapPoints,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStock = `-- name: GetStock :one
+SELECT product_id, on_hand, reserved, updated_at FROM product_stock WHERE product_id = $1
+`
+
+func (q *Queries) GetStock(ctx context.Context, productID string) (ProductStock, error) {
+	row := q.db.QueryRow(ctx, getStock, productID)
+	var i ProductStock
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.Reserved,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWallet = `-- name: GetWallet :one
+SELECT user_id, soda_points, soda_balance FROM wallets WHERE user_id = $1
+`
+
+func (q *Queries) GetWallet(ctx context.Context, userID string) (Wallet, error) {
+	row := q.db.QueryRow(ctx, getWallet, userID)
+	var i Wallet
+	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
+	return i, err
+}
+
+const getWalletForUpdate = `-- name: GetWalletForUpdate :one
+SELECT user_id, soda_points, soda_balance FROM wallets WHERE user_id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetWalletForUpdate(ctx context.Context, userID string) (Wallet, error) {
+	row := q.db.QueryRow(ctx, getWalletForUpdate, userID)
 	var i Wallet
 	err := row.Scan(&i.UserID, &i.SodaPoints, &i.SodaBalance)
 	return i, err
 }
 
-const listBlogs = `-- name: ListBlogs :many
-SELECT id, author_id, content, product_id FROM blogs
+const listActiveRewardRules = `-- name: ListActiveRewardRules :many
+SELECT id, name, recipient, kind, value, priority, product_id, first_purchase_only, min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by, created_at FROM reward_rules
+WHERE starts_at <= $1 AND (ends_at IS NULL OR ends_at > $1)
+ORDER BY priority, id
 `
 
-func (q *Queries) ListBlogs(ctx context.Context) ([]Blog, error) {
-	rows, err := q.db.Query(ctx, listBlogs)
+// The rules in force at the given time, in the order they are evaluated.
+func (q *Queries) ListActiveRewardRules(ctx context.Context, at pgtype.Timestamptz) ([]RewardRule, error) {
+	rows, err := q.db.Query(ctx, listActiveRewardRules, at)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Blog
+	var items []RewardRule
 	for rows.Next() {
-		var i Blog
+		var i RewardRule
 		if err := rows.Scan(
 			&i.ID,
-			&i.AuthorID,
-			&i.Content,
+			&i.Name,
+			&i.Recipient,
+			&i.Kind,
+			&i.Value,
+			&i.Priority,
 			&i.ProductID,
+			&i.FirstPurchaseOnly,
+			&i.MinAuthorSales,
+			&i.MaxAuthorSales,
+			&i.UserCapPoints,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -307,26 +1292,84 @@ func (q *Queries) ListBlogs(ctx context.Context) ([]Blog, error) {
 	return items, nil
 }
 
-const listProducts = `-- name: ListProducts :many
-SELECT id, name, description, price, buyer_reward_points, author_reward_points FROM products
+const listBlogRevisions = `-- name: ListBlogRevisions :many
+SELECT id, blog_id, action, content, status, actor_id, note, created_at FROM blog_revisions WHERE blog_id = $1 ORDER BY id
 `
 
-func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
-	rows, err := q.db.Query(ctx, listProducts)
+func (q *Queries) ListBlogRevisions(ctx context.Context, blogID string) ([]BlogRevision, error) {
+	rows, err := q.db.Query(ctx, listBlogRevisions, blogID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Product
+	var items []BlogRevision
 	for rows.Next() {
-		var i Product
+		var i BlogRevision
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.Price,
-			&i.BuyerRewardPoints,
-			&i.AuthorRewardPoints,
+			&i.BlogID,
+			&i.Action,
+			&i.Content,
+			&i.Status,
+			&i.ActorID,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBlogsByCreatedAt = `-- name: ListBlogsByCreatedAt :many
+SELECT id, author_id, content, product_id, status, created_at, updated_at, deleted_at FROM blogs
+WHERE status = 'PUBLISHED' AND deleted_at IS NULL
+  AND ($1::text IS NULL OR author_id = $1)
+  AND ($2::text IS NULL OR product_id = $2)
+  AND (created_at, id) > ($3::timestamptz, $4::text)
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListBlogsByCreatedAtParams struct {
+	AuthorID        pgtype.Text        `json:"author_id"`
+	ProductID       pgtype.Text        `json:"product_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        string             `json:"cursor_id"`
+	RowLimit        int32              `json:"row_limit"`
+}
+
+// ListBlogs* return published blogs strictly after the (cursor_created_at,
+// cursor_id) keyset in their sort, like ListProducts*. NULL filters match
+// every blog.
+func (q *Queries) ListBlogsByCreatedAt(ctx context.Context, arg ListBlogsByCreatedAtParams) ([]Blog, error) {
+	rows, err := q.db.Query(ctx, listBlogsByCreatedAt,
+		arg.AuthorID,
+		arg.ProductID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Blog
+	for rows.Next() {
+		var i Blog
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Content,
+			&i.ProductID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -337,3 +1380,1591 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 	}
 	return items, nil
 }
+
+const listBlogsByCreatedAtDesc = `-- name: ListBlogsByCreatedAtDesc :many
+SELECT id, author_id, content, product_id, status, created_at, updated_at, deleted_at FROM blogs
+WHERE status = 'PUBLISHED' AND deleted_at IS NULL
+  AND ($1::text IS NULL OR author_id = $1)
+  AND ($2::text IS NULL OR product_id = $2)
+  AND (created_at, id) < ($3::timestamptz, $4::text)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListBlogsByCreatedAtDescParams struct {
+	AuthorID        pgtype.Text        `json:"author_id"`
+	ProductID       pgtype.Text        `json:"product_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        string             `json:"cursor_id"`
+	RowLimit        int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListBlogsByCreatedAtDesc(ctx context.Context, arg ListBlogsByCreatedAtDescParams) ([]Blog, error) {
+	rows, err := q.db.Query(ctx, listBlogsByCreatedAtDesc,
+		arg.AuthorID,
+		arg.ProductID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Blog
+	for rows.Next() {
+		var i Blog
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Content,
+			&i.ProductID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listConversionPolicies = `-- name: ListConversionPolicies :many
+SELECT version, points_per_yen, min_balance, min_points, max_points, daily_cap_points, effective_from, created_by, created_at FROM conversion_policies ORDER BY effective_from DESC, version DESC
+`
+
+func (q *Queries) ListConversionPolicies(ctx context.Context) ([]ConversionPolicy, error) {
+	rows, err := q.db.Query(ctx, listConversionPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ConversionPolicy
+	for rows.Next() {
+		var i ConversionPolicy
+		if err := rows.Scan(
+			&i.Version,
+			&i.PointsPerYen,
+			&i.MinBalance,
+			&i.MinPoints,
+			&i.MaxPoints,
+			&i.DailyCapPoints,
+			&i.EffectiveFrom,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDuePointLots = `-- name: ListDuePointLots :many
+SELECT id FROM point_lots
+WHERE remaining > 0 AND expires_at <= $1
+ORDER BY expires_at, id
+LIMIT $2
+`
+
+type ListDuePointLotsParams struct {
+	DueAt    pgtype.Timestamptz `json:"due_at"`
+	RowLimit int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListDuePointLots(ctx context.Context, arg ListDuePointLotsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listDuePointLots, arg.DueAt, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiringPointLots = `-- name: ListExpiringPointLots :many
+SELECT id, user_id, transaction_id, amount, remaining, expires_at, expired_at, created_at FROM point_lots
+WHERE user_id = $1 AND remaining > 0 AND expires_at < $2
+ORDER BY expires_at, id
+`
+
+type ListExpiringPointLotsParams struct {
+	UserID string             `json:"user_id"`
+	Before pgtype.Timestamptz `json:"before"`
+}
+
+func (q *Queries) ListExpiringPointLots(ctx context.Context, arg ListExpiringPointLotsParams) ([]PointLot, error) {
+	rows, err := q.db.Query(ctx, listExpiringPointLots, arg.UserID, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PointLot
+	for rows.Next() {
+		var i PointLot
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TransactionID,
+			&i.Amount,
+			&i.Remaining,
+			&i.ExpiresAt,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInventoryMovements = `-- name: ListInventoryMovements :many
+SELECT id, product_id, order_id, kind, on_hand_delta, reserved_delta, reason, created_at FROM inventory_movements WHERE product_id = $1 ORDER BY created_at, id
+`
+
+func (q *Queries) ListInventoryMovements(ctx context.Context, productID string) ([]InventoryMovement, error) {
+	rows, err := q.db.Query(ctx, listInventoryMovements, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryMovement
+	for rows.Next() {
+		var i InventoryMovement
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.OrderID,
+			&i.Kind,
+			&i.OnHandDelta,
+			&i.ReservedDelta,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLedgerEntriesByTransaction = `-- name: ListLedgerEntriesByTransaction :many
+SELECT id, transaction_id, account_id, direction, amount, currency, created_at FROM ledger_entries WHERE transaction_id = $1 ORDER BY id
+`
+
+func (q *Queries) ListLedgerEntriesByTransaction(ctx context.Context, transactionID string) ([]LedgerEntry, error) {
+	rows, err := q.db.Query(ctx, listLedgerEntriesByTransaction, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LedgerEntry
+	for rows.Next() {
+		var i LedgerEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.AccountID,
+			&i.Direction,
+			&i.Amount,
+			&i.Currency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenOrderPointLotsForUpdate = `-- name: ListOpenOrderPointLotsForUpdate :many
+SELECT l.id, l.user_id, l.transaction_id, l.amount, l.remaining, l.expires_at, l.expired_at, l.created_at FROM point_lots l
+JOIN transactions t ON t.id = l.transaction_id
+WHERE l.user_id = $1 AND t.related_order_id = $2 AND l.remaining > 0
+ORDER BY l.expires_at ASC NULLS LAST, l.id
+FOR UPDATE OF l
+`
+
+type ListOpenOrderPointLotsForUpdateParams struct {
+	UserID         string      `json:"user_id"`
+	RelatedOrderID pgtype.Text `json:"related_order_id"`
+}
+
+// Open lots the user's EARNED transactions for the order opened, in the
+// order they are spent.
+func (q *Queries) ListOpenOrderPointLotsForUpdate(ctx context.Context, arg ListOpenOrderPointLotsForUpdateParams) ([]PointLot, error) {
+	rows, err := q.db.Query(ctx, listOpenOrderPointLotsForUpdate, arg.UserID, arg.RelatedOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PointLot
+	for rows.Next() {
+		var i PointLot
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TransactionID,
+			&i.Amount,
+			&i.Remaining,
+			&i.ExpiresAt,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenPointLotsForUpdate = `-- name: ListOpenPointLotsForUpdate :many
+SELECT id, user_id, transaction_id, amount, remaining, expires_at, expired_at, created_at FROM point_lots
+WHERE user_id = $1 AND remaining > 0
+ORDER BY expires_at ASC NULLS LAST, id
+FOR UPDATE
+`
+
+// Open lots in the order they are spent: soonest expiry first, lots that
+// never expire last.
+func (q *Queries) ListOpenPointLotsForUpdate(ctx context.Context, userID string) ([]PointLot, error) {
+	rows, err := q.db.Query(ctx, listOpenPointLotsForUpdate, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PointLot
+	for rows.Next() {
+		var i PointLot
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TransactionID,
+			&i.Amount,
+			&i.Remaining,
+			&i.ExpiresAt,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderItems = `-- name: ListOrderItems :many
+SELECT order_id, line_no, product_id, blog_id, quantity, unit_price, amount, buyer_reward_points, author_reward_points, attribution FROM order_items WHERE order_id = $1 ORDER BY line_no
+`
+
+func (q *Queries) ListOrderItems(ctx context.Context, orderID string) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listOrderItems, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderItem
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.LineNo,
+			&i.ProductID,
+			&i.BlogID,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.Amount,
+			&i.BuyerRewardPoints,
+			&i.AuthorRewardPoints,
+			&i.Attribution,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutboxEvents = `-- name: ListOutboxEvents :many
+SELECT id, event_id, event_type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, created_at, published_at FROM outbox_events
+WHERE ($1::TEXT IS NULL OR aggregate_id = $1)
+ORDER BY id
+`
+
+func (q *Queries) ListOutboxEvents(ctx context.Context, aggregateID pgtype.Text) ([]OutboxEvent, error) {
+	rows, err := q.db.Query(ctx, listOutboxEvents, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentActions = `-- name: ListPaymentActions :many
+SELECT id, order_id, payment_id, action, amount, status, attempts, next_attempt_at, last_error, created_at, done_at FROM payment_actions WHERE order_id = $1 ORDER BY id
+`
+
+func (q *Queries) ListPaymentActions(ctx context.Context, orderID string) ([]PaymentAction, error) {
+	rows, err := q.db.Query(ctx, listPaymentActions, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PaymentAction
+	for rows.Next() {
+		var i PaymentAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PaymentID,
+			&i.Action,
+			&i.Amount,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DoneAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByCreatedAt = `-- name: ListProductsByCreatedAt :many
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products
+WHERE archived_at IS NULL
+  AND ($1::bigint IS NULL OR price >= $1)
+  AND ($2::bigint IS NULL OR price <= $2)
+  AND ($3::text IS NULL OR name ILIKE $3)
+  AND (created_at, id) > ($4::timestamptz, $5::text)
+ORDER BY created_at, id
+LIMIT $6
+`
+
+type ListProductsByCreatedAtParams struct {
+	MinPrice        pgtype.Int8        `json:"min_price"`
+	MaxPrice        pgtype.Int8        `json:"max_price"`
+	NamePattern     pgtype.Text        `json:"name_pattern"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        string             `json:"cursor_id"`
+	RowLimit        int32              `json:"row_limit"`
+}
+
+// ListProducts* return products on sale strictly after the (cursor key,
+// cursor_id) keyset in their sort, with id breaking ties in the same
+// direction. Each sort has its own query so the keyset comparison can use
+// the matching index. NULL filters match every product.
+func (q *Queries) ListProductsByCreatedAt(ctx context.Context, arg ListProductsByCreatedAtParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductsByCreatedAt,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.NamePattern,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.BuyerRewardPoints,
+			&i.AuthorRewardPoints,
+			&i.ArchivedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByCreatedAtDesc = `-- name: ListProductsByCreatedAtDesc :many
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products
+WHERE archived_at IS NULL
+  AND ($1::bigint IS NULL OR price >= $1)
+  AND ($2::bigint IS NULL OR price <= $2)
+  AND ($3::text IS NULL OR name ILIKE $3)
+  AND (created_at, id) < ($4::timestamptz, $5::text)
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type ListProductsByCreatedAtDescParams struct {
+	MinPrice        pgtype.Int8        `json:"min_price"`
+	MaxPrice        pgtype.Int8        `json:"max_price"`
+	NamePattern     pgtype.Text        `json:"name_pattern"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        string             `json:"cursor_id"`
+	RowLimit        int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListProductsByCreatedAtDesc(ctx context.Context, arg ListProductsByCreatedAtDescParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductsByCreatedAtDesc,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.NamePattern,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.BuyerRewardPoints,
+			&i.AuthorRewardPoints,
+			&i.ArchivedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByPrice = `-- name: ListProductsByPrice :many
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products
+WHERE archived_at IS NULL
+  AND ($1::bigint IS NULL OR price >= $1)
+  AND ($2::bigint IS NULL OR price <= $2)
+  AND ($3::text IS NULL OR name ILIKE $3)
+  AND (price, id) > ($4::bigint, $5::text)
+ORDER BY price, id
+LIMIT $6
+`
+
+type ListProductsByPriceParams struct {
+	MinPrice    pgtype.Int8 `json:"min_price"`
+	MaxPrice    pgtype.Int8 `json:"max_price"`
+	NamePattern pgtype.Text `json:"name_pattern"`
+	CursorPrice int64       `json:"cursor_price"`
+	CursorID    string      `json:"cursor_id"`
+	RowLimit    int32       `json:"row_limit"`
+}
+
+func (q *Queries) ListProductsByPrice(ctx context.Context, arg ListProductsByPriceParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductsByPrice,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.NamePattern,
+		arg.CursorPrice,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.BuyerRewardPoints,
+			&i.AuthorRewardPoints,
+			&i.ArchivedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByPriceDesc = `-- name: ListProductsByPriceDesc :many
+SELECT id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at FROM products
+WHERE archived_at IS NULL
+  AND ($1::bigint IS NULL OR price >= $1)
+  AND ($2::bigint IS NULL OR price <= $2)
+  AND ($3::text IS NULL OR name ILIKE $3)
+  AND (price, id) < ($4::bigint, $5::text)
+ORDER BY price DESC, id DESC
+LIMIT $6
+`
+
+type ListProductsByPriceDescParams struct {
+	MinPrice    pgtype.Int8 `json:"min_price"`
+	MaxPrice    pgtype.Int8 `json:"max_price"`
+	NamePattern pgtype.Text `json:"name_pattern"`
+	CursorPrice int64       `json:"cursor_price"`
+	CursorID    string      `json:"cursor_id"`
+	RowLimit    int32       `json:"row_limit"`
+}
+
+func (q *Queries) ListProductsByPriceDesc(ctx context.Context, arg ListProductsByPriceDescParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductsByPriceDesc,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.NamePattern,
+		arg.CursorPrice,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.BuyerRewardPoints,
+			&i.AuthorRewardPoints,
+			&i.ArchivedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRewardGrantsByOrder = `-- name: ListRewardGrantsByOrder :many
+SELECT g.order_id, g.line_no, g.rule_id, g.user_id, g.points, r.recipient FROM reward_grants g
+JOIN reward_rules r ON r.id = g.rule_id
+WHERE g.order_id = $1
+ORDER BY g.line_no, r.priority, r.id
+`
+
+type ListRewardGrantsByOrderRow struct {
+	OrderID   string `json:"order_id"`
+	LineNo    int32  `json:"line_no"`
+	RuleID    int64  `json:"rule_id"`
+	UserID    string `json:"user_id"`
+	Points    int64  `json:"points"`
+	Recipient string `json:"recipient"`
+}
+
+func (q *Queries) ListRewardGrantsByOrder(ctx context.Context, orderID string) ([]ListRewardGrantsByOrderRow, error) {
+	rows, err := q.db.Query(ctx, listRewardGrantsByOrder, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRewardGrantsByOrderRow
+	for rows.Next() {
+		var i ListRewardGrantsByOrderRow
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.LineNo,
+			&i.RuleID,
+			&i.UserID,
+			&i.Points,
+			&i.Recipient,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRewardRules = `-- name: ListRewardRules :many
+SELECT id, name, recipient, kind, value, priority, product_id, first_purchase_only, min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by, created_at FROM reward_rules ORDER BY id DESC
+`
+
+func (q *Queries) ListRewardRules(ctx context.Context) ([]RewardRule, error) {
+	rows, err := q.db.Query(ctx, listRewardRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RewardRule
+	for rows.Next() {
+		var i RewardRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Recipient,
+			&i.Kind,
+			&i.Value,
+			&i.Priority,
+			&i.ProductID,
+			&i.FirstPurchaseOnly,
+			&i.MinAuthorSales,
+			&i.MaxAuthorSales,
+			&i.UserCapPoints,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionsByOrder = `-- name: ListTransactionsByOrder :many
+SELECT id, user_id, type, amount, related_order_id, created_at, policy_version, reward_rule_ids FROM transactions WHERE related_order_id = $1 ORDER BY created_at, id
+`
+
+func (q *Queries) ListTransactionsByOrder(ctx context.Context, relatedOrderID pgtype.Text) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listTransactionsByOrder, relatedOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Amount,
+			&i.RelatedOrderID,
+			&i.CreatedAt,
+			&i.PolicyVersion,
+			&i.RewardRuleIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionsByUser = `-- name: ListTransactionsByUser :many
+SELECT id, user_id, type, amount, related_order_id, created_at, policy_version, reward_rule_ids FROM transactions
+WHERE user_id = $1
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR type = ANY($2::text[]))
+  AND ($3::timestamptz IS NULL OR created_at >= $3)
+  AND ($4::timestamptz IS NULL OR created_at < $4)
+  AND ($5::timestamptz IS NULL
+       OR (created_at, id) < ($5::timestamptz, $6::text))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
+`
+
+type ListTransactionsByUserParams struct {
+	UserID          string             `json:"user_id"`
+	Types           []string           `json:"types"`
+	CreatedFrom     pgtype.Timestamptz `json:"created_from"`
+	CreatedTo       pgtype.Timestamptz `json:"created_to"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        string             `json:"cursor_id"`
+	RowLimit        int32              `json:"row_limit"`
+}
+
+// Newest first. Rows strictly after the (cursor_created_at, cursor_id) keyset
+// are returned when a cursor is given; empty types matches every type.
+func (q *Queries) ListTransactionsByUser(ctx context.Context, arg ListTransactionsByUserParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listTransactionsByUser,
+		arg.UserID,
+		arg.Types,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Amount,
+			&i.RelatedOrderID,
+			&i.CreatedAt,
+			&i.PolicyVersion,
+			&i.RewardRuleIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbalancedTransactions = `-- name: ListUnbalancedTransactions :many
+SELECT transaction_id,
+       currency,
+       SUM(CASE direction WHEN 'CREDIT' THEN amount ELSE -amount END)::BIGINT AS net
+FROM ledger_entries
+GROUP BY transaction_id, currency
+HAVING SUM(CASE direction WHEN 'CREDIT' THEN amount ELSE -amount END) <> 0
+ORDER BY transaction_id, currency
+`
+
+type ListUnbalancedTransactionsRow struct {
+	TransactionID string `json:"transaction_id"`
+	Currency      string `json:"currency"`
+	Net           int64  `json:"net"`
+}
+
+// Transactions whose entries do not net to zero in some currency.
+func (q *Queries) ListUnbalancedTransactions(ctx context.Context) ([]ListUnbalancedTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, listUnbalancedTransactions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnbalancedTransactionsRow
+	for rows.Next() {
+		var i ListUnbalancedTransactionsRow
+		if err := rows.Scan(&i.TransactionID, &i.Currency, &i.Net); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWalletDrift = `-- name: ListWalletDrift :many
+WITH ledger AS (
+    SELECT a.owner_id AS user_id,
+           COALESCE(SUM(CASE WHEN a.kind = 'USER_POINTS' THEN
+               CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END END), 0)::BIGINT AS points,
+           COALESCE(SUM(CASE WHEN a.kind = 'USER_BALANCE' THEN
+               CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END END), 0)::BIGINT AS balance
+    FROM ledger_accounts a
+    JOIN ledger_entries e ON e.account_id = a.id
+    WHERE a.owner_id IS NOT NULL
+    GROUP BY a.owner_id
+)
+SELECT w.user_id,
+       w.soda_points,
+       w.soda_balance,
+       COALESCE(l.points, 0)::BIGINT AS ledger_points,
+       COALESCE(l.balance, 0)::BIGINT AS ledger_balance
+FROM wallets w
+LEFT JOIN ledger l ON l.user_id = w.user_id
+WHERE w.soda_points <> COALESCE(l.points, 0) OR w.soda_balance <> COALESCE(l.balance, 0)
+ORDER BY w.user_id
+`
+
+type ListWalletDriftRow struct {
+	UserID        string `json:"user_id"`
+	SodaPoints    int64  `json:"soda_points"`
+	SodaBalance   int64  `json:"soda_balance"`
+	LedgerPoints  int64  `json:"ledger_points"`
+	LedgerBalance int64  `json:"ledger_balance"`
+}
+
+// Wallets whose cached totals differ from the sum of their ledger accounts.
+func (q *Queries) ListWalletDrift(ctx context.Context) ([]ListWalletDriftRow, error) {
+	rows, err := q.db.Query(ctx, listWalletDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWalletDriftRow
+	for rows.Next() {
+		var i ListWalletDriftRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.SodaPoints,
+			&i.SodaBalance,
+			&i.LedgerPoints,
+			&i.LedgerBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLedger = `-- name: LockLedger :exec
+LOCK TABLE ledger_entries IN SHARE MODE
+`
+
+// Waits for in-flight postings and blocks new ones until the transaction ends.
+func (q *Queries) LockLedger(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockLedger)
+	return err
+}
+
+const lockRewardCap = `-- name: LockRewardCap :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::BIGINT || ':' || $2::TEXT, 0))
+`
+
+type LockRewardCapParams struct {
+	RuleID int64  `json:"rule_id"`
+	UserID string `json:"user_id"`
+}
+
+// Serializes orders drawing on the same user's cap for a rule until the
+// transaction ends. The key hashes the full rule id with the user id, so
+// rule ids past 32 bits do not share locks.
+func (q *Queries) LockRewardCap(ctx context.Context, arg LockRewardCapParams) error {
+	_, err := q.db.Exec(ctx, lockRewardCap, arg.RuleID, arg.UserID)
+	return err
+}
+
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :exec
+UPDATE outbox_events
+SET status = $1, attempts = attempts + 1, next_attempt_at = $2,
+    last_error = $3
+WHERE id = $4
+`
+
+type MarkOutboxEventFailedParams struct {
+	Status        string             `json:"status"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	ID            int64              `json:"id"`
+}
+
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxEventFailed,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE outbox_events SET status = 'PUBLISHED', attempts = attempts + 1, published_at = NOW(), last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markOutboxEventPublished, id)
+	return err
+}
+
+const markPaymentActionDone = `-- name: MarkPaymentActionDone :exec
+UPDATE payment_actions SET status = 'DONE', attempts = attempts + 1, done_at = NOW(), last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkPaymentActionDone(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markPaymentActionDone, id)
+	return err
+}
+
+const markPaymentActionFailed = `-- name: MarkPaymentActionFailed :exec
+UPDATE payment_actions
+SET status = $1, attempts = attempts + 1, next_attempt_at = $2,
+    last_error = $3
+WHERE id = $4
+`
+
+type MarkPaymentActionFailedParams struct {
+	Status        string             `json:"status"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	ID            int64              `json:"id"`
+}
+
+func (q *Queries) MarkPaymentActionFailed(ctx context.Context, arg MarkPaymentActionFailedParams) error {
+	_, err := q.db.Exec(ctx, markPaymentActionFailed,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const rebuildWallets = `-- name: RebuildWallets :execrows
+UPDATE wallets w
+SET soda_points = COALESCE((
+        SELECT SUM(CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END)
+        FROM ledger_entries e
+        JOIN ledger_accounts a ON a.id = e.account_id
+        WHERE a.owner_id = w.user_id AND a.kind = 'USER_POINTS'), 0),
+    soda_balance = COALESCE((
+        SELECT SUM(CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END)
+        FROM ledger_entries e
+        JOIN ledger_accounts a ON a.id = e.account_id
+        WHERE a.owner_id = w.user_id AND a.kind = 'USER_BALANCE'), 0)
+`
+
+// Recomputes every wallet from the ledger.
+func (q *Queries) RebuildWallets(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, rebuildWallets)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const referralStatsByAuthor = `-- name: ReferralStatsByAuthor :many
+SELECT b.author_id,
+       COUNT(DISTINCT b.id) AS blogs,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= $1 AND o.created_at < $2
+  AND ($3::text IS NULL OR b.author_id = $3)
+  AND ($4::text IS NULL OR b.id = $4)
+GROUP BY b.author_id
+ORDER BY revenue DESC, b.author_id
+`
+
+type ReferralStatsByAuthorParams struct {
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	AuthorID    pgtype.Text        `json:"author_id"`
+	BlogID      pgtype.Text        `json:"blog_id"`
+}
+
+type ReferralStatsByAuthorRow struct {
+	AuthorID string `json:"author_id"`
+	Blogs    int64  `json:"blogs"`
+	Orders   int64  `json:"orders"`
+	Revenue  int64  `json:"revenue"`
+	Points   int64  `json:"points"`
+}
+
+// ReferralStatsByBlog rolled up per author.
+func (q *Queries) ReferralStatsByAuthor(ctx context.Context, arg ReferralStatsByAuthorParams) ([]ReferralStatsByAuthorRow, error) {
+	rows, err := q.db.Query(ctx, referralStatsByAuthor,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AuthorID,
+		arg.BlogID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReferralStatsByAuthorRow
+	for rows.Next() {
+		var i ReferralStatsByAuthorRow
+		if err := rows.Scan(
+			&i.AuthorID,
+			&i.Blogs,
+			&i.Orders,
+			&i.Revenue,
+			&i.Points,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const referralStatsByBlog = `-- name: ReferralStatsByBlog :many
+SELECT b.id AS blog_id, b.author_id, b.product_id,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= $1 AND o.created_at < $2
+  AND ($3::text IS NULL OR b.author_id = $3)
+  AND ($4::text IS NULL OR b.id = $4)
+GROUP BY b.id
+ORDER BY revenue DESC, b.id
+`
+
+type ReferralStatsByBlogParams struct {
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	AuthorID    pgtype.Text        `json:"author_id"`
+	BlogID      pgtype.Text        `json:"blog_id"`
+}
+
+type ReferralStatsByBlogRow struct {
+	BlogID    string `json:"blog_id"`
+	AuthorID  string `json:"author_id"`
+	ProductID string `json:"product_id"`
+	Orders    int64  `json:"orders"`
+	Revenue   int64  `json:"revenue"`
+	Points    int64  `json:"points"`
+}
+
+// Referred sales per blog: the lines of orders that still stand (confirmed,
+// shipped or delivered) placed in [created_from, created_to).
+func (q *Queries) ReferralStatsByBlog(ctx context.Context, arg ReferralStatsByBlogParams) ([]ReferralStatsByBlogRow, error) {
+	rows, err := q.db.Query(ctx, referralStatsByBlog,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AuthorID,
+		arg.BlogID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReferralStatsByBlogRow
+	for rows.Next() {
+		var i ReferralStatsByBlogRow
+		if err := rows.Scan(
+			&i.BlogID,
+			&i.AuthorID,
+			&i.ProductID,
+			&i.Orders,
+			&i.Revenue,
+			&i.Points,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const referralStatsSeries = `-- name: ReferralStatsSeries :many
+SELECT date_trunc($1::text, o.created_at, 'UTC')::TIMESTAMPTZ AS bucket_start,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= $2 AND o.created_at < $3
+  AND ($4::text IS NULL OR b.author_id = $4)
+  AND ($5::text IS NULL OR b.id = $5)
+GROUP BY bucket_start
+ORDER BY bucket_start
+`
+
+type ReferralStatsSeriesParams struct {
+	Bucket      string             `json:"bucket"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	AuthorID    pgtype.Text        `json:"author_id"`
+	BlogID      pgtype.Text        `json:"blog_id"`
+}
+
+type ReferralStatsSeriesRow struct {
+	BucketStart pgtype.Timestamptz `json:"bucket_start"`
+	Orders      int64              `json:"orders"`
+	Revenue     int64              `json:"revenue"`
+	Points      int64              `json:"points"`
+}
+
+// ReferralStatsByBlog totalled per UTC day or week (bucket is 'day' or
+// 'week'). Buckets without sales are omitted.
+func (q *Queries) ReferralStatsSeries(ctx context.Context, arg ReferralStatsSeriesParams) ([]ReferralStatsSeriesRow, error) {
+	rows, err := q.db.Query(ctx, referralStatsSeries,
+		arg.Bucket,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AuthorID,
+		arg.BlogID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReferralStatsSeriesRow
+	for rows.Next() {
+		var i ReferralStatsSeriesRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.Orders,
+			&i.Revenue,
+			&i.Points,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseStock = `-- name: ReleaseStock :one
+WITH released AS (
+    UPDATE product_stock
+    SET reserved = reserved - $1, updated_at = NOW()
+    WHERE product_stock.product_id = $2
+    RETURNING product_id, on_hand, reserved, updated_at
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT released.product_id, $3, 'RELEASE', 0, -$1::INTEGER FROM released
+)
+SELECT product_id, on_hand, reserved, updated_at FROM released
+`
+
+type ReleaseStockParams struct {
+	Quantity  int32       `json:"quantity"`
+	ProductID string      `json:"product_id"`
+	OrderID   pgtype.Text `json:"order_id"`
+}
+
+type ReleaseStockRow struct {
+	ProductID string             `json:"product_id"`
+	OnHand    int32              `json:"on_hand"`
+	Reserved  int32              `json:"reserved"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Returns units reserved by a cancelled order to the available pool.
+func (q *Queries) ReleaseStock(ctx context.Context, arg ReleaseStockParams) (ReleaseStockRow, error) {
+	row := q.db.QueryRow(ctx, releaseStock, arg.Quantity, arg.ProductID, arg.OrderID)
+	var i ReleaseStockRow
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.Reserved,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const requeueOutboxEvent = `-- name: RequeueOutboxEvent :execrows
+UPDATE outbox_events SET status = 'PENDING', attempts = 0, next_attempt_at = NOW()
+WHERE id = $1 AND status = 'DEAD'
+`
+
+func (q *Queries) RequeueOutboxEvent(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueOutboxEvent, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reserveStock = `-- name: ReserveStock :one
+WITH reserved AS (
+    UPDATE product_stock
+    SET reserved = reserved + $1, updated_at = NOW()
+    WHERE product_stock.product_id = $2 AND on_hand - reserved >= $1
+    RETURNING product_id, on_hand, reserved, updated_at
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT reserved.product_id, $3, 'RESERVE', 0, $1 FROM reserved
+)
+SELECT product_id, on_hand, reserved, updated_at FROM reserved
+`
+
+type ReserveStockParams struct {
+	Quantity  int32       `json:"quantity"`
+	ProductID string      `json:"product_id"`
+	OrderID   pgtype.Text `json:"order_id"`
+}
+
+type ReserveStockRow struct {
+	ProductID string             `json:"product_id"`
+	OnHand    int32              `json:"on_hand"`
+	Reserved  int32              `json:"reserved"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Holds quantity units for an order. No row when fewer are available.
+func (q *Queries) ReserveStock(ctx context.Context, arg ReserveStockParams) (ReserveStockRow, error) {
+	row := q.db.QueryRow(ctx, reserveStock, arg.Quantity, arg.ProductID, arg.OrderID)
+	var i ReserveStockRow
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.Reserved,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const reservedForOrder = `-- name: ReservedForOrder :one
+SELECT COALESCE(SUM(reserved_delta), 0)::INTEGER FROM inventory_movements
+WHERE order_id = $1 AND product_id = $2
+`
+
+type ReservedForOrderParams struct {
+	OrderID   pgtype.Text `json:"order_id"`
+	ProductID string      `json:"product_id"`
+}
+
+// Units of the product still held for the order.
+func (q *Queries) ReservedForOrder(ctx context.Context, arg ReservedForOrderParams) (int32, error) {
+	row := q.db.QueryRow(ctx, reservedForOrder, arg.OrderID, arg.ProductID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const saveIdempotencyResponse = `-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys SET response = $3 WHERE user_id = $1 AND idempotency_key = $2
+`
+
+type SaveIdempotencyResponseParams struct {
+	UserID         string `json:"user_id"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Response       []byte `json:"response"`
+}
+
+func (q *Queries) SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error {
+	_, err := q.db.Exec(ctx, saveIdempotencyResponse, arg.UserID, arg.IdempotencyKey, arg.Response)
+	return err
+}
+
+const setOrderPaymentID = `-- name: SetOrderPaymentID :one
+UPDATE orders SET payment_id = $2, updated_at = NOW() WHERE id = $1 RETURNING id, buyer_id, product_id, blog_id, amount, status, created_at, updated_at, balance_amount, payment_id, attribution
+`
+
+type SetOrderPaymentIDParams struct {
+	ID        string      `json:"id"`
+	PaymentID pgtype.Text `json:"payment_id"`
+}
+
+func (q *Queries) SetOrderPaymentID(ctx context.Context, arg SetOrderPaymentIDParams) (Order, error) {
+	row := q.db.QueryRow(ctx, setOrderPaymentID, arg.ID, arg.PaymentID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerID,
+		&i.ProductID,
+		&i.BlogID,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BalanceAmount,
+		&i.PaymentID,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const setPointLotRemaining = `-- name: SetPointLotRemaining :exec
+UPDATE point_lots SET remaining = $2 WHERE id = $1
+`
+
+type SetPointLotRemainingParams struct {
+	ID        int64 `json:"id"`
+	Remaining int64 `json:"remaining"`
+}
+
+func (q *Queries) SetPointLotRemaining(ctx context.Context, arg SetPointLotRemainingParams) error {
+	_, err := q.db.Exec(ctx, setPointLotRemaining, arg.ID, arg.Remaining)
+	return err
+}
+
+const shipStock = `-- name: ShipStock :one
+WITH shipped AS (
+    UPDATE product_stock
+    SET on_hand = on_hand - $1, reserved = reserved - $1, updated_at = NOW()
+    WHERE product_stock.product_id = $2
+    RETURNING product_id, on_hand, reserved, updated_at
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT shipped.product_id, $3, 'SHIP', -$1::INTEGER, -$1::INTEGER FROM shipped
+)
+SELECT product_id, on_hand, reserved, updated_at FROM shipped
+`
+
+type ShipStockParams struct {
+	Quantity  int32       `json:"quantity"`
+	ProductID string      `json:"product_id"`
+	OrderID   pgtype.Text `json:"order_id"`
+}
+
+type ShipStockRow struct {
+	ProductID string             `json:"product_id"`
+	OnHand    int32              `json:"on_hand"`
+	Reserved  int32              `json:"reserved"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Removes units reserved by a shipped order from on_hand.
+func (q *Queries) ShipStock(ctx context.Context, arg ShipStockParams) (ShipStockRow, error) {
+	row := q.db.QueryRow(ctx, shipStock, arg.Quantity, arg.ProductID, arg.OrderID)
+	var i ShipStockRow
+	err := row.Scan(
+		&i.ProductID,
+		&i.OnHand,
+		&i.Reserved,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const sumConvertedPointsSince = `-- name: SumConvertedPointsSince :one
+SELECT COALESCE(SUM(amount), 0)::BIGINT FROM transactions
+WHERE user_id = $1 AND type = 'CONVERTED' AND created_at >= $2
+`
+
+type SumConvertedPointsSinceParams struct {
+	UserID string             `json:"user_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) SumConvertedPointsSince(ctx context.Context, arg SumConvertedPointsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumConvertedPointsSince, arg.UserID, arg.Since)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const sumReferredSalesByAuthor = `-- name: SumReferredSalesByAuthor :one
+SELECT COALESCE(SUM(i.amount), 0)::BIGINT FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE b.author_id = $1 AND o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+`
+
+// The author's lifetime referred sales in yen: the referred lines of orders
+// that still stand.
+func (q *Queries) SumReferredSalesByAuthor(ctx context.Context, authorID string) (int64, error) {
+	row := q.db.QueryRow(ctx, sumReferredSalesByAuthor, authorID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const sumRewardGrants = `-- name: SumRewardGrants :one
+SELECT COALESCE(SUM(g.points), 0)::BIGINT FROM reward_grants g
+JOIN orders o ON o.id = g.order_id
+WHERE g.rule_id = $1 AND g.user_id = $2 AND o.status NOT IN ('CANCELLED', 'REFUNDED')
+`
+
+type SumRewardGrantsParams struct {
+	RuleID int64  `json:"rule_id"`
+	UserID string `json:"user_id"`
+}
+
+// Points the user has earned from the rule on orders that were not cancelled
+// or refunded.
+func (q *Queries) SumRewardGrants(ctx context.Context, arg SumRewardGrantsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumRewardGrants, arg.RuleID, arg.UserID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const updateBlog = `-- name: UpdateBlog :one
+UPDATE blogs SET content = $2, status = $3, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, author_id, content, product_id, status, created_at, updated_at, deleted_at
+`
+
+type UpdateBlogParams struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Status  string `json:"status"`
+}
+
+func (q *Queries) UpdateBlog(ctx context.Context, arg UpdateBlogParams) (Blog, error) {
+	row := q.db.QueryRow(ctx, updateBlog, arg.ID, arg.Content, arg.Status)
+	var i Blog
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ProductID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :one
+UPDATE orders SET status = $1, updated_at = NOW()
+WHERE id = $2 AND status = $3
+RETURNING id, buyer_id, product_id, blog_id, amount, status, created_at, updated_at, balance_amount, payment_id, attribution
+`
+
+type UpdateOrderStatusParams struct {
+	Status     string `json:"status"`
+	ID         string `json:"id"`
+	FromStatus string `json:"from_status"`
+}
+
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error) {
+	row := q.db.QueryRow(ctx, updateOrderStatus, arg.Status, arg.ID, arg.FromStatus)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerID,
+		&i.ProductID,
+		&i.BlogID,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BalanceAmount,
+		&i.PaymentID,
+		&i.Attribution,
+	)
+	return i, err
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+UPDATE products
+SET name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    price = COALESCE($3, price),
+    buyer_reward_points = COALESCE($4, buyer_reward_points),
+    author_reward_points = COALESCE($5, author_reward_points)
+WHERE id = $6 AND archived_at IS NULL
+RETURNING id, name, description, price, buyer_reward_points, author_reward_points, archived_at, created_at
+`
+
+type UpdateProductParams struct {
+	Name               pgtype.Text `json:"name"`
+	Description        pgtype.Text `json:"description"`
+	Price              pgtype.Int8 `json:"price"`
+	BuyerRewardPoints  pgtype.Int4 `json:"buyer_reward_points"`
+	AuthorRewardPoints pgtype.Int4 `json:"author_reward_points"`
+	ID                 string      `json:"id"`
+}
+
+// NULL arguments leave the column unchanged. Archived products are not updated.
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProduct,
+		arg.Name,
+		arg.Description,
+		arg.Price,
+		arg.BuyerRewardPoints,
+		arg.AuthorRewardPoints,
+		arg.ID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.BuyerRewardPoints,
+		&i.AuthorRewardPoints,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
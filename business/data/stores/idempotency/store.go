@@ -0,0 +1,108 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"soda-interview/business/data/stores/db"
+	"soda-interview/foundation/logger"
+)
+
+// DefaultTTL is how long a completed request can be replayed by key.
+const DefaultTTL = 24 * time.Hour
+
+// MaxKeyLength bounds client supplied keys.
+const MaxKeyLength = 128
+
+var (
+	// ErrKeyReused is returned when a key is replayed with a different
+	// operation or different request parameters.
+	ErrKeyReused = errors.New("idempotency key already used for a different request")
+	// ErrKeyInFlight is returned when the original request holding the key has
+	// not stored its response yet.
+	ErrKeyInFlight = errors.New("idempotency key is still being processed")
+)
+
+type Store struct {
+	log *logger.Logger
+	q   *db.Queries
+}
+
+func NewStore(log *logger.Logger, pool *pgxpool.Pool) *Store {
+	return &Store{
+		log: log,
+		q:   db.New(pool),
+	}
+}
+
+func (s *Store) WithTx(tx pgx.Tx) *Store {
+	return &Store{
+		log: s.log,
+		q:   s.q.WithTx(tx),
+	}
+}
+
+// Begin claims the key for the operation inside the current transaction. If
+// an earlier request with the same key already completed, its stored response
+// is returned with replay set to true and the caller must not re-execute.
+//
+// A concurrent request using the same key blocks on the primary key until the
+// first transaction commits or rolls back, so at most one of them executes.
+func (s *Store) Begin(ctx context.Context, userID, key, operation, requestHash string, ttl time.Duration) (response []byte, replay bool, err error) {
+	_, err = s.q.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+		UserID:         userID,
+		IdempotencyKey: key,
+		Operation:      operation,
+		RequestHash:    requestHash,
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err == nil {
+		return nil, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("claiming idempotency key: %w", err)
+	}
+
+	rec, err := s.q.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		UserID:         userID,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("querying idempotency key: %w", err)
+	}
+
+	if rec.Operation != operation || rec.RequestHash != requestHash {
+		return nil, false, ErrKeyReused
+	}
+	if rec.Response == nil {
+		return nil, false, ErrKeyInFlight
+	}
+	return rec.Response, true, nil
+}
+
+// Complete stores the response for a key claimed with Begin.
+func (s *Store) Complete(ctx context.Context, userID, key string, response []byte) error {
+	if err := s.q.SaveIdempotencyResponse(ctx, db.SaveIdempotencyResponseParams{
+		UserID:         userID,
+		IdempotencyKey: key,
+		Response:       response,
+	}); err != nil {
+		return fmt.Errorf("saving idempotency response: %w", err)
+	}
+	return nil
+}
+
+// Hash fingerprints the request parameters so a key replayed with different
+// parameters can be detected.
+func Hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+// Gateway calls persisted in payment_actions.action.
+const (
+	ActionCapture = "CAPTURE"
+	ActionVoid    = "VOID"
+	ActionRefund  = "REFUND"
+)
+
+// Action statuses persisted in payment_actions.status.
+const (
+	ActionPending = "PENDING"
+	ActionDone    = "DONE"
+	ActionDead    = "DEAD"
+)
+
+// CreatePaymentAction records a gateway call the order owes. Call it on a
+// store bound to the transaction making the change, so the call is made if
+// and only if the change commits.
+func (s *Store) CreatePaymentAction(ctx context.Context, params db.CreatePaymentActionParams) (db.PaymentAction, error) {
+	a, err := s.q.CreatePaymentAction(ctx, params)
+	if err != nil {
+		return db.PaymentAction{}, fmt.Errorf("creating payment action: %w", err)
+	}
+	return a, nil
+}
+
+// ClaimPaymentAction locks the action if it can run now: it is pending, no
+// earlier action for its payment is, and no other transaction holds it. It
+// reports whether the action was claimed.
+func (s *Store) ClaimPaymentAction(ctx context.Context, id int64) (db.PaymentAction, bool, error) {
+	a, err := s.q.ClaimPaymentAction(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.PaymentAction{}, false, nil
+		}
+		return db.PaymentAction{}, false, fmt.Errorf("claiming payment action: %w", err)
+	}
+	return a, true, nil
+}
+
+// ClaimDuePaymentActions locks up to batchSize actions due by now that can
+// run, oldest first. Actions locked by another transaction are skipped.
+func (s *Store) ClaimDuePaymentActions(ctx context.Context, now time.Time, batchSize int32) ([]db.PaymentAction, error) {
+	actions, err := s.q.ClaimPaymentActions(ctx, db.ClaimPaymentActionsParams{
+		Now:       pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize: batchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claiming payment actions: %w", err)
+	}
+	return actions, nil
+}
+
+func (s *Store) MarkPaymentActionDone(ctx context.Context, id int64) error {
+	if err := s.q.MarkPaymentActionDone(ctx, id); err != nil {
+		return fmt.Errorf("marking payment action done: %w", err)
+	}
+	return nil
+}
+
+// MarkPaymentActionFailed records a failed attempt. The action is retried at
+// next, or dead-lettered when dead is set.
+func (s *Store) MarkPaymentActionFailed(ctx context.Context, id int64, cause error, next time.Time, dead bool) error {
+	status := ActionPending
+	if dead {
+		status = ActionDead
+	}
+	err := s.q.MarkPaymentActionFailed(ctx, db.MarkPaymentActionFailedParams{
+		ID:            id,
+		Status:        status,
+		NextAttemptAt: pgtype.Timestamptz{Time: next, Valid: true},
+		LastError:     pgtype.Text{String: cause.Error(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("marking payment action failed: %w", err)
+	}
+	return nil
+}
+
+// ListPaymentActions returns the order's gateway calls, oldest first.
+func (s *Store) ListPaymentActions(ctx context.Context, orderID string) ([]db.PaymentAction, error) {
+	actions, err := s.q.ListPaymentActions(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("listing payment actions: %w", err)
+	}
+	return actions, nil
+}
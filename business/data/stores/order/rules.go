@@ -0,0 +1,117 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+var (
+	ErrRuleNotFound = errors.New("reward rule not found")
+	// ErrRuleEnded is returned when ending a rule that has already ended.
+	ErrRuleEnded = errors.New("reward rule already ended")
+)
+
+func (s *Store) CreateRewardRule(ctx context.Context, params db.CreateRewardRuleParams) (db.RewardRule, error) {
+	r, err := s.q.CreateRewardRule(ctx, params)
+	if err != nil {
+		return db.RewardRule{}, fmt.Errorf("creating reward rule: %w", err)
+	}
+	return r, nil
+}
+
+func (s *Store) GetRewardRule(ctx context.Context, id int64) (db.RewardRule, error) {
+	r, err := s.q.GetRewardRule(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.RewardRule{}, ErrRuleNotFound
+		}
+		return db.RewardRule{}, fmt.Errorf("querying reward rule: %w", err)
+	}
+	return r, nil
+}
+
+// ListRewardRules returns every rule, newest first.
+func (s *Store) ListRewardRules(ctx context.Context) ([]db.RewardRule, error) {
+	rs, err := s.q.ListRewardRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing reward rules: %w", err)
+	}
+	return rs, nil
+}
+
+// ListActiveRewardRules returns the rules in force at the given time, in
+// evaluation order.
+func (s *Store) ListActiveRewardRules(ctx context.Context, at time.Time) ([]db.RewardRule, error) {
+	rs, err := s.q.ListActiveRewardRules(ctx, pgtype.Timestamptz{Time: at, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing active reward rules: %w", err)
+	}
+	return rs, nil
+}
+
+// EndRewardRule stops the rule at the given time.
+func (s *Store) EndRewardRule(ctx context.Context, id int64, at time.Time) (db.RewardRule, error) {
+	r, err := s.q.EndRewardRule(ctx, db.EndRewardRuleParams{
+		ID:     id,
+		EndsAt: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := s.GetRewardRule(ctx, id); err != nil {
+				return db.RewardRule{}, err
+			}
+			return db.RewardRule{}, ErrRuleEnded
+		}
+		return db.RewardRule{}, fmt.Errorf("ending reward rule: %w", err)
+	}
+	return r, nil
+}
+
+// LockRewardCap holds the user's cap for the rule until the transaction ends.
+func (s *Store) LockRewardCap(ctx context.Context, ruleID int64, userID string) error {
+	if err := s.q.LockRewardCap(ctx, db.LockRewardCapParams{RuleID: ruleID, UserID: userID}); err != nil {
+		return fmt.Errorf("locking reward cap: %w", err)
+	}
+	return nil
+}
+
+// SumRewardGrants returns the points the user has earned from the rule on
+// orders that still stand or are yet to be paid.
+func (s *Store) SumRewardGrants(ctx context.Context, ruleID int64, userID string) (int64, error) {
+	n, err := s.q.SumRewardGrants(ctx, db.SumRewardGrantsParams{RuleID: ruleID, UserID: userID})
+	if err != nil {
+		return 0, fmt.Errorf("summing reward grants: %w", err)
+	}
+	return n, nil
+}
+
+func (s *Store) CreateRewardGrant(ctx context.Context, params db.CreateRewardGrantParams) error {
+	if err := s.q.CreateRewardGrant(ctx, params); err != nil {
+		return fmt.Errorf("creating reward grant: %w", err)
+	}
+	return nil
+}
+
+// ListRewardGrants returns what each rule added to the order's lines.
+func (s *Store) ListRewardGrants(ctx context.Context, orderID string) ([]db.ListRewardGrantsByOrderRow, error) {
+	gs, err := s.q.ListRewardGrantsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("listing reward grants: %w", err)
+	}
+	return gs, nil
+}
+
+// ReferredSales returns the author's lifetime referred sales in yen.
+func (s *Store) ReferredSales(ctx context.Context, authorID string) (int64, error) {
+	n, err := s.q.SumReferredSalesByAuthor(ctx, authorID)
+	if err != nil {
+		return 0, fmt.Errorf("summing referred sales: %w", err)
+	}
+	return n, nil
+}
@@ -2,14 +2,23 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"soda-interview/business/data/stores/db"
 	"soda-interview/foundation/logger"
 )
 
+var (
+	ErrNotFound = errors.New("order not found")
+	// ErrStatusChanged is returned when an order left the expected status
+	// between reading and updating it.
+	ErrStatusChanged = errors.New("order status changed concurrently")
+)
+
 type Store struct {
 	log *logger.Logger
 	q   *db.Queries
@@ -37,6 +46,61 @@ func (s *Store) CreateOrder(ctx context.Context, params db.CreateOrderParams) (d
 	return o, nil
 }
 
+func (s *Store) GetOrder(ctx context.Context, id string) (db.Order, error) {
+	o, err := s.q.GetOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Order{}, ErrNotFound
+		}
+		return db.Order{}, fmt.Errorf("querying order: %w", err)
+	}
+	return o, nil
+}
+
+// GetOrderForUpdate loads the order and locks its row until the surrounding
+// transaction ends.
+func (s *Store) GetOrderForUpdate(ctx context.Context, id string) (db.Order, error) {
+	o, err := s.q.GetOrderForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Order{}, ErrNotFound
+		}
+		return db.Order{}, fmt.Errorf("locking order: %w", err)
+	}
+	return o, nil
+}
+
+// SetOrderPaymentID records the payment gateway's reference for the order.
+func (s *Store) SetOrderPaymentID(ctx context.Context, id, paymentID string) (db.Order, error) {
+	o, err := s.q.SetOrderPaymentID(ctx, db.SetOrderPaymentIDParams{
+		ID:        id,
+		PaymentID: pgtype.Text{String: paymentID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Order{}, ErrNotFound
+		}
+		return db.Order{}, fmt.Errorf("setting order payment: %w", err)
+	}
+	return o, nil
+}
+
+// UpdateOrderStatus moves the order to status only if it is still in fromStatus.
+func (s *Store) UpdateOrderStatus(ctx context.Context, id, fromStatus, status string) (db.Order, error) {
+	o, err := s.q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		Status:     status,
+		ID:         id,
+		FromStatus: fromStatus,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Order{}, ErrStatusChanged
+		}
+		return db.Order{}, fmt.Errorf("updating order status: %w", err)
+	}
+	return o, nil
+}
+
 func (s *Store) CountOrdersByBuyer(ctx context.Context, buyerID string) (int64, error) {
 	c, err := s.q.CountOrdersByBuyer(ctx, buyerID)
 	if err != nil {
@@ -54,4 +118,19 @@ func (s *Store) CountOrdersByBuyerAndProduct(ctx context.Context, buyerID, produ
 		return 0, fmt.Errorf("counting orders by product: %w", err)
 	}
 	return c, nil
-}
\ No newline at end of file
+}
+func (s *Store) CreateOrderItem(ctx context.Context, params db.CreateOrderItemParams) (db.OrderItem, error) {
+	i, err := s.q.CreateOrderItem(ctx, params)
+	if err != nil {
+		return db.OrderItem{}, fmt.Errorf("creating order item: %w", err)
+	}
+	return i, nil
+}
+
+func (s *Store) ListOrderItems(ctx context.Context, orderID string) ([]db.OrderItem, error) {
+	items, err := s.q.ListOrderItems(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("listing order items: %w", err)
+	}
+	return items, nil
+}
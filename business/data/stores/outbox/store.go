@@ -0,0 +1,123 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"soda-interview/business/data/stores/db"
+	"soda-interview/foundation/logger"
+)
+
+// Event statuses persisted in outbox_events.status.
+const (
+	StatusPending   = "PENDING"
+	StatusPublished = "PUBLISHED"
+	StatusDead      = "DEAD"
+)
+
+type Store struct {
+	log *logger.Logger
+	q   *db.Queries
+}
+
+func NewStore(log *logger.Logger, pool *pgxpool.Pool) *Store {
+	return &Store{
+		log: log,
+		q:   db.New(pool),
+	}
+}
+
+func (s *Store) WithTx(tx pgx.Tx) *Store {
+	return &Store{
+		log: s.log,
+		q:   s.q.WithTx(tx),
+	}
+}
+
+// Append records msg as an event about aggregateID. Call it on a store bound
+// to the transaction making the change, so the event is published if and
+// only if the change commits. The event type is the message's full name.
+func (s *Store) Append(ctx context.Context, aggregateID string, msg proto.Message) error {
+	payload, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	err = s.q.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		EventID:     uuid.NewString(),
+		EventType:   string(msg.ProtoReflect().Descriptor().FullName()),
+		AggregateID: aggregateID,
+		Payload:     payload,
+	})
+	if err != nil {
+		return fmt.Errorf("appending outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue locks up to batchSize pending events due by now, oldest first.
+// Events locked by another transaction are skipped.
+func (s *Store) ClaimDue(ctx context.Context, now time.Time, batchSize int32) ([]db.OutboxEvent, error) {
+	events, err := s.q.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+		Now:       pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize: batchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claiming outbox events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *Store) MarkPublished(ctx context.Context, id int64) error {
+	if err := s.q.MarkOutboxEventPublished(ctx, id); err != nil {
+		return fmt.Errorf("marking outbox event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt. The event is retried at next, or
+// dead-lettered when dead is set.
+func (s *Store) MarkFailed(ctx context.Context, id int64, cause error, next time.Time, dead bool) error {
+	status := StatusPending
+	if dead {
+		status = StatusDead
+	}
+	err := s.q.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+		ID:            id,
+		Status:        status,
+		NextAttemptAt: pgtype.Timestamptz{Time: next, Valid: true},
+		LastError:     pgtype.Text{String: cause.Error(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("marking outbox event failed: %w", err)
+	}
+	return nil
+}
+
+// List returns the events about aggregateID, or every event when it is
+// empty, oldest first.
+func (s *Store) List(ctx context.Context, aggregateID string) ([]db.OutboxEvent, error) {
+	events, err := s.q.ListOutboxEvents(ctx, pgtype.Text{String: aggregateID, Valid: aggregateID != ""})
+	if err != nil {
+		return nil, fmt.Errorf("listing outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// Requeue moves a dead event back to pending with a fresh set of attempts.
+// It reports whether the event was dead.
+func (s *Store) Requeue(ctx context.Context, id int64) (bool, error) {
+	n, err := s.q.RequeueOutboxEvent(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("requeueing outbox event: %w", err)
+	}
+	return n > 0, nil
+}
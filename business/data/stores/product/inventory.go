@@ -0,0 +1,116 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+var (
+	// ErrNotTracked is returned for products that have no stock record.
+	ErrNotTracked = errors.New("product stock is not tracked")
+	// ErrOutOfStock is returned when fewer units are available than requested.
+	ErrOutOfStock = errors.New("product is out of stock")
+	// ErrBelowReserved is returned when an adjustment would leave fewer units
+	// on hand than are reserved by open orders.
+	ErrBelowReserved = errors.New("stock on hand cannot fall below reserved")
+)
+
+// GetStock returns ErrNotTracked if the product has no stock record.
+func (s *Store) GetStock(ctx context.Context, productID string) (db.ProductStock, error) {
+	st, err := s.q.GetStock(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ProductStock{}, ErrNotTracked
+		}
+		return db.ProductStock{}, fmt.Errorf("querying stock: %w", err)
+	}
+	return st, nil
+}
+
+// AdjustStock changes on_hand by delta and logs an ADJUST movement. The
+// first adjustment that is accepted starts tracking the product; a rejected
+// one leaves it untracked.
+func (s *Store) AdjustStock(ctx context.Context, productID string, delta int32, reason string) (db.ProductStock, error) {
+	st, err := s.q.AdjustStock(ctx, db.AdjustStockParams{
+		ProductID: productID,
+		Delta:     delta,
+		Reason:    reason,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ProductStock{}, ErrBelowReserved
+		}
+		return db.ProductStock{}, fmt.Errorf("adjusting stock: %w", err)
+	}
+	return db.ProductStock(st), nil
+}
+
+// ReserveStock holds quantity units for an order and logs a RESERVE
+// movement. The update is conditional, so concurrent orders cannot reserve
+// more than is available.
+func (s *Store) ReserveStock(ctx context.Context, productID, orderID string, quantity int32) (db.ProductStock, error) {
+	st, err := s.q.ReserveStock(ctx, db.ReserveStockParams{
+		ProductID: productID,
+		OrderID:   pgtype.Text{String: orderID, Valid: true},
+		Quantity:  quantity,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ProductStock{}, ErrOutOfStock
+		}
+		return db.ProductStock{}, fmt.Errorf("reserving stock: %w", err)
+	}
+	return db.ProductStock(st), nil
+}
+
+// ReleaseStock returns units reserved by an order and logs a RELEASE movement.
+func (s *Store) ReleaseStock(ctx context.Context, productID, orderID string, quantity int32) (db.ProductStock, error) {
+	st, err := s.q.ReleaseStock(ctx, db.ReleaseStockParams{
+		ProductID: productID,
+		OrderID:   pgtype.Text{String: orderID, Valid: true},
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return db.ProductStock{}, fmt.Errorf("releasing stock: %w", err)
+	}
+	return db.ProductStock(st), nil
+}
+
+// ShipStock removes units reserved by an order from on_hand and logs a SHIP
+// movement.
+func (s *Store) ShipStock(ctx context.Context, productID, orderID string, quantity int32) (db.ProductStock, error) {
+	st, err := s.q.ShipStock(ctx, db.ShipStockParams{
+		ProductID: productID,
+		OrderID:   pgtype.Text{String: orderID, Valid: true},
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return db.ProductStock{}, fmt.Errorf("shipping stock: %w", err)
+	}
+	return db.ProductStock(st), nil
+}
+
+// ReservedForOrder returns how many units of the product the order still holds.
+func (s *Store) ReservedForOrder(ctx context.Context, productID, orderID string) (int32, error) {
+	n, err := s.q.ReservedForOrder(ctx, db.ReservedForOrderParams{
+		ProductID: productID,
+		OrderID:   pgtype.Text{String: orderID, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querying reservation: %w", err)
+	}
+	return n, nil
+}
+
+func (s *Store) ListInventoryMovements(ctx context.Context, productID string) ([]db.InventoryMovement, error) {
+	ms, err := s.q.ListInventoryMovements(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("listing inventory movements: %w", err)
+	}
+	return ms, nil
+}
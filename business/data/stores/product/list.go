@@ -0,0 +1,109 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"soda-interview/business/data/stores/db"
+	"soda-interview/foundation/paging"
+)
+
+// Sort orders for ListProducts. Each sorts ties by id in the same direction
+// and has its own query, over a matching index.
+const (
+	OrderByCreatedAt     = "created_at"
+	OrderByCreatedAtDesc = "created_at desc"
+	OrderByPrice         = "price"
+	OrderByPriceDesc     = "price desc"
+)
+
+// ProductFilter narrows and pages ListProducts. Zero values leave a filter
+// open.
+type ProductFilter struct {
+	MinPrice int64
+	MaxPrice int64
+	// NamePattern is an ILIKE pattern the name must match.
+	NamePattern string
+	OrderBy     string
+	// After continues from the last row of an earlier page: its price, or
+	// its created_at in microseconds, and its id. Nil starts at the top.
+	After *paging.Cursor
+	Limit int32
+}
+
+// ListProducts returns a page of the products on sale.
+func (s *Store) ListProducts(ctx context.Context, f ProductFilter) ([]db.Product, error) {
+	minPrice := pgtype.Int8{Int64: f.MinPrice, Valid: f.MinPrice > 0}
+	maxPrice := pgtype.Int8{Int64: f.MaxPrice, Valid: f.MaxPrice > 0}
+	name := pgtype.Text{String: f.NamePattern, Valid: f.NamePattern != ""}
+
+	var (
+		products []db.Product
+		err      error
+	)
+	switch f.OrderBy {
+	case OrderByCreatedAt:
+		products, err = s.q.ListProductsByCreatedAt(ctx, db.ListProductsByCreatedAtParams{
+			MinPrice: minPrice, MaxPrice: maxPrice, NamePattern: name,
+			CursorCreatedAt: afterTime(f.After, false), CursorID: afterID(f.After), RowLimit: f.Limit,
+		})
+	case OrderByCreatedAtDesc:
+		products, err = s.q.ListProductsByCreatedAtDesc(ctx, db.ListProductsByCreatedAtDescParams{
+			MinPrice: minPrice, MaxPrice: maxPrice, NamePattern: name,
+			CursorCreatedAt: afterTime(f.After, true), CursorID: afterID(f.After), RowLimit: f.Limit,
+		})
+	case OrderByPrice:
+		products, err = s.q.ListProductsByPrice(ctx, db.ListProductsByPriceParams{
+			MinPrice: minPrice, MaxPrice: maxPrice, NamePattern: name,
+			CursorPrice: afterPrice(f.After, false), CursorID: afterID(f.After), RowLimit: f.Limit,
+		})
+	case OrderByPriceDesc:
+		products, err = s.q.ListProductsByPriceDesc(ctx, db.ListProductsByPriceDescParams{
+			MinPrice: minPrice, MaxPrice: maxPrice, NamePattern: name,
+			CursorPrice: afterPrice(f.After, true), CursorID: afterID(f.After), RowLimit: f.Limit,
+		})
+	default:
+		return nil, fmt.Errorf("listing products: unknown order %q", f.OrderBy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing products: %w", err)
+	}
+	return products, nil
+}
+
+// The first page starts after a key that every row sorts past, so each sort
+// needs only the one keyset comparison. With the empty id, which sorts
+// first, the bound itself is never listed.
+
+func afterID(c *paging.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	return c.ID
+}
+
+func afterTime(c *paging.Cursor, desc bool) pgtype.Timestamptz {
+	switch {
+	case c != nil:
+		return pgtype.Timestamptz{Time: time.UnixMicro(c.Key), Valid: true}
+	case desc:
+		return pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true}
+	default:
+		return pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	}
+}
+
+func afterPrice(c *paging.Cursor, desc bool) int64 {
+	switch {
+	case c != nil:
+		return c.Key
+	case desc:
+		return math.MaxInt64
+	default:
+		return math.MinInt64
+	}
+}
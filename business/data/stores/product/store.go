@@ -38,7 +38,7 @@ func (s *Store) WithTx(tx pgx.Tx) *Store {
 func (s *Store) GetProduct(ctx context.Context, id string) (db.Product, error) {
 	p, err := s.q.GetProduct(ctx, id)
 	if err != nil {
-		if err.Error() == "no rows in result set" {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return db.Product{}, ErrNotFound
 		}
 		return db.Product{}, fmt.Errorf("querying product: %w", err)
@@ -46,12 +46,17 @@ func (s *Store) GetProduct(ctx context.Context, id string) (db.Product, error) {
 	return p, nil
 }
 
-func (s *Store) ListProducts(ctx context.Context) ([]db.Product, error) {
-	products, err := s.q.ListProducts(ctx)
+// GetProductForShare loads the product and keeps it from being archived or
+// updated until the surrounding transaction ends.
+func (s *Store) GetProductForShare(ctx context.Context, id string) (db.Product, error) {
+	p, err := s.q.GetProductForShare(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("listing products: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrNotFound
+		}
+		return db.Product{}, fmt.Errorf("querying product: %w", err)
 	}
-	return products, nil
+	return p, nil
 }
 
 func (s *Store) CreateProduct(ctx context.Context, params db.CreateProductParams) (db.Product, error) {
@@ -60,4 +65,29 @@ func (s *Store) CreateProduct(ctx context.Context, params db.CreateProductParams
 		return db.Product{}, fmt.Errorf("creating product: %w", err)
 	}
 	return p, nil
-}
\ No newline at end of file
+}
+// UpdateProduct changes the non-NULL fields of params. It returns ErrNotFound
+// if the product does not exist or is archived.
+func (s *Store) UpdateProduct(ctx context.Context, params db.UpdateProductParams) (db.Product, error) {
+	p, err := s.q.UpdateProduct(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrNotFound
+		}
+		return db.Product{}, fmt.Errorf("updating product: %w", err)
+	}
+	return p, nil
+}
+
+// ArchiveProduct marks the product archived. It returns ErrNotFound if the
+// product does not exist or is already archived.
+func (s *Store) ArchiveProduct(ctx context.Context, id string) (db.Product, error) {
+	p, err := s.q.ArchiveProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrNotFound
+		}
+		return db.Product{}, fmt.Errorf("archiving product: %w", err)
+	}
+	return p, nil
+}
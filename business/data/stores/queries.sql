@@ -1,48 +1,611 @@
 -- name: GetProduct :one
 SELECT * FROM products WHERE id = $1;
 
--- name: ListProducts :many
-SELECT * FROM products;
+-- name: GetProductForShare :one
+-- Blocks ArchiveProduct and UpdateProduct on the row until the transaction ends.
+SELECT * FROM products WHERE id = $1 FOR SHARE;
+
+-- ListProducts* return products on sale strictly after the (cursor key,
+-- cursor_id) keyset in their sort, with id breaking ties in the same
+-- direction. Each sort has its own query so the keyset comparison can use
+-- the matching index. NULL filters match every product.
+-- name: ListProductsByCreatedAt :many
+SELECT * FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.narg(min_price)::bigint IS NULL OR price >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR price <= sqlc.narg(max_price))
+  AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+  AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::text)
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListProductsByCreatedAtDesc :many
+SELECT * FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.narg(min_price)::bigint IS NULL OR price >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR price <= sqlc.narg(max_price))
+  AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+  AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::text)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListProductsByPrice :many
+SELECT * FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.narg(min_price)::bigint IS NULL OR price >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR price <= sqlc.narg(max_price))
+  AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+  AND (price, id) > (sqlc.arg(cursor_price)::bigint, sqlc.arg(cursor_id)::text)
+ORDER BY price, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListProductsByPriceDesc :many
+SELECT * FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.narg(min_price)::bigint IS NULL OR price >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR price <= sqlc.narg(max_price))
+  AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+  AND (price, id) < (sqlc.arg(cursor_price)::bigint, sqlc.arg(cursor_id)::text)
+ORDER BY price DESC, id DESC
+LIMIT sqlc.arg(row_limit);
 
 -- name: CreateProduct :one
 INSERT INTO products (id, name, description, price, buyer_reward_points, author_reward_points) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
 
+-- name: UpdateProduct :one
+-- NULL arguments leave the column unchanged. Archived products are not updated.
+UPDATE products
+SET name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    price = COALESCE(sqlc.narg(price), price),
+    buyer_reward_points = COALESCE(sqlc.narg(buyer_reward_points), buyer_reward_points),
+    author_reward_points = COALESCE(sqlc.narg(author_reward_points), author_reward_points)
+WHERE id = sqlc.arg(id) AND archived_at IS NULL
+RETURNING *;
+
+-- name: ArchiveProduct :one
+UPDATE products SET archived_at = NOW() WHERE id = $1 AND archived_at IS NULL RETURNING *;
+
+-- name: GetStock :one
+SELECT * FROM product_stock WHERE product_id = $1;
+
+-- name: AdjustStock :one
+-- Changes on_hand and logs the movement, creating the stock row for an
+-- untracked product. No row, and nothing written, when on_hand would fall
+-- below reserved. The proposed row never holds a negative on_hand, which
+-- would fail the CHECK before the conflict is seen.
+WITH adjusted AS (
+    INSERT INTO product_stock (product_id, on_hand)
+    SELECT sqlc.arg(product_id), GREATEST(sqlc.arg(delta)::INTEGER, 0)
+    WHERE sqlc.arg(delta) >= 0
+       OR EXISTS (SELECT 1 FROM product_stock s WHERE s.product_id = sqlc.arg(product_id))
+    ON CONFLICT (product_id) DO UPDATE
+    SET on_hand = product_stock.on_hand + sqlc.arg(delta), updated_at = NOW()
+    WHERE product_stock.on_hand + sqlc.arg(delta) >= product_stock.reserved
+    RETURNING *
+), logged AS (
+    INSERT INTO inventory_movements (product_id, kind, on_hand_delta, reserved_delta, reason)
+    SELECT adjusted.product_id, 'ADJUST', sqlc.arg(delta), 0, sqlc.arg(reason) FROM adjusted
+)
+SELECT * FROM adjusted;
+
+-- name: ReserveStock :one
+-- Holds quantity units for an order. No row when fewer are available.
+WITH reserved AS (
+    UPDATE product_stock
+    SET reserved = reserved + sqlc.arg(quantity), updated_at = NOW()
+    WHERE product_stock.product_id = sqlc.arg(product_id) AND on_hand - reserved >= sqlc.arg(quantity)
+    RETURNING *
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT reserved.product_id, sqlc.arg(order_id), 'RESERVE', 0, sqlc.arg(quantity) FROM reserved
+)
+SELECT * FROM reserved;
+
+-- name: ReleaseStock :one
+-- Returns units reserved by a cancelled order to the available pool.
+WITH released AS (
+    UPDATE product_stock
+    SET reserved = reserved - sqlc.arg(quantity), updated_at = NOW()
+    WHERE product_stock.product_id = sqlc.arg(product_id)
+    RETURNING *
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT released.product_id, sqlc.arg(order_id), 'RELEASE', 0, -sqlc.arg(quantity)::INTEGER FROM released
+)
+SELECT * FROM released;
+
+-- name: ShipStock :one
+-- Removes units reserved by a shipped order from on_hand.
+WITH shipped AS (
+    UPDATE product_stock
+    SET on_hand = on_hand - sqlc.arg(quantity), reserved = reserved - sqlc.arg(quantity), updated_at = NOW()
+    WHERE product_stock.product_id = sqlc.arg(product_id)
+    RETURNING *
+), logged AS (
+    INSERT INTO inventory_movements (product_id, order_id, kind, on_hand_delta, reserved_delta)
+    SELECT shipped.product_id, sqlc.arg(order_id), 'SHIP', -sqlc.arg(quantity)::INTEGER, -sqlc.arg(quantity)::INTEGER FROM shipped
+)
+SELECT * FROM shipped;
+
+-- name: ReservedForOrder :one
+-- Units of the product still held for the order.
+SELECT COALESCE(SUM(reserved_delta), 0)::INTEGER FROM inventory_movements
+WHERE order_id = $1 AND product_id = $2;
+
+-- name: ListInventoryMovements :many
+SELECT * FROM inventory_movements WHERE product_id = $1 ORDER BY created_at, id;
+
 -- name: CreateBlog :one
-INSERT INTO blogs (id, author_id, content, product_id) VALUES ($1, $2, $3, $4) RETURNING *;
+INSERT INTO blogs (id, author_id, content, product_id, status) VALUES ($1, $2, $3, $4, $5) RETURNING *;
 
 -- name: GetBlog :one
+-- Deleted blogs are returned too; callers check deleted_at.
 SELECT * FROM blogs WHERE id = $1;
 
--- name: ListBlogs :many
-SELECT * FROM blogs;
+-- name: GetBlogForShare :one
+-- Blocks UpdateBlog, ModerateBlog and DeleteBlog on the row until the
+-- transaction ends.
+SELECT * FROM blogs WHERE id = $1 FOR SHARE;
+
+-- name: GetBlogForUpdate :one
+SELECT * FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- ListBlogs* return published blogs strictly after the (cursor_created_at,
+-- cursor_id) keyset in their sort, like ListProducts*. NULL filters match
+-- every blog.
+-- name: ListBlogsByCreatedAt :many
+SELECT * FROM blogs
+WHERE status = 'PUBLISHED' AND deleted_at IS NULL
+  AND (sqlc.narg(author_id)::text IS NULL OR author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(product_id)::text IS NULL OR product_id = sqlc.narg(product_id))
+  AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::text)
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListBlogsByCreatedAtDesc :many
+SELECT * FROM blogs
+WHERE status = 'PUBLISHED' AND deleted_at IS NULL
+  AND (sqlc.narg(author_id)::text IS NULL OR author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(product_id)::text IS NULL OR product_id = sqlc.narg(product_id))
+  AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::text)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateBlog :one
+UPDATE blogs SET content = $2, status = $3, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteBlog :one
+UPDATE blogs SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateBlogRevision :one
+INSERT INTO blog_revisions (blog_id, action, content, status, actor_id, note)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: ListBlogRevisions :many
+SELECT * FROM blog_revisions WHERE blog_id = $1 ORDER BY id;
+
+-- name: CreateReferralVisit :one
+INSERT INTO referral_visits (visitor_id, blog_id) VALUES ($1, $2) RETURNING *;
+
+-- name: GetLatestReferralVisit :one
+-- The visitor's most recent visit since the given time to a blog that could
+-- still refer their order of the product.
+SELECT v.* FROM referral_visits v
+JOIN blogs b ON b.id = v.blog_id
+WHERE v.visitor_id = sqlc.arg(visitor_id)
+  AND v.visited_at >= sqlc.arg(visited_since)
+  AND b.product_id = sqlc.arg(product_id)
+  AND b.author_id <> v.visitor_id
+  AND b.status = 'PUBLISHED' AND b.deleted_at IS NULL
+ORDER BY v.visited_at DESC, v.id DESC
+LIMIT 1;
 
 -- name: CreateOrder :one
-INSERT INTO orders (id, buyer_id, product_id, blog_id, amount, status, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+INSERT INTO orders (id, buyer_id, product_id, blog_id, amount, status, created_at, balance_amount, attribution) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders WHERE id = $1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders WHERE id = $1 FOR UPDATE;
+
+-- name: SetOrderPaymentID :one
+UPDATE orders SET payment_id = $2, updated_at = NOW() WHERE id = $1 RETURNING *;
+
+-- name: UpdateOrderStatus :one
+UPDATE orders SET status = sqlc.arg(status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
 
 -- name: CountOrdersByBuyer :one
 SELECT COUNT(*) FROM orders WHERE buyer_id = $1;
 
 -- name: CountOrdersByBuyerAndProduct :one
-SELECT COUNT(*) FROM orders WHERE buyer_id = $1 AND product_id = $2;
+-- Counts live orders with a line for the product, cart orders included.
+SELECT COUNT(DISTINCT o.id) FROM orders o
+JOIN order_items i ON i.order_id = o.id
+WHERE o.buyer_id = $1 AND i.product_id = $2 AND o.status NOT IN ('CANCELLED', 'REFUNDED');
+
+-- name: CreateOrderItem :one
+INSERT INTO order_items (order_id, line_no, product_id, blog_id, quantity, unit_price, amount,
+    buyer_reward_points, author_reward_points, attribution)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: ListOrderItems :many
+SELECT * FROM order_items WHERE order_id = $1 ORDER BY line_no;
+
+-- name: CreatePaymentAction :one
+INSERT INTO payment_actions (order_id, payment_id, action, amount)
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: ClaimPaymentAction :one
+-- The action, locked until the transaction ends, if it is pending and
+-- nothing before it for the same payment is. A row another transaction holds
+-- is skipped.
+SELECT * FROM payment_actions a
+WHERE a.id = $1 AND a.status = 'PENDING'
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_actions e
+    WHERE e.payment_id = a.payment_id AND e.id < a.id AND e.status = 'PENDING'
+  )
+FOR UPDATE SKIP LOCKED;
+
+-- name: ClaimPaymentActions :many
+-- Due actions that nothing earlier for the same payment is waiting on,
+-- locked like ClaimPaymentAction.
+SELECT * FROM payment_actions a
+WHERE a.status = 'PENDING' AND a.next_attempt_at <= sqlc.arg(now)
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_actions e
+    WHERE e.payment_id = a.payment_id AND e.id < a.id AND e.status = 'PENDING'
+  )
+ORDER BY a.id
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkPaymentActionDone :exec
+UPDATE payment_actions SET status = 'DONE', attempts = attempts + 1, done_at = NOW(), last_error = NULL
+WHERE id = $1;
+
+-- name: MarkPaymentActionFailed :exec
+UPDATE payment_actions
+SET status = sqlc.arg(status), attempts = attempts + 1, next_attempt_at = sqlc.arg(next_attempt_at),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: ListPaymentActions :many
+SELECT * FROM payment_actions WHERE order_id = $1 ORDER BY id;
 
 -- name: GetWallet :one
 SELECT * FROM wallets WHERE user_id = $1;
 
+-- name: GetWalletForUpdate :one
+SELECT * FROM wallets WHERE user_id = $1 FOR UPDATE;
+
 -- name: CreateWallet :one
 INSERT INTO wallets (user_id, soda_points, soda_balance) VALUES ($1, 0, 0) ON CONFLICT (user_id) DO NOTHING RETURNING *;
 
--- name: AddPoints :one
-UPDATE wallets SET soda_points = soda_points + sqlc.arg(amount) WHERE user_id = sqlc.arg(user_id) RETURNING *;
-
--- name: AddBalance :one
-UPDATE wallets SET soda_balance = soda_balance + sqlc.arg(amount) WHERE user_id = sqlc.arg(user_id) RETURNING *;
+-- name: ApplyWalletDelta :one
+-- Moves the cached wallet projection by the net effect of a ledger posting,
+-- creating the wallet on first use.
+INSERT INTO wallets (user_id, soda_points, soda_balance)
+VALUES (sqlc.arg(user_id), sqlc.arg(points_delta), sqlc.arg(balance_delta))
+ON CONFLICT (user_id) DO UPDATE
+SET soda_points = wallets.soda_points + EXCLUDED.soda_points,
+    soda_balance = wallets.soda_balance + EXCLUDED.soda_balance
+RETURNING *;
 
--- name: ConvertPointsToBalance :one
-UPDATE wallets 
-SET soda_points = soda_points - sqlc.arg(points_deducted),
-    soda_balance = soda_balance + sqlc.arg(balance_added)
-WHERE user_id = sqlc.arg(user_id) AND soda_points >= sqlc.arg(points_deducted)
+-- name: DebitWalletBalance :one
+-- Takes yen from soda_balance only if enough is there. Returns no row
+-- otherwise, so the balance can never go negative.
+UPDATE wallets SET soda_balance = soda_balance - sqlc.arg(amount)
+WHERE user_id = sqlc.arg(user_id) AND soda_balance >= sqlc.arg(amount)
 RETURNING *;
 
 -- name: CreateTransaction :one
-INSERT INTO transactions (id, user_id, type, amount, related_order_id) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+INSERT INTO transactions (id, user_id, type, amount, related_order_id, policy_version, reward_rule_ids) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: SumConvertedPointsSince :one
+SELECT COALESCE(SUM(amount), 0)::BIGINT FROM transactions
+WHERE user_id = $1 AND type = 'CONVERTED' AND created_at >= sqlc.arg(since);
+
+-- name: ListTransactionsByOrder :many
+SELECT * FROM transactions WHERE related_order_id = $1 ORDER BY created_at, id;
+
+-- name: EnsureLedgerAccount :exec
+INSERT INTO ledger_accounts (id, owner_id, kind, currency)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: CreateLedgerEntry :exec
+INSERT INTO ledger_entries (transaction_id, account_id, direction, amount, currency)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListLedgerEntriesByTransaction :many
+SELECT * FROM ledger_entries WHERE transaction_id = $1 ORDER BY id;
+
+-- name: ListWalletDrift :many
+-- Wallets whose cached totals differ from the sum of their ledger accounts.
+WITH ledger AS (
+    SELECT a.owner_id AS user_id,
+           COALESCE(SUM(CASE WHEN a.kind = 'USER_POINTS' THEN
+               CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END END), 0)::BIGINT AS points,
+           COALESCE(SUM(CASE WHEN a.kind = 'USER_BALANCE' THEN
+               CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END END), 0)::BIGINT AS balance
+    FROM ledger_accounts a
+    JOIN ledger_entries e ON e.account_id = a.id
+    WHERE a.owner_id IS NOT NULL
+    GROUP BY a.owner_id
+)
+SELECT w.user_id,
+       w.soda_points,
+       w.soda_balance,
+       COALESCE(l.points, 0)::BIGINT AS ledger_points,
+       COALESCE(l.balance, 0)::BIGINT AS ledger_balance
+FROM wallets w
+LEFT JOIN ledger l ON l.user_id = w.user_id
+WHERE w.soda_points <> COALESCE(l.points, 0) OR w.soda_balance <> COALESCE(l.balance, 0)
+ORDER BY w.user_id;
+
+-- name: ListUnbalancedTransactions :many
+-- Transactions whose entries do not net to zero in some currency.
+SELECT transaction_id,
+       currency,
+       SUM(CASE direction WHEN 'CREDIT' THEN amount ELSE -amount END)::BIGINT AS net
+FROM ledger_entries
+GROUP BY transaction_id, currency
+HAVING SUM(CASE direction WHEN 'CREDIT' THEN amount ELSE -amount END) <> 0
+ORDER BY transaction_id, currency;
+
+-- name: LockLedger :exec
+-- Waits for in-flight postings and blocks new ones until the transaction ends.
+LOCK TABLE ledger_entries IN SHARE MODE;
+
+-- name: RebuildWallets :execrows
+-- Recomputes every wallet from the ledger.
+UPDATE wallets w
+SET soda_points = COALESCE((
+        SELECT SUM(CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END)
+        FROM ledger_entries e
+        JOIN ledger_accounts a ON a.id = e.account_id
+        WHERE a.owner_id = w.user_id AND a.kind = 'USER_POINTS'), 0),
+    soda_balance = COALESCE((
+        SELECT SUM(CASE e.direction WHEN 'CREDIT' THEN e.amount ELSE -e.amount END)
+        FROM ledger_entries e
+        JOIN ledger_accounts a ON a.id = e.account_id
+        WHERE a.owner_id = w.user_id AND a.kind = 'USER_BALANCE'), 0);
+
+-- name: ListTransactionsByUser :many
+-- Newest first. Rows strictly after the (cursor_created_at, cursor_id) keyset
+-- are returned when a cursor is given; empty types matches every type.
+SELECT * FROM transactions
+WHERE user_id = sqlc.arg(user_id)
+  AND (COALESCE(cardinality(sqlc.arg(types)::text[]), 0) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+       OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::text))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetActiveConversionPolicy :one
+-- The policy in force now: the latest one whose effective_from has passed.
+SELECT * FROM conversion_policies
+WHERE effective_from <= NOW()
+ORDER BY effective_from DESC, version DESC
+LIMIT 1;
+
+-- name: CreateConversionPolicy :one
+INSERT INTO conversion_policies (points_per_yen, min_balance, min_points, max_points, daily_cap_points, effective_from, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListConversionPolicies :many
+SELECT * FROM conversion_policies ORDER BY effective_from DESC, version DESC;
+
+-- name: CreatePointLot :exec
+INSERT INTO point_lots (user_id, transaction_id, amount, remaining, expires_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(transaction_id), sqlc.arg(amount), sqlc.arg(amount), sqlc.arg(expires_at));
+
+-- name: ListOpenPointLotsForUpdate :many
+-- Open lots in the order they are spent: soonest expiry first, lots that
+-- never expire last.
+SELECT * FROM point_lots
+WHERE user_id = $1 AND remaining > 0
+ORDER BY expires_at ASC NULLS LAST, id
+FOR UPDATE;
+
+-- name: ListOpenOrderPointLotsForUpdate :many
+-- Open lots the user's EARNED transactions for the order opened, in the
+-- order they are spent.
+SELECT l.* FROM point_lots l
+JOIN transactions t ON t.id = l.transaction_id
+WHERE l.user_id = $1 AND t.related_order_id = $2 AND l.remaining > 0
+ORDER BY l.expires_at ASC NULLS LAST, l.id
+FOR UPDATE OF l;
+
+-- name: SetPointLotRemaining :exec
+UPDATE point_lots SET remaining = $2 WHERE id = $1;
+
+-- name: GetPointLot :one
+SELECT * FROM point_lots WHERE id = $1;
+
+-- name: GetPointLotForUpdate :one
+SELECT * FROM point_lots WHERE id = $1 FOR UPDATE;
+
+-- name: ExpirePointLot :exec
+UPDATE point_lots SET remaining = 0, expired_at = NOW() WHERE id = $1;
+
+-- name: ListDuePointLots :many
+SELECT id FROM point_lots
+WHERE remaining > 0 AND expires_at <= sqlc.arg(due_at)
+ORDER BY expires_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListExpiringPointLots :many
+SELECT * FROM point_lots
+WHERE user_id = $1 AND remaining > 0 AND expires_at < sqlc.arg(before)
+ORDER BY expires_at, id;
+
+-- name: ClaimIdempotencyKey :one
+-- Inserts the key, or takes over an expired one. Returns no row while an
+-- unexpired record for the same (user_id, idempotency_key) exists.
+INSERT INTO idempotency_keys (user_id, idempotency_key, operation, request_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET operation = EXCLUDED.operation,
+    request_hash = EXCLUDED.request_hash,
+    response = NULL,
+    created_at = NOW(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= NOW()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys SET response = $3 WHERE user_id = $1 AND idempotency_key = $2;
+
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox_events (event_id, event_type, aggregate_id, payload)
+VALUES ($1, $2, $3, $4);
+
+-- name: ClaimOutboxEvents :many
+-- Due events, locked until the relay's transaction ends. Rows another relay
+-- holds are skipped, so replicas can relay side by side.
+SELECT * FROM outbox_events
+WHERE status = 'PENDING' AND next_attempt_at <= sqlc.arg(now)
+ORDER BY id
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox_events SET status = 'PUBLISHED', attempts = attempts + 1, published_at = NOW(), last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox_events
+SET status = sqlc.arg(status), attempts = attempts + 1, next_attempt_at = sqlc.arg(next_attempt_at),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: ListOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE (sqlc.narg(aggregate_id)::TEXT IS NULL OR aggregate_id = sqlc.narg(aggregate_id))
+ORDER BY id;
+
+-- name: RequeueOutboxEvent :execrows
+UPDATE outbox_events SET status = 'PENDING', attempts = 0, next_attempt_at = NOW()
+WHERE id = $1 AND status = 'DEAD';
+
+-- name: ReferralStatsByBlog :many
+-- Referred sales per blog: the lines of orders that still stand (confirmed,
+-- shipped or delivered) placed in [created_from, created_to).
+SELECT b.id AS blog_id, b.author_id, b.product_id,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= sqlc.arg(created_from) AND o.created_at < sqlc.arg(created_to)
+  AND (sqlc.narg(author_id)::text IS NULL OR b.author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(blog_id)::text IS NULL OR b.id = sqlc.narg(blog_id))
+GROUP BY b.id
+ORDER BY revenue DESC, b.id;
+
+-- name: ReferralStatsByAuthor :many
+-- ReferralStatsByBlog rolled up per author.
+SELECT b.author_id,
+       COUNT(DISTINCT b.id) AS blogs,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= sqlc.arg(created_from) AND o.created_at < sqlc.arg(created_to)
+  AND (sqlc.narg(author_id)::text IS NULL OR b.author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(blog_id)::text IS NULL OR b.id = sqlc.narg(blog_id))
+GROUP BY b.author_id
+ORDER BY revenue DESC, b.author_id;
+
+-- name: ReferralStatsSeries :many
+-- ReferralStatsByBlog totalled per UTC day or week (bucket is 'day' or
+-- 'week'). Buckets without sales are omitted.
+SELECT date_trunc(sqlc.arg(bucket)::text, o.created_at, 'UTC')::TIMESTAMPTZ AS bucket_start,
+       COUNT(DISTINCT o.id) AS orders,
+       COALESCE(SUM(i.amount), 0)::BIGINT AS revenue,
+       COALESCE(SUM(i.author_reward_points), 0)::BIGINT AS points
+FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED')
+  AND o.created_at >= sqlc.arg(created_from) AND o.created_at < sqlc.arg(created_to)
+  AND (sqlc.narg(author_id)::text IS NULL OR b.author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(blog_id)::text IS NULL OR b.id = sqlc.narg(blog_id))
+GROUP BY bucket_start
+ORDER BY bucket_start;
+
+-- name: CreateRewardRule :one
+INSERT INTO reward_rules (name, recipient, kind, value, priority, product_id, first_purchase_only,
+    min_author_sales, max_author_sales, user_cap_points, starts_at, ends_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING *;
+
+-- name: ListRewardRules :many
+SELECT * FROM reward_rules ORDER BY id DESC;
+
+-- name: ListActiveRewardRules :many
+-- The rules in force at the given time, in the order they are evaluated.
+SELECT * FROM reward_rules
+WHERE starts_at <= sqlc.arg(at) AND (ends_at IS NULL OR ends_at > sqlc.arg(at))
+ORDER BY priority, id;
+
+-- name: EndRewardRule :one
+-- Ends a rule that is still running or scheduled. A rule that has not started
+-- yet ends as it starts, so it never applies.
+UPDATE reward_rules SET ends_at = GREATEST(starts_at, sqlc.arg(ends_at))
+WHERE id = sqlc.arg(id) AND (ends_at IS NULL OR ends_at > sqlc.arg(ends_at))
+RETURNING *;
+
+-- name: GetRewardRule :one
+SELECT * FROM reward_rules WHERE id = $1;
+
+-- name: LockRewardCap :exec
+-- Serializes orders drawing on the same user's cap for a rule until the
+-- transaction ends. The key hashes the full rule id with the user id, so
+-- rule ids past 32 bits do not share locks.
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(rule_id)::BIGINT || ':' || sqlc.arg(user_id)::TEXT, 0));
+
+-- name: SumRewardGrants :one
+-- Points the user has earned from the rule on orders that were not cancelled
+-- or refunded.
+SELECT COALESCE(SUM(g.points), 0)::BIGINT FROM reward_grants g
+JOIN orders o ON o.id = g.order_id
+WHERE g.rule_id = $1 AND g.user_id = $2 AND o.status NOT IN ('CANCELLED', 'REFUNDED');
+
+-- name: CreateRewardGrant :exec
+INSERT INTO reward_grants (order_id, line_no, rule_id, user_id, points) VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListRewardGrantsByOrder :many
+SELECT g.*, r.recipient FROM reward_grants g
+JOIN reward_rules r ON r.id = g.rule_id
+WHERE g.order_id = $1
+ORDER BY g.line_no, r.priority, r.id;
+
+-- name: SumReferredSalesByAuthor :one
+-- The author's lifetime referred sales in yen: the referred lines of orders
+-- that still stand.
+SELECT COALESCE(SUM(i.amount), 0)::BIGINT FROM order_items i
+JOIN orders o ON o.id = i.order_id
+JOIN blogs b ON b.id = i.blog_id
+WHERE b.author_id = $1 AND o.status IN ('CONFIRMED', 'SHIPPED', 'DELIVERED');
@@ -0,0 +1,77 @@
+package referralblog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"soda-interview/business/data/stores/db"
+)
+
+// ReferralFilter narrows referral statistics to orders placed in [From, To)
+// and, optionally, to one author or blog.
+type ReferralFilter struct {
+	From     time.Time
+	To       time.Time
+	AuthorID string
+	BlogID   string
+}
+
+func (f ReferralFilter) from() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: f.From, Valid: true}
+}
+
+func (f ReferralFilter) to() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: f.To, Valid: true}
+}
+
+func (f ReferralFilter) authorID() pgtype.Text {
+	return pgtype.Text{String: f.AuthorID, Valid: f.AuthorID != ""}
+}
+
+func (f ReferralFilter) blogID() pgtype.Text {
+	return pgtype.Text{String: f.BlogID, Valid: f.BlogID != ""}
+}
+
+func (s *Store) ReferralStatsByBlog(ctx context.Context, f ReferralFilter) ([]db.ReferralStatsByBlogRow, error) {
+	rows, err := s.q.ReferralStatsByBlog(ctx, db.ReferralStatsByBlogParams{
+		CreatedFrom: f.from(),
+		CreatedTo:   f.to(),
+		AuthorID:    f.authorID(),
+		BlogID:      f.blogID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aggregating referrals by blog: %w", err)
+	}
+	return rows, nil
+}
+
+func (s *Store) ReferralStatsByAuthor(ctx context.Context, f ReferralFilter) ([]db.ReferralStatsByAuthorRow, error) {
+	rows, err := s.q.ReferralStatsByAuthor(ctx, db.ReferralStatsByAuthorParams{
+		CreatedFrom: f.from(),
+		CreatedTo:   f.to(),
+		AuthorID:    f.authorID(),
+		BlogID:      f.blogID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aggregating referrals by author: %w", err)
+	}
+	return rows, nil
+}
+
+// ReferralStatsSeries totals referrals per UTC "day" or "week" bucket.
+func (s *Store) ReferralStatsSeries(ctx context.Context, f ReferralFilter, bucket string) ([]db.ReferralStatsSeriesRow, error) {
+	rows, err := s.q.ReferralStatsSeries(ctx, db.ReferralStatsSeriesParams{
+		Bucket:      bucket,
+		CreatedFrom: f.from(),
+		CreatedTo:   f.to(),
+		AuthorID:    f.authorID(),
+		BlogID:      f.blogID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aggregating referrals by %s: %w", bucket, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,71 @@
+package referralblog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"soda-interview/business/data/stores/db"
+	"soda-interview/foundation/paging"
+)
+
+// Sort orders for ListBlogs. Each sorts ties by id in the same direction and
+// has its own query, over a matching index.
+const (
+	OrderByCreatedAt     = "created_at"
+	OrderByCreatedAtDesc = "created_at desc"
+)
+
+// BlogFilter narrows and pages ListBlogs. Empty ids leave a filter open.
+type BlogFilter struct {
+	AuthorID  string
+	ProductID string
+	OrderBy   string
+	// After continues from the last row of an earlier page: its created_at
+	// in microseconds and its id. Nil starts at the top.
+	After *paging.Cursor
+	Limit int32
+}
+
+// ListBlogs returns a page of the published blogs.
+func (s *Store) ListBlogs(ctx context.Context, f BlogFilter) ([]db.Blog, error) {
+	authorID := pgtype.Text{String: f.AuthorID, Valid: f.AuthorID != ""}
+	productID := pgtype.Text{String: f.ProductID, Valid: f.ProductID != ""}
+
+	// The first page starts after a bound every row sorts past, so each
+	// sort needs only the one keyset comparison.
+	after := pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	var afterID string
+	switch {
+	case f.After != nil:
+		after = pgtype.Timestamptz{Time: time.UnixMicro(f.After.Key), Valid: true}
+		afterID = f.After.ID
+	case f.OrderBy == OrderByCreatedAtDesc:
+		after.InfinityModifier = pgtype.Infinity
+	}
+
+	var (
+		blogs []db.Blog
+		err   error
+	)
+	switch f.OrderBy {
+	case OrderByCreatedAt:
+		blogs, err = s.q.ListBlogsByCreatedAt(ctx, db.ListBlogsByCreatedAtParams{
+			AuthorID: authorID, ProductID: productID,
+			CursorCreatedAt: after, CursorID: afterID, RowLimit: f.Limit,
+		})
+	case OrderByCreatedAtDesc:
+		blogs, err = s.q.ListBlogsByCreatedAtDesc(ctx, db.ListBlogsByCreatedAtDescParams{
+			AuthorID: authorID, ProductID: productID,
+			CursorCreatedAt: after, CursorID: afterID, RowLimit: f.Limit,
+		})
+	default:
+		return nil, fmt.Errorf("listing blogs: unknown order %q", f.OrderBy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing blogs: %w", err)
+	}
+	return blogs, nil
+}
@@ -15,6 +15,24 @@ var (
 	ErrNotFound = errors.New("blog not found")
 )
 
+// Blog statuses persisted in blogs.status. Only PUBLISHED blogs are listed
+// and earn referral rewards.
+const (
+	StatusDraft         = "DRAFT"
+	StatusPendingReview = "PENDING_REVIEW"
+	StatusPublished     = "PUBLISHED"
+	StatusRejected      = "REJECTED"
+	StatusTakenDown     = "TAKEN_DOWN"
+)
+
+// Revision actions persisted in blog_revisions.action.
+const (
+	ActionCreated   = "CREATED"
+	ActionEdited    = "EDITED"
+	ActionModerated = "MODERATED"
+	ActionDeleted   = "DELETED"
+)
+
 type Store struct {
 	log *logger.Logger
 	q   *db.Queries
@@ -42,10 +60,24 @@ func (s *Store) CreateBlog(ctx context.Context, params db.CreateBlogParams) (db.
 	return b, nil
 }
 
+// GetBlog returns the blog, even if it was deleted.
 func (s *Store) GetBlog(ctx context.Context, id string) (db.Blog, error) {
 	b, err := s.q.GetBlog(ctx, id)
 	if err != nil {
-		if err.Error() == "no rows in result set" {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Blog{}, ErrNotFound
+		}
+		return db.Blog{}, fmt.Errorf("querying blog: %w", err)
+	}
+	return b, nil
+}
+
+// GetBlogForShare is GetBlog with the row share-locked until the
+// transaction ends.
+func (s *Store) GetBlogForShare(ctx context.Context, id string) (db.Blog, error) {
+	b, err := s.q.GetBlogForShare(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return db.Blog{}, ErrNotFound
 		}
 		return db.Blog{}, fmt.Errorf("querying blog: %w", err)
@@ -53,10 +85,63 @@ func (s *Store) GetBlog(ctx context.Context, id string) (db.Blog, error) {
 	return b, nil
 }
 
-func (s *Store) ListBlogs(ctx context.Context) ([]db.Blog, error) {
-	blogs, err := s.q.ListBlogs(ctx)
+// GetBlogForUpdate locks a blog that has not been deleted.
+func (s *Store) GetBlogForUpdate(ctx context.Context, id string) (db.Blog, error) {
+	b, err := s.q.GetBlogForUpdate(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("listing blogs: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Blog{}, ErrNotFound
+		}
+		return db.Blog{}, fmt.Errorf("querying blog: %w", err)
 	}
-	return blogs, nil
-}
\ No newline at end of file
+	return b, nil
+}
+
+func (s *Store) UpdateBlog(ctx context.Context, id, content, status string) (db.Blog, error) {
+	b, err := s.q.UpdateBlog(ctx, db.UpdateBlogParams{ID: id, Content: content, Status: status})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Blog{}, ErrNotFound
+		}
+		return db.Blog{}, fmt.Errorf("updating blog: %w", err)
+	}
+	return b, nil
+}
+
+// DeleteBlog marks the blog deleted. It returns ErrNotFound if the blog does
+// not exist or is already deleted.
+func (s *Store) DeleteBlog(ctx context.Context, id string) (db.Blog, error) {
+	b, err := s.q.DeleteBlog(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Blog{}, ErrNotFound
+		}
+		return db.Blog{}, fmt.Errorf("deleting blog: %w", err)
+	}
+	return b, nil
+}
+
+// AddRevision records the state a change left the blog in.
+func (s *Store) AddRevision(ctx context.Context, b db.Blog, action, actorID, note string) error {
+	_, err := s.q.CreateBlogRevision(ctx, db.CreateBlogRevisionParams{
+		BlogID:  b.ID,
+		Action:  action,
+		Content: b.Content,
+		Status:  b.Status,
+		ActorID: actorID,
+		Note:    note,
+	})
+	if err != nil {
+		return fmt.Errorf("recording blog revision: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns the blog's history, oldest first.
+func (s *Store) ListRevisions(ctx context.Context, blogID string) ([]db.BlogRevision, error) {
+	revs, err := s.q.ListBlogRevisions(ctx, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("listing blog revisions: %w", err)
+	}
+	return revs, nil
+}
@@ -0,0 +1,45 @@
+package referralblog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"soda-interview/business/data/stores/db"
+)
+
+// ErrNoVisit is returned when the visitor has no visit that can refer their
+// order.
+var ErrNoVisit = errors.New("no referral visit")
+
+func (s *Store) RecordVisit(ctx context.Context, visitorID, blogID string) (db.ReferralVisit, error) {
+	v, err := s.q.CreateReferralVisit(ctx, db.CreateReferralVisitParams{
+		VisitorID: visitorID,
+		BlogID:    blogID,
+	})
+	if err != nil {
+		return db.ReferralVisit{}, fmt.Errorf("recording referral visit: %w", err)
+	}
+	return v, nil
+}
+
+// LatestVisit returns the visitor's most recent visit since the given time to
+// a published blog about the product that they did not write.
+func (s *Store) LatestVisit(ctx context.Context, visitorID, productID string, since time.Time) (db.ReferralVisit, error) {
+	v, err := s.q.GetLatestReferralVisit(ctx, db.GetLatestReferralVisitParams{
+		VisitorID:    visitorID,
+		VisitedSince: pgtype.Timestamptz{Time: since, Valid: true},
+		ProductID:    productID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ReferralVisit{}, ErrNoVisit
+		}
+		return db.ReferralVisit{}, fmt.Errorf("querying latest referral visit: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,447 @@
+package sodafinance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+// ErrUnbalanced is returned when a posting's debits and credits differ in
+// some currency.
+var ErrUnbalanced = errors.New("ledger posting is unbalanced")
+
+// Ledger currencies.
+const (
+	CurrencyPoints = "POINTS"
+	CurrencyYen    = "YEN"
+)
+
+// Entry directions. User accounts are credit-normal, so a CREDIT raises the
+// wallet and a DEBIT lowers it.
+const (
+	Debit  = "DEBIT"
+	Credit = "CREDIT"
+)
+
+// Account kinds stored in ledger_accounts.kind.
+const (
+	KindUserPoints      = "USER_POINTS"
+	KindUserBalance     = "USER_BALANCE"
+	KindRewardPool      = "REWARD_POOL"
+	KindConversionSink  = "CONVERSION_SINK"
+	KindAdjustments     = "ADJUSTMENTS"
+	KindExpiredPoints   = "EXPIRED_POINTS"
+	KindBalancePayments = "BALANCE_PAYMENTS"
+)
+
+// Account identifies a ledger account. Each account holds one currency, so
+// the conversion sink is split into a points side and a yen side.
+type Account struct {
+	ID       string
+	OwnerID  string // Empty for platform accounts.
+	Kind     string
+	Currency string
+}
+
+// Platform accounts. Rewards are paid out of the reward pool, conversions
+// move points into the sink and yen out of it, and manual corrections go
+// through the adjustment accounts. Expired points are written off to the
+// expired points account, and yen spent on orders goes to balance payments.
+var (
+	RewardPool           = Account{ID: "platform:reward_pool", Kind: KindRewardPool, Currency: CurrencyPoints}
+	ConversionSinkPoints = Account{ID: "platform:conversion_sink:points", Kind: KindConversionSink, Currency: CurrencyPoints}
+	ConversionSinkYen    = Account{ID: "platform:conversion_sink:yen", Kind: KindConversionSink, Currency: CurrencyYen}
+	AdjustmentsPoints    = Account{ID: "platform:adjustments:points", Kind: KindAdjustments, Currency: CurrencyPoints}
+	AdjustmentsYen       = Account{ID: "platform:adjustments:yen", Kind: KindAdjustments, Currency: CurrencyYen}
+	ExpiredPoints        = Account{ID: "platform:expired_points", Kind: KindExpiredPoints, Currency: CurrencyPoints}
+	BalancePayments      = Account{ID: "platform:balance_payments:yen", Kind: KindBalancePayments, Currency: CurrencyYen}
+)
+
+// UserPoints is the account behind wallets.soda_points.
+func UserPoints(userID string) Account {
+	return Account{ID: "user:" + userID + ":points", OwnerID: userID, Kind: KindUserPoints, Currency: CurrencyPoints}
+}
+
+// UserBalance is the account behind wallets.soda_balance.
+func UserBalance(userID string) Account {
+	return Account{ID: "user:" + userID + ":balance", OwnerID: userID, Kind: KindUserBalance, Currency: CurrencyYen}
+}
+
+type Entry struct {
+	Account   Account
+	Direction string
+	Amount    int64
+}
+
+// Posting is one journal transaction and its entries.
+type Posting struct {
+	ID             string // Optional. Generated when empty.
+	UserID         string
+	Type           string
+	Amount         int64
+	RelatedOrderID string  // Optional.
+	PolicyVersion  int64   // Conversion policy applied; zero for other types.
+	RewardRuleIDs  []int64 // Reward rules that added to EARNED points, if any.
+	Entries        []Entry
+}
+
+// transfer moves amount from one account to another in their shared currency.
+func transfer(from, to Account, amount int64) []Entry {
+	return []Entry{
+		{Account: from, Direction: Debit, Amount: amount},
+		{Account: to, Direction: Credit, Amount: amount},
+	}
+}
+
+// Post records the transaction and its entries, then moves the cached wallet
+// of every user whose accounts were touched. It returns the wallet of
+// p.UserID. Post runs in the store's transaction, or in its own if there is
+// none, so a failure leaves neither the ledger nor the wallets changed.
+func (s *Store) Post(ctx context.Context, p Posting) (db.Wallet, error) {
+	return s.post(ctx, p, true)
+}
+
+// post implements Post. With project false only the journal is written and
+// the cached wallets are left alone.
+func (s *Store) post(ctx context.Context, p Posting, project bool) (db.Wallet, error) {
+	if err := checkBalanced(p.Entries); err != nil {
+		return db.Wallet{}, err
+	}
+
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		txID := p.ID
+		if txID == "" {
+			txID = uuid.NewString()
+		}
+		if _, err := s.q.CreateTransaction(ctx, db.CreateTransactionParams{
+			ID:             txID,
+			UserID:         p.UserID,
+			Type:           p.Type,
+			Amount:         p.Amount,
+			RelatedOrderID: pgtype.Text{String: p.RelatedOrderID, Valid: p.RelatedOrderID != ""},
+			PolicyVersion:  pgtype.Int8{Int64: p.PolicyVersion, Valid: p.PolicyVersion != 0},
+			RewardRuleIds:  p.RewardRuleIDs,
+		}); err != nil {
+			return fmt.Errorf("creating transaction: %w", err)
+		}
+
+		var owners []string
+		deltas := make(map[string]*db.ApplyWalletDeltaParams)
+		for _, e := range p.Entries {
+			if err := s.q.EnsureLedgerAccount(ctx, db.EnsureLedgerAccountParams{
+				ID:       e.Account.ID,
+				OwnerID:  pgtype.Text{String: e.Account.OwnerID, Valid: e.Account.OwnerID != ""},
+				Kind:     e.Account.Kind,
+				Currency: e.Account.Currency,
+			}); err != nil {
+				return fmt.Errorf("ensuring account %s: %w", e.Account.ID, err)
+			}
+
+			if err := s.q.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+				TransactionID: txID,
+				AccountID:     e.Account.ID,
+				Direction:     e.Direction,
+				Amount:        e.Amount,
+				Currency:      e.Account.Currency,
+			}); err != nil {
+				return fmt.Errorf("creating entry: %w", err)
+			}
+
+			if !project || e.Account.OwnerID == "" {
+				continue
+			}
+			d, ok := deltas[e.Account.OwnerID]
+			if !ok {
+				d = &db.ApplyWalletDeltaParams{UserID: e.Account.OwnerID}
+				deltas[e.Account.OwnerID] = d
+				owners = append(owners, e.Account.OwnerID)
+			}
+			signed := e.Amount
+			if e.Direction == Debit {
+				signed = -signed
+			}
+			switch e.Account.Kind {
+			case KindUserPoints:
+				d.PointsDelta += signed
+			case KindUserBalance:
+				d.BalanceDelta += signed
+			}
+		}
+
+		for _, owner := range owners {
+			updated, err := s.q.ApplyWalletDelta(ctx, *deltas[owner])
+			if err != nil {
+				return fmt.Errorf("updating wallet %s: %w", owner, err)
+			}
+			if owner == p.UserID {
+				w = updated
+			}
+		}
+
+		if w.UserID == "" {
+			got, err := s.GetOrCreateWallet(ctx, p.UserID)
+			if err != nil {
+				return err
+			}
+			w = got
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// checkBalanced reports ErrUnbalanced unless debits equal credits in every
+// currency and every amount is positive.
+func checkBalanced(entries []Entry) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: no entries", ErrUnbalanced)
+	}
+	net := make(map[string]int64)
+	for _, e := range entries {
+		if e.Amount <= 0 {
+			return fmt.Errorf("%w: non-positive amount %d on %s", ErrUnbalanced, e.Amount, e.Account.ID)
+		}
+		switch e.Direction {
+		case Credit:
+			net[e.Account.Currency] += e.Amount
+		case Debit:
+			net[e.Account.Currency] -= e.Amount
+		default:
+			return fmt.Errorf("%w: unknown direction %q", ErrUnbalanced, e.Direction)
+		}
+	}
+	for currency, n := range net {
+		if n != 0 {
+			return fmt.Errorf("%w: %s off by %d", ErrUnbalanced, currency, n)
+		}
+	}
+	return nil
+}
+
+// Earn credits reward points for an order from the reward pool. The points
+// form a lot that expires after the store's points TTL.
+func (s *Store) Earn(ctx context.Context, userID string, points int64, orderID string) (db.Wallet, error) {
+	return s.EarnWithRules(ctx, userID, points, orderID, nil)
+}
+
+// EarnWithRules is Earn for points that reward rules added to, recording the
+// ids of the rules on the EARNED transaction.
+func (s *Store) EarnWithRules(ctx context.Context, userID string, points int64, orderID string, ruleIDs []int64) (db.Wallet, error) {
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		txID := uuid.NewString()
+		var err error
+		w, err = s.Post(ctx, Posting{
+			ID:             txID,
+			UserID:         userID,
+			Type:           TxEarned,
+			Amount:         points,
+			RelatedOrderID: orderID,
+			RewardRuleIDs:  ruleIDs,
+			Entries:        transfer(RewardPool, UserPoints(userID), points),
+		})
+		if err != nil {
+			return err
+		}
+		return s.openLot(ctx, userID, txID, points)
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// Clawback returns reward points for an order to the reward pool. The user's
+// points may go negative. The points come out of the lots the order opened
+// before any others.
+func (s *Store) Clawback(ctx context.Context, userID string, points int64, orderID string) (db.Wallet, error) {
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		var err error
+		w, err = s.Post(ctx, Posting{
+			UserID:         userID,
+			Type:           TxClawback,
+			Amount:         points,
+			RelatedOrderID: orderID,
+			Entries:        transfer(UserPoints(userID), RewardPool, points),
+		})
+		if err != nil {
+			return err
+		}
+		return s.drawOrderLots(ctx, userID, orderID, points)
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// ClawbackBalance recovers reward points that were already converted. yen
+// leaves the user's balance and goes back to the sink, and the points it was
+// converted from go back from the sink to the reward pool.
+func (s *Store) ClawbackBalance(ctx context.Context, userID string, yen, points int64, orderID string) (db.Wallet, error) {
+	return s.Post(ctx, Posting{
+		UserID:         userID,
+		Type:           TxClawbackBalance,
+		Amount:         yen,
+		RelatedOrderID: orderID,
+		Entries: append(
+			transfer(UserBalance(userID), ConversionSinkYen, yen),
+			transfer(ConversionSinkPoints, RewardPool, points)...,
+		),
+	})
+}
+
+// Convert exchanges points for yen under the given conversion policy
+// version, spending the soonest-expiring lots first. It locks the wallet and
+// returns ErrInsufficientPoints if the user holds fewer than points.
+func (s *Store) Convert(ctx context.Context, userID string, points, yen, policyVersion int64) (db.Wallet, error) {
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		current, err := s.GetWalletForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if current.SodaPoints < points {
+			return ErrInsufficientPoints
+		}
+
+		w, err = s.Post(ctx, Posting{
+			UserID:        userID,
+			Type:          TxConverted,
+			Amount:        points,
+			PolicyVersion: policyVersion,
+			Entries: append(
+				transfer(UserPoints(userID), ConversionSinkPoints, points),
+				transfer(ConversionSinkYen, UserBalance(userID), yen)...,
+			),
+		})
+		if err != nil {
+			return err
+		}
+		return s.drawLots(ctx, userID, points)
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// Adjust corrects a wallet by the given signed amounts against the platform
+// adjustment accounts. Zero deltas are skipped. Points added this way do not
+// expire; points removed are drawn from the soonest-expiring lots.
+func (s *Store) Adjust(ctx context.Context, userID string, pointsDelta, balanceDelta int64) (db.Wallet, error) {
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		var err error
+		w, err = s.post(ctx, adjustment(userID, pointsDelta, balanceDelta), true)
+		if err != nil {
+			return err
+		}
+		if pointsDelta < 0 {
+			return s.drawLots(ctx, userID, -pointsDelta)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// RecordAdjustment writes an ADJUSTMENT to the ledger for a movement the
+// cached wallet already reflects, bringing the ledger in line with the wallet
+// without changing the wallet.
+func (s *Store) RecordAdjustment(ctx context.Context, userID string, pointsDelta, balanceDelta int64) error {
+	_, err := s.post(ctx, adjustment(userID, pointsDelta, balanceDelta), false)
+	return err
+}
+
+func adjustment(userID string, pointsDelta, balanceDelta int64) Posting {
+	var entries []Entry
+	if pointsDelta > 0 {
+		entries = append(entries, transfer(AdjustmentsPoints, UserPoints(userID), pointsDelta)...)
+	} else if pointsDelta < 0 {
+		entries = append(entries, transfer(UserPoints(userID), AdjustmentsPoints, -pointsDelta)...)
+	}
+	if balanceDelta > 0 {
+		entries = append(entries, transfer(AdjustmentsYen, UserBalance(userID), balanceDelta)...)
+	} else if balanceDelta < 0 {
+		entries = append(entries, transfer(UserBalance(userID), AdjustmentsYen, -balanceDelta)...)
+	}
+
+	return Posting{
+		UserID:  userID,
+		Type:    TxAdjustment,
+		Amount:  max(pointsDelta, -pointsDelta) + max(balanceDelta, -balanceDelta),
+		Entries: entries,
+	}
+}
+
+// ListLedgerEntries returns the entries posted with a transaction.
+func (s *Store) ListLedgerEntries(ctx context.Context, transactionID string) ([]db.LedgerEntry, error) {
+	entries, err := s.q.ListLedgerEntriesByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ListWalletDrift returns wallets whose cached totals differ from the ledger.
+func (s *Store) ListWalletDrift(ctx context.Context) ([]db.ListWalletDriftRow, error) {
+	rows, err := s.q.ListWalletDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing wallet drift: %w", err)
+	}
+	return rows, nil
+}
+
+// ListUnbalancedTransactions returns transactions whose entries do not net to
+// zero in some currency.
+func (s *Store) ListUnbalancedTransactions(ctx context.Context) ([]db.ListUnbalancedTransactionsRow, error) {
+	rows, err := s.q.ListUnbalancedTransactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing unbalanced transactions: %w", err)
+	}
+	return rows, nil
+}
+
+// LockLedger blocks postings until the store's transaction ends. It must be
+// called on a store bound with WithTx.
+func (s *Store) LockLedger(ctx context.Context) error {
+	if s.tx == nil {
+		return errors.New("locking ledger: no transaction")
+	}
+	if err := s.q.LockLedger(ctx); err != nil {
+		return fmt.Errorf("locking ledger: %w", err)
+	}
+	return nil
+}
+
+// RebuildWallets recomputes every cached wallet from the ledger and returns
+// how many wallets were rewritten. Postings are blocked while it runs.
+func (s *Store) RebuildWallets(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.inTx(ctx, func(s *Store) error {
+		if err := s.LockLedger(ctx); err != nil {
+			return err
+		}
+		var err error
+		if n, err = s.q.RebuildWallets(ctx); err != nil {
+			return fmt.Errorf("rebuilding wallets: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
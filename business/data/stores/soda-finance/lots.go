@@ -0,0 +1,166 @@
+package sodafinance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+// DefaultPointsTTL is how long earned points last unless SetPointsTTL says
+// otherwise.
+const DefaultPointsTTL = 365 * 24 * time.Hour
+
+// SetPointsTTL sets how long points earned through this store last. Zero
+// means they never expire. Call it before the store is shared.
+func (s *Store) SetPointsTTL(ttl time.Duration) {
+	s.pointsTTL = ttl
+}
+
+// openLot records points earned by transaction txID as a lot.
+func (s *Store) openLot(ctx context.Context, userID, txID string, points int64) error {
+	var expiresAt pgtype.Timestamptz
+	if s.pointsTTL > 0 {
+		expiresAt = pgtype.Timestamptz{Time: time.Now().Add(s.pointsTTL), Valid: true}
+	}
+	if err := s.q.CreatePointLot(ctx, db.CreatePointLotParams{
+		UserID:        userID,
+		TransactionID: txID,
+		Amount:        points,
+		ExpiresAt:     expiresAt,
+	}); err != nil {
+		return fmt.Errorf("creating point lot: %w", err)
+	}
+	return nil
+}
+
+// drawLots takes points out of the user's open lots, soonest expiry first.
+// Points beyond what the lots hold came from outside any lot and are simply
+// not tracked. It must run after the wallet row is locked.
+func (s *Store) drawLots(ctx context.Context, userID string, points int64) error {
+	lots, err := s.q.ListOpenPointLotsForUpdate(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("locking point lots: %w", err)
+	}
+	_, err = s.takeFromLots(ctx, lots, points)
+	return err
+}
+
+// drawOrderLots takes points out of the lots the order opened for the user
+// first, so reversing one order does not eat into points earned by another,
+// then falls back to drawLots for the rest. It must run after the wallet row
+// is locked.
+func (s *Store) drawOrderLots(ctx context.Context, userID, orderID string, points int64) error {
+	lots, err := s.q.ListOpenOrderPointLotsForUpdate(ctx, db.ListOpenOrderPointLotsForUpdateParams{
+		UserID:         userID,
+		RelatedOrderID: pgtype.Text{String: orderID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("locking order point lots: %w", err)
+	}
+	points, err = s.takeFromLots(ctx, lots, points)
+	if err != nil || points <= 0 {
+		return err
+	}
+	return s.drawLots(ctx, userID, points)
+}
+
+// takeFromLots draws up to points from the locked lots in order and returns
+// what they could not cover.
+func (s *Store) takeFromLots(ctx context.Context, lots []db.PointLot, points int64) (int64, error) {
+	for _, lot := range lots {
+		if points <= 0 {
+			break
+		}
+		take := min(points, lot.Remaining)
+		if err := s.q.SetPointLotRemaining(ctx, db.SetPointLotRemainingParams{
+			ID:        lot.ID,
+			Remaining: lot.Remaining - take,
+		}); err != nil {
+			return 0, fmt.Errorf("drawing point lot %d: %w", lot.ID, err)
+		}
+		points -= take
+	}
+	return points, nil
+}
+
+// ListDuePointLots returns up to limit IDs of lots that reached their expiry
+// by dueAt and still hold points.
+func (s *Store) ListDuePointLots(ctx context.Context, dueAt time.Time, limit int32) ([]int64, error) {
+	ids, err := s.q.ListDuePointLots(ctx, db.ListDuePointLotsParams{
+		DueAt:    pgtype.Timestamptz{Time: dueAt, Valid: true},
+		RowLimit: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing due point lots: %w", err)
+	}
+	return ids, nil
+}
+
+// ExpireLot writes off what is left of a due lot with an EXPIRED transaction
+// and returns the points expired. Lots that are no longer due or already
+// empty are skipped, so concurrent sweeps are harmless.
+func (s *Store) ExpireLot(ctx context.Context, lotID int64) (int64, error) {
+	var expired int64
+	err := s.inTx(ctx, func(s *Store) error {
+		lot, err := s.q.GetPointLot(ctx, lotID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("querying point lot: %w", err)
+		}
+
+		// Wallet before lot, the same order spending takes the locks in.
+		w, err := s.GetWalletForUpdate(ctx, lot.UserID)
+		if err != nil {
+			return err
+		}
+		lot, err = s.q.GetPointLotForUpdate(ctx, lotID)
+		if err != nil {
+			return fmt.Errorf("locking point lot: %w", err)
+		}
+		if lot.Remaining == 0 || !lot.ExpiresAt.Valid || lot.ExpiresAt.Time.After(time.Now()) {
+			return nil
+		}
+
+		// The wallet can hold less than the lot after manual corrections;
+		// never expire more than is there.
+		expired = min(lot.Remaining, max(w.SodaPoints, 0))
+		if expired > 0 {
+			if _, err := s.Post(ctx, Posting{
+				UserID:  lot.UserID,
+				Type:    TxExpired,
+				Amount:  expired,
+				Entries: transfer(UserPoints(lot.UserID), ExpiredPoints, expired),
+			}); err != nil {
+				return err
+			}
+		}
+		if err := s.q.ExpirePointLot(ctx, lotID); err != nil {
+			return fmt.Errorf("expiring point lot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+// ListExpiringPointLots returns the user's lots that still hold points and
+// expire before the given time, soonest first.
+func (s *Store) ListExpiringPointLots(ctx context.Context, userID string, before time.Time) ([]db.PointLot, error) {
+	lots, err := s.q.ListExpiringPointLots(ctx, db.ListExpiringPointLotsParams{
+		UserID: userID,
+		Before: pgtype.Timestamptz{Time: before, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing expiring point lots: %w", err)
+	}
+	return lots, nil
+}
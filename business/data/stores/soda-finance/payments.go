@@ -0,0 +1,53 @@
+package sodafinance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"soda-interview/business/data/stores/db"
+)
+
+// Spend pays yen towards an order out of the user's soda_balance. The wallet
+// is debited with a conditional update, so concurrent spends cannot take the
+// balance below zero; ErrInsufficientBalance is returned instead.
+func (s *Store) Spend(ctx context.Context, userID string, yen int64, orderID string) (db.Wallet, error) {
+	var w db.Wallet
+	err := s.inTx(ctx, func(s *Store) error {
+		var err error
+		w, err = s.q.DebitWalletBalance(ctx, db.DebitWalletBalanceParams{UserID: userID, Amount: yen})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInsufficientBalance
+			}
+			return fmt.Errorf("debiting balance: %w", err)
+		}
+
+		// The wallet already moved; record the journal without projecting it
+		// again.
+		_, err = s.post(ctx, Posting{
+			UserID:         userID,
+			Type:           TxSpent,
+			Amount:         yen,
+			RelatedOrderID: orderID,
+			Entries:        transfer(UserBalance(userID), BalancePayments, yen),
+		}, false)
+		return err
+	})
+	if err != nil {
+		return db.Wallet{}, err
+	}
+	return w, nil
+}
+
+// RefundSpend returns yen spent on an order to the user's soda_balance.
+func (s *Store) RefundSpend(ctx context.Context, userID string, yen int64, orderID string) (db.Wallet, error) {
+	return s.Post(ctx, Posting{
+		UserID:         userID,
+		Type:           TxSpentRefund,
+		Amount:         yen,
+		RelatedOrderID: orderID,
+		Entries:        transfer(BalancePayments, UserBalance(userID), yen),
+	})
+}
@@ -0,0 +1,58 @@
+package sodafinance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"soda-interview/business/data/stores/db"
+)
+
+// ErrNoPolicy is returned when no conversion policy is in force yet.
+var ErrNoPolicy = errors.New("no conversion policy in force")
+
+// GetActiveConversionPolicy returns the policy in force now.
+func (s *Store) GetActiveConversionPolicy(ctx context.Context) (db.ConversionPolicy, error) {
+	p, err := s.q.GetActiveConversionPolicy(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ConversionPolicy{}, ErrNoPolicy
+		}
+		return db.ConversionPolicy{}, fmt.Errorf("querying conversion policy: %w", err)
+	}
+	return p, nil
+}
+
+// CreateConversionPolicy schedules a new policy version.
+func (s *Store) CreateConversionPolicy(ctx context.Context, params db.CreateConversionPolicyParams) (db.ConversionPolicy, error) {
+	p, err := s.q.CreateConversionPolicy(ctx, params)
+	if err != nil {
+		return db.ConversionPolicy{}, fmt.Errorf("creating conversion policy: %w", err)
+	}
+	return p, nil
+}
+
+// ListConversionPolicies returns every policy version, latest effective first.
+func (s *Store) ListConversionPolicies(ctx context.Context) ([]db.ConversionPolicy, error) {
+	ps, err := s.q.ListConversionPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing conversion policies: %w", err)
+	}
+	return ps, nil
+}
+
+// SumConvertedPointsSince returns how many points the user has converted
+// since the given time.
+func (s *Store) SumConvertedPointsSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	n, err := s.q.SumConvertedPointsSince(ctx, db.SumConvertedPointsSinceParams{
+		UserID: userID,
+		Since:  pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("summing converted points: %w", err)
+	}
+	return n, nil
+}
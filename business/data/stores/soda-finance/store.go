@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"soda-interview/business/data/stores/db"
 	"soda-interview/foundation/logger"
@@ -14,25 +16,85 @@ import (
 var (
 	ErrNotFound = errors.New("wallet not found")
 	ErrInsufficientPoints = errors.New("insufficient points")
+	// ErrInsufficientBalance is returned when soda_balance cannot cover a
+	// payment.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+// Transaction types recorded in the transactions table. Amounts are always
+// positive; the type determines which wallet column moved and in which
+// direction. The ledger entries posted with a transaction are authoritative.
+const (
+	// TxEarned credits soda_points (order rewards).
+	TxEarned = "EARNED"
+	// TxConverted debits soda_points and credits soda_balance at the rate of
+	// the conversion policy named by policy_version.
+	TxConverted = "CONVERTED"
+	// TxClawback debits soda_points when an order's rewards are reversed.
+	TxClawback = "CLAWBACK"
+	// TxClawbackBalance debits soda_balance (yen) when reversed rewards had
+	// already been converted.
+	TxClawbackBalance = "CLAWBACK_BALANCE"
+	// TxAdjustment is a manual correction of soda_points and/or soda_balance.
+	TxAdjustment = "ADJUSTMENT"
+	// TxOpeningBalance carries wallet totals that predate the ledger.
+	TxOpeningBalance = "OPENING_BALANCE"
+	// TxExpired debits soda_points left in a lot that reached its expiry.
+	TxExpired = "EXPIRED"
+	// TxSpent debits soda_balance (yen) paid towards an order.
+	TxSpent = "SPENT"
+	// TxSpentRefund credits back soda_balance spent on a cancelled or
+	// refunded order.
+	TxSpentRefund = "SPENT_REFUND"
 )
 
 type Store struct {
-	log *logger.Logger
-	q   *db.Queries
+	log       *logger.Logger
+	pool      *pgxpool.Pool
+	tx        pgx.Tx
+	q         *db.Queries
+	pointsTTL time.Duration
 }
 
 func NewStore(log *logger.Logger, pool *pgxpool.Pool) *Store {
 	return &Store{
-		log: log,
-		q:   db.New(pool),
+		log:       log,
+		pool:      pool,
+		q:         db.New(pool),
+		pointsTTL: DefaultPointsTTL,
 	}
 }
 
 func (s *Store) WithTx(tx pgx.Tx) *Store {
 	return &Store{
-		log: s.log,
-		q:   s.q.WithTx(tx),
+		log:       s.log,
+		pool:      s.pool,
+		tx:        tx,
+		q:         s.q.WithTx(tx),
+		pointsTTL: s.pointsTTL,
+	}
+}
+
+// inTx runs fn on a store bound to a transaction. It reuses the caller's
+// transaction when the store already has one.
+func (s *Store) inTx(ctx context.Context, fn func(*Store) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(s.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
 }
 
 func (s *Store) GetWallet(ctx context.Context, userID string) (db.Wallet, error) {
@@ -46,49 +108,45 @@ func (s *Store) GetWallet(ctx context.Context, userID string) (db.Wallet, error)
 	return w, nil
 }
 
-func (s *Store) GetOrCreateWallet(ctx context.Context, userID string) (db.Wallet, error) {
-	w, err := s.q.CreateWallet(ctx, userID)
+// GetWalletForUpdate loads the wallet and locks its row until the surrounding
+// transaction ends.
+func (s *Store) GetWalletForUpdate(ctx context.Context, userID string) (db.Wallet, error) {
+	w, err := s.q.GetWalletForUpdate(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			// Wallet already exists, retrieve it
-			return s.q.GetWallet(ctx, userID)
+			return db.Wallet{}, ErrNotFound
 		}
-		return db.Wallet{}, fmt.Errorf("creating wallet: %w", err)
+		return db.Wallet{}, fmt.Errorf("locking wallet: %w", err)
 	}
 	return w, nil
 }
 
-func (s *Store) AddPoints(ctx context.Context, params db.AddPointsParams) (db.Wallet, error) {
-	w, err := s.q.AddPoints(ctx, params)
+func (s *Store) GetOrCreateWallet(ctx context.Context, userID string) (db.Wallet, error) {
+	w, err := s.q.CreateWallet(ctx, userID)
 	if err != nil {
-		return db.Wallet{}, fmt.Errorf("adding points: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Wallet already exists, retrieve it
+			return s.q.GetWallet(ctx, userID)
+		}
+		return db.Wallet{}, fmt.Errorf("creating wallet: %w", err)
 	}
 	return w, nil
 }
 
-func (s *Store) AddBalance(ctx context.Context, params db.AddBalanceParams) (db.Wallet, error) {
-	w, err := s.q.AddBalance(ctx, params)
+func (s *Store) ListTransactionsByOrder(ctx context.Context, orderID string) ([]db.Transaction, error) {
+	txs, err := s.q.ListTransactionsByOrder(ctx, pgtype.Text{String: orderID, Valid: true})
 	if err != nil {
-		return db.Wallet{}, fmt.Errorf("adding balance: %w", err)
+		return nil, fmt.Errorf("listing transactions by order: %w", err)
 	}
-	return w, nil
+	return txs, nil
 }
 
-func (s *Store) ConvertPointsToBalance(ctx context.Context, params db.ConvertPointsToBalanceParams) (db.Wallet, error) {
-	w, err := s.q.ConvertPointsToBalance(ctx, params)
+// ListTransactionsByUser returns a user's transactions newest first, one page
+// at a time. See the ListTransactionsByUser query for the filter semantics.
+func (s *Store) ListTransactionsByUser(ctx context.Context, params db.ListTransactionsByUserParams) ([]db.Transaction, error) {
+	txs, err := s.q.ListTransactionsByUser(ctx, params)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return db.Wallet{}, ErrInsufficientPoints
-		}
-		return db.Wallet{}, fmt.Errorf("converting points: %w", err)
+		return nil, fmt.Errorf("listing transactions by user: %w", err)
 	}
-	return w, nil
+	return txs, nil
 }
-
-func (s *Store) CreateTransaction(ctx context.Context, params db.CreateTransactionParams) (db.Transaction, error) {
-	t, err := s.q.CreateTransaction(ctx, params)
-	if err != nil {
-		return db.Transaction{}, fmt.Errorf("creating transaction: %w", err)
-	}
-	return t, nil
-}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package sodafinance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"soda-interview/foundation/logger"
+)
+
+// WalletChannel is the notification channel a trigger on wallets notifies,
+// with the user ID as payload, whenever a wallet change commits.
+const WalletChannel = "wallet_changed"
+
+// maxListenBackoff caps the delay between attempts to reconnect the listener.
+const maxListenBackoff = 30 * time.Second
+
+// WalletWatcher fans wallet change notifications out to subscribers. It
+// holds one LISTEN connection per replica, so changes committed by any
+// replica reach the watchers on every one.
+type WalletWatcher struct {
+	log  *logger.Logger
+	pool *pgxpool.Pool
+
+	mu     sync.Mutex
+	subs   map[string]map[chan struct{}]struct{}
+	closed bool
+}
+
+func NewWalletWatcher(log *logger.Logger, pool *pgxpool.Pool) *WalletWatcher {
+	return &WalletWatcher{
+		log:  log,
+		pool: pool,
+		subs: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives a value after the user's wallet
+// changes, and a function to unsubscribe. Changes arriving faster than they
+// are received collapse into one, so subscribers should re-read the wallet
+// rather than count signals. The channel is closed when the watcher stops.
+func (w *WalletWatcher) Subscribe(userID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if w.subs[userID] == nil {
+		w.subs[userID] = make(map[chan struct{}]struct{})
+	}
+	w.subs[userID][ch] = struct{}{}
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if _, ok := w.subs[userID][ch]; !ok {
+			return
+		}
+		delete(w.subs[userID], ch)
+		if len(w.subs[userID]) == 0 {
+			delete(w.subs, userID)
+		}
+		close(ch)
+	}
+}
+
+// Run listens for wallet changes until ctx is done, reconnecting when the
+// connection is lost. It closes every subscription on return.
+func (w *WalletWatcher) Run(ctx context.Context) {
+	defer w.close()
+
+	backoff := time.Second
+	for {
+		connected, err := w.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = time.Second
+		}
+		w.log.Error("Wallet watcher lost its connection, reconnecting", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+// listen holds a LISTEN connection until it fails or ctx is done. It reports
+// whether it got as far as listening.
+func (w *WalletWatcher) listen(ctx context.Context) (bool, error) {
+	conn, err := w.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquiring connection: %w", err)
+	}
+	// A listening connection must not be handed to anyone else, so it leaves
+	// the pool for good.
+	pgConn := conn.Hijack()
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = pgConn.Close(closeCtx)
+	}()
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+WalletChannel); err != nil {
+		return false, fmt.Errorf("listening on %s: %w", WalletChannel, err)
+	}
+
+	// Changes committed while not listening were missed; have every
+	// subscriber re-read its wallet.
+	w.signalAll()
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("waiting for notification: %w", err)
+		}
+		w.signal(n.Payload)
+	}
+}
+
+func (w *WalletWatcher) signal(userID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs[userID] {
+		notify(ch)
+	}
+}
+
+func (w *WalletWatcher) signalAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, chans := range w.subs {
+		for ch := range chans {
+			notify(ch)
+		}
+	}
+}
+
+func (w *WalletWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for userID, chans := range w.subs {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(w.subs, userID)
+	}
+}
+
+// notify signals ch without blocking; a pending signal already covers this
+// one.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,88 @@
+// Package auth verifies bearer tokens and carries the authenticated caller
+// through the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// RoleAdmin may manage the catalog and act on behalf of any user.
+const RoleAdmin = "admin"
+
+var (
+	// ErrUnauthenticated is returned when a request carries no valid token.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied is returned when the caller may not perform the
+	// request, e.g. reading another user's wallet.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
+
+// UserID resolves the user a request acts on. An empty requested ID means the
+// caller. Asking for another user is only allowed for admins.
+//
+// When the context has no principal, authentication is disabled (see
+// bootstrap) and the requested ID is trusted as is.
+func UserID(ctx context.Context, requested string) (string, error) {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return requested, nil
+	}
+	if requested == "" || requested == p.Subject {
+		return p.Subject, nil
+	}
+	if p.IsAdmin() {
+		return requested, nil
+	}
+	return "", ErrPermissionDenied
+}
+
+// RequireAdmin fails unless the caller is an admin. Like UserID, it allows
+// everything when authentication is disabled.
+func RequireAdmin(ctx context.Context) error {
+	p, ok := FromContext(ctx)
+	if !ok || p.IsAdmin() {
+		return nil
+	}
+	return ErrPermissionDenied
+}
+
+// Anonymous is recorded as the actor when authentication is disabled.
+const Anonymous = "anonymous"
+
+// Actor returns who to record as having made the request: the caller, or
+// Anonymous when authentication is disabled and there is no caller.
+func Actor(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return Anonymous
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// KeySet holds the verification keys loaded from a JWKS document. RSA keys
+// verify RS256 tokens and symmetric ("oct") keys verify HS256 tokens.
+type KeySet struct {
+	rsa  map[string]*rsa.PublicKey
+	hmac map[string][]byte
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// LoadKeySet reads a JWKS file from disk.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading jwks: %w", err)
+	}
+	return ParseKeySet(data)
+}
+
+// ParseKeySet parses a JWKS document. Keys meant for anything other than
+// signatures are skipped.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding jwks: %w", err)
+	}
+
+	ks := &KeySet{
+		rsa:  make(map[string]*rsa.PublicKey),
+		hmac: make(map[string][]byte),
+	}
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			if k.Alg != "" && k.Alg != algRS256 {
+				continue
+			}
+			pub, err := parseRSAKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("key %d (%s): %w", i, k.Kid, err)
+			}
+			ks.rsa[k.Kid] = pub
+		case "oct":
+			if k.Alg != "" && k.Alg != algHS256 {
+				continue
+			}
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("key %d (%s): invalid k", i, k.Kid)
+			}
+			ks.hmac[k.Kid] = secret
+		}
+	}
+
+	if len(ks.rsa) == 0 && len(ks.hmac) == 0 {
+		return nil, fmt.Errorf("jwks has no usable signing keys")
+	}
+	return ks, nil
+}
+
+func parseRSAKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return nil, fmt.Errorf("invalid n")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("invalid e")
+	}
+
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	if pub.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("rsa key must be at least 2048 bits")
+	}
+	return pub, nil
+}
+
+// lookup returns the key named by kid, or the only key when the token names
+// none.
+func lookup[K any](keys map[string]K, kid string) (K, bool) {
+	if k, ok := keys[kid]; ok {
+		return k, true
+	}
+	var zero K
+	if kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			return k, true
+		}
+	}
+	return zero, false
+}
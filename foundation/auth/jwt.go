@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+)
+
+// Verifier checks JWT signatures and standard claims.
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	skew     time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier returns a verifier using keys. Empty issuer or audience are not
+// checked; skew is the clock leeway allowed on exp and nbf.
+func NewVerifier(keys *KeySet, issuer, audience string, skew time.Duration) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		skew:     skew,
+		now:      time.Now,
+	}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	Roles     []string `json:"roles"`
+}
+
+// audience accepts both the string and the array form of the aud claim.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Verify validates a compact JWT and returns its principal. Every failure
+// wraps ErrUnauthenticated.
+func (v *Verifier) Verify(token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Principal{}, fmt.Errorf("%w: header: %v", ErrUnauthenticated, err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: signature encoding", ErrUnauthenticated)
+	}
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], sig); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Principal{}, fmt.Errorf("%w: claims: %v", ErrUnauthenticated, err)
+	}
+	if err := v.checkClaims(c); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	return Principal{Subject: c.Subject, Roles: c.Roles}, nil
+}
+
+// verifySignature only accepts the algorithms a key was loaded for, so a
+// token cannot pick "none" or sign with an RSA public key as an HMAC secret.
+func (v *Verifier) verifySignature(h header, signed string, sig []byte) error {
+	switch h.Alg {
+	case algHS256:
+		secret, ok := lookup(v.keys.hmac, h.Kid)
+		if !ok {
+			return fmt.Errorf("unknown key %q", h.Kid)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+
+	case algRS256:
+		pub, ok := lookup(v.keys.rsa, h.Kid)
+		if !ok {
+			return fmt.Errorf("unknown key %q", h.Kid)
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported alg %q", h.Alg)
+}
+
+func (v *Verifier) checkClaims(c claims) error {
+	if c.Subject == "" {
+		return fmt.Errorf("missing sub")
+	}
+
+	now := v.now()
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("missing exp")
+	}
+	if now.After(time.Unix(*c.ExpiresAt, 0).Add(v.skew)) {
+		return fmt.Errorf("token expired")
+	}
+	if c.NotBefore != nil && now.Add(v.skew).Before(time.Unix(*c.NotBefore, 0)) {
+		return fmt.Errorf("token not yet valid")
+	}
+
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("unexpected iss %q", c.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(c.Audience, v.audience) {
+		return fmt.Errorf("token not issued for %q", v.audience)
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,93 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/foundation/auth"
+	"soda-interview/foundation/logger"
+)
+
+// publicServices need no token: probes and tooling must work without one.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+func isPublic(method string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate verifies the bearer token in the request metadata and returns
+// a context carrying its principal.
+func authenticate(ctx context.Context, log *logger.Logger, v *auth.Verifier, method string) (context.Context, error) {
+	if isPublic(method) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "malformed authorization header")
+	}
+
+	p, err := v.Verify(strings.TrimSpace(token))
+	if err != nil {
+		if !errors.Is(err, auth.ErrUnauthenticated) {
+			return nil, err
+		}
+		// The reason stays in the logs; callers only learn the token was rejected.
+		log.InfoContext(ctx, "token rejected", "method", method, "error", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	return auth.NewContext(ctx, p), nil
+}
+
+func authUnaryInterceptor(log *logger.Logger, v *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, log, v, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(log *logger.Logger, v *auth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), log, v, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &ctxStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ctxStream hands stream handlers a context of the interceptor's making,
+// e.g. one carrying the authenticated principal.
+type ctxStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *ctxStream) Context() context.Context {
+	return s.ctx
+}
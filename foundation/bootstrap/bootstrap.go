@@ -4,28 +4,43 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"soda-interview/foundation/auth"
 	"soda-interview/foundation/config"
 	"soda-interview/foundation/database/postgres"
 	"soda-interview/foundation/logger"
+	"soda-interview/foundation/metrics"
+	"soda-interview/foundation/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type RegisterFn func(log *logger.Logger, db *pgxpool.Pool, grpcServer *grpc.Server)
+// RegisterFn registers the service's handlers and returns the background
+// workers to run alongside the server.
+type RegisterFn func(log *logger.Logger, cfg *config.Config, db *pgxpool.Pool, grpcServer *grpc.Server) []Worker
+
+// Worker is a background job. Run blocks until ctx is cancelled at shutdown.
+type Worker interface {
+	Run(ctx context.Context)
+}
 
 // Run initializes the system infrastructure and starts the gRPC server.
 // It delegates the specific service registration to the register callback.
-func Run(register RegisterFn) {
+// Additional server options (e.g. service specific interceptors) are applied
+// after the built-in ones, so their interceptors run closer to the handlers.
+func Run(register RegisterFn, opts ...grpc.ServerOption) {
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
@@ -56,7 +71,55 @@ func Run(register RegisterFn) {
 		os.Exit(1)
 	}
 
-	var serverOpts []grpc.ServerOption
+	unary := []grpc.UnaryServerInterceptor{errorUnaryInterceptor(log)}
+	// Closed at shutdown to end open streams; see drainStreamInterceptor.
+	draining := make(chan struct{})
+	stream := []grpc.StreamServerInterceptor{errorStreamInterceptor(log), drainStreamInterceptor(draining)}
+
+	if cfg.Server.Timeout.Request > 0 {
+		unary = append(unary, timeoutUnaryInterceptor(cfg.Server.Timeout.Request))
+	}
+
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		registerPoolMetrics(metrics.Default, dbPool)
+		unary = append([]grpc.UnaryServerInterceptor{metricsUnaryInterceptor()}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{metricsStreamInterceptor()}, stream...)
+		metricsServer = startMetricsServer(log, cfg.GetMetricsAddress(), cfg.Metrics.Path)
+	}
+
+	if cfg.Auth.Enabled {
+		keys, err := auth.LoadKeySet(cfg.Auth.JWKSFile)
+		if err != nil {
+			log.Error("Failed to load JWKS", "path", cfg.Auth.JWKSFile, "error", err)
+			os.Exit(1)
+		}
+		verifier := auth.NewVerifier(keys, cfg.Auth.Issuer, cfg.Auth.Audience, cfg.Auth.ClockSkew)
+		unary = append(unary, authUnaryInterceptor(log, verifier))
+		stream = append(stream, authStreamInterceptor(log, verifier))
+		log.Info("Authentication enabled", "jwks", cfg.Auth.JWKSFile)
+	} else {
+		log.Warn("Authentication disabled: user IDs in requests are trusted")
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	serverOpts = append(serverOpts, transportOptions(cfg.Server.GRPC)...)
+
+	var tracer *sdktrace.TracerProvider
+	if cfg.Tracing.Enabled {
+		tracer, err = newTracer(context.Background(), log, cfg)
+		if err != nil {
+			log.Error("Failed to set up tracing", "error", err)
+			os.Exit(1)
+		}
+		tracing.SetDefault(tracer)
+		serverOpts = append(serverOpts, grpc.StatsHandler(tracingStatsHandler{tracer: tracing.Tracer()}))
+		log.Info("Tracing enabled", "provider", cfg.Tracing.Provider, "sample_rate", cfg.Tracing.SampleRate)
+	}
+	serverOpts = append(serverOpts, opts...)
 	gRPCServer := grpc.NewServer(serverOpts...)
 
 	// Register Health Service
@@ -65,7 +128,7 @@ func Run(register RegisterFn) {
 	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
 	log.Info("gRPC health service registered")
 
-	register(log, dbPool, gRPCServer)
+	workers := register(log, cfg, dbPool, gRPCServer)
 
 	if !cfg.IsProduction() {
 		reflection.Register(gRPCServer)
@@ -80,15 +143,51 @@ func Run(register RegisterFn) {
 		}
 	}()
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workersDone sync.WaitGroup
+	for _, w := range workers {
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			w.Run(workerCtx)
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info("Shutting down server...")
 
-	_, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownTimeout := cfg.Server.Timeout.Shutdown
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
 
-	gRPCServer.GracefulStop()
+	close(draining)
+	if !stopServer(shutdownCtx, gRPCServer) {
+		log.Warn("Graceful shutdown timed out, in-flight RPCs were cancelled", "timeout", shutdownTimeout)
+	}
+	stopWorkers()
+	if !waitDone(shutdownCtx, &workersDone) {
+		log.Warn("Background workers did not stop in time", "timeout", shutdownTimeout)
+	}
+	// Flushing gets its own budget so a forced stop does not lose the spans
+	// and metrics of the RPCs it cut off.
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(flushCtx); err != nil {
+			log.Error("Failed to stop metrics server", "error", err)
+		}
+	}
+	if tracer != nil {
+		if err := tracer.Shutdown(flushCtx); err != nil {
+			log.Error("Failed to flush traces", "error", err)
+		}
+	}
 	log.Info("Server stopped")
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"soda-interview/foundation/logger"
+)
+
+// HTTPWorker serves an HTTP handler alongside the gRPC server, such as a
+// webhook receiver. It stops accepting requests when the workers are stopped
+// and gives in-flight ones a few seconds to finish.
+type HTTPWorker struct {
+	log     *logger.Logger
+	name    string
+	addr    string
+	handler http.Handler
+}
+
+func NewHTTPWorker(log *logger.Logger, name, addr string, handler http.Handler) *HTTPWorker {
+	return &HTTPWorker{
+		log:     log,
+		name:    name,
+		addr:    addr,
+		handler: handler,
+	}
+}
+
+// Run serves until ctx is done.
+func (w *HTTPWorker) Run(ctx context.Context) {
+	srv := &http.Server{
+		Addr:              w.addr,
+		Handler:           w.handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	failed := make(chan struct{})
+	go func() {
+		w.log.Info("HTTP server starting", "server", w.name, "address", w.addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			w.log.Error("HTTP server failed", "server", w.name, "error", err)
+			close(failed)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-failed:
+		return
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		w.log.Error("Failed to stop HTTP server", "server", w.name, "error", err)
+	}
+}
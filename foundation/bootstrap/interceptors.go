@@ -0,0 +1,45 @@
+package bootstrap
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/foundation/logger"
+)
+
+// errorUnaryInterceptor is the outermost interceptor. Any error that reaches it
+// without a gRPC status is unexpected: it is logged in full and replaced with a
+// generic Internal status so that driver or SQL details never leak to clients.
+func errorUnaryInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, sanitize(ctx, log, info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+func errorStreamInterceptor(log *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return sanitize(ss.Context(), log, info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+func sanitize(ctx context.Context, log *logger.Logger, method string, err error) error {
+	if st, ok := status.FromError(err); ok {
+		if st.Code() == codes.Internal || st.Code() == codes.Unknown {
+			log.ErrorContext(ctx, "request failed", "method", method, "code", st.Code().String(), "error", err)
+		}
+		return err
+	}
+
+	log.ErrorContext(ctx, "request failed", "method", method, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
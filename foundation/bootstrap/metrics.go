@@ -0,0 +1,91 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/foundation/logger"
+	"soda-interview/foundation/metrics"
+)
+
+var (
+	grpcHandled = metrics.NewCounter("grpc_server_handled_total",
+		"RPCs completed on the server, by method and status code.", "grpc_method", "grpc_code")
+	grpcHandlingSeconds = metrics.NewHistogram("grpc_server_handling_seconds",
+		"Time taken to complete RPCs on the server.", nil, "grpc_method")
+)
+
+// metricsUnaryInterceptor is installed outside every other interceptor so it
+// records the status code the client actually receives.
+func metricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func metricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeRPC(method string, start time.Time, err error) {
+	grpcHandled.Inc(method, status.Code(err).String())
+	grpcHandlingSeconds.Observe(time.Since(start).Seconds(), method)
+}
+
+// registerPoolMetrics exposes the pool statistics, read on each scrape.
+func registerPoolMetrics(r *metrics.Registry, pool *pgxpool.Pool) {
+	stat := func(fn func(*pgxpool.Stat) float64) func() float64 {
+		return func() float64 { return fn(pool.Stat()) }
+	}
+
+	r.NewGaugeFunc("db_pool_acquired_conns", "Connections currently checked out of the pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }))
+	r.NewGaugeFunc("db_pool_idle_conns", "Idle connections in the pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }))
+	r.NewGaugeFunc("db_pool_total_conns", "Open connections in the pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }))
+	r.NewGaugeFunc("db_pool_max_conns", "Maximum size of the pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }))
+	r.NewCounterFunc("db_pool_acquires_total", "Successful connection acquisitions.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }))
+	r.NewCounterFunc("db_pool_empty_acquires_total", "Acquisitions that had to wait for a connection.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }))
+	r.NewCounterFunc("db_pool_acquire_wait_seconds_total", "Total time spent waiting to acquire connections.",
+		stat(func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }))
+}
+
+// startMetricsServer serves the Default registry on addr until Shutdown is
+// called on the returned server.
+func startMetricsServer(log *logger.Logger, addr, path string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(path, metrics.Handler())
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Info("Metrics server starting", "address", addr, "path", path)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	return srv
+}
@@ -0,0 +1,120 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/foundation/config"
+)
+
+// defaultShutdownTimeout applies when server.timeout.shutdown is not set.
+const defaultShutdownTimeout = 30 * time.Second
+
+// transportOptions translates the gRPC server config into server options.
+func transportOptions(cfg config.GRPCConfig) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     cfg.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.MaxConnectionAgeGrace,
+			Time:                  cfg.KeepAliveTime,
+			Timeout:               cfg.KeepAliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepAliveMinTime,
+			PermitWithoutStream: cfg.PermitWithoutStream,
+		}),
+	}
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	return opts
+}
+
+// timeoutUnaryInterceptor bounds every unary RPC by timeout, keeping the
+// client's deadline when it is sooner. Streams are left alone since they are
+// expected to stay open.
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// drainStreamInterceptor cancels the context of open streams once draining
+// is closed. Streams such as wallet watches never end on their own, so
+// without it GracefulStop would wait for them until the shutdown timeout.
+// A stream cut off this way ends with UNAVAILABLE, which tells clients to
+// reconnect, presumably to another replica.
+func drainStreamInterceptor(draining <-chan struct{}) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-draining:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		err := handler(srv, &ctxStream{ServerStream: ss, ctx: ctx})
+		select {
+		case <-draining:
+			if err == nil || errors.Is(err, context.Canceled) {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+		default:
+		}
+		return err
+	}
+}
+
+// stopServer drains in-flight RPCs and forces the remaining ones closed if
+// that takes longer than ctx allows. It reports whether the drain finished.
+func stopServer(ctx context.Context, s *grpc.Server) bool {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-ctx.Done():
+		s.Stop()
+		<-stopped
+		return false
+	}
+}
+
+// waitDone waits for wg until ctx is done. It reports whether wg finished.
+func waitDone(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
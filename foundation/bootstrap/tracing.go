@@ -0,0 +1,103 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"soda-interview/foundation/config"
+	"soda-interview/foundation/logger"
+	"soda-interview/foundation/tracing"
+)
+
+// newTracer builds the tracer provider described by the tracing config. Export
+// failures are logged rather than failing requests.
+func newTracer(ctx context.Context, log *logger.Logger, cfg *config.Config) (*sdktrace.TracerProvider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Tracing.Provider {
+	case "otlp":
+		exporter, err = tracing.NewOTLPExporter(ctx, cfg.Tracing.Endpoint)
+	case "stdout":
+		exporter, err = tracing.NewWriterExporter(os.Stdout)
+	case "file":
+		exporter, err = tracing.NewFileExporter(cfg.Tracing.Endpoint)
+	default:
+		return nil, fmt.Errorf("unknown tracing provider %q", cfg.Tracing.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warn("trace export failed", "error", err)
+	}))
+
+	return tracing.New(tracing.Config{
+		SampleRate:     cfg.Tracing.SampleRate,
+		Exporter:       exporter,
+		ServiceName:    cfg.App.Name,
+		ServiceVersion: cfg.App.Version,
+	}), nil
+}
+
+// tracingStatsHandler starts a server span for every RPC, continuing the
+// caller's trace when the request carries a traceparent.
+type tracingStatsHandler struct {
+	tracer trace.Tracer
+}
+
+func (h tracingStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = tracing.Extract(ctx, md)
+
+	service, method := splitMethod(info.FullMethodName)
+	ctx, _ = h.tracer.Start(ctx, strings.TrimPrefix(info.FullMethodName, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", method),
+		),
+	)
+	return ctx
+}
+
+func (h tracingStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	end, ok := rs.(*stats.End)
+	if !ok {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	code := status.Code(end.Error)
+	span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(code)))
+	tracing.SetError(span, end.Error)
+	span.End()
+}
+
+func (h tracingStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h tracingStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// splitMethod splits "/pkg.Service/Method" into its service and method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
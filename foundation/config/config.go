@@ -16,6 +16,11 @@ type Config struct {
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	Metrics  MetricsConfig  `mapstructure:"metrics"`
 	Tracing  TracingConfig  `mapstructure:"tracing"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+	Finance  FinanceConfig  `mapstructure:"finance"`
+	Referral ReferralConfig `mapstructure:"referral"`
+	Payment  PaymentConfig  `mapstructure:"payment"`
+	Outbox   OutboxConfig   `mapstructure:"outbox"`
 }
 
 type AppConfig struct {
@@ -30,6 +35,8 @@ type ServerConfig struct {
 	Timeout TimeoutConfig `mapstructure:"timeout"`
 }
 
+// GRPCConfig tunes the gRPC server. Zero durations and sizes keep the gRPC
+// defaults.
 type GRPCConfig struct {
 	Host                  string        `mapstructure:"host"`
 	Port                  int           `mapstructure:"port"`
@@ -38,13 +45,23 @@ type GRPCConfig struct {
 	MaxConnectionAgeGrace time.Duration `mapstructure:"max_connection_age_grace"`
 	KeepAliveTime         time.Duration `mapstructure:"keep_alive_time"`
 	KeepAliveTimeout      time.Duration `mapstructure:"keep_alive_timeout"`
+	// KeepAliveMinTime is the shortest ping interval clients may use; pinging
+	// more often gets the connection closed.
+	KeepAliveMinTime    time.Duration `mapstructure:"keep_alive_min_time"`
+	PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+	MaxRecvMsgSize      int           `mapstructure:"max_recv_msg_size"` // bytes
+	MaxSendMsgSize      int           `mapstructure:"max_send_msg_size"` // bytes
 }
 
 type TimeoutConfig struct {
-	Read    time.Duration `mapstructure:"read"`
-	Write   time.Duration `mapstructure:"write"`
-	Idle    time.Duration `mapstructure:"idle"`
+	Read  time.Duration `mapstructure:"read"`
+	Write time.Duration `mapstructure:"write"`
+	Idle  time.Duration `mapstructure:"idle"`
+	// Request caps how long a unary RPC may run. Clients can ask for less
+	// with their own deadline but not for more.
 	Request time.Duration `mapstructure:"request"`
+	// Shutdown bounds graceful shutdown before in-flight RPCs are cut off.
+	Shutdown time.Duration `mapstructure:"shutdown"`
 }
 
 type DatabaseConfig struct {
@@ -79,6 +96,9 @@ type MetricsConfig struct {
 	Path    string `mapstructure:"path"`
 }
 
+// TracingConfig selects where spans go. Provider "otlp" posts to an
+// OTLP/HTTP collector at endpoint, "file" appends JSON lines to the file at
+// endpoint and "stdout" prints them.
 type TracingConfig struct {
 	Enabled    bool    `mapstructure:"enabled"`
 	Provider   string  `mapstructure:"provider"`
@@ -86,6 +106,91 @@ type TracingConfig struct {
 	SampleRate float64 `mapstructure:"sample_rate"`
 }
 
+// AuthConfig controls bearer token verification. Keys come from a local JWKS
+// file: RSA keys verify RS256 tokens and "oct" keys verify HS256 tokens.
+type AuthConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	JWKSFile  string        `mapstructure:"jwks_file"`
+	Issuer    string        `mapstructure:"issuer"`
+	Audience  string        `mapstructure:"audience"`
+	ClockSkew time.Duration `mapstructure:"clock_skew"`
+}
+
+// FinanceConfig controls Soda Points expiry and wallet watches. Points earned
+// after a change to PointsTTL get the new lifetime; existing lots keep theirs.
+type FinanceConfig struct {
+	// PointsTTL is how long earned points last. Zero means forever.
+	PointsTTL   time.Duration     `mapstructure:"points_ttl"`
+	ExpirySweep ExpirySweepConfig `mapstructure:"expiry_sweep"`
+	// WatchHeartbeat is how often an idle WatchWallet stream gets a
+	// heartbeat. Zero means 15s.
+	WatchHeartbeat time.Duration `mapstructure:"watch_heartbeat"`
+}
+
+type ExpirySweepConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batch_size"`
+}
+
+// ReferralConfig controls how orders are attributed to referral blogs.
+type ReferralConfig struct {
+	// AttributionWindow is how long a recorded blog visit can earn the
+	// referral for an order placed without a blog. Zero turns last-touch
+	// attribution off.
+	AttributionWindow time.Duration `mapstructure:"attribution_window"`
+}
+
+// PaymentConfig selects the payment gateway that collects the external part
+// of orders. With no provider, orders are confirmed when placed. Provider
+// "fake" approves payments in-process and posts its webhooks to
+// fake.webhook_url, normally the receiver at webhook_port and webhook_path.
+type PaymentConfig struct {
+	Provider      string             `mapstructure:"provider"`
+	WebhookSecret string             `mapstructure:"webhook_secret"`
+	WebhookPort   int                `mapstructure:"webhook_port"`
+	WebhookPath   string             `mapstructure:"webhook_path"`
+	Fake          FakePaymentConfig  `mapstructure:"fake"`
+	Retry         PaymentRetryConfig `mapstructure:"retry"`
+}
+
+type FakePaymentConfig struct {
+	WebhookURL   string        `mapstructure:"webhook_url"`
+	WebhookDelay time.Duration `mapstructure:"webhook_delay"`
+	// DeclineAbove declines payments over this many yen. Zero declines none.
+	DeclineAbove int64 `mapstructure:"decline_above"`
+}
+
+// PaymentRetryConfig controls the retries of gateway calls that failed after
+// their order change committed. They run on every replica while a provider
+// is set.
+type PaymentRetryConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+}
+
+// OutboxConfig controls the relay that publishes domain events from the
+// outbox table. Publisher "file" appends them as JSON lines to path.
+type OutboxConfig struct {
+	Relay     OutboxRelayConfig     `mapstructure:"relay"`
+	Publisher OutboxPublisherConfig `mapstructure:"publisher"`
+}
+
+type OutboxRelayConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+}
+
+type OutboxPublisherConfig struct {
+	Provider string `mapstructure:"provider"`
+	Path     string `mapstructure:"path"`
+}
+
 func Load() (*Config, error) {
 	v := viper.New()
 
@@ -173,6 +278,29 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("server.grpc.host is required")
 	}
 
+	g := cfg.Server.GRPC
+	durations := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"server.grpc.max_connection_idle", g.MaxConnectionIdle},
+		{"server.grpc.max_connection_age", g.MaxConnectionAge},
+		{"server.grpc.max_connection_age_grace", g.MaxConnectionAgeGrace},
+		{"server.grpc.keep_alive_time", g.KeepAliveTime},
+		{"server.grpc.keep_alive_timeout", g.KeepAliveTimeout},
+		{"server.grpc.keep_alive_min_time", g.KeepAliveMinTime},
+		{"server.timeout.request", cfg.Server.Timeout.Request},
+		{"server.timeout.shutdown", cfg.Server.Timeout.Shutdown},
+	}
+	for _, v := range durations {
+		if v.d < 0 {
+			return fmt.Errorf("%s must be non-negative", v.name)
+		}
+	}
+	if g.MaxRecvMsgSize < 0 || g.MaxSendMsgSize < 0 {
+		return fmt.Errorf("server.grpc message size limits must be non-negative")
+	}
+
 	if cfg.Database.Postgres.Host == "" {
 		return fmt.Errorf("database.postgres.host is required")
 	}
@@ -217,17 +345,94 @@ func validate(cfg *Config) error {
 	}
 
 	if cfg.Tracing.Enabled {
-		if cfg.Tracing.Provider == "" {
-			return fmt.Errorf("tracing.provider is required when tracing is enabled")
-		}
-		if cfg.Tracing.Endpoint == "" {
-			return fmt.Errorf("tracing.endpoint is required when tracing is enabled")
+		switch cfg.Tracing.Provider {
+		case "otlp", "file":
+			if cfg.Tracing.Endpoint == "" {
+				return fmt.Errorf("tracing.endpoint is required for the %s provider", cfg.Tracing.Provider)
+			}
+		case "stdout":
+		default:
+			return fmt.Errorf("tracing.provider must be one of: otlp, stdout, file")
 		}
 		if cfg.Tracing.SampleRate < 0.0 || cfg.Tracing.SampleRate > 1.0 {
 			return fmt.Errorf("tracing.sample_rate must be between 0.0 and 1.0")
 		}
 	}
 
+	if cfg.Auth.Enabled {
+		if cfg.Auth.JWKSFile == "" {
+			return fmt.Errorf("auth.jwks_file is required when auth is enabled")
+		}
+		if cfg.Auth.ClockSkew < 0 {
+			return fmt.Errorf("auth.clock_skew must be non-negative")
+		}
+	} else if cfg.IsProduction() {
+		return fmt.Errorf("auth.enabled must be true in production")
+	}
+
+	if cfg.Finance.PointsTTL < 0 {
+		return fmt.Errorf("finance.points_ttl must be non-negative")
+	}
+	if cfg.Finance.WatchHeartbeat < 0 {
+		return fmt.Errorf("finance.watch_heartbeat must be non-negative")
+	}
+	if cfg.Referral.AttributionWindow < 0 {
+		return fmt.Errorf("referral.attribution_window must be non-negative")
+	}
+	if cfg.Finance.ExpirySweep.Enabled {
+		if cfg.Finance.ExpirySweep.Interval <= 0 {
+			return fmt.Errorf("finance.expiry_sweep.interval must be positive when the sweep is enabled")
+		}
+		if cfg.Finance.ExpirySweep.BatchSize < 1 {
+			return fmt.Errorf("finance.expiry_sweep.batch_size must be at least 1 when the sweep is enabled")
+		}
+	}
+
+	switch cfg.Payment.Provider {
+	case "":
+	case "fake":
+		if cfg.IsProduction() {
+			return fmt.Errorf("payment.provider fake is not allowed in production")
+		}
+		if cfg.Payment.Fake.WebhookDelay < 0 || cfg.Payment.Fake.DeclineAbove < 0 {
+			return fmt.Errorf("payment.fake settings must be non-negative")
+		}
+	default:
+		return fmt.Errorf("payment.provider must be empty or fake")
+	}
+	if cfg.Payment.Provider != "" {
+		if cfg.Payment.WebhookSecret == "" {
+			return fmt.Errorf("payment.webhook_secret is required when a payment provider is set")
+		}
+		if cfg.Payment.WebhookPort <= 0 || cfg.Payment.WebhookPort > 65535 {
+			return fmt.Errorf("payment.webhook_port must be between 1 and 65535")
+		}
+		if cfg.Payment.WebhookPath == "" {
+			return fmt.Errorf("payment.webhook_path is required when a payment provider is set")
+		}
+		if r := cfg.Payment.Retry; r.Interval <= 0 || r.BatchSize < 1 || r.MaxAttempts < 1 || r.Backoff < 0 {
+			return fmt.Errorf("payment.retry needs a positive interval, batch_size and max_attempts and a non-negative backoff when a payment provider is set")
+		}
+	}
+
+	if r := cfg.Outbox.Relay; r.Enabled {
+		if r.Interval <= 0 {
+			return fmt.Errorf("outbox.relay.interval must be positive when the relay is enabled")
+		}
+		if r.BatchSize < 1 || r.MaxAttempts < 1 {
+			return fmt.Errorf("outbox.relay.batch_size and max_attempts must be at least 1 when the relay is enabled")
+		}
+		if r.Backoff < 0 {
+			return fmt.Errorf("outbox.relay.backoff must be non-negative")
+		}
+		if cfg.Outbox.Publisher.Provider != "file" {
+			return fmt.Errorf("outbox.publisher.provider must be file")
+		}
+		if cfg.Outbox.Publisher.Path == "" {
+			return fmt.Errorf("outbox.publisher.path is required for the file publisher")
+		}
+	}
+
 	return nil
 }
 
@@ -254,6 +459,10 @@ func (c *Config) GetMetricsAddress() string {
 	return fmt.Sprintf(":%d", c.Metrics.Port)
 }
 
+func (c *Config) GetPaymentWebhookAddress() string {
+	return fmt.Sprintf(":%d", c.Payment.WebhookPort)
+}
+
 func (c *Config) IsProduction() bool {
 	return c.App.Environment == "production" || c.App.Environment == "prod"
 }
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WithAdvisoryLock runs fn while holding the session advisory lock key on a
+// connection of its own. If another session holds the lock, fn is not run and
+// WithAdvisoryLock reports false. Replicas use it to elect one of them to run
+// a background job.
+func WithAdvisoryLock(ctx context.Context, pool *pgxpool.Pool, key int64, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("taking advisory lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// Unlock even when ctx is done, or the pooled connection keeps the
+		// lock and no replica can take over.
+		unlockCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			// A connection in an unknown state must not go back to the pool.
+			_ = conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	return true, fn(ctx)
+}
@@ -19,6 +19,7 @@ func New(ctx context.Context, connStr string) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, err
 	}
+	config.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -0,0 +1,103 @@
+// Package events delivers domain events to downstream consumers. The outbox
+// relay hands each event to a Publisher; a message broker plugs in by
+// implementing it.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Message is one event as published.
+type Message struct {
+	ID          string          `json:"id"`   // Unique per event; consumers deduplicate on it.
+	Type        string          `json:"type"` // e.g. "events.v1.OrderPlaced"
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`     // protojson of Type
+	OccurredAt  int64           `json:"occurred_at"` // Unix timestamp
+}
+
+// Publisher sends messages to consumers. Publish must not return until the
+// message is durably accepted; an error makes the relay retry it later, so
+// consumers may see a message more than once.
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
+
+// Memory keeps published messages in memory, for tests.
+type Memory struct {
+	mu   sync.Mutex
+	msgs []Message
+	err  error
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (p *Memory) Publish(_ context.Context, m Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, m)
+	return nil
+}
+
+// Messages returns what was published so far, in order.
+func (p *Memory) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.msgs)
+}
+
+// SetError makes Publish fail with err until it is set back to nil.
+func (p *Memory) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// File appends messages to a file as JSON lines.
+type File struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFile opens path for appending, creating it if needed.
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening event file: %w", err)
+	}
+	return &File{f: f}, nil
+}
+
+// Publish writes the message and syncs the file before returning.
+func (p *File) Publish(_ context.Context, m Message) error {
+	line, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := p.f.Sync(); err != nil {
+		return fmt.Errorf("syncing event file: %w", err)
+	}
+	return nil
+}
+
+func (p *File) Close() error {
+	return p.f.Close()
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 	"log/slog"
 	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Logger struct {
@@ -31,7 +33,7 @@ func New(w io.Writer, level string) *Logger {
 		Level: l,
 	}
 
-	handler := slog.NewJSONHandler(w, opts)
+	handler := traceHandler{next: slog.NewJSONHandler(w, opts)}
 	return &Logger{
 		Logger: slog.New(handler),
 	}
@@ -62,9 +64,51 @@ func (l *Logger) With(args ...interface{}) *Logger {
 	}
 }
 
+// WithContext returns a logger that tags records with the trace in ctx, for
+// code that logs without passing a context (Info rather than InfoContext).
 func (l *Logger) WithContext(ctx context.Context) *Logger {
-	// In slog, context is passed to the log method (InfoContext, etc.),
-	// but we can also extract trace IDs here if using a specific handler.
-	// For now, just return l.
-	return l
+	th, ok := l.Handler().(traceHandler)
+	if !ok || !trace.SpanContextFromContext(ctx).IsValid() {
+		return l
+	}
+	th.bound = ctx
+	return &Logger{
+		Logger: slog.New(th),
+	}
+}
+
+// traceHandler adds trace_id and span_id to records logged with a context
+// that carries a span.
+type traceHandler struct {
+	next  slog.Handler
+	bound context.Context // fallback set by WithContext
+}
+
+func (h traceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() && h.bound != nil {
+		sc = trace.SpanContextFromContext(h.bound)
+	}
+	if sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.next = h.next.WithAttrs(attrs)
+	return h
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	h.next = h.next.WithGroup(name)
+	return h
 }
@@ -0,0 +1,107 @@
+// Package metrics records counters, gauges and histograms with the Prometheus
+// client library and serves them in its exposition format. It keeps the
+// label handling out of the callers: metrics are declared once with their
+// label names and recorded with the values in the same order.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets suit request latencies in seconds.
+var DefaultBuckets = prometheus.DefBuckets
+
+// Default is the registry the package-level constructors register with and
+// Handler serves.
+var Default = NewRegistry()
+
+// Registry holds a set of uniquely named metrics. Metrics are declared at
+// package level, so registering a name twice is a programming error and
+// panics, as prometheus.MustRegister does.
+type Registry struct {
+	reg *prometheus.Registry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{reg: prometheus.NewRegistry()}
+}
+
+// Handler serves every metric in the registry, negotiating the exposition
+// format with the scraper.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{})
+}
+
+// Handler serves the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// Counter is a monotonically increasing value per label combination.
+type Counter struct {
+	vec *prometheus.CounterVec
+}
+
+// NewCounter registers a counter with the given label names.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	r.reg.MustRegister(vec)
+	return &Counter{vec: vec}
+}
+
+// NewCounter registers a counter with the Default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// Inc adds one to the series for labelValues.
+func (c *Counter) Inc(labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Inc()
+}
+
+// Add adds v, which must not be negative, to the series for labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(v)
+}
+
+// Histogram counts observations into cumulative buckets per label
+// combination.
+type Histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// NewHistogram registers a histogram with the given upper bounds, or
+// DefaultBuckets when buckets is nil.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	r.reg.MustRegister(vec)
+	return &Histogram{vec: vec}
+}
+
+// NewHistogram registers a histogram with the Default registry.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// Observe records v in the series for labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(v)
+}
+
+// NewGaugeFunc registers an unlabelled gauge whose value is read from fn on
+// every scrape.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn))
+}
+
+// NewCounterFunc registers an unlabelled counter whose value is read from fn
+// on every scrape. fn must never return less than it did before.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.reg.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, fn))
+}
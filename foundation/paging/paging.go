@@ -0,0 +1,51 @@
+// Package paging encodes the opaque page tokens of keyset-paged lists.
+package paging
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidToken is returned for a page token that was not issued by Token.
+var ErrInvalidToken = errors.New("invalid page token")
+
+// Cursor is the last row of a page: the next page starts strictly after
+// (Key, ID) in the OrderBy sort.
+type Cursor struct {
+	// OrderBy is the sort the page was listed in. A token only continues
+	// that sort.
+	OrderBy string
+	// Key is the row's sort key: a price, or a timestamp in microseconds to
+	// match the precision Postgres stores.
+	Key int64
+	ID  string
+}
+
+// Token returns the cursor as an opaque page token.
+func (c Cursor) Token() string {
+	raw := c.OrderBy + ":" + strconv.FormatInt(c.Key, 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Parse decodes a page token built by Token.
+func Parse(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidToken
+	}
+	orderBy, rest, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidToken
+	}
+	key, id, ok := strings.Cut(rest, ":")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidToken
+	}
+	k, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidToken
+	}
+	return Cursor{OrderBy: orderBy, Key: k, ID: id}, nil
+}
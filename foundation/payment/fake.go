@@ -0,0 +1,302 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"soda-interview/foundation/logger"
+)
+
+// SignatureHeader carries the webhook signature: "t=<unix>,v1=<hex>", where
+// v1 is the HMAC-SHA256 of "<unix>.<body>" under the shared secret.
+const SignatureHeader = "Soda-Signature"
+
+// signatureTolerance is how old a signed webhook may be before it is
+// rejected as a replay.
+const signatureTolerance = 5 * time.Minute
+
+// Statuses of a payment held by the fake gateway.
+const (
+	FakeAuthorized = "AUTHORIZED"
+	FakeCaptured   = "CAPTURED"
+	FakeVoided     = "VOIDED"
+	FakeRefunded   = "REFUNDED"
+)
+
+// FakeConfig tunes the fake gateway.
+type FakeConfig struct {
+	// WebhookURL is where events are posted, normally this service's own
+	// webhook receiver. Empty means no events are sent.
+	WebhookURL string
+	// WebhookDelay is how long after authorizing the event is sent.
+	WebhookDelay time.Duration
+	// DeclineAbove declines authorizations for more than this many yen.
+	// Zero declines nothing.
+	DeclineAbove int64
+}
+
+// Fake is an in-process Gateway for local development and tests. It approves
+// every authorization it does not decline outright and reports it through a
+// signed webhook, the way a real provider would.
+type Fake struct {
+	log    *logger.Logger
+	secret []byte
+	cfg    FakeConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	err      error
+}
+
+type fakePayment struct {
+	status   string
+	amount   int64
+	captured int64
+	refunded int64
+	refunds  map[string]bool // Keys of the refunds made.
+}
+
+func NewFake(log *logger.Logger, secret string, cfg FakeConfig) *Fake {
+	return &Fake{
+		log:      log,
+		secret:   []byte(secret),
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		payments: make(map[string]*fakePayment),
+	}
+}
+
+func (f *Fake) Authorize(_ context.Context, req AuthorizeReq) (Authorization, error) {
+	if req.Amount <= 0 {
+		return Authorization{}, fmt.Errorf("authorizing %d yen: amount must be positive", req.Amount)
+	}
+	if f.cfg.DeclineAbove > 0 && req.Amount > f.cfg.DeclineAbove {
+		return Authorization{}, fmt.Errorf("%w: %d yen is over the limit", ErrDeclined, req.Amount)
+	}
+
+	id := "pay_fake_" + uuid.NewString()
+	f.mu.Lock()
+	f.payments[id] = &fakePayment{status: FakeAuthorized, amount: req.Amount}
+	f.mu.Unlock()
+
+	if f.cfg.WebhookURL != "" {
+		go f.deliver(Event{
+			ID:        "evt_fake_" + uuid.NewString(),
+			Type:      EventAuthorized,
+			CreatedAt: time.Now().Unix(),
+			PaymentID: id,
+			Reference: req.Reference,
+			Amount:    req.Amount,
+		})
+	}
+	return Authorization{PaymentID: id}, nil
+}
+
+func (f *Fake) Capture(_ context.Context, paymentID string, amount int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, paymentID)
+	}
+	if p.status == FakeCaptured && amount == p.captured {
+		return nil
+	}
+	if p.status != FakeAuthorized || amount <= 0 || amount > p.amount {
+		return fmt.Errorf("%w: capturing %d of %s payment for %d", ErrInvalidState, amount, p.status, p.amount)
+	}
+	p.status = FakeCaptured
+	p.captured = amount
+	return nil
+}
+
+func (f *Fake) Void(_ context.Context, paymentID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, paymentID)
+	}
+	switch p.status {
+	case FakeAuthorized:
+		p.status = FakeVoided
+	case FakeVoided:
+	default:
+		return fmt.Errorf("%w: voiding %s payment", ErrInvalidState, p.status)
+	}
+	return nil
+}
+
+func (f *Fake) Refund(_ context.Context, paymentID string, amount int64, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, paymentID)
+	}
+	if p.refunds[key] {
+		return nil
+	}
+	if p.status != FakeCaptured || amount <= 0 || amount > p.captured-p.refunded {
+		return fmt.Errorf("%w: refunding %d of %s payment", ErrInvalidState, amount, p.status)
+	}
+	if p.refunds == nil {
+		p.refunds = make(map[string]bool)
+	}
+	p.refunds[key] = true
+	p.refunded += amount
+	if p.refunded == p.captured {
+		p.status = FakeRefunded
+	}
+	return nil
+}
+
+// SetError makes Capture, Void and Refund fail with err, as if the provider
+// were down, until it is set back to nil.
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Status returns the status of a payment, one of the Fake* constants.
+func (f *Fake) Status(paymentID string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, paymentID)
+	}
+	return p.status, nil
+}
+
+// Webhook encodes and signs ev as the fake would send it. ID and CreatedAt
+// are filled in when empty.
+func (f *Fake) Webhook(ev Event) (http.Header, []byte, error) {
+	if ev.ID == "" {
+		ev.ID = "evt_fake_" + uuid.NewString()
+	}
+	if ev.CreatedAt == 0 {
+		ev.CreatedAt = time.Now().Unix()
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding event: %w", err)
+	}
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(SignatureHeader, "t="+ts+",v1="+hex.EncodeToString(f.sign(ts, body)))
+	return header, body, nil
+}
+
+func (f *Fake) VerifyWebhook(header http.Header, body []byte) (Event, error) {
+	var ts, sig string
+	for _, part := range strings.Split(header.Get(SignatureHeader), ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("%w: missing timestamp", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return Event{}, fmt.Errorf("%w: signed %s ago", ErrInvalidSignature, age.Round(time.Second))
+	}
+	mac, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, f.sign(ts, body)) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var ev Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return Event{}, fmt.Errorf("decoding event: %w", err)
+	}
+	return ev, nil
+}
+
+func (f *Fake) sign(ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, f.secret)
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// deliver posts ev to the webhook URL, retrying with backoff until the
+// receiver accepts it or the attempts run out.
+func (f *Fake) deliver(ev Event) {
+	const attempts = 5
+
+	time.Sleep(f.cfg.WebhookDelay)
+	backoff := time.Second
+	for i := 1; ; i++ {
+		err := f.post(ev)
+		if err == nil {
+			return
+		}
+		if i == attempts {
+			f.log.Error("Fake payment webhook not delivered", "event", ev.Type, "payment_id", ev.PaymentID, "error", err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (f *Fake) post(ev Event) error {
+	header, body, err := f.Webhook(ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, f.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header = header
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package payment defines the interface to an external payment provider.
+// Orders authorize their external amount through a Gateway and learn the
+// outcome from the provider's webhooks, so swapping providers means writing a
+// new Gateway and nothing else.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrDeclined is returned when the provider refuses an authorization.
+	ErrDeclined = errors.New("payment declined")
+	// ErrNotFound is returned for a payment the provider does not know.
+	ErrNotFound = errors.New("payment not found")
+	// ErrInvalidState is returned when a payment cannot take the requested
+	// action, such as capturing a voided authorization.
+	ErrInvalidState = errors.New("payment is not in a state that allows this action")
+	// ErrInvalidSignature is returned for webhooks that fail verification.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Webhook event types.
+const (
+	// EventAuthorized reports that the funds are held and may be captured.
+	EventAuthorized = "payment.authorized"
+	// EventFailed reports that the authorization did not go through.
+	EventFailed = "payment.failed"
+)
+
+// Gateway is a payment provider. Amounts are in yen. Capture, Void and
+// Refund are retried when they fail, so each must be safe to repeat.
+type Gateway interface {
+	// Authorize asks the provider to hold amount for the order. The outcome
+	// arrives later as an EventAuthorized or EventFailed webhook, unless the
+	// provider declines straight away with ErrDeclined.
+	Authorize(ctx context.Context, req AuthorizeReq) (Authorization, error)
+	// Capture collects an authorized amount. Capturing the amount a payment
+	// was already captured for is not an error.
+	Capture(ctx context.Context, paymentID string, amount int64) error
+	// Void releases an authorization that was not captured. Voiding a payment
+	// that already failed or was voided is not an error.
+	Void(ctx context.Context, paymentID string) error
+	// Refund returns up to the captured amount to the customer. key names
+	// the refund: a second refund with the same key returns nothing more.
+	Refund(ctx context.Context, paymentID string, amount int64, key string) error
+	// VerifyWebhook checks that a webhook request came from the provider and
+	// decodes its event.
+	VerifyWebhook(header http.Header, body []byte) (Event, error)
+}
+
+type AuthorizeReq struct {
+	// Reference identifies what is being paid for. Orders use their ID, and
+	// webhook events carry it back.
+	Reference  string
+	CustomerID string
+	Amount     int64
+}
+
+type Authorization struct {
+	PaymentID string
+}
+
+// Event is a payment status change reported by the provider's webhook.
+type Event struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	PaymentID string `json:"payment_id"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	CreatedAt int64  `json:"created_at"` // Unix timestamp
+}
@@ -0,0 +1,481 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.26.1
+// source: foundation/proto/events/v1/events.proto
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderPlaced is emitted when an order is written, whether it is confirmed
+// at once or waits for payment.
+type OrderPlaced struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	BuyerId       string                 `protobuf:"bytes,2,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	BalanceAmount int64                  `protobuf:"varint,4,opt,name=balance_amount,json=balanceAmount,proto3" json:"balance_amount,omitempty"` // Paid from Soda Balance; the rest is external.
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Lines         []*OrderLine           `protobuf:"bytes,6,rep,name=lines,proto3" json:"lines,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderPlaced) Reset() {
+	*x = OrderPlaced{}
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderPlaced) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderPlaced) ProtoMessage() {}
+
+func (x *OrderPlaced) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderPlaced.ProtoReflect.Descriptor instead.
+func (*OrderPlaced) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_events_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderPlaced) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderPlaced) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *OrderPlaced) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderPlaced) GetBalanceAmount() int64 {
+	if x != nil {
+		return x.BalanceAmount
+	}
+	return 0
+}
+
+func (x *OrderPlaced) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderPlaced) GetLines() []*OrderLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *OrderPlaced) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type OrderLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	BlogId        string                 `protobuf:"bytes,2,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Attribution   string                 `protobuf:"bytes,5,opt,name=attribution,proto3" json:"attribution,omitempty"` // REQUEST or LAST_TOUCH. Empty without blog_id.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLine) Reset() {
+	*x = OrderLine{}
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLine) ProtoMessage() {}
+
+func (x *OrderLine) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLine.ProtoReflect.Descriptor instead.
+func (*OrderLine) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_events_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderLine) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderLine) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *OrderLine) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderLine) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderLine) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+// PointsEarned is emitted for each reward an order pays out.
+type PointsEarned struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Recipient     string                 `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"` // "buyer" or "author"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointsEarned) Reset() {
+	*x = PointsEarned{}
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointsEarned) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointsEarned) ProtoMessage() {}
+
+func (x *PointsEarned) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointsEarned.ProtoReflect.Descriptor instead.
+func (*PointsEarned) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_events_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PointsEarned) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PointsEarned) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *PointsEarned) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PointsEarned) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+// PointsConverted is emitted when a user converts Soda Points to Soda Balance.
+type PointsConverted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Yen           int64                  `protobuf:"varint,3,opt,name=yen,proto3" json:"yen,omitempty"`
+	PolicyVersion int64                  `protobuf:"varint,4,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointsConverted) Reset() {
+	*x = PointsConverted{}
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointsConverted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointsConverted) ProtoMessage() {}
+
+func (x *PointsConverted) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointsConverted.ProtoReflect.Descriptor instead.
+func (*PointsConverted) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_events_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PointsConverted) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PointsConverted) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *PointsConverted) GetYen() int64 {
+	if x != nil {
+		return x.Yen
+	}
+	return 0
+}
+
+func (x *PointsConverted) GetPolicyVersion() int64 {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return 0
+}
+
+// BlogCreated is emitted when a referral blog is created.
+type BlogCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlogId        string                 `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlogCreated) Reset() {
+	*x = BlogCreated{}
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlogCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlogCreated) ProtoMessage() {}
+
+func (x *BlogCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_events_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlogCreated.ProtoReflect.Descriptor instead.
+func (*BlogCreated) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_events_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BlogCreated) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *BlogCreated) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *BlogCreated) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+var File_foundation_proto_events_v1_events_proto protoreflect.FileDescriptor
+
+const file_foundation_proto_events_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"'foundation/proto/events/v1/events.proto\x12\tevents.v1\"\xe5\x01\n" +
+	"\vOrderPlaced\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bbuyer_id\x18\x02 \x01(\tR\abuyerId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12%\n" +
+	"\x0ebalance_amount\x18\x04 \x01(\x03R\rbalanceAmount\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12*\n" +
+	"\x05lines\x18\x06 \x03(\v2\x14.events.v1.OrderLineR\x05lines\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\"\x99\x01\n" +
+	"\tOrderLine\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x17\n" +
+	"\ablog_id\x18\x02 \x01(\tR\x06blogId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12 \n" +
+	"\vattribution\x18\x05 \x01(\tR\vattribution\"x\n" +
+	"\fPointsEarned\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x1c\n" +
+	"\trecipient\x18\x04 \x01(\tR\trecipient\"{\n" +
+	"\x0fPointsConverted\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x10\n" +
+	"\x03yen\x18\x03 \x01(\x03R\x03yen\x12%\n" +
+	"\x0epolicy_version\x18\x04 \x01(\x03R\rpolicyVersion\"b\n" +
+	"\vBlogCreated\x12\x17\n" +
+	"\ablog_id\x18\x01 \x01(\tR\x06blogId\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductIdB4Z2soda-interview/foundation/proto/events/v1;eventsv1b\x06proto3"
+
+var (
+	file_foundation_proto_events_v1_events_proto_rawDescOnce sync.Once
+	file_foundation_proto_events_v1_events_proto_rawDescData []byte
+)
+
+func file_foundation_proto_events_v1_events_proto_rawDescGZIP() []byte {
+	file_foundation_proto_events_v1_events_proto_rawDescOnce.Do(func() {
+		file_foundation_proto_events_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_foundation_proto_events_v1_events_proto_rawDesc), len(file_foundation_proto_events_v1_events_proto_rawDesc)))
+	})
+	return file_foundation_proto_events_v1_events_proto_rawDescData
+}
+
+var file_foundation_proto_events_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_foundation_proto_events_v1_events_proto_goTypes = []any{
+	(*OrderPlaced)(nil),     // 0: events.v1.OrderPlaced
+	(*OrderLine)(nil),       // 1: events.v1.OrderLine
+	(*PointsEarned)(nil),    // 2: events.v1.PointsEarned
+	(*PointsConverted)(nil), // 3: events.v1.PointsConverted
+	(*BlogCreated)(nil),     // 4: events.v1.BlogCreated
+}
+var file_foundation_proto_events_v1_events_proto_depIdxs = []int32{
+	1, // 0: events.v1.OrderPlaced.lines:type_name -> events.v1.OrderLine
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_foundation_proto_events_v1_events_proto_init() }
+func file_foundation_proto_events_v1_events_proto_init() {
+	if File_foundation_proto_events_v1_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_foundation_proto_events_v1_events_proto_rawDesc), len(file_foundation_proto_events_v1_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_foundation_proto_events_v1_events_proto_goTypes,
+		DependencyIndexes: file_foundation_proto_events_v1_events_proto_depIdxs,
+		MessageInfos:      file_foundation_proto_events_v1_events_proto_msgTypes,
+	}.Build()
+	File_foundation_proto_events_v1_events_proto = out.File
+	file_foundation_proto_events_v1_events_proto_goTypes = nil
+	file_foundation_proto_events_v1_events_proto_depIdxs = nil
+}
@@ -22,13 +22,19 @@ const (
 )
 
 type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	BuyerId       string                 `protobuf:"bytes,2,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
-	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BuyerId        string                 `protobuf:"bytes,2,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	ProductId      string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Amount         int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	Items          []*OrderItem           `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	BalanceAmount  int64                  `protobuf:"varint,8,opt,name=balance_amount,json=balanceAmount,proto3" json:"balance_amount,omitempty"`    // Paid from the buyer's Soda Balance.
+	ExternalAmount int64                  `protobuf:"varint,9,opt,name=external_amount,json=externalAmount,proto3" json:"external_amount,omitempty"` // Paid externally. balance_amount + external_amount = amount.
+	// The payment gateway's reference for external_amount. Orders paid through
+	// the gateway stay PENDING_PAYMENT until it confirms the payment.
+	PaymentId     string `protobuf:"bytes,10,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -105,18 +111,202 @@ func (x *Order) GetCreatedAt() int64 {
 	return 0
 }
 
-type PlaceOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BuyerId       string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
-	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	BlogId        string                 `protobuf:"bytes,3,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"` // To track referral
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetBalanceAmount() int64 {
+	if x != nil {
+		return x.BalanceAmount
+	}
+	return 0
+}
+
+func (x *Order) GetExternalAmount() int64 {
+	if x != nil {
+		return x.ExternalAmount
+	}
+	return 0
+}
+
+func (x *Order) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type OrderItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	LineNo    int32                  `protobuf:"varint,1,opt,name=line_no,json=lineNo,proto3" json:"line_no,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	BlogId    string                 `protobuf:"bytes,3,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice int64                  `protobuf:"varint,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Amount    int64                  `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"` // unit_price * quantity
+	// How blog_id was found: REQUEST when the client named it, LAST_TOUCH when
+	// it came from the buyer's blog visits. Empty without blog_id.
+	Attribution   string `protobuf:"bytes,7,opt,name=attribution,proto3" json:"attribution,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderItem) GetLineNo() int32 {
+	if x != nil {
+		return x.LineNo
+	}
+	return 0
+}
+
+func (x *OrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetUnitPrice() int64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *OrderItem) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderItem) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+// Payment splits the order amount between the buyer's Soda Balance and an
+// external method. Omit it to pay everything externally; otherwise the two
+// amounts must add up to the order amount.
+type Payment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BalanceAmount  int64                  `protobuf:"varint,1,opt,name=balance_amount,json=balanceAmount,proto3" json:"balance_amount,omitempty"`
+	ExternalAmount int64                  `protobuf:"varint,2,opt,name=external_amount,json=externalAmount,proto3" json:"external_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Payment) GetBalanceAmount() int64 {
+	if x != nil {
+		return x.BalanceAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetExternalAmount() int64 {
+	if x != nil {
+		return x.ExternalAmount
+	}
+	return 0
+}
+
+type PlaceOrderRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId   string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// Optional. Referral blog; must promote product_id and not belong to the
+	// buyer. Without it, the order is credited to the last blog the buyer
+	// visited for the product (see RecordReferralVisit), if recent enough.
+	BlogId string `protobuf:"bytes,3,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	// Optional. Retrying with the same key returns the original order instead
+	// of placing a new one. Keys are scoped to buyer_id and kept for 24 hours.
+	IdempotencyKey string   `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Payment        *Payment `protobuf:"bytes,5,opt,name=payment,proto3" json:"payment,omitempty"` // Optional.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *PlaceOrderRequest) Reset() {
 	*x = PlaceOrderRequest{}
-	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[1]
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -128,7 +318,7 @@ func (x *PlaceOrderRequest) String() string {
 func (*PlaceOrderRequest) ProtoMessage() {}
 
 func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[1]
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -141,7 +331,7 @@ func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
 func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{1}
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PlaceOrderRequest) GetBuyerId() string {
@@ -165,6 +355,150 @@ func (x *PlaceOrderRequest) GetBlogId() string {
 	return ""
 }
 
+func (x *PlaceOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+type CartLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	BlogId        string                 `protobuf:"bytes,3,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"` // Optional. Referral blog; must promote product_id and not belong to the buyer. Defaults as in PlaceOrderRequest.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartLine) Reset() {
+	*x = CartLine{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartLine) ProtoMessage() {}
+
+func (x *CartLine) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartLine.ProtoReflect.Descriptor instead.
+func (*CartLine) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CartLine) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CartLine) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartLine) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+type PlaceCartOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	// At most 50 lines, each for a different product.
+	Lines []*CartLine `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	// Optional. Same semantics as PlaceOrderRequest.idempotency_key.
+	IdempotencyKey string   `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Payment        *Payment `protobuf:"bytes,4,opt,name=payment,proto3" json:"payment,omitempty"` // Optional.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PlaceCartOrderRequest) Reset() {
+	*x = PlaceCartOrderRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceCartOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceCartOrderRequest) ProtoMessage() {}
+
+func (x *PlaceCartOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceCartOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceCartOrderRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlaceCartOrderRequest) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *PlaceCartOrderRequest) GetLines() []*CartLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *PlaceCartOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *PlaceCartOrderRequest) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
 type OrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
@@ -174,7 +508,7 @@ type OrderResponse struct {
 
 func (x *OrderResponse) Reset() {
 	*x = OrderResponse{}
-	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[2]
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -186,7 +520,7 @@ func (x *OrderResponse) String() string {
 func (*OrderResponse) ProtoMessage() {}
 
 func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[2]
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -199,7 +533,7 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
 func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{2}
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *OrderResponse) GetOrder() *Order {
@@ -209,30 +543,676 @@ func (x *OrderResponse) GetOrder() *Order {
 	return nil
 }
 
-var File_foundation_proto_order_v1_order_proto protoreflect.FileDescriptor
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_foundation_proto_order_v1_order_proto_rawDesc = "" +
-	"\n" +
-	"%foundation/proto/order/v1/order.proto\x12\border.v1\"\xa0\x01\n" +
-	"\x05Order\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
-	"\bbuyer_id\x18\x02 \x01(\tR\abuyerId\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x03 \x01(\tR\tproductId\x12\x16\n" +
-	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"f\n" +
-	"\x11PlaceOrderRequest\x12\x19\n" +
-	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x02 \x01(\tR\tproductId\x12\x17\n" +
-	"\ablog_id\x18\x03 \x01(\tR\x06blogId\"6\n" +
-	"\rOrderResponse\x12%\n" +
-	"\x05order\x18\x01 \x01(\v2\x0f.order.v1.OrderR\x05order2R\n" +
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type RefundOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundOrderRequest) Reset() {
+	*x = RefundOrderRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundOrderRequest) ProtoMessage() {}
+
+func (x *RefundOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundOrderRequest.ProtoReflect.Descriptor instead.
+func (*RefundOrderRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RefundOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // CONFIRMED, SHIPPED or DELIVERED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// RewardRule adds reward points on top of those set on products. The rules in
+// force when an order is placed are evaluated for each line in priority
+// order, then by id. Rules are never edited; EndRewardRule stops one.
+type RewardRule struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Recipient string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"` // BUYER or AUTHOR
+	// BONUS adds value points; PERCENT_OF_PRICE adds value basis points of the
+	// line amount (500 is 5%); MULTIPLIER scales the points so far to value
+	// percent (200 doubles them).
+	Kind              string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Value             int64  `protobuf:"varint,5,opt,name=value,proto3" json:"value,omitempty"`
+	Priority          int32  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`                                              // Lower runs first.
+	ProductId         string `protobuf:"bytes,7,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`                            // Empty for every product.
+	FirstPurchaseOnly bool   `protobuf:"varint,8,opt,name=first_purchase_only,json=firstPurchaseOnly,proto3" json:"first_purchase_only,omitempty"` // Only the buyer's first order of the product.
+	// Author tier: the author's lifetime referred sales in yen must be at least
+	// min_author_sales and, unless max_author_sales is 0, less than it.
+	MinAuthorSales int64  `protobuf:"varint,9,opt,name=min_author_sales,json=minAuthorSales,proto3" json:"min_author_sales,omitempty"`
+	MaxAuthorSales int64  `protobuf:"varint,10,opt,name=max_author_sales,json=maxAuthorSales,proto3" json:"max_author_sales,omitempty"`
+	UserCapPoints  int64  `protobuf:"varint,11,opt,name=user_cap_points,json=userCapPoints,proto3" json:"user_cap_points,omitempty"` // Most points one user can earn from the rule. 0 means no cap.
+	StartsAt       int64  `protobuf:"varint,12,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`                  // Unix timestamp
+	EndsAt         int64  `protobuf:"varint,13,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`                        // Unix timestamp. 0 means open-ended.
+	CreatedBy      string `protobuf:"bytes,14,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt      int64  `protobuf:"varint,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RewardRule) Reset() {
+	*x = RewardRule{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RewardRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewardRule) ProtoMessage() {}
+
+func (x *RewardRule) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewardRule.ProtoReflect.Descriptor instead.
+func (*RewardRule) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RewardRule) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RewardRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RewardRule) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *RewardRule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RewardRule) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *RewardRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *RewardRule) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RewardRule) GetFirstPurchaseOnly() bool {
+	if x != nil {
+		return x.FirstPurchaseOnly
+	}
+	return false
+}
+
+func (x *RewardRule) GetMinAuthorSales() int64 {
+	if x != nil {
+		return x.MinAuthorSales
+	}
+	return 0
+}
+
+func (x *RewardRule) GetMaxAuthorSales() int64 {
+	if x != nil {
+		return x.MaxAuthorSales
+	}
+	return 0
+}
+
+func (x *RewardRule) GetUserCapPoints() int64 {
+	if x != nil {
+		return x.UserCapPoints
+	}
+	return 0
+}
+
+func (x *RewardRule) GetStartsAt() int64 {
+	if x != nil {
+		return x.StartsAt
+	}
+	return 0
+}
+
+func (x *RewardRule) GetEndsAt() int64 {
+	if x != nil {
+		return x.EndsAt
+	}
+	return 0
+}
+
+func (x *RewardRule) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *RewardRule) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateRewardRuleRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Recipient         string                 `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Kind              string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Value             int64                  `protobuf:"varint,4,opt,name=value,proto3" json:"value,omitempty"`
+	Priority          int32                  `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	ProductId         string                 `protobuf:"bytes,6,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	FirstPurchaseOnly bool                   `protobuf:"varint,7,opt,name=first_purchase_only,json=firstPurchaseOnly,proto3" json:"first_purchase_only,omitempty"`
+	MinAuthorSales    int64                  `protobuf:"varint,8,opt,name=min_author_sales,json=minAuthorSales,proto3" json:"min_author_sales,omitempty"`
+	MaxAuthorSales    int64                  `protobuf:"varint,9,opt,name=max_author_sales,json=maxAuthorSales,proto3" json:"max_author_sales,omitempty"`
+	UserCapPoints     int64                  `protobuf:"varint,10,opt,name=user_cap_points,json=userCapPoints,proto3" json:"user_cap_points,omitempty"`
+	StartsAt          int64                  `protobuf:"varint,11,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"` // Unix timestamp. Not in the past; 0 means now.
+	EndsAt            int64                  `protobuf:"varint,12,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`       // Unix timestamp. Optional.
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateRewardRuleRequest) Reset() {
+	*x = CreateRewardRuleRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRewardRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRewardRuleRequest) ProtoMessage() {}
+
+func (x *CreateRewardRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRewardRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateRewardRuleRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateRewardRuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRewardRuleRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *CreateRewardRuleRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CreateRewardRuleRequest) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateRewardRuleRequest) GetFirstPurchaseOnly() bool {
+	if x != nil {
+		return x.FirstPurchaseOnly
+	}
+	return false
+}
+
+func (x *CreateRewardRuleRequest) GetMinAuthorSales() int64 {
+	if x != nil {
+		return x.MinAuthorSales
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetMaxAuthorSales() int64 {
+	if x != nil {
+		return x.MaxAuthorSales
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetUserCapPoints() int64 {
+	if x != nil {
+		return x.UserCapPoints
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetStartsAt() int64 {
+	if x != nil {
+		return x.StartsAt
+	}
+	return 0
+}
+
+func (x *CreateRewardRuleRequest) GetEndsAt() int64 {
+	if x != nil {
+		return x.EndsAt
+	}
+	return 0
+}
+
+type ListRewardRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRewardRulesRequest) Reset() {
+	*x = ListRewardRulesRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRewardRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRewardRulesRequest) ProtoMessage() {}
+
+func (x *ListRewardRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRewardRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRewardRulesRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{12}
+}
+
+type ListRewardRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RewardRule          `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"` // Newest first.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRewardRulesResponse) Reset() {
+	*x = ListRewardRulesResponse{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRewardRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRewardRulesResponse) ProtoMessage() {}
+
+func (x *ListRewardRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRewardRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRewardRulesResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRewardRulesResponse) GetRules() []*RewardRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type EndRewardRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndRewardRuleRequest) Reset() {
+	*x = EndRewardRuleRequest{}
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndRewardRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndRewardRuleRequest) ProtoMessage() {}
+
+func (x *EndRewardRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_order_v1_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndRewardRuleRequest.ProtoReflect.Descriptor instead.
+func (*EndRewardRuleRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_order_v1_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EndRewardRuleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_foundation_proto_order_v1_order_proto protoreflect.FileDescriptor
+
+const file_foundation_proto_order_v1_order_proto_rawDesc = "" +
+	"\n" +
+	"%foundation/proto/order/v1/order.proto\x12\border.v1\"\xba\x02\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bbuyer_id\x18\x02 \x01(\tR\abuyerId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12)\n" +
+	"\x05items\x18\a \x03(\v2\x13.order.v1.OrderItemR\x05items\x12%\n" +
+	"\x0ebalance_amount\x18\b \x01(\x03R\rbalanceAmount\x12'\n" +
+	"\x0fexternal_amount\x18\t \x01(\x03R\x0eexternalAmount\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\n" +
+	" \x01(\tR\tpaymentId\"\xd1\x01\n" +
+	"\tOrderItem\x12\x17\n" +
+	"\aline_no\x18\x01 \x01(\x05R\x06lineNo\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x17\n" +
+	"\ablog_id\x18\x03 \x01(\tR\x06blogId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\x03R\tunitPrice\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12 \n" +
+	"\vattribution\x18\a \x01(\tR\vattribution\"Y\n" +
+	"\aPayment\x12%\n" +
+	"\x0ebalance_amount\x18\x01 \x01(\x03R\rbalanceAmount\x12'\n" +
+	"\x0fexternal_amount\x18\x02 \x01(\x03R\x0eexternalAmount\"\xbc\x01\n" +
+	"\x11PlaceOrderRequest\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x17\n" +
+	"\ablog_id\x18\x03 \x01(\tR\x06blogId\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12+\n" +
+	"\apayment\x18\x05 \x01(\v2\x11.order.v1.PaymentR\apayment\"^\n" +
+	"\bCartLine\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x17\n" +
+	"\ablog_id\x18\x03 \x01(\tR\x06blogId\"\xb2\x01\n" +
+	"\x15PlaceCartOrderRequest\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12(\n" +
+	"\x05lines\x18\x02 \x03(\v2\x12.order.v1.CartLineR\x05lines\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12+\n" +
+	"\apayment\x18\x04 \x01(\v2\x11.order.v1.PaymentR\apayment\"6\n" +
+	"\rOrderResponse\x12%\n" +
+	"\x05order\x18\x01 \x01(\v2\x0f.order.v1.OrderR\x05order\"/\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"/\n" +
+	"\x12RefundOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"M\n" +
+	"\x18UpdateOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"\xd3\x03\n" +
+	"\n" +
+	"RewardRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\trecipient\x18\x03 \x01(\tR\trecipient\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x03R\x05value\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\a \x01(\tR\tproductId\x12.\n" +
+	"\x13first_purchase_only\x18\b \x01(\bR\x11firstPurchaseOnly\x12(\n" +
+	"\x10min_author_sales\x18\t \x01(\x03R\x0eminAuthorSales\x12(\n" +
+	"\x10max_author_sales\x18\n" +
+	" \x01(\x03R\x0emaxAuthorSales\x12&\n" +
+	"\x0fuser_cap_points\x18\v \x01(\x03R\ruserCapPoints\x12\x1b\n" +
+	"\tstarts_at\x18\f \x01(\x03R\bstartsAt\x12\x17\n" +
+	"\aends_at\x18\r \x01(\x03R\x06endsAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x0e \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\x03R\tcreatedAt\"\x92\x03\n" +
+	"\x17CreateRewardRuleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x03R\x05value\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x06 \x01(\tR\tproductId\x12.\n" +
+	"\x13first_purchase_only\x18\a \x01(\bR\x11firstPurchaseOnly\x12(\n" +
+	"\x10min_author_sales\x18\b \x01(\x03R\x0eminAuthorSales\x12(\n" +
+	"\x10max_author_sales\x18\t \x01(\x03R\x0emaxAuthorSales\x12&\n" +
+	"\x0fuser_cap_points\x18\n" +
+	" \x01(\x03R\ruserCapPoints\x12\x1b\n" +
+	"\tstarts_at\x18\v \x01(\x03R\bstartsAt\x12\x17\n" +
+	"\aends_at\x18\f \x01(\x03R\x06endsAt\"\x18\n" +
+	"\x16ListRewardRulesRequest\"E\n" +
+	"\x17ListRewardRulesResponse\x12*\n" +
+	"\x05rules\x18\x01 \x03(\v2\x14.order.v1.RewardRuleR\x05rules\"&\n" +
+	"\x14EndRewardRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id2\xe8\x04\n" +
 	"\fOrderService\x12B\n" +
 	"\n" +
-	"PlaceOrder\x12\x1b.order.v1.PlaceOrderRequest\x1a\x17.order.v1.OrderResponseB2Z0soda-interview/foundation/proto/order/v1;orderv1b\x06proto3"
+	"PlaceOrder\x12\x1b.order.v1.PlaceOrderRequest\x1a\x17.order.v1.OrderResponse\x12J\n" +
+	"\x0ePlaceCartOrder\x12\x1f.order.v1.PlaceCartOrderRequest\x1a\x17.order.v1.OrderResponse\x12D\n" +
+	"\vCancelOrder\x12\x1c.order.v1.CancelOrderRequest\x1a\x17.order.v1.OrderResponse\x12D\n" +
+	"\vRefundOrder\x12\x1c.order.v1.RefundOrderRequest\x1a\x17.order.v1.OrderResponse\x12P\n" +
+	"\x11UpdateOrderStatus\x12\".order.v1.UpdateOrderStatusRequest\x1a\x17.order.v1.OrderResponse\x12K\n" +
+	"\x10CreateRewardRule\x12!.order.v1.CreateRewardRuleRequest\x1a\x14.order.v1.RewardRule\x12V\n" +
+	"\x0fListRewardRules\x12 .order.v1.ListRewardRulesRequest\x1a!.order.v1.ListRewardRulesResponse\x12E\n" +
+	"\rEndRewardRule\x12\x1e.order.v1.EndRewardRuleRequest\x1a\x14.order.v1.RewardRuleB2Z0soda-interview/foundation/proto/order/v1;orderv1b\x06proto3"
 
 var (
 	file_foundation_proto_order_v1_order_proto_rawDescOnce sync.Once
@@ -246,21 +1226,52 @@ func file_foundation_proto_order_v1_order_proto_rawDescGZIP() []byte {
 	return file_foundation_proto_order_v1_order_proto_rawDescData
 }
 
-var file_foundation_proto_order_v1_order_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_foundation_proto_order_v1_order_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_foundation_proto_order_v1_order_proto_goTypes = []any{
-	(*Order)(nil),             // 0: order.v1.Order
-	(*PlaceOrderRequest)(nil), // 1: order.v1.PlaceOrderRequest
-	(*OrderResponse)(nil),     // 2: order.v1.OrderResponse
+	(*Order)(nil),                    // 0: order.v1.Order
+	(*OrderItem)(nil),                // 1: order.v1.OrderItem
+	(*Payment)(nil),                  // 2: order.v1.Payment
+	(*PlaceOrderRequest)(nil),        // 3: order.v1.PlaceOrderRequest
+	(*CartLine)(nil),                 // 4: order.v1.CartLine
+	(*PlaceCartOrderRequest)(nil),    // 5: order.v1.PlaceCartOrderRequest
+	(*OrderResponse)(nil),            // 6: order.v1.OrderResponse
+	(*CancelOrderRequest)(nil),       // 7: order.v1.CancelOrderRequest
+	(*RefundOrderRequest)(nil),       // 8: order.v1.RefundOrderRequest
+	(*UpdateOrderStatusRequest)(nil), // 9: order.v1.UpdateOrderStatusRequest
+	(*RewardRule)(nil),               // 10: order.v1.RewardRule
+	(*CreateRewardRuleRequest)(nil),  // 11: order.v1.CreateRewardRuleRequest
+	(*ListRewardRulesRequest)(nil),   // 12: order.v1.ListRewardRulesRequest
+	(*ListRewardRulesResponse)(nil),  // 13: order.v1.ListRewardRulesResponse
+	(*EndRewardRuleRequest)(nil),     // 14: order.v1.EndRewardRuleRequest
 }
 var file_foundation_proto_order_v1_order_proto_depIdxs = []int32{
-	0, // 0: order.v1.OrderResponse.order:type_name -> order.v1.Order
-	1, // 1: order.v1.OrderService.PlaceOrder:input_type -> order.v1.PlaceOrderRequest
-	2, // 2: order.v1.OrderService.PlaceOrder:output_type -> order.v1.OrderResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1,  // 0: order.v1.Order.items:type_name -> order.v1.OrderItem
+	2,  // 1: order.v1.PlaceOrderRequest.payment:type_name -> order.v1.Payment
+	4,  // 2: order.v1.PlaceCartOrderRequest.lines:type_name -> order.v1.CartLine
+	2,  // 3: order.v1.PlaceCartOrderRequest.payment:type_name -> order.v1.Payment
+	0,  // 4: order.v1.OrderResponse.order:type_name -> order.v1.Order
+	10, // 5: order.v1.ListRewardRulesResponse.rules:type_name -> order.v1.RewardRule
+	3,  // 6: order.v1.OrderService.PlaceOrder:input_type -> order.v1.PlaceOrderRequest
+	5,  // 7: order.v1.OrderService.PlaceCartOrder:input_type -> order.v1.PlaceCartOrderRequest
+	7,  // 8: order.v1.OrderService.CancelOrder:input_type -> order.v1.CancelOrderRequest
+	8,  // 9: order.v1.OrderService.RefundOrder:input_type -> order.v1.RefundOrderRequest
+	9,  // 10: order.v1.OrderService.UpdateOrderStatus:input_type -> order.v1.UpdateOrderStatusRequest
+	11, // 11: order.v1.OrderService.CreateRewardRule:input_type -> order.v1.CreateRewardRuleRequest
+	12, // 12: order.v1.OrderService.ListRewardRules:input_type -> order.v1.ListRewardRulesRequest
+	14, // 13: order.v1.OrderService.EndRewardRule:input_type -> order.v1.EndRewardRuleRequest
+	6,  // 14: order.v1.OrderService.PlaceOrder:output_type -> order.v1.OrderResponse
+	6,  // 15: order.v1.OrderService.PlaceCartOrder:output_type -> order.v1.OrderResponse
+	6,  // 16: order.v1.OrderService.CancelOrder:output_type -> order.v1.OrderResponse
+	6,  // 17: order.v1.OrderService.RefundOrder:output_type -> order.v1.OrderResponse
+	6,  // 18: order.v1.OrderService.UpdateOrderStatus:output_type -> order.v1.OrderResponse
+	10, // 19: order.v1.OrderService.CreateRewardRule:output_type -> order.v1.RewardRule
+	13, // 20: order.v1.OrderService.ListRewardRules:output_type -> order.v1.ListRewardRulesResponse
+	10, // 21: order.v1.OrderService.EndRewardRule:output_type -> order.v1.RewardRule
+	14, // [14:22] is the sub-list for method output_type
+	6,  // [6:14] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_foundation_proto_order_v1_order_proto_init() }
@@ -274,7 +1285,7 @@ func file_foundation_proto_order_v1_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_foundation_proto_order_v1_order_proto_rawDesc), len(file_foundation_proto_order_v1_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
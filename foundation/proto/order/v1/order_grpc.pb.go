@@ -19,7 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderService_PlaceOrder_FullMethodName = "/order.v1.OrderService/PlaceOrder"
+	OrderService_PlaceOrder_FullMethodName        = "/order.v1.OrderService/PlaceOrder"
+	OrderService_PlaceCartOrder_FullMethodName    = "/order.v1.OrderService/PlaceCartOrder"
+	OrderService_CancelOrder_FullMethodName       = "/order.v1.OrderService/CancelOrder"
+	OrderService_RefundOrder_FullMethodName       = "/order.v1.OrderService/RefundOrder"
+	OrderService_UpdateOrderStatus_FullMethodName = "/order.v1.OrderService/UpdateOrderStatus"
+	OrderService_CreateRewardRule_FullMethodName  = "/order.v1.OrderService/CreateRewardRule"
+	OrderService_ListRewardRules_FullMethodName   = "/order.v1.OrderService/ListRewardRules"
+	OrderService_EndRewardRule_FullMethodName     = "/order.v1.OrderService/EndRewardRule"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -27,6 +34,26 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderServiceClient interface {
 	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// PlaceCartOrder places one order for several products atomically. Each
+	// line earns its own first-purchase buyer reward and author reward.
+	PlaceCartOrder(ctx context.Context, in *PlaceCartOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// CancelOrder cancels an order that has not shipped, returns any Soda
+	// Balance it was paid with and reverses its rewards.
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// RefundOrder refunds a shipped or delivered order, returns any Soda
+	// Balance it was paid with and reverses its rewards.
+	RefundOrder(ctx context.Context, in *RefundOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// UpdateOrderStatus moves an order forward through fulfilment.
+	UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// CreateRewardRule adds a reward rule taking effect at starts_at. Admin
+	// only.
+	CreateRewardRule(ctx context.Context, in *CreateRewardRuleRequest, opts ...grpc.CallOption) (*RewardRule, error)
+	// ListRewardRules returns every reward rule, ended and scheduled ones
+	// included. Admin only.
+	ListRewardRules(ctx context.Context, in *ListRewardRulesRequest, opts ...grpc.CallOption) (*ListRewardRulesResponse, error)
+	// EndRewardRule stops a reward rule now. Orders already placed keep the
+	// points it added. Admin only.
+	EndRewardRule(ctx context.Context, in *EndRewardRuleRequest, opts ...grpc.CallOption) (*RewardRule, error)
 }
 
 type orderServiceClient struct {
@@ -47,11 +74,101 @@ func (c *orderServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderReque
 	return out, nil
 }
 
+func (c *orderServiceClient) PlaceCartOrder(ctx context.Context, in *PlaceCartOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_PlaceCartOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RefundOrder(ctx context.Context, in *RefundOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_RefundOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_UpdateOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateRewardRule(ctx context.Context, in *CreateRewardRuleRequest, opts ...grpc.CallOption) (*RewardRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RewardRule)
+	err := c.cc.Invoke(ctx, OrderService_CreateRewardRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListRewardRules(ctx context.Context, in *ListRewardRulesRequest, opts ...grpc.CallOption) (*ListRewardRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRewardRulesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListRewardRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) EndRewardRule(ctx context.Context, in *EndRewardRuleRequest, opts ...grpc.CallOption) (*RewardRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RewardRule)
+	err := c.cc.Invoke(ctx, OrderService_EndRewardRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
 type OrderServiceServer interface {
 	PlaceOrder(context.Context, *PlaceOrderRequest) (*OrderResponse, error)
+	// PlaceCartOrder places one order for several products atomically. Each
+	// line earns its own first-purchase buyer reward and author reward.
+	PlaceCartOrder(context.Context, *PlaceCartOrderRequest) (*OrderResponse, error)
+	// CancelOrder cancels an order that has not shipped, returns any Soda
+	// Balance it was paid with and reverses its rewards.
+	CancelOrder(context.Context, *CancelOrderRequest) (*OrderResponse, error)
+	// RefundOrder refunds a shipped or delivered order, returns any Soda
+	// Balance it was paid with and reverses its rewards.
+	RefundOrder(context.Context, *RefundOrderRequest) (*OrderResponse, error)
+	// UpdateOrderStatus moves an order forward through fulfilment.
+	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*OrderResponse, error)
+	// CreateRewardRule adds a reward rule taking effect at starts_at. Admin
+	// only.
+	CreateRewardRule(context.Context, *CreateRewardRuleRequest) (*RewardRule, error)
+	// ListRewardRules returns every reward rule, ended and scheduled ones
+	// included. Admin only.
+	ListRewardRules(context.Context, *ListRewardRulesRequest) (*ListRewardRulesResponse, error)
+	// EndRewardRule stops a reward rule now. Orders already placed keep the
+	// points it added. Admin only.
+	EndRewardRule(context.Context, *EndRewardRuleRequest) (*RewardRule, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -65,6 +182,27 @@ type UnimplementedOrderServiceServer struct{}
 func (UnimplementedOrderServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) PlaceCartOrder(context.Context, *PlaceCartOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceCartOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) RefundOrder(context.Context, *RefundOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateRewardRule(context.Context, *CreateRewardRuleRequest) (*RewardRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRewardRule not implemented")
+}
+func (UnimplementedOrderServiceServer) ListRewardRules(context.Context, *ListRewardRulesRequest) (*ListRewardRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRewardRules not implemented")
+}
+func (UnimplementedOrderServiceServer) EndRewardRule(context.Context, *EndRewardRuleRequest) (*RewardRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndRewardRule not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -104,6 +242,132 @@ func _OrderService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_PlaceCartOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceCartOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PlaceCartOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PlaceCartOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PlaceCartOrder(ctx, req.(*PlaceCartOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RefundOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RefundOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RefundOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RefundOrder(ctx, req.(*RefundOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UpdateOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UpdateOrderStatus(ctx, req.(*UpdateOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateRewardRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRewardRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateRewardRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateRewardRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateRewardRule(ctx, req.(*CreateRewardRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListRewardRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRewardRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListRewardRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListRewardRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListRewardRules(ctx, req.(*ListRewardRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_EndRewardRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndRewardRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).EndRewardRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_EndRewardRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).EndRewardRule(ctx, req.(*EndRewardRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +379,34 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PlaceOrder",
 			Handler:    _OrderService_PlaceOrder_Handler,
 		},
+		{
+			MethodName: "PlaceCartOrder",
+			Handler:    _OrderService_PlaceCartOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrderService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "RefundOrder",
+			Handler:    _OrderService_RefundOrder_Handler,
+		},
+		{
+			MethodName: "UpdateOrderStatus",
+			Handler:    _OrderService_UpdateOrderStatus_Handler,
+		},
+		{
+			MethodName: "CreateRewardRule",
+			Handler:    _OrderService_CreateRewardRule_Handler,
+		},
+		{
+			MethodName: "ListRewardRules",
+			Handler:    _OrderService_ListRewardRules_Handler,
+		},
+		{
+			MethodName: "EndRewardRule",
+			Handler:    _OrderService_EndRewardRule_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "foundation/proto/order/v1/order.proto",
@@ -9,6 +9,7 @@ package productv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,9 +28,12 @@ type Product struct {
 	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Price             int64                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
-	BuyerRewardPoints int32                  `protobuf:"varint,5,opt,name=buyer_reward_points,json=buyerRewardPoints,proto3" json:"buyer_reward_points,omitempty"` // author_reward_points is internal and not exposed.
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	BuyerRewardPoints int32                  `protobuf:"varint,5,opt,name=buyer_reward_points,json=buyerRewardPoints,proto3" json:"buyer_reward_points,omitempty"`
+	// author_reward_points is internal and not exposed.
+	ArchivedAt    int64 `protobuf:"varint,6,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"` // Unix timestamp; 0 while the product is on sale.
+	CreatedAt     int64 `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`    // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Product) Reset() {
@@ -97,6 +101,20 @@ func (x *Product) GetBuyerRewardPoints() int32 {
 	return 0
 }
 
+func (x *Product) GetArchivedAt() int64 {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return 0
+}
+
+func (x *Product) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
 type ProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -141,16 +159,103 @@ func (x *ProductRequest) GetId() string {
 	return ""
 }
 
+type ListProductsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MinPrice     int64                  `protobuf:"varint,1,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`            // Optional. Inclusive; 0 leaves it open.
+	MaxPrice     int64                  `protobuf:"varint,2,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`            // Optional. Inclusive; 0 leaves it open.
+	NameContains string                 `protobuf:"bytes,3,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"` // Optional. Case-insensitive substring of the name.
+	// created_at, "created_at desc" (the default), price or "price desc". Ties
+	// are sorted by id.
+	OrderBy       string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token from the previous page, listed with the same order_by.
+	PageSize      int32  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Defaults to 50, at most 200.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsRequest) Reset() {
+	*x = ListProductsRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsRequest) ProtoMessage() {}
+
+func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListProductsRequest) GetMinPrice() int64 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetMaxPrice() int64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
 type ProductList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductList) Reset() {
 	*x = ProductList{}
-	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[2]
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -162,7 +267,7 @@ func (x *ProductList) String() string {
 func (*ProductList) ProtoMessage() {}
 
 func (x *ProductList) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[2]
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -175,7 +280,7 @@ func (x *ProductList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductList.ProtoReflect.Descriptor instead.
 func (*ProductList) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{2}
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ProductList) GetProducts() []*Product {
@@ -185,27 +290,204 @@ func (x *ProductList) GetProducts() []*Product {
 	return nil
 }
 
-type Empty struct {
+func (x *ProductList) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CreateProductRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description        string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price              int64                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	BuyerRewardPoints  int32                  `protobuf:"varint,4,opt,name=buyer_reward_points,json=buyerRewardPoints,proto3" json:"buyer_reward_points,omitempty"`
+	AuthorRewardPoints int32                  `protobuf:"varint,5,opt,name=author_reward_points,json=authorRewardPoints,proto3" json:"author_reward_points,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetBuyerRewardPoints() int32 {
+	if x != nil {
+		return x.BuyerRewardPoints
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetAuthorRewardPoints() int32 {
+	if x != nil {
+		return x.AuthorRewardPoints
+	}
+	return 0
+}
+
+type UpdateProductRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description        string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price              int64                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	BuyerRewardPoints  int32                  `protobuf:"varint,5,opt,name=buyer_reward_points,json=buyerRewardPoints,proto3" json:"buyer_reward_points,omitempty"`
+	AuthorRewardPoints int32                  `protobuf:"varint,6,opt,name=author_reward_points,json=authorRewardPoints,proto3" json:"author_reward_points,omitempty"`
+	// Required. Paths name the fields of this request to apply, e.g. "price".
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,7,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetBuyerRewardPoints() int32 {
+	if x != nil {
+		return x.BuyerRewardPoints
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetAuthorRewardPoints() int32 {
+	if x != nil {
+		return x.AuthorRewardPoints
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ArchiveProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[3]
+func (x *ArchiveProductRequest) Reset() {
+	*x = ArchiveProductRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *ArchiveProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*ArchiveProductRequest) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[3]
+func (x *ArchiveProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -216,9 +498,196 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ArchiveProductRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveProductRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ArchiveProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Stock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Tracked       bool                   `protobuf:"varint,2,opt,name=tracked,proto3" json:"tracked,omitempty"` // False until the first AdjustStock; untracked products never run out.
+	OnHand        int32                  `protobuf:"varint,3,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`
+	Reserved      int32                  `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`   // Held by orders that have not shipped.
+	Available     int32                  `protobuf:"varint,5,opt,name=available,proto3" json:"available,omitempty"` // on_hand - reserved
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stock) Reset() {
+	*x = Stock{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stock) ProtoMessage() {}
+
+func (x *Stock) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stock.ProtoReflect.Descriptor instead.
+func (*Stock) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Stock) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Stock) GetTracked() bool {
+	if x != nil {
+		return x.Tracked
+	}
+	return false
+}
+
+func (x *Stock) GetOnHand() int32 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *Stock) GetReserved() int32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *Stock) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type GetStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockRequest) Reset() {
+	*x = GetStockRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockRequest) ProtoMessage() {}
+
+func (x *GetStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockRequest.ProtoReflect.Descriptor instead.
+func (*GetStockRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetStockRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type AdjustStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Delta         int32                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`  // Units to add on hand; negative to remove.
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Required. Recorded in the inventory movement log.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustStockRequest) Reset() {
+	*x = AdjustStockRequest{}
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustStockRequest) ProtoMessage() {}
+
+func (x *AdjustStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_product_v1_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustStockRequest.ProtoReflect.Descriptor instead.
+func (*AdjustStockRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_product_v1_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AdjustStockRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AdjustStockRequest) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *AdjustStockRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
 var File_foundation_proto_product_v1_product_proto protoreflect.FileDescriptor
@@ -226,22 +695,71 @@ var File_foundation_proto_product_v1_product_proto protoreflect.FileDescriptor
 const file_foundation_proto_product_v1_product_proto_rawDesc = "" +
 	"\n" +
 	")foundation/proto/product/v1/product.proto\x12\n" +
-	"product.v1\"\x95\x01\n" +
+	"product.v1\x1a google/protobuf/field_mask.proto\"\xd5\x01\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x04 \x01(\x03R\x05price\x12.\n" +
-	"\x13buyer_reward_points\x18\x05 \x01(\x05R\x11buyerRewardPoints\" \n" +
+	"\x13buyer_reward_points\x18\x05 \x01(\x05R\x11buyerRewardPoints\x12\x1f\n" +
+	"\varchived_at\x18\x06 \x01(\x03R\n" +
+	"archivedAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\" \n" +
 	"\x0eProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcb\x01\n" +
+	"\x13ListProductsRequest\x12\x1b\n" +
+	"\tmin_price\x18\x01 \x01(\x03R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x02 \x01(\x03R\bmaxPrice\x12#\n" +
+	"\rname_contains\x18\x03 \x01(\tR\fnameContains\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"f\n" +
 	"\vProductList\x12/\n" +
-	"\bproducts\x18\x01 \x03(\v2\x13.product.v1.ProductR\bproducts\"\a\n" +
-	"\x05Empty2\x8b\x01\n" +
+	"\bproducts\x18\x01 \x03(\v2\x13.product.v1.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc4\x01\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x03R\x05price\x12.\n" +
+	"\x13buyer_reward_points\x18\x04 \x01(\x05R\x11buyerRewardPoints\x120\n" +
+	"\x14author_reward_points\x18\x05 \x01(\x05R\x12authorRewardPoints\"\x91\x02\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x03R\x05price\x12.\n" +
+	"\x13buyer_reward_points\x18\x05 \x01(\x05R\x11buyerRewardPoints\x120\n" +
+	"\x14author_reward_points\x18\x06 \x01(\x05R\x12authorRewardPoints\x12;\n" +
+	"\vupdate_mask\x18\a \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"'\n" +
+	"\x15ArchiveProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x93\x01\n" +
+	"\x05Stock\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x18\n" +
+	"\atracked\x18\x02 \x01(\bR\atracked\x12\x17\n" +
+	"\aon_hand\x18\x03 \x01(\x05R\x06onHand\x12\x1a\n" +
+	"\breserved\x18\x04 \x01(\x05R\breserved\x12\x1c\n" +
+	"\tavailable\x18\x05 \x01(\x05R\tavailable\"0\n" +
+	"\x0fGetStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"a\n" +
+	"\x12AdjustStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x05R\x05delta\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason2\xf1\x03\n" +
 	"\x0eProductService\x12=\n" +
 	"\n" +
-	"GetProduct\x12\x1a.product.v1.ProductRequest\x1a\x13.product.v1.Product\x12:\n" +
-	"\fListProducts\x12\x11.product.v1.Empty\x1a\x17.product.v1.ProductListB6Z4soda-interview/foundation/proto/product/v1;productv1b\x06proto3"
+	"GetProduct\x12\x1a.product.v1.ProductRequest\x1a\x13.product.v1.Product\x12H\n" +
+	"\fListProducts\x12\x1f.product.v1.ListProductsRequest\x1a\x17.product.v1.ProductList\x12F\n" +
+	"\rCreateProduct\x12 .product.v1.CreateProductRequest\x1a\x13.product.v1.Product\x12F\n" +
+	"\rUpdateProduct\x12 .product.v1.UpdateProductRequest\x1a\x13.product.v1.Product\x12H\n" +
+	"\x0eArchiveProduct\x12!.product.v1.ArchiveProductRequest\x1a\x13.product.v1.Product\x12:\n" +
+	"\bGetStock\x12\x1b.product.v1.GetStockRequest\x1a\x11.product.v1.Stock\x12@\n" +
+	"\vAdjustStock\x12\x1e.product.v1.AdjustStockRequest\x1a\x11.product.v1.StockB6Z4soda-interview/foundation/proto/product/v1;productv1b\x06proto3"
 
 var (
 	file_foundation_proto_product_v1_product_proto_rawDescOnce sync.Once
@@ -255,24 +773,42 @@ func file_foundation_proto_product_v1_product_proto_rawDescGZIP() []byte {
 	return file_foundation_proto_product_v1_product_proto_rawDescData
 }
 
-var file_foundation_proto_product_v1_product_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_foundation_proto_product_v1_product_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_foundation_proto_product_v1_product_proto_goTypes = []any{
-	(*Product)(nil),        // 0: product.v1.Product
-	(*ProductRequest)(nil), // 1: product.v1.ProductRequest
-	(*ProductList)(nil),    // 2: product.v1.ProductList
-	(*Empty)(nil),          // 3: product.v1.Empty
+	(*Product)(nil),               // 0: product.v1.Product
+	(*ProductRequest)(nil),        // 1: product.v1.ProductRequest
+	(*ListProductsRequest)(nil),   // 2: product.v1.ListProductsRequest
+	(*ProductList)(nil),           // 3: product.v1.ProductList
+	(*CreateProductRequest)(nil),  // 4: product.v1.CreateProductRequest
+	(*UpdateProductRequest)(nil),  // 5: product.v1.UpdateProductRequest
+	(*ArchiveProductRequest)(nil), // 6: product.v1.ArchiveProductRequest
+	(*Stock)(nil),                 // 7: product.v1.Stock
+	(*GetStockRequest)(nil),       // 8: product.v1.GetStockRequest
+	(*AdjustStockRequest)(nil),    // 9: product.v1.AdjustStockRequest
+	(*fieldmaskpb.FieldMask)(nil), // 10: google.protobuf.FieldMask
 }
 var file_foundation_proto_product_v1_product_proto_depIdxs = []int32{
-	0, // 0: product.v1.ProductList.products:type_name -> product.v1.Product
-	1, // 1: product.v1.ProductService.GetProduct:input_type -> product.v1.ProductRequest
-	3, // 2: product.v1.ProductService.ListProducts:input_type -> product.v1.Empty
-	0, // 3: product.v1.ProductService.GetProduct:output_type -> product.v1.Product
-	2, // 4: product.v1.ProductService.ListProducts:output_type -> product.v1.ProductList
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: product.v1.ProductList.products:type_name -> product.v1.Product
+	10, // 1: product.v1.UpdateProductRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 2: product.v1.ProductService.GetProduct:input_type -> product.v1.ProductRequest
+	2,  // 3: product.v1.ProductService.ListProducts:input_type -> product.v1.ListProductsRequest
+	4,  // 4: product.v1.ProductService.CreateProduct:input_type -> product.v1.CreateProductRequest
+	5,  // 5: product.v1.ProductService.UpdateProduct:input_type -> product.v1.UpdateProductRequest
+	6,  // 6: product.v1.ProductService.ArchiveProduct:input_type -> product.v1.ArchiveProductRequest
+	8,  // 7: product.v1.ProductService.GetStock:input_type -> product.v1.GetStockRequest
+	9,  // 8: product.v1.ProductService.AdjustStock:input_type -> product.v1.AdjustStockRequest
+	0,  // 9: product.v1.ProductService.GetProduct:output_type -> product.v1.Product
+	3,  // 10: product.v1.ProductService.ListProducts:output_type -> product.v1.ProductList
+	0,  // 11: product.v1.ProductService.CreateProduct:output_type -> product.v1.Product
+	0,  // 12: product.v1.ProductService.UpdateProduct:output_type -> product.v1.Product
+	0,  // 13: product.v1.ProductService.ArchiveProduct:output_type -> product.v1.Product
+	7,  // 14: product.v1.ProductService.GetStock:output_type -> product.v1.Stock
+	7,  // 15: product.v1.ProductService.AdjustStock:output_type -> product.v1.Stock
+	9,  // [9:16] is the sub-list for method output_type
+	2,  // [2:9] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_foundation_proto_product_v1_product_proto_init() }
@@ -286,7 +822,7 @@ func file_foundation_proto_product_v1_product_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_foundation_proto_product_v1_product_proto_rawDesc), len(file_foundation_proto_product_v1_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -9,6 +9,7 @@ package referral_blogv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,8 +28,13 @@ type Blog struct {
 	AuthorId        string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
 	Content         string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	LinkedProductId string                 `protobuf:"bytes,4,opt,name=linked_product_id,json=linkedProductId,proto3" json:"linked_product_id,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// DRAFT, PENDING_REVIEW, PUBLISHED, REJECTED or TAKEN_DOWN. Only
+	// PUBLISHED blogs are listed and earn referral rewards.
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     int64  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	UpdatedAt     int64  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Blog) Reset() {
@@ -89,11 +95,34 @@ func (x *Blog) GetLinkedProductId() string {
 	return ""
 }
 
+func (x *Blog) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Blog) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Blog) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
 type CreateBlogRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AuthorId      string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId  string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Content   string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	ProductId string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// Optional. Keeps the blog as a DRAFT instead of submitting it for review.
+	Draft         bool `protobuf:"varint,4,opt,name=draft,proto3" json:"draft,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -149,6 +178,13 @@ func (x *CreateBlogRequest) GetProductId() string {
 	return ""
 }
 
+func (x *CreateBlogRequest) GetDraft() bool {
+	if x != nil {
+		return x.Draft
+	}
+	return false
+}
+
 type BlogRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -193,16 +229,94 @@ func (x *BlogRequest) GetId() string {
 	return ""
 }
 
+type ListBlogsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId  string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`    // Optional. Only this author's blogs.
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"` // Optional. Only blogs promoting this product.
+	// created_at or "created_at desc" (the default). Ties are sorted by id.
+	OrderBy       string `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token from the previous page, listed with the same order_by.
+	PageSize      int32  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Defaults to 50, at most 200.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlogsRequest) Reset() {
+	*x = ListBlogsRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogsRequest) ProtoMessage() {}
+
+func (x *ListBlogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogsRequest.ProtoReflect.Descriptor instead.
+func (*ListBlogsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBlogsRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListBlogsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListBlogsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListBlogsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListBlogsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
 type BlogList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Blogs         []*Blog                `protobuf:"bytes,1,rep,name=blogs,proto3" json:"blogs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BlogList) Reset() {
 	*x = BlogList{}
-	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[3]
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -214,7 +328,7 @@ func (x *BlogList) String() string {
 func (*BlogList) ProtoMessage() {}
 
 func (x *BlogList) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[3]
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +341,7 @@ func (x *BlogList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlogList.ProtoReflect.Descriptor instead.
 func (*BlogList) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{3}
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *BlogList) GetBlogs() []*Blog {
@@ -237,6 +351,13 @@ func (x *BlogList) GetBlogs() []*Blog {
 	return nil
 }
 
+func (x *BlogList) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -245,7 +366,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[4]
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -257,7 +378,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[4]
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -270,34 +391,1038 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{4}
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{5}
 }
 
-var File_foundation_proto_referral_blog_v1_referral_blog_proto protoreflect.FileDescriptor
+type UpdateBlogRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Content  string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// DRAFT to take the blog back, PENDING_REVIEW to submit it.
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Required. Paths name the fields of this request to apply: "content",
+	// "status".
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDesc = "" +
-	"\n" +
-	"5foundation/proto/referral-blog/v1/referral_blog.proto\x12\x10referral_blog.v1\"y\n" +
-	"\x04Blog\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
-	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\x12*\n" +
-	"\x11linked_product_id\x18\x04 \x01(\tR\x0flinkedProductId\"i\n" +
-	"\x11CreateBlogRequest\x12\x1b\n" +
-	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x03 \x01(\tR\tproductId\"\x1d\n" +
-	"\vBlogRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
-	"\bBlogList\x12,\n" +
-	"\x05blogs\x18\x01 \x03(\v2\x16.referral_blog.v1.BlogR\x05blogs\"\a\n" +
-	"\x05Empty2\xdc\x01\n" +
-	"\vBlogService\x12I\n" +
+func (x *UpdateBlogRequest) Reset() {
+	*x = UpdateBlogRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBlogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBlogRequest) ProtoMessage() {}
+
+func (x *UpdateBlogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBlogRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBlogRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateBlogRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateBlogRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *UpdateBlogRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateBlogRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateBlogRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteBlogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBlogRequest) Reset() {
+	*x = DeleteBlogRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBlogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBlogRequest) ProtoMessage() {}
+
+func (x *DeleteBlogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBlogRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBlogRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteBlogRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteBlogRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+type ModerateBlogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // PUBLISHED, REJECTED or TAKEN_DOWN
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Shown to the author. Required unless publishing.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateBlogRequest) Reset() {
+	*x = ModerateBlogRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateBlogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateBlogRequest) ProtoMessage() {}
+
+func (x *ModerateBlogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateBlogRequest.ProtoReflect.Descriptor instead.
+func (*ModerateBlogRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ModerateBlogRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModerateBlogRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ModerateBlogRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type BlogRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BlogId        string                 `protobuf:"bytes,2,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`   // CREATED, EDITED, MODERATED or DELETED
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"` // Content after the change.
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`   // Status after the change.
+	ActorId       string                 `protobuf:"bytes,6,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Note          string                 `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`                             // The moderator's reason, if any.
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlogRevision) Reset() {
+	*x = BlogRevision{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlogRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlogRevision) ProtoMessage() {}
+
+func (x *BlogRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlogRevision.ProtoReflect.Descriptor instead.
+func (*BlogRevision) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BlogRevision) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BlogRevision) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ListBlogRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlogId        string                 `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlogRevisionsRequest) Reset() {
+	*x = ListBlogRevisionsRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlogRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogRevisionsRequest) ProtoMessage() {}
+
+func (x *ListBlogRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListBlogRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListBlogRevisionsRequest) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+type ListBlogRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*BlogRevision        `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // Oldest first.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlogRevisionsResponse) Reset() {
+	*x = ListBlogRevisionsResponse{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlogRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogRevisionsResponse) ProtoMessage() {}
+
+func (x *ListBlogRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListBlogRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListBlogRevisionsResponse) GetRevisions() []*BlogRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type ReferralAnalyticsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional. Defaults to the caller. Admins may leave it empty to cover
+	// every author.
+	AuthorId      string `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	BlogId        string `protobuf:"bytes,2,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"` // Optional. Only this blog.
+	From          int64  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                  // Optional. Unix timestamp, inclusive. Defaults to 30 days before to.
+	To            int64  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                      // Optional. Unix timestamp, exclusive. Defaults to now. At most 366 days after from.
+	Granularity   string `protobuf:"bytes,5,opt,name=granularity,proto3" json:"granularity,omitempty"`     // DAY (default) or WEEK. Buckets are UTC; weeks start on Monday.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferralAnalyticsRequest) Reset() {
+	*x = ReferralAnalyticsRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferralAnalyticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferralAnalyticsRequest) ProtoMessage() {}
+
+func (x *ReferralAnalyticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferralAnalyticsRequest.ProtoReflect.Descriptor instead.
+func (*ReferralAnalyticsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReferralAnalyticsRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ReferralAnalyticsRequest) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *ReferralAnalyticsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ReferralAnalyticsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ReferralAnalyticsRequest) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+// ReferralStats sums up referred sales. Only orders that still stand count:
+// confirmed, shipped or delivered.
+type ReferralStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        int64                  `protobuf:"varint,1,opt,name=orders,proto3" json:"orders,omitempty"`   // Referred orders, i.e. conversions.
+	Revenue       int64                  `protobuf:"varint,2,opt,name=revenue,proto3" json:"revenue,omitempty"` // Yen, of the referred order lines only.
+	Points        int64                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`   // Author reward points earned.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferralStats) Reset() {
+	*x = ReferralStats{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferralStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferralStats) ProtoMessage() {}
+
+func (x *ReferralStats) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferralStats.ProtoReflect.Descriptor instead.
+func (*ReferralStats) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReferralStats) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+func (x *ReferralStats) GetRevenue() int64 {
+	if x != nil {
+		return x.Revenue
+	}
+	return 0
+}
+
+func (x *ReferralStats) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+type BlogReferralStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlogId        string                 `protobuf:"bytes,1,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Stats         *ReferralStats         `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlogReferralStats) Reset() {
+	*x = BlogReferralStats{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlogReferralStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlogReferralStats) ProtoMessage() {}
+
+func (x *BlogReferralStats) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlogReferralStats.ProtoReflect.Descriptor instead.
+func (*BlogReferralStats) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BlogReferralStats) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *BlogReferralStats) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *BlogReferralStats) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *BlogReferralStats) GetStats() *ReferralStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type AuthorReferralStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId      string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Blogs         int64                  `protobuf:"varint,2,opt,name=blogs,proto3" json:"blogs,omitempty"` // Blogs with at least one referred order.
+	Stats         *ReferralStats         `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorReferralStats) Reset() {
+	*x = AuthorReferralStats{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorReferralStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorReferralStats) ProtoMessage() {}
+
+func (x *AuthorReferralStats) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorReferralStats.ProtoReflect.Descriptor instead.
+func (*AuthorReferralStats) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AuthorReferralStats) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *AuthorReferralStats) GetBlogs() int64 {
+	if x != nil {
+		return x.Blogs
+	}
+	return 0
+}
+
+func (x *AuthorReferralStats) GetStats() *ReferralStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type ReferralBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"` // Unix timestamp
+	Stats         *ReferralStats         `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferralBucket) Reset() {
+	*x = ReferralBucket{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferralBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferralBucket) ProtoMessage() {}
+
+func (x *ReferralBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferralBucket.ProtoReflect.Descriptor instead.
+func (*ReferralBucket) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReferralBucket) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ReferralBucket) GetStats() *ReferralStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type ReferralAnalyticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Granularity   string                 `protobuf:"bytes,3,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	Totals        *ReferralStats         `protobuf:"bytes,4,opt,name=totals,proto3" json:"totals,omitempty"`
+	Blogs         []*BlogReferralStats   `protobuf:"bytes,5,rep,name=blogs,proto3" json:"blogs,omitempty"`     // Highest revenue first.
+	Authors       []*AuthorReferralStats `protobuf:"bytes,6,rep,name=authors,proto3" json:"authors,omitempty"` // Highest revenue first.
+	Series        []*ReferralBucket      `protobuf:"bytes,7,rep,name=series,proto3" json:"series,omitempty"`   // Every bucket in range, empty ones included, oldest first.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferralAnalyticsResponse) Reset() {
+	*x = ReferralAnalyticsResponse{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferralAnalyticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferralAnalyticsResponse) ProtoMessage() {}
+
+func (x *ReferralAnalyticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferralAnalyticsResponse.ProtoReflect.Descriptor instead.
+func (*ReferralAnalyticsResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReferralAnalyticsResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ReferralAnalyticsResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ReferralAnalyticsResponse) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *ReferralAnalyticsResponse) GetTotals() *ReferralStats {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+func (x *ReferralAnalyticsResponse) GetBlogs() []*BlogReferralStats {
+	if x != nil {
+		return x.Blogs
+	}
+	return nil
+}
+
+func (x *ReferralAnalyticsResponse) GetAuthors() []*AuthorReferralStats {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *ReferralAnalyticsResponse) GetSeries() []*ReferralBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type RecordReferralVisitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VisitorId     string                 `protobuf:"bytes,1,opt,name=visitor_id,json=visitorId,proto3" json:"visitor_id,omitempty"` // Defaults to the caller.
+	BlogId        string                 `protobuf:"bytes,2,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordReferralVisitRequest) Reset() {
+	*x = RecordReferralVisitRequest{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordReferralVisitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordReferralVisitRequest) ProtoMessage() {}
+
+func (x *RecordReferralVisitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordReferralVisitRequest.ProtoReflect.Descriptor instead.
+func (*RecordReferralVisitRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RecordReferralVisitRequest) GetVisitorId() string {
+	if x != nil {
+		return x.VisitorId
+	}
+	return ""
+}
+
+func (x *RecordReferralVisitRequest) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+type ReferralVisit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	VisitorId     string                 `protobuf:"bytes,2,opt,name=visitor_id,json=visitorId,proto3" json:"visitor_id,omitempty"`
+	BlogId        string                 `protobuf:"bytes,3,opt,name=blog_id,json=blogId,proto3" json:"blog_id,omitempty"`
+	VisitedAt     int64                  `protobuf:"varint,4,opt,name=visited_at,json=visitedAt,proto3" json:"visited_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferralVisit) Reset() {
+	*x = ReferralVisit{}
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferralVisit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferralVisit) ProtoMessage() {}
+
+func (x *ReferralVisit) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferralVisit.ProtoReflect.Descriptor instead.
+func (*ReferralVisit) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReferralVisit) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReferralVisit) GetVisitorId() string {
+	if x != nil {
+		return x.VisitorId
+	}
+	return ""
+}
+
+func (x *ReferralVisit) GetBlogId() string {
+	if x != nil {
+		return x.BlogId
+	}
+	return ""
+}
+
+func (x *ReferralVisit) GetVisitedAt() int64 {
+	if x != nil {
+		return x.VisitedAt
+	}
+	return 0
+}
+
+var File_foundation_proto_referral_blog_v1_referral_blog_proto protoreflect.FileDescriptor
+
+const file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDesc = "" +
+	"\n" +
+	"5foundation/proto/referral-blog/v1/referral_blog.proto\x12\x10referral_blog.v1\x1a google/protobuf/field_mask.proto\"\xcf\x01\n" +
+	"\x04Blog\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12*\n" +
+	"\x11linked_product_id\x18\x04 \x01(\tR\x0flinkedProductId\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\x03R\tupdatedAt\"\x7f\n" +
+	"\x11CreateBlogRequest\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05draft\x18\x04 \x01(\bR\x05draft\"\x1d\n" +
+	"\vBlogRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa5\x01\n" +
+	"\x10ListBlogsRequest\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
+	"\border_by\x18\x03 \x01(\tR\aorderBy\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"`\n" +
+	"\bBlogList\x12,\n" +
+	"\x05blogs\x18\x01 \x03(\v2\x16.referral_blog.v1.BlogR\x05blogs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\a\n" +
+	"\x05Empty\"\xaf\x01\n" +
+	"\x11UpdateBlogRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12;\n" +
+	"\vupdate_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"@\n" +
+	"\x11DeleteBlogRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\"U\n" +
+	"\x13ModerateBlogRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xcf\x01\n" +
+	"\fBlogRevision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\ablog_id\x18\x02 \x01(\tR\x06blogId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x19\n" +
+	"\bactor_id\x18\x06 \x01(\tR\aactorId\x12\x12\n" +
+	"\x04note\x18\a \x01(\tR\x04note\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\"3\n" +
+	"\x18ListBlogRevisionsRequest\x12\x17\n" +
+	"\ablog_id\x18\x01 \x01(\tR\x06blogId\"Y\n" +
+	"\x19ListBlogRevisionsResponse\x12<\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1e.referral_blog.v1.BlogRevisionR\trevisions\"\x96\x01\n" +
+	"\x18ReferralAnalyticsRequest\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x17\n" +
+	"\ablog_id\x18\x02 \x01(\tR\x06blogId\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12 \n" +
+	"\vgranularity\x18\x05 \x01(\tR\vgranularity\"Y\n" +
+	"\rReferralStats\x12\x16\n" +
+	"\x06orders\x18\x01 \x01(\x03R\x06orders\x12\x18\n" +
+	"\arevenue\x18\x02 \x01(\x03R\arevenue\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x03R\x06points\"\x9f\x01\n" +
+	"\x11BlogReferralStats\x12\x17\n" +
+	"\ablog_id\x18\x01 \x01(\tR\x06blogId\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x125\n" +
+	"\x05stats\x18\x04 \x01(\v2\x1f.referral_blog.v1.ReferralStatsR\x05stats\"\x7f\n" +
+	"\x13AuthorReferralStats\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x14\n" +
+	"\x05blogs\x18\x02 \x01(\x03R\x05blogs\x125\n" +
+	"\x05stats\x18\x03 \x01(\v2\x1f.referral_blog.v1.ReferralStatsR\x05stats\"]\n" +
+	"\x0eReferralBucket\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x125\n" +
+	"\x05stats\x18\x02 \x01(\v2\x1f.referral_blog.v1.ReferralStatsR\x05stats\"\xd0\x02\n" +
+	"\x19ReferralAnalyticsResponse\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\x12 \n" +
+	"\vgranularity\x18\x03 \x01(\tR\vgranularity\x127\n" +
+	"\x06totals\x18\x04 \x01(\v2\x1f.referral_blog.v1.ReferralStatsR\x06totals\x129\n" +
+	"\x05blogs\x18\x05 \x03(\v2#.referral_blog.v1.BlogReferralStatsR\x05blogs\x12?\n" +
+	"\aauthors\x18\x06 \x03(\v2%.referral_blog.v1.AuthorReferralStatsR\aauthors\x128\n" +
+	"\x06series\x18\a \x03(\v2 .referral_blog.v1.ReferralBucketR\x06series\"T\n" +
+	"\x1aRecordReferralVisitRequest\x12\x1d\n" +
+	"\n" +
+	"visitor_id\x18\x01 \x01(\tR\tvisitorId\x12\x17\n" +
+	"\ablog_id\x18\x02 \x01(\tR\x06blogId\"v\n" +
+	"\rReferralVisit\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"visitor_id\x18\x02 \x01(\tR\tvisitorId\x12\x17\n" +
+	"\ablog_id\x18\x03 \x01(\tR\x06blogId\x12\x1d\n" +
+	"\n" +
+	"visited_at\x18\x04 \x01(\x03R\tvisitedAt2\x8f\x06\n" +
+	"\vBlogService\x12I\n" +
+	"\n" +
+	"CreateBlog\x12#.referral_blog.v1.CreateBlogRequest\x1a\x16.referral_blog.v1.Blog\x12K\n" +
+	"\tListBlogs\x12\".referral_blog.v1.ListBlogsRequest\x1a\x1a.referral_blog.v1.BlogList\x12@\n" +
+	"\aGetBlog\x12\x1d.referral_blog.v1.BlogRequest\x1a\x16.referral_blog.v1.Blog\x12I\n" +
+	"\n" +
+	"UpdateBlog\x12#.referral_blog.v1.UpdateBlogRequest\x1a\x16.referral_blog.v1.Blog\x12J\n" +
 	"\n" +
-	"CreateBlog\x12#.referral_blog.v1.CreateBlogRequest\x1a\x16.referral_blog.v1.Blog\x12@\n" +
-	"\tListBlogs\x12\x17.referral_blog.v1.Empty\x1a\x1a.referral_blog.v1.BlogList\x12@\n" +
-	"\aGetBlog\x12\x1d.referral_blog.v1.BlogRequest\x1a\x16.referral_blog.v1.BlogBBZ@soda-interview/foundation/proto/referral-blog/v1;referral_blogv1b\x06proto3"
+	"DeleteBlog\x12#.referral_blog.v1.DeleteBlogRequest\x1a\x17.referral_blog.v1.Empty\x12M\n" +
+	"\fModerateBlog\x12%.referral_blog.v1.ModerateBlogRequest\x1a\x16.referral_blog.v1.Blog\x12l\n" +
+	"\x11ListBlogRevisions\x12*.referral_blog.v1.ListBlogRevisionsRequest\x1a+.referral_blog.v1.ListBlogRevisionsResponse\x12l\n" +
+	"\x11ReferralAnalytics\x12*.referral_blog.v1.ReferralAnalyticsRequest\x1a+.referral_blog.v1.ReferralAnalyticsResponse\x12d\n" +
+	"\x13RecordReferralVisit\x12,.referral_blog.v1.RecordReferralVisitRequest\x1a\x1f.referral_blog.v1.ReferralVisitBBZ@soda-interview/foundation/proto/referral-blog/v1;referral_blogv1b\x06proto3"
 
 var (
 	file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescOnce sync.Once
@@ -311,27 +1436,64 @@ func file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescGZIP() []
 	return file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDescData
 }
 
-var file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_foundation_proto_referral_blog_v1_referral_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_foundation_proto_referral_blog_v1_referral_blog_proto_goTypes = []any{
-	(*Blog)(nil),              // 0: referral_blog.v1.Blog
-	(*CreateBlogRequest)(nil), // 1: referral_blog.v1.CreateBlogRequest
-	(*BlogRequest)(nil),       // 2: referral_blog.v1.BlogRequest
-	(*BlogList)(nil),          // 3: referral_blog.v1.BlogList
-	(*Empty)(nil),             // 4: referral_blog.v1.Empty
+	(*Blog)(nil),                       // 0: referral_blog.v1.Blog
+	(*CreateBlogRequest)(nil),          // 1: referral_blog.v1.CreateBlogRequest
+	(*BlogRequest)(nil),                // 2: referral_blog.v1.BlogRequest
+	(*ListBlogsRequest)(nil),           // 3: referral_blog.v1.ListBlogsRequest
+	(*BlogList)(nil),                   // 4: referral_blog.v1.BlogList
+	(*Empty)(nil),                      // 5: referral_blog.v1.Empty
+	(*UpdateBlogRequest)(nil),          // 6: referral_blog.v1.UpdateBlogRequest
+	(*DeleteBlogRequest)(nil),          // 7: referral_blog.v1.DeleteBlogRequest
+	(*ModerateBlogRequest)(nil),        // 8: referral_blog.v1.ModerateBlogRequest
+	(*BlogRevision)(nil),               // 9: referral_blog.v1.BlogRevision
+	(*ListBlogRevisionsRequest)(nil),   // 10: referral_blog.v1.ListBlogRevisionsRequest
+	(*ListBlogRevisionsResponse)(nil),  // 11: referral_blog.v1.ListBlogRevisionsResponse
+	(*ReferralAnalyticsRequest)(nil),   // 12: referral_blog.v1.ReferralAnalyticsRequest
+	(*ReferralStats)(nil),              // 13: referral_blog.v1.ReferralStats
+	(*BlogReferralStats)(nil),          // 14: referral_blog.v1.BlogReferralStats
+	(*AuthorReferralStats)(nil),        // 15: referral_blog.v1.AuthorReferralStats
+	(*ReferralBucket)(nil),             // 16: referral_blog.v1.ReferralBucket
+	(*ReferralAnalyticsResponse)(nil),  // 17: referral_blog.v1.ReferralAnalyticsResponse
+	(*RecordReferralVisitRequest)(nil), // 18: referral_blog.v1.RecordReferralVisitRequest
+	(*ReferralVisit)(nil),              // 19: referral_blog.v1.ReferralVisit
+	(*fieldmaskpb.FieldMask)(nil),      // 20: google.protobuf.FieldMask
 }
 var file_foundation_proto_referral_blog_v1_referral_blog_proto_depIdxs = []int32{
-	0, // 0: referral_blog.v1.BlogList.blogs:type_name -> referral_blog.v1.Blog
-	1, // 1: referral_blog.v1.BlogService.CreateBlog:input_type -> referral_blog.v1.CreateBlogRequest
-	4, // 2: referral_blog.v1.BlogService.ListBlogs:input_type -> referral_blog.v1.Empty
-	2, // 3: referral_blog.v1.BlogService.GetBlog:input_type -> referral_blog.v1.BlogRequest
-	0, // 4: referral_blog.v1.BlogService.CreateBlog:output_type -> referral_blog.v1.Blog
-	3, // 5: referral_blog.v1.BlogService.ListBlogs:output_type -> referral_blog.v1.BlogList
-	0, // 6: referral_blog.v1.BlogService.GetBlog:output_type -> referral_blog.v1.Blog
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: referral_blog.v1.BlogList.blogs:type_name -> referral_blog.v1.Blog
+	20, // 1: referral_blog.v1.UpdateBlogRequest.update_mask:type_name -> google.protobuf.FieldMask
+	9,  // 2: referral_blog.v1.ListBlogRevisionsResponse.revisions:type_name -> referral_blog.v1.BlogRevision
+	13, // 3: referral_blog.v1.BlogReferralStats.stats:type_name -> referral_blog.v1.ReferralStats
+	13, // 4: referral_blog.v1.AuthorReferralStats.stats:type_name -> referral_blog.v1.ReferralStats
+	13, // 5: referral_blog.v1.ReferralBucket.stats:type_name -> referral_blog.v1.ReferralStats
+	13, // 6: referral_blog.v1.ReferralAnalyticsResponse.totals:type_name -> referral_blog.v1.ReferralStats
+	14, // 7: referral_blog.v1.ReferralAnalyticsResponse.blogs:type_name -> referral_blog.v1.BlogReferralStats
+	15, // 8: referral_blog.v1.ReferralAnalyticsResponse.authors:type_name -> referral_blog.v1.AuthorReferralStats
+	16, // 9: referral_blog.v1.ReferralAnalyticsResponse.series:type_name -> referral_blog.v1.ReferralBucket
+	1,  // 10: referral_blog.v1.BlogService.CreateBlog:input_type -> referral_blog.v1.CreateBlogRequest
+	3,  // 11: referral_blog.v1.BlogService.ListBlogs:input_type -> referral_blog.v1.ListBlogsRequest
+	2,  // 12: referral_blog.v1.BlogService.GetBlog:input_type -> referral_blog.v1.BlogRequest
+	6,  // 13: referral_blog.v1.BlogService.UpdateBlog:input_type -> referral_blog.v1.UpdateBlogRequest
+	7,  // 14: referral_blog.v1.BlogService.DeleteBlog:input_type -> referral_blog.v1.DeleteBlogRequest
+	8,  // 15: referral_blog.v1.BlogService.ModerateBlog:input_type -> referral_blog.v1.ModerateBlogRequest
+	10, // 16: referral_blog.v1.BlogService.ListBlogRevisions:input_type -> referral_blog.v1.ListBlogRevisionsRequest
+	12, // 17: referral_blog.v1.BlogService.ReferralAnalytics:input_type -> referral_blog.v1.ReferralAnalyticsRequest
+	18, // 18: referral_blog.v1.BlogService.RecordReferralVisit:input_type -> referral_blog.v1.RecordReferralVisitRequest
+	0,  // 19: referral_blog.v1.BlogService.CreateBlog:output_type -> referral_blog.v1.Blog
+	4,  // 20: referral_blog.v1.BlogService.ListBlogs:output_type -> referral_blog.v1.BlogList
+	0,  // 21: referral_blog.v1.BlogService.GetBlog:output_type -> referral_blog.v1.Blog
+	0,  // 22: referral_blog.v1.BlogService.UpdateBlog:output_type -> referral_blog.v1.Blog
+	5,  // 23: referral_blog.v1.BlogService.DeleteBlog:output_type -> referral_blog.v1.Empty
+	0,  // 24: referral_blog.v1.BlogService.ModerateBlog:output_type -> referral_blog.v1.Blog
+	11, // 25: referral_blog.v1.BlogService.ListBlogRevisions:output_type -> referral_blog.v1.ListBlogRevisionsResponse
+	17, // 26: referral_blog.v1.BlogService.ReferralAnalytics:output_type -> referral_blog.v1.ReferralAnalyticsResponse
+	19, // 27: referral_blog.v1.BlogService.RecordReferralVisit:output_type -> referral_blog.v1.ReferralVisit
+	19, // [19:28] is the sub-list for method output_type
+	10, // [10:19] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_foundation_proto_referral_blog_v1_referral_blog_proto_init() }
@@ -345,7 +1507,7 @@ func file_foundation_proto_referral_blog_v1_referral_blog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDesc), len(file_foundation_proto_referral_blog_v1_referral_blog_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
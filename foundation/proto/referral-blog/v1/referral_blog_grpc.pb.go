@@ -19,9 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BlogService_CreateBlog_FullMethodName = "/referral_blog.v1.BlogService/CreateBlog"
-	BlogService_ListBlogs_FullMethodName  = "/referral_blog.v1.BlogService/ListBlogs"
-	BlogService_GetBlog_FullMethodName    = "/referral_blog.v1.BlogService/GetBlog"
+	BlogService_CreateBlog_FullMethodName          = "/referral_blog.v1.BlogService/CreateBlog"
+	BlogService_ListBlogs_FullMethodName           = "/referral_blog.v1.BlogService/ListBlogs"
+	BlogService_GetBlog_FullMethodName             = "/referral_blog.v1.BlogService/GetBlog"
+	BlogService_UpdateBlog_FullMethodName          = "/referral_blog.v1.BlogService/UpdateBlog"
+	BlogService_DeleteBlog_FullMethodName          = "/referral_blog.v1.BlogService/DeleteBlog"
+	BlogService_ModerateBlog_FullMethodName        = "/referral_blog.v1.BlogService/ModerateBlog"
+	BlogService_ListBlogRevisions_FullMethodName   = "/referral_blog.v1.BlogService/ListBlogRevisions"
+	BlogService_ReferralAnalytics_FullMethodName   = "/referral_blog.v1.BlogService/ReferralAnalytics"
+	BlogService_RecordReferralVisit_FullMethodName = "/referral_blog.v1.BlogService/RecordReferralVisit"
 )
 
 // BlogServiceClient is the client API for BlogService service.
@@ -29,8 +35,27 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BlogServiceClient interface {
 	CreateBlog(ctx context.Context, in *CreateBlogRequest, opts ...grpc.CallOption) (*Blog, error)
-	ListBlogs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlogList, error)
+	// ListBlogs pages through the published blogs.
+	ListBlogs(ctx context.Context, in *ListBlogsRequest, opts ...grpc.CallOption) (*BlogList, error)
+	// GetBlog returns a published blog. Other blogs are only visible to their
+	// author and admins.
 	GetBlog(ctx context.Context, in *BlogRequest, opts ...grpc.CallOption) (*Blog, error)
+	// UpdateBlog edits a blog or moves it between DRAFT and PENDING_REVIEW.
+	// Author only. Editing a blog outside DRAFT sends it back to review.
+	UpdateBlog(ctx context.Context, in *UpdateBlogRequest, opts ...grpc.CallOption) (*Blog, error)
+	// DeleteBlog removes a blog. Author only. Orders it referred are kept.
+	DeleteBlog(ctx context.Context, in *DeleteBlogRequest, opts ...grpc.CallOption) (*Empty, error)
+	// ModerateBlog publishes, rejects or takes down a blog. Admin only.
+	ModerateBlog(ctx context.Context, in *ModerateBlogRequest, opts ...grpc.CallOption) (*Blog, error)
+	// ListBlogRevisions returns a blog's history. Author and admins only.
+	ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsRequest, opts ...grpc.CallOption) (*ListBlogRevisionsResponse, error)
+	// ReferralAnalytics reports how an author's referrals perform: orders,
+	// revenue and points per blog, per author and per day or week.
+	ReferralAnalytics(ctx context.Context, in *ReferralAnalyticsRequest, opts ...grpc.CallOption) (*ReferralAnalyticsResponse, error)
+	// RecordReferralVisit logs that a user viewed a published blog. Orders
+	// they place for its product without a blog_id are credited to the last
+	// blog they visited within the attribution window.
+	RecordReferralVisit(ctx context.Context, in *RecordReferralVisitRequest, opts ...grpc.CallOption) (*ReferralVisit, error)
 }
 
 type blogServiceClient struct {
@@ -51,7 +76,7 @@ func (c *blogServiceClient) CreateBlog(ctx context.Context, in *CreateBlogReques
 	return out, nil
 }
 
-func (c *blogServiceClient) ListBlogs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlogList, error) {
+func (c *blogServiceClient) ListBlogs(ctx context.Context, in *ListBlogsRequest, opts ...grpc.CallOption) (*BlogList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BlogList)
 	err := c.cc.Invoke(ctx, BlogService_ListBlogs_FullMethodName, in, out, cOpts...)
@@ -71,13 +96,92 @@ func (c *blogServiceClient) GetBlog(ctx context.Context, in *BlogRequest, opts .
 	return out, nil
 }
 
+func (c *blogServiceClient) UpdateBlog(ctx context.Context, in *UpdateBlogRequest, opts ...grpc.CallOption) (*Blog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Blog)
+	err := c.cc.Invoke(ctx, BlogService_UpdateBlog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) DeleteBlog(ctx context.Context, in *DeleteBlogRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, BlogService_DeleteBlog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ModerateBlog(ctx context.Context, in *ModerateBlogRequest, opts ...grpc.CallOption) (*Blog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Blog)
+	err := c.cc.Invoke(ctx, BlogService_ModerateBlog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsRequest, opts ...grpc.CallOption) (*ListBlogRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlogRevisionsResponse)
+	err := c.cc.Invoke(ctx, BlogService_ListBlogRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ReferralAnalytics(ctx context.Context, in *ReferralAnalyticsRequest, opts ...grpc.CallOption) (*ReferralAnalyticsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReferralAnalyticsResponse)
+	err := c.cc.Invoke(ctx, BlogService_ReferralAnalytics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) RecordReferralVisit(ctx context.Context, in *RecordReferralVisitRequest, opts ...grpc.CallOption) (*ReferralVisit, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReferralVisit)
+	err := c.cc.Invoke(ctx, BlogService_RecordReferralVisit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlogServiceServer is the server API for BlogService service.
 // All implementations must embed UnimplementedBlogServiceServer
 // for forward compatibility.
 type BlogServiceServer interface {
 	CreateBlog(context.Context, *CreateBlogRequest) (*Blog, error)
-	ListBlogs(context.Context, *Empty) (*BlogList, error)
+	// ListBlogs pages through the published blogs.
+	ListBlogs(context.Context, *ListBlogsRequest) (*BlogList, error)
+	// GetBlog returns a published blog. Other blogs are only visible to their
+	// author and admins.
 	GetBlog(context.Context, *BlogRequest) (*Blog, error)
+	// UpdateBlog edits a blog or moves it between DRAFT and PENDING_REVIEW.
+	// Author only. Editing a blog outside DRAFT sends it back to review.
+	UpdateBlog(context.Context, *UpdateBlogRequest) (*Blog, error)
+	// DeleteBlog removes a blog. Author only. Orders it referred are kept.
+	DeleteBlog(context.Context, *DeleteBlogRequest) (*Empty, error)
+	// ModerateBlog publishes, rejects or takes down a blog. Admin only.
+	ModerateBlog(context.Context, *ModerateBlogRequest) (*Blog, error)
+	// ListBlogRevisions returns a blog's history. Author and admins only.
+	ListBlogRevisions(context.Context, *ListBlogRevisionsRequest) (*ListBlogRevisionsResponse, error)
+	// ReferralAnalytics reports how an author's referrals perform: orders,
+	// revenue and points per blog, per author and per day or week.
+	ReferralAnalytics(context.Context, *ReferralAnalyticsRequest) (*ReferralAnalyticsResponse, error)
+	// RecordReferralVisit logs that a user viewed a published blog. Orders
+	// they place for its product without a blog_id are credited to the last
+	// blog they visited within the attribution window.
+	RecordReferralVisit(context.Context, *RecordReferralVisitRequest) (*ReferralVisit, error)
 	mustEmbedUnimplementedBlogServiceServer()
 }
 
@@ -91,12 +195,30 @@ type UnimplementedBlogServiceServer struct{}
 func (UnimplementedBlogServiceServer) CreateBlog(context.Context, *CreateBlogRequest) (*Blog, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateBlog not implemented")
 }
-func (UnimplementedBlogServiceServer) ListBlogs(context.Context, *Empty) (*BlogList, error) {
+func (UnimplementedBlogServiceServer) ListBlogs(context.Context, *ListBlogsRequest) (*BlogList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListBlogs not implemented")
 }
 func (UnimplementedBlogServiceServer) GetBlog(context.Context, *BlogRequest) (*Blog, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlog not implemented")
 }
+func (UnimplementedBlogServiceServer) UpdateBlog(context.Context, *UpdateBlogRequest) (*Blog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBlog not implemented")
+}
+func (UnimplementedBlogServiceServer) DeleteBlog(context.Context, *DeleteBlogRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBlog not implemented")
+}
+func (UnimplementedBlogServiceServer) ModerateBlog(context.Context, *ModerateBlogRequest) (*Blog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateBlog not implemented")
+}
+func (UnimplementedBlogServiceServer) ListBlogRevisions(context.Context, *ListBlogRevisionsRequest) (*ListBlogRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlogRevisions not implemented")
+}
+func (UnimplementedBlogServiceServer) ReferralAnalytics(context.Context, *ReferralAnalyticsRequest) (*ReferralAnalyticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReferralAnalytics not implemented")
+}
+func (UnimplementedBlogServiceServer) RecordReferralVisit(context.Context, *RecordReferralVisitRequest) (*ReferralVisit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordReferralVisit not implemented")
+}
 func (UnimplementedBlogServiceServer) mustEmbedUnimplementedBlogServiceServer() {}
 func (UnimplementedBlogServiceServer) testEmbeddedByValue()                     {}
 
@@ -137,7 +259,7 @@ func _BlogService_CreateBlog_Handler(srv interface{}, ctx context.Context, dec f
 }
 
 func _BlogService_ListBlogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(ListBlogsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -149,7 +271,7 @@ func _BlogService_ListBlogs_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: BlogService_ListBlogs_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BlogServiceServer).ListBlogs(ctx, req.(*Empty))
+		return srv.(BlogServiceServer).ListBlogs(ctx, req.(*ListBlogsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -172,6 +294,114 @@ func _BlogService_GetBlog_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlogService_UpdateBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBlogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).UpdateBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_UpdateBlog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).UpdateBlog(ctx, req.(*UpdateBlogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_DeleteBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBlogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).DeleteBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_DeleteBlog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).DeleteBlog(ctx, req.(*DeleteBlogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_ModerateBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateBlogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).ModerateBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_ModerateBlog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).ModerateBlog(ctx, req.(*ModerateBlogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_ListBlogRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlogRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).ListBlogRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_ListBlogRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).ListBlogRevisions(ctx, req.(*ListBlogRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_ReferralAnalytics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReferralAnalyticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).ReferralAnalytics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_ReferralAnalytics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).ReferralAnalytics(ctx, req.(*ReferralAnalyticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_RecordReferralVisit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordReferralVisitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).RecordReferralVisit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlogService_RecordReferralVisit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).RecordReferralVisit(ctx, req.(*RecordReferralVisitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BlogService_ServiceDesc is the grpc.ServiceDesc for BlogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +421,30 @@ var BlogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlog",
 			Handler:    _BlogService_GetBlog_Handler,
 		},
+		{
+			MethodName: "UpdateBlog",
+			Handler:    _BlogService_UpdateBlog_Handler,
+		},
+		{
+			MethodName: "DeleteBlog",
+			Handler:    _BlogService_DeleteBlog_Handler,
+		},
+		{
+			MethodName: "ModerateBlog",
+			Handler:    _BlogService_ModerateBlog_Handler,
+		},
+		{
+			MethodName: "ListBlogRevisions",
+			Handler:    _BlogService_ListBlogRevisions_Handler,
+		},
+		{
+			MethodName: "ReferralAnalytics",
+			Handler:    _BlogService_ReferralAnalytics_Handler,
+		},
+		{
+			MethodName: "RecordReferralVisit",
+			Handler:    _BlogService_RecordReferralVisit_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "foundation/proto/referral-blog/v1/referral_blog.proto",
@@ -135,8 +135,11 @@ type ConvertRequest struct {
 	// It doesn't say "convert X points". Usually it's "convert all eligible" or a specific amount.
 	// I'll add `amount_points` to be safe, but make it optional logic-wise.
 	PointsToConvert int64 `protobuf:"varint,2,opt,name=points_to_convert,json=pointsToConvert,proto3" json:"points_to_convert,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Optional. Retrying with the same key returns the original wallet instead
+	// of converting again. Keys are scoped to user_id and kept for 24 hours.
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ConvertRequest) Reset() {
@@ -183,6 +186,895 @@ func (x *ConvertRequest) GetPointsToConvert() int64 {
 	return 0
 }
 
+func (x *ConvertRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type Transaction struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`                                                  // EARNED, CONVERTED, EXPIRED, SPENT, SPENT_REFUND, CLAWBACK, ...
+	Amount         int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`                                             // Always positive; type gives the direction.
+	RelatedOrderId string                 `protobuf:"bytes,5,opt,name=related_order_id,json=relatedOrderId,proto3" json:"related_order_id,omitempty"`      // Empty when not tied to an order.
+	CreatedAt      int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                      // Unix timestamp
+	PolicyVersion  int64                  `protobuf:"varint,7,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`          // Conversion policy applied to CONVERTED entries, 0 otherwise.
+	RewardRuleIds  []int64                `protobuf:"varint,8,rep,packed,name=reward_rule_ids,json=rewardRuleIds,proto3" json:"reward_rule_ids,omitempty"` // Reward rules that added to EARNED entries.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transaction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transaction) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transaction) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetRelatedOrderId() string {
+	if x != nil {
+		return x.RelatedOrderId
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Transaction) GetPolicyVersion() int64 {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return 0
+}
+
+func (x *Transaction) GetRewardRuleIds() []int64 {
+	if x != nil {
+		return x.RewardRuleIds
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`                                 // Optional. Only return these types.
+	CreatedFrom   int64                  `protobuf:"varint,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"` // Optional. Unix timestamp, inclusive.
+	CreatedTo     int64                  `protobuf:"varint,4,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`       // Optional. Unix timestamp, exclusive.
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`        // next_page_token from the previous page.
+	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`          // Defaults to 50, at most 200.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTransactionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetCreatedFrom() int64 {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetCreatedTo() int64 {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`                          // Newest first.
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ConversionPolicy is a version of the points-to-yen conversion rules.
+type ConversionPolicy struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Version        int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PointsPerYen   int64                  `protobuf:"varint,2,opt,name=points_per_yen,json=pointsPerYen,proto3" json:"points_per_yen,omitempty"`       // Points exchanged for one yen.
+	MinBalance     int64                  `protobuf:"varint,3,opt,name=min_balance,json=minBalance,proto3" json:"min_balance,omitempty"`               // Smallest soda_points balance allowed to convert.
+	MinPoints      int64                  `protobuf:"varint,4,opt,name=min_points,json=minPoints,proto3" json:"min_points,omitempty"`                  // Smallest single conversion.
+	MaxPoints      int64                  `protobuf:"varint,5,opt,name=max_points,json=maxPoints,proto3" json:"max_points,omitempty"`                  // Largest single conversion. 0 means no limit.
+	DailyCapPoints int64                  `protobuf:"varint,6,opt,name=daily_cap_points,json=dailyCapPoints,proto3" json:"daily_cap_points,omitempty"` // Points a user may convert per UTC day. 0 means no cap.
+	EffectiveFrom  int64                  `protobuf:"varint,7,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`      // Unix timestamp
+	CreatedBy      string                 `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversionPolicy) Reset() {
+	*x = ConversionPolicy{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversionPolicy) ProtoMessage() {}
+
+func (x *ConversionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversionPolicy.ProtoReflect.Descriptor instead.
+func (*ConversionPolicy) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConversionPolicy) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetPointsPerYen() int64 {
+	if x != nil {
+		return x.PointsPerYen
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetMinBalance() int64 {
+	if x != nil {
+		return x.MinBalance
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetMinPoints() int64 {
+	if x != nil {
+		return x.MinPoints
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetMaxPoints() int64 {
+	if x != nil {
+		return x.MaxPoints
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetDailyCapPoints() int64 {
+	if x != nil {
+		return x.DailyCapPoints
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetEffectiveFrom() int64 {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return 0
+}
+
+func (x *ConversionPolicy) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ConversionPolicy) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type GetConversionPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConversionPolicyRequest) Reset() {
+	*x = GetConversionPolicyRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversionPolicyRequest) ProtoMessage() {}
+
+func (x *GetConversionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetConversionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{7}
+}
+
+type ScheduleConversionPolicyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PointsPerYen   int64                  `protobuf:"varint,1,opt,name=points_per_yen,json=pointsPerYen,proto3" json:"points_per_yen,omitempty"`
+	MinBalance     int64                  `protobuf:"varint,2,opt,name=min_balance,json=minBalance,proto3" json:"min_balance,omitempty"`
+	MinPoints      int64                  `protobuf:"varint,3,opt,name=min_points,json=minPoints,proto3" json:"min_points,omitempty"`
+	MaxPoints      int64                  `protobuf:"varint,4,opt,name=max_points,json=maxPoints,proto3" json:"max_points,omitempty"`                  // Optional. 0 means no limit.
+	DailyCapPoints int64                  `protobuf:"varint,5,opt,name=daily_cap_points,json=dailyCapPoints,proto3" json:"daily_cap_points,omitempty"` // Optional. 0 means no cap.
+	EffectiveFrom  int64                  `protobuf:"varint,6,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`      // Optional. Unix timestamp, defaults to now. Must not be in the past.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduleConversionPolicyRequest) Reset() {
+	*x = ScheduleConversionPolicyRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleConversionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleConversionPolicyRequest) ProtoMessage() {}
+
+func (x *ScheduleConversionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleConversionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleConversionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ScheduleConversionPolicyRequest) GetPointsPerYen() int64 {
+	if x != nil {
+		return x.PointsPerYen
+	}
+	return 0
+}
+
+func (x *ScheduleConversionPolicyRequest) GetMinBalance() int64 {
+	if x != nil {
+		return x.MinBalance
+	}
+	return 0
+}
+
+func (x *ScheduleConversionPolicyRequest) GetMinPoints() int64 {
+	if x != nil {
+		return x.MinPoints
+	}
+	return 0
+}
+
+func (x *ScheduleConversionPolicyRequest) GetMaxPoints() int64 {
+	if x != nil {
+		return x.MaxPoints
+	}
+	return 0
+}
+
+func (x *ScheduleConversionPolicyRequest) GetDailyCapPoints() int64 {
+	if x != nil {
+		return x.DailyCapPoints
+	}
+	return 0
+}
+
+func (x *ScheduleConversionPolicyRequest) GetEffectiveFrom() int64 {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return 0
+}
+
+type ListConversionPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConversionPoliciesRequest) Reset() {
+	*x = ListConversionPoliciesRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConversionPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConversionPoliciesRequest) ProtoMessage() {}
+
+func (x *ListConversionPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConversionPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListConversionPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{9}
+}
+
+type ListConversionPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*ConversionPolicy    `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"` // Latest effective_from first.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConversionPoliciesResponse) Reset() {
+	*x = ListConversionPoliciesResponse{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConversionPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConversionPoliciesResponse) ProtoMessage() {}
+
+func (x *ListConversionPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConversionPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListConversionPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListConversionPoliciesResponse) GetPolicies() []*ConversionPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type GetExpiringPointsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Before        int64                  `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"` // Optional. Unix timestamp, defaults to 30 days from now.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExpiringPointsRequest) Reset() {
+	*x = GetExpiringPointsRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExpiringPointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExpiringPointsRequest) ProtoMessage() {}
+
+func (x *GetExpiringPointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExpiringPointsRequest.ProtoReflect.Descriptor instead.
+func (*GetExpiringPointsRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetExpiringPointsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetExpiringPointsRequest) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+// PointLot is what is left of the points earned in one transaction.
+type PointLot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // The EARNED transaction.
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointLot) Reset() {
+	*x = PointLot{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointLot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointLot) ProtoMessage() {}
+
+func (x *PointLot) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointLot.ProtoReflect.Descriptor instead.
+func (*PointLot) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PointLot) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PointLot) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *PointLot) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type GetExpiringPointsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalPoints   int64                  `protobuf:"varint,1,opt,name=total_points,json=totalPoints,proto3" json:"total_points,omitempty"`
+	Lots          []*PointLot            `protobuf:"bytes,2,rep,name=lots,proto3" json:"lots,omitempty"` // Soonest expiry first.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExpiringPointsResponse) Reset() {
+	*x = GetExpiringPointsResponse{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExpiringPointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExpiringPointsResponse) ProtoMessage() {}
+
+func (x *GetExpiringPointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExpiringPointsResponse.ProtoReflect.Descriptor instead.
+func (*GetExpiringPointsResponse) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetExpiringPointsResponse) GetTotalPoints() int64 {
+	if x != nil {
+		return x.TotalPoints
+	}
+	return 0
+}
+
+func (x *GetExpiringPointsResponse) GetLots() []*PointLot {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+type WatchWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchWalletRequest) Reset() {
+	*x = WatchWalletRequest{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchWalletRequest) ProtoMessage() {}
+
+func (x *WatchWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchWalletRequest.ProtoReflect.Descriptor instead.
+func (*WatchWalletRequest) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchWalletRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Heartbeat is sent on an idle wallet watch so clients can tell a quiet
+// stream from a dead one.
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SentAt        int64                  `protobuf:"varint,1,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Heartbeat) GetSentAt() int64 {
+	if x != nil {
+		return x.SentAt
+	}
+	return 0
+}
+
+type WalletEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*WalletEvent_Wallet
+	//	*WalletEvent_Heartbeat
+	Event         isWalletEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletEvent) Reset() {
+	*x = WalletEvent{}
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletEvent) ProtoMessage() {}
+
+func (x *WalletEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletEvent.ProtoReflect.Descriptor instead.
+func (*WalletEvent) Descriptor() ([]byte, []int) {
+	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WalletEvent) GetEvent() isWalletEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *WalletEvent) GetWallet() *Wallet {
+	if x != nil {
+		if x, ok := x.Event.(*WalletEvent_Wallet); ok {
+			return x.Wallet
+		}
+	}
+	return nil
+}
+
+func (x *WalletEvent) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Event.(*WalletEvent_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+type isWalletEvent_Event interface {
+	isWalletEvent_Event()
+}
+
+type WalletEvent_Wallet struct {
+	Wallet *Wallet `protobuf:"bytes,1,opt,name=wallet,proto3,oneof"` // The wallet after a committed change.
+}
+
+type WalletEvent_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+func (*WalletEvent_Wallet) isWalletEvent_Event() {}
+
+func (*WalletEvent_Heartbeat) isWalletEvent_Event() {}
+
 var File_foundation_proto_soda_finance_v1_finance_proto protoreflect.FileDescriptor
 
 const file_foundation_proto_soda_finance_v1_finance_proto_rawDesc = "" +
@@ -194,13 +1086,90 @@ const file_foundation_proto_soda_finance_v1_finance_proto_rawDesc = "" +
 	"sodaPoints\x12!\n" +
 	"\fsoda_balance\x18\x03 \x01(\x03R\vsodaBalance\"&\n" +
 	"\vUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"U\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"~\n" +
 	"\x0eConvertRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12*\n" +
-	"\x11points_to_convert\x18\x02 \x01(\x03R\x0fpointsToConvert2\x9f\x01\n" +
+	"\x11points_to_convert\x18\x02 \x01(\x03R\x0fpointsToConvert\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\xfa\x01\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12(\n" +
+	"\x10related_order_id\x18\x05 \x01(\tR\x0erelatedOrderId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12%\n" +
+	"\x0epolicy_version\x18\a \x01(\x03R\rpolicyVersion\x12&\n" +
+	"\x0freward_rule_ids\x18\b \x03(\x03R\rrewardRuleIds\"\xc6\x01\n" +
+	"\x17ListTransactionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12!\n" +
+	"\fcreated_from\x18\x03 \x01(\x03R\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\x04 \x01(\x03R\tcreatedTo\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"\x84\x01\n" +
+	"\x18ListTransactionsResponse\x12@\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x1c.soda_finance.v1.TransactionR\ftransactions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc0\x02\n" +
+	"\x10ConversionPolicy\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12$\n" +
+	"\x0epoints_per_yen\x18\x02 \x01(\x03R\fpointsPerYen\x12\x1f\n" +
+	"\vmin_balance\x18\x03 \x01(\x03R\n" +
+	"minBalance\x12\x1d\n" +
+	"\n" +
+	"min_points\x18\x04 \x01(\x03R\tminPoints\x12\x1d\n" +
+	"\n" +
+	"max_points\x18\x05 \x01(\x03R\tmaxPoints\x12(\n" +
+	"\x10daily_cap_points\x18\x06 \x01(\x03R\x0edailyCapPoints\x12%\n" +
+	"\x0eeffective_from\x18\a \x01(\x03R\reffectiveFrom\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\b \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\"\x1c\n" +
+	"\x1aGetConversionPolicyRequest\"\xf7\x01\n" +
+	"\x1fScheduleConversionPolicyRequest\x12$\n" +
+	"\x0epoints_per_yen\x18\x01 \x01(\x03R\fpointsPerYen\x12\x1f\n" +
+	"\vmin_balance\x18\x02 \x01(\x03R\n" +
+	"minBalance\x12\x1d\n" +
+	"\n" +
+	"min_points\x18\x03 \x01(\x03R\tminPoints\x12\x1d\n" +
+	"\n" +
+	"max_points\x18\x04 \x01(\x03R\tmaxPoints\x12(\n" +
+	"\x10daily_cap_points\x18\x05 \x01(\x03R\x0edailyCapPoints\x12%\n" +
+	"\x0eeffective_from\x18\x06 \x01(\x03R\reffectiveFrom\"\x1f\n" +
+	"\x1dListConversionPoliciesRequest\"_\n" +
+	"\x1eListConversionPoliciesResponse\x12=\n" +
+	"\bpolicies\x18\x01 \x03(\v2!.soda_finance.v1.ConversionPolicyR\bpolicies\"K\n" +
+	"\x18GetExpiringPointsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\x03R\x06before\"h\n" +
+	"\bPointLot\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"m\n" +
+	"\x19GetExpiringPointsResponse\x12!\n" +
+	"\ftotal_points\x18\x01 \x01(\x03R\vtotalPoints\x12-\n" +
+	"\x04lots\x18\x02 \x03(\v2\x19.soda_finance.v1.PointLotR\x04lots\"-\n" +
+	"\x12WatchWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"$\n" +
+	"\tHeartbeat\x12\x17\n" +
+	"\asent_at\x18\x01 \x01(\x03R\x06sentAt\"\x85\x01\n" +
+	"\vWalletEvent\x121\n" +
+	"\x06wallet\x18\x01 \x01(\v2\x17.soda_finance.v1.WalletH\x00R\x06wallet\x12:\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x1a.soda_finance.v1.HeartbeatH\x00R\theartbeatB\a\n" +
+	"\x05event2\x9b\x06\n" +
 	"\x0eFinanceService\x12B\n" +
 	"\tGetWallet\x12\x1c.soda_finance.v1.UserRequest\x1a\x17.soda_finance.v1.Wallet\x12I\n" +
-	"\rConvertPoints\x12\x1f.soda_finance.v1.ConvertRequest\x1a\x17.soda_finance.v1.WalletB;Z9soda-interview/foundation/proto/soda-finance/v1;financev1b\x06proto3"
+	"\rConvertPoints\x12\x1f.soda_finance.v1.ConvertRequest\x1a\x17.soda_finance.v1.Wallet\x12R\n" +
+	"\vWatchWallet\x12#.soda_finance.v1.WatchWalletRequest\x1a\x1c.soda_finance.v1.WalletEvent0\x01\x12g\n" +
+	"\x10ListTransactions\x12(.soda_finance.v1.ListTransactionsRequest\x1a).soda_finance.v1.ListTransactionsResponse\x12j\n" +
+	"\x11GetExpiringPoints\x12).soda_finance.v1.GetExpiringPointsRequest\x1a*.soda_finance.v1.GetExpiringPointsResponse\x12e\n" +
+	"\x13GetConversionPolicy\x12+.soda_finance.v1.GetConversionPolicyRequest\x1a!.soda_finance.v1.ConversionPolicy\x12o\n" +
+	"\x18ScheduleConversionPolicy\x120.soda_finance.v1.ScheduleConversionPolicyRequest\x1a!.soda_finance.v1.ConversionPolicy\x12y\n" +
+	"\x16ListConversionPolicies\x12..soda_finance.v1.ListConversionPoliciesRequest\x1a/.soda_finance.v1.ListConversionPoliciesResponseB;Z9soda-interview/foundation/proto/soda-finance/v1;financev1b\x06proto3"
 
 var (
 	file_foundation_proto_soda_finance_v1_finance_proto_rawDescOnce sync.Once
@@ -214,22 +1183,53 @@ func file_foundation_proto_soda_finance_v1_finance_proto_rawDescGZIP() []byte {
 	return file_foundation_proto_soda_finance_v1_finance_proto_rawDescData
 }
 
-var file_foundation_proto_soda_finance_v1_finance_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_foundation_proto_soda_finance_v1_finance_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_foundation_proto_soda_finance_v1_finance_proto_goTypes = []any{
-	(*Wallet)(nil),         // 0: soda_finance.v1.Wallet
-	(*UserRequest)(nil),    // 1: soda_finance.v1.UserRequest
-	(*ConvertRequest)(nil), // 2: soda_finance.v1.ConvertRequest
+	(*Wallet)(nil),                          // 0: soda_finance.v1.Wallet
+	(*UserRequest)(nil),                     // 1: soda_finance.v1.UserRequest
+	(*ConvertRequest)(nil),                  // 2: soda_finance.v1.ConvertRequest
+	(*Transaction)(nil),                     // 3: soda_finance.v1.Transaction
+	(*ListTransactionsRequest)(nil),         // 4: soda_finance.v1.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),        // 5: soda_finance.v1.ListTransactionsResponse
+	(*ConversionPolicy)(nil),                // 6: soda_finance.v1.ConversionPolicy
+	(*GetConversionPolicyRequest)(nil),      // 7: soda_finance.v1.GetConversionPolicyRequest
+	(*ScheduleConversionPolicyRequest)(nil), // 8: soda_finance.v1.ScheduleConversionPolicyRequest
+	(*ListConversionPoliciesRequest)(nil),   // 9: soda_finance.v1.ListConversionPoliciesRequest
+	(*ListConversionPoliciesResponse)(nil),  // 10: soda_finance.v1.ListConversionPoliciesResponse
+	(*GetExpiringPointsRequest)(nil),        // 11: soda_finance.v1.GetExpiringPointsRequest
+	(*PointLot)(nil),                        // 12: soda_finance.v1.PointLot
+	(*GetExpiringPointsResponse)(nil),       // 13: soda_finance.v1.GetExpiringPointsResponse
+	(*WatchWalletRequest)(nil),              // 14: soda_finance.v1.WatchWalletRequest
+	(*Heartbeat)(nil),                       // 15: soda_finance.v1.Heartbeat
+	(*WalletEvent)(nil),                     // 16: soda_finance.v1.WalletEvent
 }
 var file_foundation_proto_soda_finance_v1_finance_proto_depIdxs = []int32{
-	1, // 0: soda_finance.v1.FinanceService.GetWallet:input_type -> soda_finance.v1.UserRequest
-	2, // 1: soda_finance.v1.FinanceService.ConvertPoints:input_type -> soda_finance.v1.ConvertRequest
-	0, // 2: soda_finance.v1.FinanceService.GetWallet:output_type -> soda_finance.v1.Wallet
-	0, // 3: soda_finance.v1.FinanceService.ConvertPoints:output_type -> soda_finance.v1.Wallet
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3,  // 0: soda_finance.v1.ListTransactionsResponse.transactions:type_name -> soda_finance.v1.Transaction
+	6,  // 1: soda_finance.v1.ListConversionPoliciesResponse.policies:type_name -> soda_finance.v1.ConversionPolicy
+	12, // 2: soda_finance.v1.GetExpiringPointsResponse.lots:type_name -> soda_finance.v1.PointLot
+	0,  // 3: soda_finance.v1.WalletEvent.wallet:type_name -> soda_finance.v1.Wallet
+	15, // 4: soda_finance.v1.WalletEvent.heartbeat:type_name -> soda_finance.v1.Heartbeat
+	1,  // 5: soda_finance.v1.FinanceService.GetWallet:input_type -> soda_finance.v1.UserRequest
+	2,  // 6: soda_finance.v1.FinanceService.ConvertPoints:input_type -> soda_finance.v1.ConvertRequest
+	14, // 7: soda_finance.v1.FinanceService.WatchWallet:input_type -> soda_finance.v1.WatchWalletRequest
+	4,  // 8: soda_finance.v1.FinanceService.ListTransactions:input_type -> soda_finance.v1.ListTransactionsRequest
+	11, // 9: soda_finance.v1.FinanceService.GetExpiringPoints:input_type -> soda_finance.v1.GetExpiringPointsRequest
+	7,  // 10: soda_finance.v1.FinanceService.GetConversionPolicy:input_type -> soda_finance.v1.GetConversionPolicyRequest
+	8,  // 11: soda_finance.v1.FinanceService.ScheduleConversionPolicy:input_type -> soda_finance.v1.ScheduleConversionPolicyRequest
+	9,  // 12: soda_finance.v1.FinanceService.ListConversionPolicies:input_type -> soda_finance.v1.ListConversionPoliciesRequest
+	0,  // 13: soda_finance.v1.FinanceService.GetWallet:output_type -> soda_finance.v1.Wallet
+	0,  // 14: soda_finance.v1.FinanceService.ConvertPoints:output_type -> soda_finance.v1.Wallet
+	16, // 15: soda_finance.v1.FinanceService.WatchWallet:output_type -> soda_finance.v1.WalletEvent
+	5,  // 16: soda_finance.v1.FinanceService.ListTransactions:output_type -> soda_finance.v1.ListTransactionsResponse
+	13, // 17: soda_finance.v1.FinanceService.GetExpiringPoints:output_type -> soda_finance.v1.GetExpiringPointsResponse
+	6,  // 18: soda_finance.v1.FinanceService.GetConversionPolicy:output_type -> soda_finance.v1.ConversionPolicy
+	6,  // 19: soda_finance.v1.FinanceService.ScheduleConversionPolicy:output_type -> soda_finance.v1.ConversionPolicy
+	10, // 20: soda_finance.v1.FinanceService.ListConversionPolicies:output_type -> soda_finance.v1.ListConversionPoliciesResponse
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_foundation_proto_soda_finance_v1_finance_proto_init() }
@@ -237,13 +1237,17 @@ func file_foundation_proto_soda_finance_v1_finance_proto_init() {
 	if File_foundation_proto_soda_finance_v1_finance_proto != nil {
 		return
 	}
+	file_foundation_proto_soda_finance_v1_finance_proto_msgTypes[16].OneofWrappers = []any{
+		(*WalletEvent_Wallet)(nil),
+		(*WalletEvent_Heartbeat)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_foundation_proto_soda_finance_v1_finance_proto_rawDesc), len(file_foundation_proto_soda_finance_v1_finance_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
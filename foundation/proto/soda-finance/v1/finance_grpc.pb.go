@@ -19,8 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FinanceService_GetWallet_FullMethodName     = "/soda_finance.v1.FinanceService/GetWallet"
-	FinanceService_ConvertPoints_FullMethodName = "/soda_finance.v1.FinanceService/ConvertPoints"
+	FinanceService_GetWallet_FullMethodName                = "/soda_finance.v1.FinanceService/GetWallet"
+	FinanceService_ConvertPoints_FullMethodName            = "/soda_finance.v1.FinanceService/ConvertPoints"
+	FinanceService_WatchWallet_FullMethodName              = "/soda_finance.v1.FinanceService/WatchWallet"
+	FinanceService_ListTransactions_FullMethodName         = "/soda_finance.v1.FinanceService/ListTransactions"
+	FinanceService_GetExpiringPoints_FullMethodName        = "/soda_finance.v1.FinanceService/GetExpiringPoints"
+	FinanceService_GetConversionPolicy_FullMethodName      = "/soda_finance.v1.FinanceService/GetConversionPolicy"
+	FinanceService_ScheduleConversionPolicy_FullMethodName = "/soda_finance.v1.FinanceService/ScheduleConversionPolicy"
+	FinanceService_ListConversionPolicies_FullMethodName   = "/soda_finance.v1.FinanceService/ListConversionPolicies"
 )
 
 // FinanceServiceClient is the client API for FinanceService service.
@@ -29,6 +35,22 @@ const (
 type FinanceServiceClient interface {
 	GetWallet(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*Wallet, error)
 	ConvertPoints(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*Wallet, error)
+	// WatchWallet sends the wallet, then the wallet again after every change
+	// to it commits, with heartbeats in between. The stream ends with
+	// UNAVAILABLE when the server shuts down; clients should reconnect.
+	WatchWallet(ctx context.Context, in *WatchWalletRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalletEvent], error)
+	// ListTransactions pages through a user's wallet history.
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// GetExpiringPoints returns the user's points that expire before a time.
+	GetExpiringPoints(ctx context.Context, in *GetExpiringPointsRequest, opts ...grpc.CallOption) (*GetExpiringPointsResponse, error)
+	// GetConversionPolicy returns the conversion policy in force now.
+	GetConversionPolicy(ctx context.Context, in *GetConversionPolicyRequest, opts ...grpc.CallOption) (*ConversionPolicy, error)
+	// ScheduleConversionPolicy adds a policy version taking effect at
+	// effective_from. Admin only.
+	ScheduleConversionPolicy(ctx context.Context, in *ScheduleConversionPolicyRequest, opts ...grpc.CallOption) (*ConversionPolicy, error)
+	// ListConversionPolicies returns every policy version, scheduled ones
+	// included. Admin only.
+	ListConversionPolicies(ctx context.Context, in *ListConversionPoliciesRequest, opts ...grpc.CallOption) (*ListConversionPoliciesResponse, error)
 }
 
 type financeServiceClient struct {
@@ -59,12 +81,97 @@ func (c *financeServiceClient) ConvertPoints(ctx context.Context, in *ConvertReq
 	return out, nil
 }
 
+func (c *financeServiceClient) WatchWallet(ctx context.Context, in *WatchWalletRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalletEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FinanceService_ServiceDesc.Streams[0], FinanceService_WatchWallet_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchWalletRequest, WalletEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FinanceService_WatchWalletClient = grpc.ServerStreamingClient[WalletEvent]
+
+func (c *financeServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, FinanceService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *financeServiceClient) GetExpiringPoints(ctx context.Context, in *GetExpiringPointsRequest, opts ...grpc.CallOption) (*GetExpiringPointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetExpiringPointsResponse)
+	err := c.cc.Invoke(ctx, FinanceService_GetExpiringPoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *financeServiceClient) GetConversionPolicy(ctx context.Context, in *GetConversionPolicyRequest, opts ...grpc.CallOption) (*ConversionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConversionPolicy)
+	err := c.cc.Invoke(ctx, FinanceService_GetConversionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *financeServiceClient) ScheduleConversionPolicy(ctx context.Context, in *ScheduleConversionPolicyRequest, opts ...grpc.CallOption) (*ConversionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConversionPolicy)
+	err := c.cc.Invoke(ctx, FinanceService_ScheduleConversionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *financeServiceClient) ListConversionPolicies(ctx context.Context, in *ListConversionPoliciesRequest, opts ...grpc.CallOption) (*ListConversionPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConversionPoliciesResponse)
+	err := c.cc.Invoke(ctx, FinanceService_ListConversionPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FinanceServiceServer is the server API for FinanceService service.
 // All implementations must embed UnimplementedFinanceServiceServer
 // for forward compatibility.
 type FinanceServiceServer interface {
 	GetWallet(context.Context, *UserRequest) (*Wallet, error)
 	ConvertPoints(context.Context, *ConvertRequest) (*Wallet, error)
+	// WatchWallet sends the wallet, then the wallet again after every change
+	// to it commits, with heartbeats in between. The stream ends with
+	// UNAVAILABLE when the server shuts down; clients should reconnect.
+	WatchWallet(*WatchWalletRequest, grpc.ServerStreamingServer[WalletEvent]) error
+	// ListTransactions pages through a user's wallet history.
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	// GetExpiringPoints returns the user's points that expire before a time.
+	GetExpiringPoints(context.Context, *GetExpiringPointsRequest) (*GetExpiringPointsResponse, error)
+	// GetConversionPolicy returns the conversion policy in force now.
+	GetConversionPolicy(context.Context, *GetConversionPolicyRequest) (*ConversionPolicy, error)
+	// ScheduleConversionPolicy adds a policy version taking effect at
+	// effective_from. Admin only.
+	ScheduleConversionPolicy(context.Context, *ScheduleConversionPolicyRequest) (*ConversionPolicy, error)
+	// ListConversionPolicies returns every policy version, scheduled ones
+	// included. Admin only.
+	ListConversionPolicies(context.Context, *ListConversionPoliciesRequest) (*ListConversionPoliciesResponse, error)
 	mustEmbedUnimplementedFinanceServiceServer()
 }
 
@@ -81,6 +188,24 @@ func (UnimplementedFinanceServiceServer) GetWallet(context.Context, *UserRequest
 func (UnimplementedFinanceServiceServer) ConvertPoints(context.Context, *ConvertRequest) (*Wallet, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConvertPoints not implemented")
 }
+func (UnimplementedFinanceServiceServer) WatchWallet(*WatchWalletRequest, grpc.ServerStreamingServer[WalletEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchWallet not implemented")
+}
+func (UnimplementedFinanceServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedFinanceServiceServer) GetExpiringPoints(context.Context, *GetExpiringPointsRequest) (*GetExpiringPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExpiringPoints not implemented")
+}
+func (UnimplementedFinanceServiceServer) GetConversionPolicy(context.Context, *GetConversionPolicyRequest) (*ConversionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConversionPolicy not implemented")
+}
+func (UnimplementedFinanceServiceServer) ScheduleConversionPolicy(context.Context, *ScheduleConversionPolicyRequest) (*ConversionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleConversionPolicy not implemented")
+}
+func (UnimplementedFinanceServiceServer) ListConversionPolicies(context.Context, *ListConversionPoliciesRequest) (*ListConversionPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConversionPolicies not implemented")
+}
 func (UnimplementedFinanceServiceServer) mustEmbedUnimplementedFinanceServiceServer() {}
 func (UnimplementedFinanceServiceServer) testEmbeddedByValue()                        {}
 
@@ -138,6 +263,107 @@ func _FinanceService_ConvertPoints_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FinanceService_WatchWallet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchWalletRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FinanceServiceServer).WatchWallet(m, &grpc.GenericServerStream[WatchWalletRequest, WalletEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FinanceService_WatchWalletServer = grpc.ServerStreamingServer[WalletEvent]
+
+func _FinanceService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinanceServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinanceService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinanceServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinanceService_GetExpiringPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExpiringPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinanceServiceServer).GetExpiringPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinanceService_GetExpiringPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinanceServiceServer).GetExpiringPoints(ctx, req.(*GetExpiringPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinanceService_GetConversionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConversionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinanceServiceServer).GetConversionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinanceService_GetConversionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinanceServiceServer).GetConversionPolicy(ctx, req.(*GetConversionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinanceService_ScheduleConversionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleConversionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinanceServiceServer).ScheduleConversionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinanceService_ScheduleConversionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinanceServiceServer).ScheduleConversionPolicy(ctx, req.(*ScheduleConversionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinanceService_ListConversionPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConversionPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinanceServiceServer).ListConversionPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinanceService_ListConversionPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinanceServiceServer).ListConversionPolicies(ctx, req.(*ListConversionPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FinanceService_ServiceDesc is the grpc.ServiceDesc for FinanceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +379,33 @@ var FinanceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ConvertPoints",
 			Handler:    _FinanceService_ConvertPoints_Handler,
 		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _FinanceService_ListTransactions_Handler,
+		},
+		{
+			MethodName: "GetExpiringPoints",
+			Handler:    _FinanceService_GetExpiringPoints_Handler,
+		},
+		{
+			MethodName: "GetConversionPolicy",
+			Handler:    _FinanceService_GetConversionPolicy_Handler,
+		},
+		{
+			MethodName: "ScheduleConversionPolicy",
+			Handler:    _FinanceService_ScheduleConversionPolicy_Handler,
+		},
+		{
+			MethodName: "ListConversionPolicies",
+			Handler:    _FinanceService_ListConversionPolicies_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchWallet",
+			Handler:       _FinanceService_WatchWallet_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "foundation/proto/soda-finance/v1/finance.proto",
 }
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpTracesPath is the collector path the OTLP/HTTP specification assigns to
+// traces.
+const otlpTracesPath = "/v1/traces"
+
+// NewOTLPExporter posts spans to an OpenTelemetry collector using OTLP/HTTP
+// with protobuf encoding. "/v1/traces" is appended when the endpoint has no
+// path, and plain http endpoints are sent without TLS.
+func NewOTLPExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+}
+
+// NewWriterExporter writes one JSON object per span to w, e.g. os.Stdout, for
+// offline inspection.
+func NewWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// NewFileExporter appends spans to the file at path, creating it if needed.
+// The file is closed on Shutdown.
+func NewFileExporter(path string) (sdktrace.SpanExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening trace file: %w", err)
+	}
+	exp, err := NewWriterExporter(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return fileExporter{SpanExporter: exp, file: f}, nil
+}
+
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if cerr := e.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+// propagator reads and writes the W3C traceparent and tracestate headers.
+var propagator = propagation.TraceContext{}
+
+// metadataCarrier adapts gRPC metadata to the propagation.TextMapCarrier the
+// OpenTelemetry propagators read and write.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Extract returns ctx carrying the caller's span context from md, which the
+// next span started from it continues. ctx is returned unchanged when md
+// carries no valid traceparent.
+func Extract(ctx context.Context, md metadata.MD) context.Context {
+	return propagator.Extract(ctx, metadataCarrier(md))
+}
+
+// Inject writes the span context in ctx to md as a W3C traceparent, for
+// outgoing calls.
+func Inject(ctx context.Context, md metadata.MD) {
+	propagator.Inject(ctx, metadataCarrier(md))
+}
@@ -0,0 +1,74 @@
+// Package tracing sets up the OpenTelemetry SDK for the service and gives the
+// rest of the code a short way to start spans.
+//
+// Services start spans with Start against the global tracer provider, which
+// is a no-op until SetDefault installs a configured one. Spans that are not
+// sampled still carry their IDs so log lines can be correlated downstream.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the scope every span of the service is recorded
+// under.
+const instrumentationName = "soda-interview"
+
+// Config configures a tracer provider.
+type Config struct {
+	// SampleRate is the fraction of new traces recorded, from 0 to 1. Traces
+	// continued from a caller follow the caller's sampling decision.
+	SampleRate     float64
+	Exporter       sdktrace.SpanExporter
+	ServiceName    string
+	ServiceVersion string
+}
+
+// New returns a tracer provider that exports sampled spans in batches until
+// Shutdown.
+func New(cfg Config) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(cfg.Exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	)
+}
+
+// SetDefault installs tp as the provider used by Start, and W3C trace
+// context as the propagator.
+func SetDefault(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+}
+
+// Tracer returns the service's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start begins an internal span as a child of the span in ctx, or a new trace
+// when there is none.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SetError marks span as failed with err. A nil error is ignored.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError describes a single request field that failed validation.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors collects every invalid field found while validating a request.
+type FieldErrors []FieldError
+
+// Add records a validation failure for the given field.
+func (fe *FieldErrors) Add(field, message string) {
+	*fe = append(*fe, FieldError{Field: field, Message: message})
+}
+
+// Err returns the collected errors, or nil if nothing failed.
+func (fe FieldErrors) Err() error {
+	if len(fe) == 0 {
+		return nil
+	}
+	return fe
+}
+
+func (fe FieldErrors) Error() string {
+	parts := make([]string, len(fe))
+	for i, f := range fe {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// AsFieldErrors reports whether err wraps FieldErrors and returns them.
+func AsFieldErrors(err error) (FieldErrors, bool) {
+	var fe FieldErrors
+	if errors.As(err, &fe) {
+		return fe, true
+	}
+	return nil, false
+}
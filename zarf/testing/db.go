@@ -78,9 +78,22 @@ func (c *DBContainer) Truncate(t *testing.T) {
 	defer cancel()
 
 	tables := []string{
+		"idempotency_keys",
+		"outbox_events",
+		"inventory_movements",
+		"product_stock",
+		"point_lots",
+		"ledger_entries",
+		"ledger_accounts",
 		"transactions",
+		"reward_grants",
+		"payment_actions",
+		"order_items",
 		"orders",
+		"blog_revisions",
+		"referral_visits",
 		"blogs",
+		"reward_rules",
 		"products",
 		"wallets",
 	}
@@ -89,4 +102,10 @@ func (c *DBContainer) Truncate(t *testing.T) {
 	if _, err := c.DB.Exec(ctx, q); err != nil {
 		t.Fatalf("failed to truncate tables: %v", err)
 	}
+
+	// Keep the conversion policy seeded by the migrations and drop any a test
+	// scheduled.
+	if _, err := c.DB.Exec(ctx, "DELETE FROM conversion_policies WHERE version > 1;"); err != nil {
+		t.Fatalf("failed to reset conversion policies: %v", err)
+	}
 }